@@ -18,32 +18,13 @@ import (
 // alerts will return the saved
 func (a *Action) alert(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
 	// Read params
-	params := apirouter.GetParams(req)
-	if params == nil {
-		apiError := apirouter.ErrorFromRequest(req, "parameters is nil", "no parameters specified", http.StatusBadRequest, http.StatusBadRequest, "")
-		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
-		return
-	}
-	idStr := params.GetString("sequence")
-	if idStr == "" {
-		apiError := apirouter.ErrorFromRequest(req, "missing sequence param", "missing sequence param", http.StatusBadRequest, http.StatusBadRequest, "")
-		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
-		return
-	}
-	sequenceNumber, err := strconv.Atoi(idStr)
-	if err != nil {
-		apiError := apirouter.ErrorFromRequest(req, "sequence is invalid", "sequence is invalid", http.StatusBadRequest, http.StatusBadRequest, "")
-		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
-		return
-	}
-	if sequenceNumber < 0 || sequenceNumber > 4294967295 {
-		apiError := apirouter.ErrorFromRequest(req, "sequence out of range", "sequence out of range", http.StatusBadRequest, http.StatusBadRequest, "")
-		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+	sequenceNumber, ok := readSequenceParam(w, req)
+	if !ok {
 		return
 	}
 
 	// Get alert
-	alertModel, err := models.GetAlertMessageBySequenceNumber(req.Context(), uint32(sequenceNumber), model.WithAllDependencies(a.Config))
+	alertModel, err := models.GetAlertMessageBySequenceNumber(req.Context(), sequenceNumber, model.WithAllDependencies(a.Config))
 	if err != nil {
 		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
 		return
@@ -56,26 +37,54 @@ func (a *Action) alert(w http.ResponseWriter, req *http.Request, _ httprouter.Pa
 		app.APIErrorResponse(w, req, http.StatusInternalServerError, ErrAlertFailed)
 		return
 	}
-	am := alertModel.ProcessAlertMessage()
-	if am == nil {
-		app.APIErrorResponse(w, req, http.StatusInternalServerError, ErrAlertNotValidType)
-		return
-	}
-	err = am.Read(alertModel.GetRawMessage())
+	am, err := alertModel.ProcessAlertMessage()
 	if err != nil {
 		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
 		return
+	} else if am == nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, ErrAlertNotValidType)
+		return
 	}
 	p := webhook.Payload{
-		AlertType: alertModel.GetAlertType(),
-		Sequence:  alertModel.SequenceNumber,
-		Raw:       hex.EncodeToString(alertModel.GetRawData()),
-		Text:      am.MessageString(),
+		AlertType:    alertModel.GetAlertType().String(),
+		Sequence:     alertModel.SequenceNumber,
+		SupersededBy: alertModel.SupersededBy,
+		Supersedes:   alertModel.Supersedes,
+		Raw:          hex.EncodeToString(alertModel.GetRawData()),
+		Text:         am.MessageString(),
 	}
 	// Return the response
 	_ = apirouter.ReturnJSONEncode(
 		w,
 		http.StatusOK,
 		json.NewEncoder(w),
-		p, []string{"sequence", "raw", "text", "alert_type"})
+		p, []string{"sequence", "raw", "text", "alert_type", "supersedes", "superseded_by"})
+}
+
+// readSequenceParam will read the sequence number from the request, writing the error response if it's not valid
+func readSequenceParam(w http.ResponseWriter, req *http.Request) (uint32, bool) {
+	params := apirouter.GetParams(req)
+	if params == nil {
+		apiError := apirouter.ErrorFromRequest(req, "parameters is nil", "no parameters specified", http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return 0, false
+	}
+	idStr := params.GetString("sequence")
+	if idStr == "" {
+		apiError := apirouter.ErrorFromRequest(req, "missing sequence param", "missing sequence param", http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return 0, false
+	}
+	sequenceNumber, err := strconv.Atoi(idStr)
+	if err != nil {
+		apiError := apirouter.ErrorFromRequest(req, "sequence is invalid", "sequence is invalid", http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return 0, false
+	}
+	if sequenceNumber < 0 || sequenceNumber > 4294967295 {
+		apiError := apirouter.ErrorFromRequest(req, "sequence out of range", "sequence out of range", http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return 0, false
+	}
+	return uint32(sequenceNumber), true
 }
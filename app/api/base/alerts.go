@@ -2,7 +2,9 @@ package base
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/julienschmidt/httprouter"
 	apirouter "github.com/mrz1836/go-api-router"
@@ -12,22 +14,60 @@ import (
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
 )
 
+// defaultAlertsLimit is the number of alerts returned when no limit is requested
+const defaultAlertsLimit = 50
+
 // AlertsResponse is the response for the alerts endpoint
 type AlertsResponse struct {
-	Alerts         []*models.AlertMessage `json:"alerts"`
-	LatestSequence uint32                 `json:"latest_sequence"`
+	Alerts         []*AlertSummary `json:"alerts"`
+	LatestSequence uint32          `json:"latest_sequence"`
+	Limit          int             `json:"limit"`
+	Offset         int             `json:"offset"`
+	Total          int             `json:"total"`
+}
+
+// AlertSummary is an alert in the alerts listing
+type AlertSummary struct {
+	AlertType    string `json:"alert_type"`
+	Message      string `json:"message"`
+	Sequence     uint32 `json:"sequence"`
+	Superseded   bool   `json:"superseded"`
+	SupersededBy uint32 `json:"superseded_by"`
+	Supersedes   uint32 `json:"supersedes"`
+	Timestamp    uint64 `json:"timestamp"`
 }
 
-// alerts will return the saved
+// alerts will return a page of the saved alerts, newest first
+//
+// Optional pagination: limit (1 to 1000, defaults to 50) and offset (defaults to 0)
 func (a *Action) alerts(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	// Get all alerts
-	alerts, err := models.GetAllAlerts(req.Context(), nil, model.WithAllDependencies(a.Config))
+	// Read the pagination
+	limit, offset, err := parseAlertsPage(req)
+	if err != nil {
+		apiError := apirouter.ErrorFromRequest(req, err.Error(), err.Error(), http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return
+	}
+
+	// Get the page of alerts
+	alerts, total, err := models.GetAlertMessages(req.Context(), limit, offset, model.WithAllDependencies(a.Config))
 	if err != nil {
-		app.APIErrorResponse(w, req, http.StatusBadRequest, err)
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
 		return
-	} else if alerts == nil {
-		app.APIErrorResponse(w, req, http.StatusNotFound, ErrAlertNotFound)
+	}
+
+	// Get the latest sequence, whatever page was requested
+	response := AlertsResponse{Alerts: make([]*AlertSummary, 0, len(alerts)), Limit: limit, Offset: offset, Total: total}
+	latest, err := models.GetLatestAlert(req.Context(), nil, model.WithAllDependencies(a.Config))
+	if err != nil && !errors.Is(err, models.ErrLatestAlertNotFound) {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
 		return
+	} else if latest != nil {
+		response.LatestSequence = latest.SequenceNumber
+	}
+
+	for _, alert := range alerts {
+		response.Alerts = append(response.Alerts, a.alertSummary(alert))
 	}
 
 	// Return the response
@@ -35,8 +75,44 @@ func (a *Action) alerts(w http.ResponseWriter, req *http.Request, _ httprouter.P
 		w,
 		http.StatusOK,
 		json.NewEncoder(w),
-		AlertsResponse{
-			Alerts:         alerts,
-			LatestSequence: alerts[len(alerts)-1].SequenceNumber,
-		}, []string{"alerts", "latest_sequence"})
+		response, []string{"alerts", "latest_sequence", "limit", "offset", "total"})
+}
+
+// alertSummary will summarize a stored alert, an alert that can't be read is listed without its message
+func (a *Action) alertSummary(alert *models.AlertMessage) *AlertSummary {
+	summary := &AlertSummary{
+		AlertType:    models.AlertType(alert.AlertType).String(),
+		Sequence:     alert.SequenceNumber,
+		Superseded:   alert.Superseded,
+		SupersededBy: alert.SupersededBy,
+		Supersedes:   alert.Supersedes,
+	}
+	alert.SetOptions(model.WithAllDependencies(a.Config))
+	if err := alert.ReadRaw(); err != nil {
+		a.Config.Services.Log.Errorf("failed to read stored alert %d: %s", alert.SequenceNumber, err.Error())
+		return summary
+	}
+	summary.AlertType = alert.GetAlertType().String()
+	summary.Timestamp = alert.Timestamp()
+	if body, err := alert.ProcessAlertMessage(); err == nil && body != nil {
+		summary.Message = body.MessageString()
+	}
+	return summary
+}
+
+// parseAlertsPage will read the alerts pagination from the request query
+func parseAlertsPage(req *http.Request) (limit, offset int, err error) {
+	query := req.URL.Query()
+	limit = defaultAlertsLimit
+	if l := query.Get("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 || limit > models.MaxAlertMessagesLimit {
+			return 0, 0, ErrInvalidPageLimit
+		}
+	}
+	if o := query.Get("offset"); o != "" {
+		if offset, err = strconv.Atoi(o); err != nil || offset < 0 {
+			return 0, 0, ErrInvalidPageOffset
+		}
+	}
+	return limit, offset, nil
 }
@@ -0,0 +1,116 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// TestAction_Alerts_Superseded will test the method alerts() reports superseded alerts
+func (ts *TestSuite) TestAction_Alerts_Superseded() {
+	// saveAlert will save a signed informational alert, superseding the given sequence if not zero
+	saveAlert := func(sequence, supersedes uint32) {
+		a := models.NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(models.AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SequenceNumber = sequence
+		a.SetTimestamp(1700000000)
+		a.SetVersion(1)
+		if supersedes > 0 {
+			a.SetSupersedes(supersedes)
+		}
+		a.SerializeData()
+		sigs, err := utils.SignWithGenesis(a.GetRawData())
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+
+		parsed, err := models.NewAlertFromBytes(a.Serialize(), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NoError(parsed.Save(context.Background()))
+		ts.Require().NoError(models.MarkSupersededAlert(context.Background(), parsed))
+	}
+	saveAlert(1, 0)
+	saveAlert(2, 1)
+
+	action := &Action{app.Action{Config: ts.Dependencies}}
+	req := httptest.NewRequest(http.MethodGet, "/alerts", nil)
+	w := httptest.NewRecorder()
+	action.alerts(w, req, nil)
+	ts.Require().Equal(http.StatusOK, w.Code)
+
+	// Newest first
+	response := &AlertsResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	ts.Require().Len(response.Alerts, 2)
+	ts.Equal(uint32(2), response.Alerts[0].Sequence)
+	ts.Equal(uint32(0), response.Alerts[0].SupersededBy)
+	ts.Equal(uint32(1), response.Alerts[0].Supersedes)
+	ts.Equal(uint32(2), response.Alerts[1].SupersededBy)
+	ts.Equal(uint32(0), response.Alerts[1].Supersedes)
+}
+
+// TestAction_Alerts will test the method alerts()
+func (ts *TestSuite) TestAction_Alerts() {
+	action := &Action{app.Action{Config: ts.Dependencies}}
+
+	// getAlerts will request the alerts with the query
+	getAlerts := func(query string) (*httptest.ResponseRecorder, *AlertsResponse) {
+		w := httptest.NewRecorder()
+		action.alerts(w, httptest.NewRequest(http.MethodGet, "/alerts"+query, nil), nil)
+		response := &AlertsResponse{}
+		if w.Code == http.StatusOK {
+			ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+		}
+		return w, response
+	}
+
+	ts.Run("no alerts is an empty list", func() {
+		w, response := getAlerts("")
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Contains(w.Body.String(), `"alerts":[]`)
+		ts.Equal(0, response.Total)
+		ts.Equal(defaultAlertsLimit, response.Limit)
+	})
+
+	for sequence := uint32(1); sequence <= 3; sequence++ {
+		a := models.NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(models.AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SequenceNumber = sequence
+		a.SetTimestamp(1700000000 + uint64(sequence))
+		a.SetVersion(1)
+		a.SerializeData()
+		sigs, err := utils.SignWithGenesis(a.GetRawData())
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		_ = a.Serialize()
+		ts.Require().NoError(a.Save(context.Background()))
+	}
+
+	ts.Run("page", func() {
+		w, response := getAlerts("?limit=1&offset=1")
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(3, response.Total)
+		ts.Equal(uint32(3), response.LatestSequence)
+		ts.Require().Len(response.Alerts, 1)
+		ts.Equal(&AlertSummary{
+			AlertType: "informational",
+			Message:   "[informational] Informational: hi",
+			Sequence:  2,
+			Timestamp: 1700000002,
+		}, response.Alerts[0])
+	})
+
+	ts.Run("invalid pagination", func() {
+		for _, query := range []string{"?limit=0", "?limit=1001", "?limit=ten", "?offset=-1", "?offset=one"} {
+			w, _ := getAlerts(query)
+			ts.Equal(http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// maxAuditLimit is the most audit entries that can be requested at once
+const maxAuditLimit = 1000
+
+// AuditResponse is the response for the audit endpoint
+type AuditResponse struct {
+	ChainError string               `json:"chain_error,omitempty"`
+	ChainValid bool                 `json:"chain_valid"`
+	Entries    []*models.AuditEntry `json:"entries"`
+}
+
+// audit will return the audit log of applied alert actions
+//
+// Optional filters: from and to (RFC3339 timestamps), type (alert type name or number) and limit
+// The whole stored chain is verified on each request, chain_valid is false if it was tampered with
+func (a *Action) audit(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Read the filters
+	filter, err := parseAuditFilter(req)
+	if err != nil {
+		apiError := apirouter.ErrorFromRequest(req, err.Error(), err.Error(), http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return
+	}
+
+	// Get the audit entries
+	var entries []*models.AuditEntry
+	if entries, err = models.GetAuditEntries(req.Context(), filter, nil, model.WithAllDependencies(a.Config)); err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Verify the stored chain
+	response := AuditResponse{ChainValid: true, Entries: entries}
+	if err = models.VerifyStoredAuditChain(req.Context(), model.WithAllDependencies(a.Config)); err != nil {
+		a.Config.Services.Log.Errorf("audit log verification failed: %s", err.Error())
+		response.ChainValid = false
+		response.ChainError = err.Error()
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		response, []string{"chain_error", "chain_valid", "entries"})
+}
+
+// parseAuditFilter will read the audit filters from the request query
+func parseAuditFilter(req *http.Request) (*models.AuditFilter, error) {
+	query := req.URL.Query()
+	filter := &models.AuditFilter{}
+
+	var err error
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return nil, ErrInvalidAuditFrom
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return nil, ErrInvalidAuditTo
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 1 || filter.Limit > maxAuditLimit {
+			return nil, ErrInvalidAuditLimit
+		}
+	}
+	if alertType := query.Get("type"); alertType != "" {
+		var at models.AlertType
+		if at, err = models.ParseAlertType(alertType); err != nil {
+			return nil, ErrInvalidAuditType
+		}
+		filter.AlertType = &at
+	}
+	return filter, nil
+}
@@ -0,0 +1,100 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// executeTestAlert will execute an alert of the given type so it's recorded in the audit log
+func (ts *TestSuite) executeTestAlert(sequence uint32, alertType models.AlertType, body []byte) {
+	alert := models.NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+	alert.SequenceNumber = sequence
+	alert.SetAlertType(alertType)
+	alert.SetRawMessage(body)
+
+	action, err := alert.ProcessAlertMessage()
+	ts.Require().NoError(err)
+	_ = models.ExecuteAlertAction(context.Background(), alert, action, models.AuditSourceGossip)
+}
+
+// auditRequest will call the audit endpoint with the given query
+func (ts *TestSuite) auditRequest(query string) (*httptest.ResponseRecorder, *AuditResponse) {
+	action := &Action{app.Action{Config: ts.Dependencies}}
+	req := httptest.NewRequest(http.MethodGet, "/audit?"+query, nil)
+	w := httptest.NewRecorder()
+	action.audit(w, req, nil)
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+
+	response := &AuditResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	return w, response
+}
+
+// TestAction_Audit will test the method audit()
+func (ts *TestSuite) TestAction_Audit() {
+	ts.executeTestAlert(10, models.AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+	ts.executeTestAlert(11, models.AlertTypeInformational, []byte{0x02, 'h', 'i'})
+	ts.executeTestAlert(12, models.AlertTypeSetKeys, make([]byte, models.SetKeysMessageSize))
+
+	ts.Run("all entries", func() {
+		w, response := ts.auditRequest("")
+		ts.Equal(http.StatusOK, w.Code)
+		ts.Require().Len(response.Entries, 3)
+		ts.True(response.ChainValid)
+		ts.Empty(response.ChainError)
+	})
+
+	ts.Run("valid filter", func() {
+		w, response := ts.auditRequest("from=2000-01-01T00:00:00Z&to=2100-01-01T00:00:00Z&type=1")
+		ts.Equal(http.StatusOK, w.Code)
+		ts.Require().Len(response.Entries, 2)
+		ts.Equal(uint32(10), response.Entries[0].SequenceNumber)
+		ts.Equal(uint32(11), response.Entries[1].SequenceNumber)
+	})
+
+	ts.Run("filter by type", func() {
+		_, response := ts.auditRequest("type=8")
+		ts.Require().Len(response.Entries, 1)
+		ts.Equal(uint32(models.AlertTypeSetKeys), response.Entries[0].AlertType)
+
+		_, response = ts.auditRequest("type=5")
+		ts.Empty(response.Entries)
+
+		_, response = ts.auditRequest("type=set_keys")
+		ts.Require().Len(response.Entries, 1)
+	})
+
+	ts.Run("limit", func() {
+		_, response := ts.auditRequest("limit=1")
+		ts.Require().Len(response.Entries, 1)
+		ts.Equal(uint32(10), response.Entries[0].SequenceNumber)
+	})
+
+	ts.Run("invalid filters", func() {
+		for _, query := range []string{"from=yesterday", "to=2024-13-01", "type=freeze", "type=-1", "limit=0", "limit=5000"} {
+			w, _ := ts.auditRequest(query)
+			ts.Equal(http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	ts.Run("tampered chain is reported", func() {
+		entries, err := models.GetAuditEntries(context.Background(), nil, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		entries[1].SetOptions(model.WithAllDependencies(ts.Dependencies))
+		entries[1].Summary = "tampered"
+		ts.Require().NoError(entries[1].Save(context.Background()))
+
+		w, response := ts.auditRequest("")
+		ts.Equal(http.StatusOK, w.Code)
+		ts.False(response.ChainValid)
+		ts.Contains(response.ChainError, models.ErrAuditChainBroken.Error())
+	})
+}
@@ -0,0 +1,65 @@
+package base
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+)
+
+// APIKeyHeader is the header the auth token can be sent in, instead of as a bearer token
+const APIKeyHeader = "X-API-Key"
+
+// authRequest will process a request to a protected endpoint in the router
+func (a *Action) authRequest(router *apirouter.Router, h httprouter.Handle) httprouter.Handle {
+	return a.request(router, a.requireToken(h))
+}
+
+// adminRequest will process a request to an endpoint that changes state (re-running alert actions against the node),
+// it fails closed: without a configured auth token every request is refused with a 403
+func (a *Action) adminRequest(router *apirouter.Router, h httprouter.Handle) httprouter.Handle {
+	return a.request(router, a.requireConfiguredToken(a.requireToken(h)))
+}
+
+// requireConfiguredToken will only call the handler if an auth token is configured
+// Without one the request gets a 403 with a JSON error, whatever it sends
+func (a *Action) requireConfiguredToken(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		if a.Config.WebServer.AuthToken == "" {
+			app.APIErrorResponse(w, req, http.StatusForbidden, ErrAuthTokenNotConfigured)
+			return
+		}
+		h(w, req, ps)
+	}
+}
+
+// requireToken will only call the handler if the request has the auth token, or no auth token is configured
+// A request without the token gets a 401 with a JSON error
+func (a *Action) requireToken(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		if !a.authorized(req) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			app.APIErrorResponse(w, req, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+		h(w, req, ps)
+	}
+}
+
+// authorized returns true if the request has the auth token (as a bearer token or an API key),
+// or no auth token is configured. The token is compared in constant time
+func (a *Action) authorized(req *http.Request) bool {
+	token := a.Config.WebServer.AuthToken
+	if token == "" {
+		return true
+	}
+	given := req.Header.Get(APIKeyHeader)
+	if bearer, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); found {
+		given = bearer
+	}
+	return given != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
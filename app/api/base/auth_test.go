@@ -0,0 +1,62 @@
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	apirouter "github.com/mrz1836/go-api-router"
+)
+
+// testAuthToken is the auth token configured by the tests of protected endpoints
+const testAuthToken = "secret"
+
+// authRequest will call the endpoint through the router with the given auth headers
+func (ts *TestSuite) authRequest(path string, headers map[string]string) *httptest.ResponseRecorder {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	return w
+}
+
+// TestAction_RequireToken will test the method requireToken()
+func (ts *TestSuite) TestAction_RequireToken() {
+	ts.Run("open without an auth token", func() {
+		w := ts.authRequest("/alerts", nil)
+		ts.Equal(http.StatusOK, w.Code)
+	})
+
+	ts.Run("auth token", func() {
+		ts.Dependencies.WebServer.AuthToken = "secret"
+		defer func() {
+			ts.Dependencies.WebServer.AuthToken = ""
+		}()
+
+		w := ts.authRequest("/alerts", nil)
+		ts.Equal(http.StatusUnauthorized, w.Code)
+		ts.Contains(w.Body.String(), ErrUnauthorized.Error())
+		ts.Equal("Bearer", w.Header().Get("WWW-Authenticate"))
+
+		w = ts.authRequest("/alerts", map[string]string{"Authorization": "Bearer wrong"})
+		ts.Equal(http.StatusUnauthorized, w.Code)
+
+		w = ts.authRequest("/alerts", map[string]string{"Authorization": "secret"})
+		ts.Equal(http.StatusUnauthorized, w.Code)
+
+		w = ts.authRequest("/alerts", map[string]string{"Authorization": "Bearer secret"})
+		ts.Equal(http.StatusOK, w.Code)
+
+		w = ts.authRequest("/alerts", map[string]string{APIKeyHeader: "secret"})
+		ts.Equal(http.StatusOK, w.Code)
+
+		w = ts.authRequest("/audit", map[string]string{APIKeyHeader: "wrong"})
+		ts.Equal(http.StatusUnauthorized, w.Code)
+
+		w = ts.authRequest("/livez", nil)
+		ts.Equal(http.StatusOK, w.Code)
+	})
+}
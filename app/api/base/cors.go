@@ -0,0 +1,72 @@
+package base
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+)
+
+// corsMaxAge is how long (in seconds) a browser may cache the answer to a preflight request
+const corsMaxAge = "600"
+
+// request will process a request in the router, allowing it cross-origin if the origin is allowed
+func (a *Action) request(router *apirouter.Router, h httprouter.Handle) httprouter.Handle {
+	return a.Request(router, func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		a.setCORSHeaders(w.Header(), req)
+		h(w, req, ps)
+	})
+}
+
+// preflight will answer the CORS preflight (OPTIONS) request for any route
+// The allowed methods and headers are only sent if the origin is allowed, so the browser refuses the others
+func (a *Action) preflight(w http.ResponseWriter, req *http.Request) {
+	if a.setCORSHeaders(w.Header(), req) {
+		cors := a.Config.WebServer.CORS
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(cors.AllowedMethods, ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(cors.AllowedHeaders, ", "))
+		w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setCORSHeaders will set the allowed origin header if the request origin is allowed
+// Returns true if the origin is allowed
+func (a *Action) setCORSHeaders(header http.Header, req *http.Request) bool {
+	allowed := a.Config.WebServer.CORS.AllowedOrigins
+	if len(allowed) == 0 {
+		return false
+	}
+
+	// The answer depends on the origin, caches need to keep them apart
+	header.Add("Vary", "Origin")
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	for _, pattern := range allowed {
+		if pattern == "*" {
+			header.Set("Access-Control-Allow-Origin", "*")
+			return true
+		} else if originMatches(pattern, origin) {
+			header.Set("Access-Control-Allow-Origin", origin)
+			return true
+		}
+	}
+	return false
+}
+
+// originMatches returns true if the origin is the allowed origin, or one of its subdomains if it's a wildcard
+// (https://*.example.com allows https://app.example.com but not https://example.com)
+func originMatches(allowed, origin string) bool {
+	if strings.EqualFold(allowed, origin) {
+		return true
+	}
+	scheme, domain, wildcard := strings.Cut(allowed, "://*.")
+	if !wildcard {
+		return false
+	}
+	host, found := strings.CutPrefix(strings.ToLower(origin), strings.ToLower(scheme)+"://")
+	return found && !strings.Contains(host, "/") && strings.HasSuffix(host, "."+strings.ToLower(domain)) && len(host) > len(domain)+1
+}
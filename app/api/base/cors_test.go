@@ -0,0 +1,91 @@
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apirouter "github.com/mrz1836/go-api-router"
+	"github.com/stretchr/testify/assert"
+)
+
+// corsRequest will call the endpoint through the router from the given origin
+func (ts *TestSuite) corsRequest(method, path, origin string) *httptest.ResponseRecorder {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Origin", origin)
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	return w
+}
+
+// TestAction_CORS will test the methods preflight() and setCORSHeaders()
+func (ts *TestSuite) TestAction_CORS() {
+	ts.Run("off by default", func() {
+		w := ts.corsRequest(http.MethodOptions, "/alerts", "https://dashboard.example.com")
+		ts.Equal(http.StatusNoContent, w.Code)
+		ts.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+
+		w = ts.corsRequest(http.MethodGet, "/alerts", "https://dashboard.example.com")
+		ts.Equal(http.StatusOK, w.Code)
+		ts.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	ts.Run("allowed origins", func() {
+		ts.Dependencies.WebServer.CORS.AllowedOrigins = []string{"https://dashboard.example.com"}
+		defer func() {
+			ts.Dependencies.WebServer.CORS.AllowedOrigins = nil
+		}()
+
+		w := ts.corsRequest(http.MethodOptions, "/alerts", "https://dashboard.example.com")
+		ts.Equal(http.StatusNoContent, w.Code)
+		ts.Equal("https://dashboard.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		ts.Equal("GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+		ts.Contains(w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+		ts.Equal("Origin", w.Header().Get("Vary"))
+
+		w = ts.corsRequest(http.MethodGet, "/alerts", "https://dashboard.example.com")
+		ts.Equal(http.StatusOK, w.Code)
+		ts.Equal("https://dashboard.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+		w = ts.corsRequest(http.MethodOptions, "/alerts", "https://evil.example.org")
+		ts.Equal(http.StatusNoContent, w.Code)
+		ts.Empty(w.Header().Get("Access-Control-Allow-Origin"))
+		ts.Empty(w.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	ts.Run("any origin", func() {
+		ts.Dependencies.WebServer.CORS.AllowedOrigins = []string{"*"}
+		defer func() {
+			ts.Dependencies.WebServer.CORS.AllowedOrigins = nil
+		}()
+
+		w := ts.corsRequest(http.MethodGet, "/livez", "https://anywhere.example.org")
+		ts.Equal("*", w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+// TestOriginMatches will test the method originMatches()
+func TestOriginMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		allowed string
+		origin  string
+		matches bool
+	}{
+		{allowed: "https://example.com", origin: "https://example.com", matches: true},
+		{allowed: "https://example.com", origin: "HTTPS://EXAMPLE.COM", matches: true},
+		{allowed: "https://example.com", origin: "http://example.com"},
+		{allowed: "https://example.com", origin: "https://example.com:8443"},
+		{allowed: "https://*.example.com", origin: "https://app.example.com", matches: true},
+		{allowed: "https://*.example.com", origin: "https://a.b.example.com", matches: true},
+		{allowed: "https://*.example.com", origin: "https://example.com"},
+		{allowed: "https://*.example.com", origin: "https://evilexample.com"},
+		{allowed: "https://*.example.com", origin: "http://app.example.com"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.matches, originMatches(tt.allowed, tt.origin), tt.allowed+" "+tt.origin)
+	}
+}
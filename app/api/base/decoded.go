@@ -0,0 +1,71 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// DecodedAlertResponse is the response for the decoded alert endpoint
+type DecodedAlertResponse struct {
+	AlertType string          `json:"alert_type"`
+	Body      json.RawMessage `json:"body"`
+	Sequence  uint32          `json:"sequence"`
+}
+
+// alertDecoded will return the fully decoded body of a stored alert
+func (a *Action) alertDecoded(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Read params
+	sequenceNumber, ok := readSequenceParam(w, req)
+	if !ok {
+		return
+	}
+
+	// Get alert
+	alertModel, err := models.GetAlertMessageBySequenceNumber(req.Context(), sequenceNumber, model.WithAllDependencies(a.Config))
+	if errors.Is(err, models.ErrAlertNotFound) {
+		app.APIErrorResponse(w, req, http.StatusNotFound, ErrAlertNotFound)
+		return
+	} else if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+	if err = alertModel.ReadRaw(); err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, fmt.Errorf("%w: %s", ErrAlertMalformed, err.Error()))
+		return
+	}
+	body, err := alertModel.ProcessAlertMessage()
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	} else if body == nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, ErrAlertNotValidType)
+		return
+	}
+
+	// ToJSON returns nothing if the body couldn't be marshaled
+	decoded := body.ToJSON(req.Context())
+	if len(decoded) == 0 {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, fmt.Errorf("%w: %s", ErrAlertNotDecoded, alertModel.GetAlertType().String()))
+		return
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		DecodedAlertResponse{
+			AlertType: alertModel.GetAlertType().String(),
+			Body:      decoded,
+			Sequence:  alertModel.SequenceNumber,
+		}, []string{"alert_type", "body", "sequence"})
+}
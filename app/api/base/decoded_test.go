@@ -0,0 +1,82 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// decodedRequest will call the decoded alert endpoint through the router
+func (ts *TestSuite) decodedRequest(sequence string) (*httptest.ResponseRecorder, *DecodedAlertResponse) {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(http.MethodGet, "/alert/"+sequence+"/decoded", nil)
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+
+	response := &DecodedAlertResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	return w, response
+}
+
+// TestAction_AlertDecoded will test the method alertDecoded()
+func (ts *TestSuite) TestAction_AlertDecoded() {
+	ts.Run("informational body", func() {
+		ts.saveSignedAlert(1)
+
+		w, response := ts.decodedRequest("1")
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(uint32(1), response.Sequence)
+		ts.Equal(models.AlertTypeInformational.String(), response.AlertType)
+
+		body := map[string]interface{}{}
+		ts.Require().NoError(json.Unmarshal(response.Body, &body))
+		ts.Equal("hi", body["message"])
+	})
+
+	ts.Run("body that can't be encoded", func() {
+		ts.Dependencies.InfoMessageEncoding = config.InfoMessageEncodingStrict
+		defer func() {
+			ts.Dependencies.InfoMessageEncoding = config.InfoMessageEncodingBase64
+		}()
+
+		// A message that isn't valid UTF-8 is refused by the strict encoding
+		a := models.NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(models.AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 0xff, 0xfe})
+		a.SequenceNumber = 2
+		a.SetTimestamp(1700000000)
+		a.SetVersion(1)
+		a.SerializeData()
+		sigs, err := utils.SignWithGenesis(a.GetRawData())
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		a.Serialize()
+		ts.Require().NoError(a.Save(context.Background()))
+
+		w, _ := ts.decodedRequest("2")
+		ts.Equal(http.StatusInternalServerError, w.Code)
+		ts.Contains(w.Body.String(), ErrAlertNotDecoded.Error())
+	})
+
+	ts.Run("unknown alert", func() {
+		w, _ := ts.decodedRequest("99")
+		ts.Equal(http.StatusNotFound, w.Code)
+	})
+
+	ts.Run("invalid sequence", func() {
+		w, _ := ts.decodedRequest("abc")
+		ts.Equal(http.StatusBadRequest, w.Code)
+	})
+}
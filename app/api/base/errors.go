@@ -4,7 +4,24 @@ import "errors"
 
 // Static errors for the base API package
 var (
-	ErrAlertNotFound     = errors.New("alert not found")
-	ErrAlertFailed       = errors.New("alert failed")
-	ErrAlertNotValidType = errors.New("alert not valid type")
+	ErrAlertNotFound          = errors.New("alert not found")
+	ErrAlertFailed            = errors.New("alert failed")
+	ErrAlertNotValidType      = errors.New("alert not valid type")
+	ErrAlertMalformed         = errors.New("stored alert is malformed")
+	ErrAlertDeferred          = errors.New("too few peers are connected, the alert is executed once enough are")
+	ErrInvalidAlertRaw        = errors.New("raw must be a hex encoded alert")
+	ErrAlertNotDecoded        = errors.New("alert body could not be encoded as JSON")
+	ErrAlertProcessed         = errors.New("alert was already processed")
+	ErrInvalidSequence        = errors.New("sequence must be between 0 and 4294967295")
+	ErrUnauthorized           = errors.New("missing or invalid auth token")
+	ErrAuthTokenNotConfigured = errors.New("endpoint is disabled until web_server.auth_token is set")
+	ErrInvalidPageLimit       = errors.New("limit must be between 1 and 1000")
+	ErrInvalidPageOffset      = errors.New("offset must be 0 or more")
+	ErrInvalidAuditFrom       = errors.New("from must be an RFC3339 timestamp")
+	ErrInvalidAuditLimit      = errors.New("limit must be between 1 and 1000")
+	ErrInvalidAuditTo         = errors.New("to must be an RFC3339 timestamp")
+	ErrInvalidAuditType       = errors.New("type must be an alert type name or number")
+	ErrInvalidExportFrom      = errors.New("from must be a sequence between 0 and 4294967295")
+	ErrInvalidExportRange     = errors.New("from must not be after to")
+	ErrInvalidExportTo        = errors.New("to must be a sequence between 0 and 4294967295")
 )
@@ -0,0 +1,84 @@
+package base
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// exportContentType is the content type of the alert export, one JSON alert per line
+const exportContentType = "application/x-ndjson"
+
+// alertsAction will route the actions under /alerts that share their path segment with the sequence of an alert
+func (a *Action) alertsAction(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+	if ps.ByName("sequence") == "export" {
+		a.exportAlerts(w, req, ps)
+		return
+	}
+	app.NotFound(w, req)
+}
+
+// exportAlerts will stream the stored alerts as NDJSON, lowest sequence first, for archiving
+//
+// Optional range: from and to (inclusive sequence numbers, defaulting to every alert)
+// The alerts are flushed to the client as they're written, an error part way can only end the stream early
+func (a *Action) exportAlerts(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Read the range
+	from, to, err := parseExportRange(req)
+	if err != nil {
+		apiError := apirouter.ErrorFromRequest(req, err.Error(), err.Error(), http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return
+	}
+
+	// Stream the alerts
+	w.Header().Set("Content-Type", exportContentType)
+	w.WriteHeader(http.StatusOK)
+	if err = models.ExportAlerts(req.Context(), &flushWriter{w: w}, from, to, model.WithAllDependencies(a.Config)); err != nil {
+		a.Config.Services.Log.Errorf("alert export from %d to %d stopped: %s", from, to, err.Error())
+	}
+}
+
+// parseExportRange will read the sequence range of the export from the request query
+func parseExportRange(req *http.Request) (from, to uint32, err error) {
+	query := req.URL.Query()
+	to = math.MaxUint32
+	var value uint64
+	if f := query.Get("from"); f != "" {
+		if value, err = strconv.ParseUint(f, 10, 32); err != nil {
+			return 0, 0, ErrInvalidExportFrom
+		}
+		from = uint32(value)
+	}
+	if t := query.Get("to"); t != "" {
+		if value, err = strconv.ParseUint(t, 10, 32); err != nil {
+			return 0, 0, ErrInvalidExportTo
+		}
+		to = uint32(value)
+	}
+	if from > to {
+		return 0, 0, ErrInvalidExportRange
+	}
+	return from, to, nil
+}
+
+// flushWriter flushes the response after each write, so the client gets each alert as it's exported
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+// Write writes to the response and flushes it
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,66 @@
+package base
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	bnmodels "github.com/bsv-blockchain/go-bn/models"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// TestAction_ExportAlerts will test the method exportAlerts()
+func (ts *TestSuite) TestAction_ExportAlerts() {
+	// exportRequest will call the alert export endpoint through the router
+	exportRequest := func(path string) *httptest.ResponseRecorder {
+		router := apirouter.New()
+		RegisterRoutes(router, ts.Dependencies, nil)
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.HTTPRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	fund := bnmodels.Fund{TxOut: bnmodels.TxOut{TxId: strings.Repeat("a", 64), Vout: 1}, EnforceAtHeight: []bnmodels.Enforce{{Start: 100, Stop: 200}}}
+	ts.saveFundsAlert(1, models.AlertTypeFreezeUtxo, fund)
+	ts.saveFundsAlert(2, models.AlertTypeUnfreezeUtxo, fund)
+
+	ts.Run("every alert as NDJSON", func() {
+		w := exportRequest("/alerts/export")
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal("application/x-ndjson", w.Header().Get("Content-Type"))
+
+		sequences := make([]uint32, 0)
+		scanner := bufio.NewScanner(bytes.NewReader(w.Body.Bytes()))
+		for scanner.Scan() {
+			var alert models.AlertJSON
+			ts.Require().NoError(json.Unmarshal(scanner.Bytes(), &alert))
+			sequences = append(sequences, alert.Sequence)
+		}
+		ts.Equal([]uint32{1, 2}, sequences)
+	})
+
+	ts.Run("a range of sequences", func() {
+		w := exportRequest("/alerts/export?from=2&to=2")
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(1, strings.Count(w.Body.String(), "\n"))
+		ts.Contains(w.Body.String(), `"sequence":2`)
+	})
+
+	ts.Run("invalid ranges", func() {
+		for _, query := range []string{"from=x", "to=-1", "to=4294967296", "from=3&to=2"} {
+			w := exportRequest("/alerts/export?" + query)
+			ts.Equal(http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	ts.Run("other paths aren't found", func() {
+		w := exportRequest("/alerts/other")
+		ts.Equal(http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,35 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+
+	bnmodels "github.com/bsv-blockchain/go-bn/models"
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// FrozenResponse is the response for the frozen UTXOs endpoint
+type FrozenResponse struct {
+	Funds []bnmodels.Fund `json:"funds"`
+}
+
+// frozen will return the UTXOs currently frozen according to the alert history, accounting for later unfreezes
+func (a *Action) frozen(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	funds, err := models.GetCurrentlyFrozenUtxos(req.Context(), model.WithAllDependencies(a.Config))
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		FrozenResponse{Funds: funds}, []string{"funds"})
+}
@@ -0,0 +1,74 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	bnmodels "github.com/bsv-blockchain/go-bn/models"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// saveFundsAlert will save a signed freeze or unfreeze alert for the fund
+func (ts *TestSuite) saveFundsAlert(sequence uint32, alertType models.AlertType, fund bnmodels.Fund) {
+	body := &models.AlertMessageFreezeUtxo{Funds: []bnmodels.Fund{fund}}
+	message, err := body.Serialize()
+	ts.Require().NoError(err)
+
+	a := models.NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+	a.SetAlertType(alertType)
+	a.SetRawMessage(message)
+	a.SequenceNumber = sequence
+	a.SetTimestamp(1700000000)
+	a.SetVersion(models.AlertVersionCurrent)
+	a.SerializeData()
+	sigs, err := utils.SignWithGenesis(a.GetRawData())
+	ts.Require().NoError(err)
+	a.SetSignatures(sigs)
+
+	parsed, err := models.NewAlertFromBytes(a.Serialize(), model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().NoError(parsed.Save(context.Background()))
+}
+
+// TestAction_Frozen will test the method frozen()
+func (ts *TestSuite) TestAction_Frozen() {
+	// frozenRequest will call the frozen UTXOs endpoint through the router
+	frozenRequest := func() *FrozenResponse {
+		router := apirouter.New()
+		RegisterRoutes(router, ts.Dependencies, nil)
+		req := httptest.NewRequest(http.MethodGet, "/frozen", nil)
+		w := httptest.NewRecorder()
+		router.HTTPRouter.ServeHTTP(w, req)
+		ts.Require().Equal(http.StatusOK, w.Code)
+
+		response := &FrozenResponse{}
+		ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+		return response
+	}
+
+	ts.Run("nothing frozen", func() {
+		response := frozenRequest()
+		ts.NotNil(response.Funds)
+		ts.Empty(response.Funds)
+	})
+
+	ts.Run("frozen utxos", func() {
+		first := bnmodels.Fund{TxOut: bnmodels.TxOut{TxId: strings.Repeat("a", 64), Vout: 1}, EnforceAtHeight: []bnmodels.Enforce{{Start: 100, Stop: 200}}}
+		second := bnmodels.Fund{TxOut: bnmodels.TxOut{TxId: strings.Repeat("b", 64), Vout: 2}, EnforceAtHeight: []bnmodels.Enforce{{Start: 100, Stop: 200}}}
+		ts.saveFundsAlert(1, models.AlertTypeFreezeUtxo, first)
+		ts.saveFundsAlert(2, models.AlertTypeFreezeUtxo, second)
+		ts.saveFundsAlert(3, models.AlertTypeUnfreezeUtxo, first)
+
+		response := frozenRequest()
+		ts.Require().Len(response.Funds, 1)
+		ts.Equal(second.TxOut, response.Funds[0].TxOut)
+		ts.Equal(second.EnforceAtHeight, response.Funds[0].EnforceAtHeight)
+	})
+}
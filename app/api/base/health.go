@@ -3,6 +3,8 @@ package base
 import (
 	"encoding/json"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	apirouter "github.com/mrz1836/go-api-router"
@@ -10,19 +12,59 @@ import (
 	"github.com/bsv-blockchain/go-alert-system/app"
 	"github.com/bsv-blockchain/go-alert-system/app/models"
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/app/p2p"
 )
 
 // HealthResponse is the response for the health endpoint
 type HealthResponse struct {
-	Alert             models.AlertMessage `json:"alert"`
-	Sequence          uint32              `json:"sequence"`
-	Synced            bool                `json:"synced"`
-	ActivePeers       int                 `json:"active_peers"`
-	UnprocessedAlerts int                 `json:"unprocessed_alerts"`
+	Alert                models.AlertMessage         `json:"alert"` // Latest alert, written as a models.AlertJSON
+	Sequence             uint32                      `json:"sequence"`
+	Synced               bool                        `json:"synced"`                 // Synced is true once we have every alert the connected peers advertised
+	HighestKnownSequence uint32                      `json:"highest_known_sequence"` // Highest sequence advertised by a connected peer within the staleness window
+	LocalSequence        uint32                      `json:"local_sequence"`         // Latest sequence we have stored
+	SyncStatus           SyncStatus                  `json:"sync_status"`
+	ActivePeers          int                         `json:"active_peers"`
+	UnprocessedAlerts    int                         `json:"unprocessed_alerts"`
+	DroppedAlerts        map[models.AlertType]uint64 `json:"dropped_alerts"`    // Alerts dropped on receipt, per disabled alert type
+	DryRun               bool                        `json:"dry_run"`           // DryRun is true while alert actions skip their node RPC calls
+	NodeReachable        bool                        `json:"node_reachable"`    // NodeReachable is true if the node RPC answered the last ping (cached for the node health ttl)
+	NodeLastSuccess      *time.Time                  `json:"node_last_success"` // NodeLastSuccess is when the node RPC last answered a ping, null if it never did
+	Peers                []p2p.PeerInfo              `json:"peers"`             // Connected peers: address, direction, last message, advertised sequence, score, clock skew and sync version
+	PeerCounts           p2p.PeerCounts              `json:"peer_counts"`       // Inbound and outbound peers (static peers aren't counted), and the most allowed
+	PeerScores           []p2p.PeerScore             `json:"peer_scores"`       // Misbehavior scores of sync peers, and the peers banned for reaching the threshold
+	StaticPeers          []p2p.StaticPeer            `json:"static_peers"`      // Configured static peers, and whether we are connected to each
+}
+
+// healthFields are the fields of the health response written to anyone
+var healthFields = []string{
+	"alert", "synced", "highest_known_sequence", "local_sequence", "sync_status", "sequence", "active_peers",
+	"unprocessed_alerts", "dropped_alerts", "dry_run", "node_reachable", "node_last_success", "peer_counts",
+}
+
+// healthPeerFields are the per-peer fields of the health response, only written to an authorized request
+var healthPeerFields = []string{"peers", "peer_scores", "static_peers"}
+
+// SyncStatus is the state of syncing alerts from peers
+type SyncStatus struct {
+	AbandonedRequests uint64  `json:"abandoned_requests"`
+	BackfillRequests  int     `json:"backfill_requests"` // Requests for sequences missing below our latest alert, waiting on a peer
+	CurrentSequence   uint32  `json:"current_sequence"`
+	InFlightRequests  int     `json:"in_flight_requests"`
+	Progress          float64 `json:"progress"`
+	TargetSequence    uint32  `json:"target_sequence"`
 }
 
 // health will return the health of the API and the current alert
+// It's kept for older deployments, a node that isn't ready answers like readyz
+//
+// The per-peer detail (peers, peer_scores and static_peers) is only written when the request carries the auth token
+// (or none is configured), the health probe of anyone else gets the peer counts. The peers endpoint has the full view
 func (a *Action) health(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if readiness := a.readiness(req.Context()); !readiness.Ready {
+		a.writeReadiness(w, readiness)
+		return
+	}
+
 	// Get the latest alert
 	alert, err := models.GetLatestAlert(req.Context(), nil, model.WithAllDependencies(a.Config))
 	if err != nil {
@@ -33,18 +75,50 @@ func (a *Action) health(w http.ResponseWriter, req *http.Request, _ httprouter.P
 		return
 	}
 
+	a.writeHealth(w, req, alert)
+}
+
+// writeHealth will write the health response for the latest alert
+func (a *Action) writeHealth(w http.ResponseWriter, req *http.Request, alert *models.AlertMessage) {
 	failed, _ := models.GetAllUnprocessedAlerts(req.Context(), nil, model.WithAllDependencies(a.Config))
+	current, target, pct := a.P2pServer.SyncProgress()
+	synced, highestKnown, local := a.P2pServer.Synced()
+	nodeReachable, nodeLastSuccess := a.nodeHealth(req.Context())
+	var lastSuccess *time.Time
+	if !nodeLastSuccess.IsZero() {
+		lastSuccess = &nodeLastSuccess
+	}
+
+	response := HealthResponse{
+		Alert:                *alert,
+		Sequence:             alert.SequenceNumber,
+		ActivePeers:          a.P2pServer.ActivePeers(),
+		UnprocessedAlerts:    len(failed),
+		DroppedAlerts:        a.P2pServer.DroppedAlerts(),
+		DryRun:               a.Config.DryRun,
+		NodeReachable:        nodeReachable,
+		NodeLastSuccess:      lastSuccess,
+		PeerCounts:           a.P2pServer.PeerCounts(),
+		Synced:               synced,
+		HighestKnownSequence: highestKnown,
+		LocalSequence:        local,
+		SyncStatus: SyncStatus{
+			AbandonedRequests: a.P2pServer.AbandonedSyncRequests(),
+			BackfillRequests:  a.P2pServer.BackfillRequests(),
+			CurrentSequence:   current,
+			InFlightRequests:  a.P2pServer.InFlightSyncRequests(),
+			Progress:          pct,
+			TargetSequence:    target,
+		},
+	}
+	fields := healthFields
+	if a.authorized(req) {
+		response.Peers = a.P2pServer.Peers()
+		response.PeerScores = a.P2pServer.PeerScores()
+		response.StaticPeers = a.P2pServer.StaticPeers()
+		fields = append(slices.Clone(healthFields), healthPeerFields...)
+	}
 
 	// Return the response
-	_ = apirouter.ReturnJSONEncode(
-		w,
-		http.StatusOK,
-		json.NewEncoder(w),
-		HealthResponse{
-			Alert:             *alert,
-			Sequence:          alert.SequenceNumber,
-			ActivePeers:       a.P2pServer.ActivePeers(),
-			UnprocessedAlerts: len(failed),
-			Synced:            true, // TODO actually fetch this state from the DB somehow, or from the server struct
-		}, []string{"alert", "synced", "sequence", "active_peers", "unprocessed_alerts"})
+	_ = apirouter.ReturnJSONEncode(w, http.StatusOK, json.NewEncoder(w), response, fields)
 }
@@ -0,0 +1,44 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// KeysResponse is the response for the active signing keys endpoint
+type KeysResponse struct {
+	Keys      []string `json:"keys"`
+	Threshold int      `json:"threshold"`
+}
+
+// keys will return the active public keys (hex compressed) and the signatures needed, to verify alerts independently
+//
+// The genesis keys are returned until a set keys alert rotates them, the keys are only empty before the genesis keys are stored
+func (a *Action) keys(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Get the cached active keys
+	activeKeys, err := models.GetActivePublicKeys(req.Context(), model.WithAllDependencies(a.Config))
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Only the public key is returned
+	response := KeysResponse{Keys: make([]string, 0, len(activeKeys)), Threshold: models.SignatureThreshold(a.Config)}
+	for _, key := range activeKeys {
+		response.Keys = append(response.Keys, key.Key)
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		response, []string{"keys", "threshold"})
+}
@@ -0,0 +1,58 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// keysRequest will call the active signing keys endpoint through the router
+func (ts *TestSuite) keysRequest() *KeysResponse {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(http.MethodGet, "/keys", nil)
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	ts.Require().Equal(http.StatusOK, w.Code)
+
+	response := &KeysResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	return response
+}
+
+// TestAction_Keys will test the method keys()
+func (ts *TestSuite) TestAction_Keys() {
+	ts.Run("no keys stored yet", func() {
+		response := ts.keysRequest()
+		ts.NotNil(response.Keys)
+		ts.Empty(response.Keys)
+		ts.Equal(models.SignatureThreshold(ts.Dependencies), response.Threshold)
+	})
+
+	ts.Run("active keys only", func() {
+		for i, key := range []string{utils.MainKey1, utils.MainKey2, utils.MainKey3} {
+			pk := models.NewPublicKey(model.WithAllDependencies(ts.Dependencies), model.New())
+			pk.Key = key
+			pk.Active = i < 2
+			ts.Require().NoError(pk.Save(context.Background()))
+		}
+
+		response := ts.keysRequest()
+		ts.Equal([]string{utils.MainKey1, utils.MainKey2}, response.Keys)
+	})
+}
+
+// TestAction_Keys_Genesis will test the genesis keys are the active keys until they are rotated
+func (ts *TestSuite) TestAction_Keys_Genesis() {
+	ts.Require().NoError(models.CreateGenesisAlert(context.Background(), model.WithAllDependencies(ts.Dependencies)))
+
+	response := ts.keysRequest()
+	ts.ElementsMatch(ts.Dependencies.GenesisKeys, response.Keys)
+}
@@ -0,0 +1,37 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/p2p"
+)
+
+// PeersResponse is the response for the peers endpoint
+//
+// The peer detail (addresses, scores, bans) is served behind the auth token, the health endpoint only writes it
+// to an authorized request and reports the peer counts to anyone else
+type PeersResponse struct {
+	DialBackoff []p2p.DialBackoff `json:"dial_backoff"`
+	Peers       []p2p.PeerInfo    `json:"peers"`        // Connected peers: address, direction, last message, advertised sequence, score, clock skew and sync version
+	PeerScores  []p2p.PeerScore   `json:"peer_scores"`  // Misbehavior scores of sync peers, and the peers banned for reaching the threshold
+	StaticPeers []p2p.StaticPeer  `json:"static_peers"` // Configured static peers, and whether we are connected to each
+}
+
+// peers will return the connected peers, their scores, the static peers and the peers we are backing off from dialing
+func (a *Action) peers(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		PeersResponse{
+			DialBackoff: a.P2pServer.DialBackoff(),
+			Peers:       a.P2pServer.Peers(),
+			PeerScores:  a.P2pServer.PeerScores(),
+			StaticPeers: a.P2pServer.StaticPeers(),
+		}, []string{"dial_backoff", "peers", "peer_scores", "static_peers"})
+}
@@ -0,0 +1,45 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/p2p"
+)
+
+// TestAction_Peers will test the method peers()
+func (ts *TestSuite) TestAction_Peers() {
+	ts.Dependencies.WebServer.AuthToken = testAuthToken
+
+	// peersRequest will call the peers endpoint through the router, with the auth token if given
+	peersRequest := func(token string) *httptest.ResponseRecorder {
+		router := apirouter.New()
+		RegisterRoutes(router, ts.Dependencies, &p2p.Server{})
+		req := httptest.NewRequest(http.MethodGet, "/peers", nil)
+		if token != "" {
+			req.Header.Set(APIKeyHeader, token)
+		}
+		w := httptest.NewRecorder()
+		router.HTTPRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	ts.Run("peer detail requires the auth token", func() {
+		w := peersRequest("")
+		ts.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	ts.Run("peer detail", func() {
+		w := peersRequest(testAuthToken)
+		ts.Require().Equal(http.StatusOK, w.Code)
+
+		fields := map[string]json.RawMessage{}
+		ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), &fields))
+		for _, field := range []string{"dial_backoff", "peers", "peer_scores", "static_peers"} {
+			ts.Contains(fields, field)
+		}
+	})
+}
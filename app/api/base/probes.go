@@ -0,0 +1,92 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// LivenessResponse is the response for the liveness probe
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
+
+// ReadinessResponse is the response for the readiness probe
+type ReadinessResponse struct {
+	Datastore bool `json:"datastore"` // The datastore answered a query
+	Node      bool `json:"node"`      // The node RPC answered a ping (cached for the node health ttl)
+	Peers     int  `json:"peers"`     // Connected P2P peers
+	Ready     bool `json:"ready"`
+	Synced    bool `json:"synced"` // P2P finished discovering peers and has every alert they advertised
+}
+
+// livez will return 200 as long as the process is up and the HTTP server is serving
+func (a *Action) livez(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		LivenessResponse{Status: "ok"}, []string{"status"})
+}
+
+// readyz will return 200 only when the datastore and the node are reachable and P2P has a peer or is synced
+func (a *Action) readyz(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	a.writeReadiness(w, a.readiness(req.Context()))
+}
+
+// readiness will check whether the node is ready to serve traffic
+func (a *Action) readiness(ctx context.Context) ReadinessResponse {
+	var r ReadinessResponse
+
+	// Any query will do, a node without alerts yet is still reachable
+	_, err := models.GetLatestAlert(ctx, nil, model.WithAllDependencies(a.Config))
+	r.Datastore = err == nil || errors.Is(err, models.ErrLatestAlertNotFound)
+
+	// Every alert action needs the node
+	r.Node, _ = a.nodeHealth(ctx)
+
+	if a.P2pServer != nil {
+		r.Peers = len(a.P2pServer.Peers())
+		synced, _, _ := a.P2pServer.Synced()
+		r.Synced = a.P2pServer.Connected() && synced
+	}
+
+	r.Ready = r.Datastore && r.Node && (r.Peers > 0 || r.Synced)
+	return r
+}
+
+// nodeHealth will return whether the node RPC is reachable and when it last answered, the time is zero if it never did
+func (a *Action) nodeHealth(ctx context.Context) (bool, time.Time) {
+	if a.Config.Services.NodeHealth != nil {
+		return a.Config.Services.NodeHealth.Check(ctx)
+	} else if a.Config.Services.Node == nil {
+		return false, time.Time{}
+	}
+
+	// Without the cached check (services set up by hand) the node is pinged every time
+	if _, err := a.Config.Services.Node.BlockCount(ctx); err != nil {
+		return false, time.Time{}
+	}
+	return true, time.Now()
+}
+
+// writeReadiness will write the readiness, with a 503 if the node isn't ready
+func (a *Action) writeReadiness(w http.ResponseWriter, r ReadinessResponse) {
+	status := http.StatusOK
+	if !r.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		status,
+		json.NewEncoder(w),
+		r, []string{"datastore", "node", "peers", "ready", "synced"})
+}
@@ -0,0 +1,174 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/app/p2p"
+)
+
+// errTestNodeDown is returned by the mock node that can't be reached
+var errTestNodeDown = errors.New("node is down")
+
+// probeRequest will call a probe endpoint through the router
+func (ts *TestSuite) probeRequest(path string) *httptest.ResponseRecorder {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	return w
+}
+
+// TestAction_Livez will test the method livez()
+func (ts *TestSuite) TestAction_Livez() {
+	ts.Run("serving without any alerts or peers", func() {
+		w := ts.probeRequest("/livez")
+		ts.Require().Equal(http.StatusOK, w.Code)
+
+		response := LivenessResponse{}
+		ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ts.Equal("ok", response.Status)
+	})
+}
+
+// TestAction_Readyz will test the method readyz()
+func (ts *TestSuite) TestAction_Readyz() {
+	ts.Run("datastore reachable but no p2p", func() {
+		w := ts.probeRequest("/readyz")
+		ts.Require().Equal(http.StatusServiceUnavailable, w.Code)
+
+		response := ReadinessResponse{}
+		ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ts.Equal(ReadinessResponse{Datastore: true, Node: true}, response)
+	})
+
+	ts.Run("node unreachable", func() {
+		previous := ts.Dependencies.Services.NodeHealth
+		defer func() {
+			ts.Dependencies.Services.NodeHealth = previous
+		}()
+		ts.Dependencies.Services.NodeHealth = config.NewNodeHealth(&mocks.Node{
+			BlockCountFunc: func(_ context.Context) (uint32, error) {
+				return 0, errTestNodeDown
+			},
+		}, time.Minute)
+
+		w := ts.probeRequest("/readyz")
+		ts.Require().Equal(http.StatusServiceUnavailable, w.Code)
+
+		response := ReadinessResponse{}
+		ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ts.Equal(ReadinessResponse{Datastore: true}, response)
+	})
+
+	ts.Run("health delegates to readiness", func() {
+		ts.saveSignedAlert(1)
+
+		w := ts.probeRequest("/health")
+		ts.Require().Equal(http.StatusServiceUnavailable, w.Code)
+
+		response := ReadinessResponse{}
+		ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		ts.False(response.Ready)
+	})
+}
+
+// TestHealthResponse_Alert will test the alert of the health response is written in its documented shape
+func (ts *TestSuite) TestHealthResponse_Alert() {
+	ts.saveSignedAlert(1)
+	latest, err := models.GetLatestAlert(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+
+	data, err := json.Marshal(HealthResponse{Alert: *latest, Sequence: latest.SequenceNumber})
+	ts.Require().NoError(err)
+	response := struct {
+		Alert models.AlertJSON `json:"alert"`
+	}{}
+	ts.Require().NoError(json.Unmarshal(data, &response))
+	ts.Equal(models.AlertTypeInformational.String(), response.Alert.AlertType)
+	ts.Equal(uint32(1), response.Alert.Sequence)
+	ts.Equal(latest.Hash, response.Alert.Hash)
+	ts.Equal("2023-11-14T22:13:20Z", response.Alert.Timestamp)
+	ts.JSONEq(`{"message":"hi","message_length":2}`, string(response.Alert.Body))
+	ts.NotContains(string(data), `"raw"`)
+}
+
+// TestAction_WriteHealth will test the per-peer detail of the health response is only written to an authorized request
+func (ts *TestSuite) TestAction_WriteHealth() {
+	ts.Dependencies.WebServer.AuthToken = testAuthToken
+	ts.saveSignedAlert(1)
+	latest, err := models.GetLatestAlert(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	action := &Action{app.Action{Config: ts.Dependencies, P2pServer: &p2p.Server{}}}
+
+	// writtenFields will write the health response for the request, returning its fields
+	writtenFields := func(token string) map[string]json.RawMessage {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		if token != "" {
+			req.Header.Set(APIKeyHeader, token)
+		}
+		w := httptest.NewRecorder()
+		action.writeHealth(w, req, latest)
+		ts.Require().Equal(http.StatusOK, w.Code)
+
+		fields := map[string]json.RawMessage{}
+		ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), &fields))
+		ts.Contains(fields, "peer_counts")
+		return fields
+	}
+
+	ts.Run("authorized", func() {
+		fields := writtenFields(testAuthToken)
+		for _, field := range healthPeerFields {
+			ts.Contains(fields, field)
+		}
+	})
+
+	ts.Run("anonymous", func() {
+		fields := writtenFields("")
+		for _, field := range healthPeerFields {
+			ts.NotContains(fields, field)
+		}
+	})
+
+	ts.Run("no auth token configured", func() {
+		ts.Dependencies.WebServer.AuthToken = ""
+		defer func() { ts.Dependencies.WebServer.AuthToken = testAuthToken }()
+
+		fields := writtenFields("")
+		for _, field := range healthPeerFields {
+			ts.Contains(fields, field)
+		}
+	})
+}
+
+// TestRegisterRoutes_Metrics will test that the metrics are only served when enabled
+func (ts *TestSuite) TestRegisterRoutes_Metrics() {
+	ts.Run("disabled", func() {
+		w := ts.probeRequest("/metrics")
+		ts.Equal(http.StatusNotFound, w.Code)
+	})
+
+	ts.Run("enabled", func() {
+		ts.Dependencies.MetricsEnabled = true
+		defer func() {
+			ts.Dependencies.MetricsEnabled = false
+		}()
+
+		w := ts.probeRequest("/metrics")
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Contains(w.Body.String(), "alert_system_active_peers")
+	})
+}
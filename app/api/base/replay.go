@@ -0,0 +1,69 @@
+package base
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// ReplayResponse is the response for the replay endpoint
+type ReplayResponse struct {
+	Applied int                   `json:"applied"`
+	DryRun  bool                  `json:"dry_run"`
+	Failed  int                   `json:"failed"`
+	Results []models.ReplayResult `json:"results"`
+	Skipped int                   `json:"skipped"`
+}
+
+// replay will execute the actions of the active alerts again, from the sequence given by the from param,
+// to apply the alert history to a new node (see models.ReplayAlerts)
+//
+// With the dry_run param the alerts are only validated. The outcome of each alert is returned by sequence,
+// a replay with failed alerts can be resumed from the first failed sequence
+func (a *Action) replay(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	params := apirouter.GetParams(req)
+	var from uint64
+	response := ReplayResponse{}
+	if params != nil {
+		if _, found := params.Get("from"); found {
+			var ok bool
+			if from, ok = params.GetUint64Ok("from"); !ok || from > math.MaxUint32 {
+				app.APIErrorResponse(w, req, http.StatusBadRequest, ErrInvalidSequence)
+				return
+			}
+		}
+		response.DryRun = params.GetBool("dry_run")
+	}
+
+	results, err := models.ReplayAlerts(req.Context(), uint32(from), response.DryRun, model.WithAllDependencies(a.Config))
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+	response.Results = results
+	for _, result := range results {
+		switch result.Outcome {
+		case models.ReplayApplied, models.ReplayValid:
+			response.Applied++
+		case models.ReplayFailed:
+			a.Config.Services.Log.Errorf("failed to replay alert %d: %s", result.Sequence, result.Error)
+			response.Failed++
+		case models.ReplaySkipped:
+			response.Skipped++
+		}
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		response, []string{"applied", "dry_run", "failed", "results", "skipped"})
+}
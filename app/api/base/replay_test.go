@@ -0,0 +1,84 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	bnmodels "github.com/bsv-blockchain/go-bn/models"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// replayRequest will call the replay endpoint through the router
+func (ts *TestSuite) replayRequest(body string) (*httptest.ResponseRecorder, *ReplayResponse) {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(http.MethodPost, "/alerts/replay", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(APIKeyHeader, testAuthToken)
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+	response := &ReplayResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	return w, response
+}
+
+// TestAction_Replay will test the method replay()
+func (ts *TestSuite) TestAction_Replay() {
+	ts.Dependencies.WebServer.AuthToken = testAuthToken
+	blacklisted := 0
+	ts.Dependencies.Services.Node = &mocks.Node{
+		AddToConsensusBlacklistFunc: func(_ context.Context, _ []bnmodels.Fund) (*bnmodels.AddToConsensusBlacklistResponse, error) {
+			blacklisted++
+			return &bnmodels.AddToConsensusBlacklistResponse{}, nil
+		},
+	}
+	fund := bnmodels.Fund{TxOut: bnmodels.TxOut{TxId: strings.Repeat("a", 64), Vout: 1}, EnforceAtHeight: []bnmodels.Enforce{{Start: 100, Stop: 200}}}
+	ts.saveFundsAlert(1, models.AlertTypeFreezeUtxo, fund)
+	ts.saveSignedAlert(2)
+	ts.saveFundsAlert(3, models.AlertTypeFreezeUtxo, fund)
+
+	ts.Run("dry run", func() {
+		w, response := ts.replayRequest(`{"dry_run": true}`)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.True(response.DryRun)
+		ts.Equal(2, response.Applied)
+		ts.Equal(1, response.Skipped)
+		ts.Zero(response.Failed)
+		ts.Require().Len(response.Results, 3)
+		ts.Equal(models.ReplayValid, response.Results[0].Outcome)
+		ts.Zero(blacklisted)
+	})
+
+	ts.Run("from a sequence", func() {
+		w, response := ts.replayRequest(`{"from": 2}`)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(1, response.Applied)
+		ts.Equal(1, response.Skipped)
+		ts.Require().Len(response.Results, 2)
+		ts.Equal(uint32(3), response.Results[1].Sequence)
+		ts.Equal(models.ReplayApplied, response.Results[1].Outcome)
+		ts.Equal(1, blacklisted)
+	})
+
+	ts.Run("invalid sequence", func() {
+		w, _ := ts.replayRequest(`{"from": -1}`)
+		ts.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	ts.Run("disabled without an auth token", func() {
+		ts.Dependencies.WebServer.AuthToken = ""
+		w, _ := ts.replayRequest(`{}`)
+		ts.Equal(http.StatusForbidden, w.Code)
+		ts.Contains(w.Body.String(), ErrAuthTokenNotConfigured.Error())
+		ts.Equal(1, blacklisted, "nothing was replayed")
+	})
+}
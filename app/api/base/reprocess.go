@@ -0,0 +1,88 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// ReprocessResponse is the response for the reprocess endpoint
+type ReprocessResponse struct {
+	Errors    map[uint32]string `json:"errors"`
+	Failed    int               `json:"failed"`
+	Succeeded int               `json:"succeeded"`
+}
+
+// reprocess will perform the action of the alerts that weren't processed again, or of one of them by its sequence
+//
+// Alerts that succeed are saved as processed, the errors of the ones that failed are returned by sequence
+// An alert processed in dry-run mode can be reprocessed by its sequence, to execute it once dry-run mode is off
+func (a *Action) reprocess(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	alerts, ok := a.alertsToReprocess(w, req)
+	if !ok {
+		return
+	}
+
+	response := ReprocessResponse{Errors: map[uint32]string{}}
+	for _, alert := range alerts {
+		alert.SetOptions(model.WithAllDependencies(a.Config))
+		if err := models.ReprocessAlert(req.Context(), alert, models.AuditSourceAPI); err != nil {
+			a.Config.Services.Log.Errorf("failed to reprocess alert %d: %s", alert.SequenceNumber, err.Error())
+			response.Errors[alert.SequenceNumber] = err.Error()
+			response.Failed++
+			continue
+		}
+		response.Succeeded++
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		response, []string{"errors", "failed", "succeeded"})
+}
+
+// alertsToReprocess will get the alerts that weren't processed, or the one given by the sequence param,
+// writing the error response if they can't be found
+func (a *Action) alertsToReprocess(w http.ResponseWriter, req *http.Request) ([]*models.AlertMessage, bool) {
+	params := apirouter.GetParams(req)
+	found := false
+	if params != nil {
+		_, found = params.Get("sequence")
+	}
+	if !found {
+		alerts, err := models.GetAllUnprocessedAlerts(req.Context(), nil, model.WithAllDependencies(a.Config))
+		if err != nil {
+			app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+			return nil, false
+		}
+		return alerts, true
+	}
+
+	sequence, ok := params.GetUint64Ok("sequence")
+	if !ok || sequence > math.MaxUint32 {
+		app.APIErrorResponse(w, req, http.StatusBadRequest, ErrInvalidSequence)
+		return nil, false
+	}
+	alert, err := models.GetAlertMessageBySequenceNumber(req.Context(), uint32(sequence), model.WithAllDependencies(a.Config))
+	if errors.Is(err, models.ErrAlertNotFound) {
+		app.APIErrorResponse(w, req, http.StatusNotFound, ErrAlertNotFound)
+		return nil, false
+	} else if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return nil, false
+	} else if alert.Processed && !alert.DryRun {
+		app.APIErrorResponse(w, req, http.StatusConflict, ErrAlertProcessed)
+		return nil, false
+	}
+	return []*models.AlertMessage{alert}, true
+}
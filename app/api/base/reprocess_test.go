@@ -0,0 +1,140 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// reprocessRequest will call the reprocess endpoint through the router
+func (ts *TestSuite) reprocessRequest(body, token string) (*httptest.ResponseRecorder, *ReprocessResponse) {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(http.MethodPost, "/alerts/reprocess", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+	response := &ReprocessResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	return w, response
+}
+
+// isProcessed will return whether the stored alert is processed
+func (ts *TestSuite) isProcessed(sequence uint32) bool {
+	alert, err := models.GetAlertMessageBySequenceNumber(context.Background(), sequence, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().NotNil(alert)
+	return alert.Processed
+}
+
+// TestAction_Reprocess will test the method reprocess()
+func (ts *TestSuite) TestAction_Reprocess() {
+	ts.Dependencies.WebServer.AuthToken = testAuthToken
+
+	ts.Run("all unprocessed alerts", func() {
+		ts.saveSignedAlert(1)
+		ts.saveSignedAlert(2)
+
+		w, response := ts.reprocessRequest("", testAuthToken)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(2, response.Succeeded)
+		ts.Equal(0, response.Failed)
+		ts.Empty(response.Errors)
+		ts.True(ts.isProcessed(1))
+		ts.True(ts.isProcessed(2))
+	})
+
+	ts.Run("already processed sequence", func() {
+		w, _ := ts.reprocessRequest(`{"sequence": 1}`, testAuthToken)
+		ts.Equal(http.StatusConflict, w.Code)
+	})
+
+	ts.Run("sequence processed in dry-run mode", func() {
+		alert, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		alert.DryRun = true
+		ts.Require().NoError(alert.Save(context.Background()))
+
+		w, response := ts.reprocessRequest(`{"sequence": 1}`, testAuthToken)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(1, response.Succeeded)
+
+		alert, err = models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.True(alert.Processed)
+		ts.False(alert.DryRun)
+	})
+
+	ts.Run("one sequence", func() {
+		ts.saveSignedAlert(3)
+		ts.saveSignedAlert(4)
+
+		w, response := ts.reprocessRequest(`{"sequence": 4}`, testAuthToken)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(1, response.Succeeded)
+		ts.False(ts.isProcessed(3))
+		ts.True(ts.isProcessed(4))
+	})
+
+	ts.Run("unknown sequence", func() {
+		w, _ := ts.reprocessRequest(`{"sequence": 99}`, testAuthToken)
+		ts.Equal(http.StatusNotFound, w.Code)
+	})
+
+	ts.Run("invalid sequence", func() {
+		w, _ := ts.reprocessRequest(`{"sequence": -1}`, testAuthToken)
+		ts.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	ts.Run("disabled without an auth token", func() {
+		ts.Dependencies.WebServer.AuthToken = ""
+		defer func() {
+			ts.Dependencies.WebServer.AuthToken = testAuthToken
+		}()
+
+		w, _ := ts.reprocessRequest("", "")
+		ts.Equal(http.StatusForbidden, w.Code)
+		ts.Contains(w.Body.String(), ErrAuthTokenNotConfigured.Error())
+		ts.False(ts.isProcessed(3))
+	})
+
+	ts.Run("auth token", func() {
+		w, _ := ts.reprocessRequest("", "")
+		ts.Equal(http.StatusUnauthorized, w.Code)
+
+		w, _ = ts.reprocessRequest("", "wrong")
+		ts.Equal(http.StatusUnauthorized, w.Code)
+
+		w, response := ts.reprocessRequest("", testAuthToken)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(1, response.Succeeded)
+		ts.True(ts.isProcessed(3))
+	})
+
+	ts.Run("alert that doesn't match its stored hash", func() {
+		ts.saveSignedAlert(5)
+		alert, err := models.GetAlertMessageBySequenceNumber(context.Background(), 5, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		alert.Hash = strings.Repeat("ab", 32)
+		ts.Require().NoError(alert.Save(context.Background()))
+
+		w, response := ts.reprocessRequest(`{"sequence": 5}`, testAuthToken)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(1, response.Failed)
+		ts.Contains(response.Errors[5], models.ErrAlertHashMismatch.Error())
+		ts.False(ts.isProcessed(5))
+	})
+}
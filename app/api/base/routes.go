@@ -3,10 +3,12 @@ package base
 import (
 	"net/http"
 
+	"github.com/julienschmidt/httprouter"
 	apirouter "github.com/mrz1836/go-api-router"
 
 	"github.com/bsv-blockchain/go-alert-system/app"
 	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/metrics"
 	"github.com/bsv-blockchain/go-alert-system/app/p2p"
 )
 
@@ -20,11 +22,18 @@ func RegisterRoutes(router *apirouter.Router, conf *config.Config, p2pServ *p2p.
 	// Load the actions and set the services
 	action := &Action{app.Action{Config: conf, P2pServer: p2pServ}}
 
+	// Every endpoint but the health probes requires the auth token, if one is configured
+	// The endpoints that change state are refused until one is
+	if conf.WebServer.AuthToken == "" {
+		conf.Services.Log.Warnf("no web_server.auth_token is set, the API is open to anyone who can reach it and the endpoints that change state are disabled")
+	}
+
 	// Set the main index page (navigating to slash or the root of the major version)
-	router.HTTPRouter.GET("/", action.Request(router, action.index))
+	router.HTTPRouter.GET("/", action.authRequest(router, action.index))
 
-	// Options request (for CORs)
-	router.HTTPRouter.OPTIONS("/", router.SetCrossOriginHeaders)
+	// Options requests (CORS preflight) for every route, the CORS headers are set from the web server config
+	router.CrossOriginEnabled = false
+	router.HTTPRouter.GlobalOPTIONS = http.HandlerFunc(action.preflight)
 
 	// Head requests are sometimes used for CORs
 	router.HTTPRouter.HEAD("/", app.Head)
@@ -36,11 +45,58 @@ func RegisterRoutes(router *apirouter.Router, conf *config.Config, p2pServ *p2p.
 	router.HTTPRouter.MethodNotAllowed = http.HandlerFunc(app.MethodNotAllowed)
 
 	// Set the health request
-	router.HTTPRouter.GET("/health", action.Request(router, action.health))
+	router.HTTPRouter.GET("/health", action.request(router, action.health))
+
+	// Set the liveness and readiness probes
+	router.HTTPRouter.GET("/livez", action.request(router, action.livez))
+	router.HTTPRouter.GET("/readyz", action.request(router, action.readyz))
 
 	// Set the get alerts request
-	router.HTTPRouter.GET("/alerts", action.Request(router, action.alerts))
+	router.HTTPRouter.GET("/alerts", action.authRequest(router, action.alerts))
 
 	// Set the get alert request
-	router.HTTPRouter.GET("/alert/:sequence", action.Request(router, action.alert))
+	router.HTTPRouter.GET("/alert/:sequence", action.authRequest(router, action.alert))
+
+	// Set the get decoded alert request
+	router.HTTPRouter.GET("/alert/:sequence/decoded", action.authRequest(router, action.alertDecoded))
+
+	// Set the get currently frozen UTXOs request
+	router.HTTPRouter.GET("/frozen", action.authRequest(router, action.frozen))
+
+	// Set the get alert stats request
+	router.HTTPRouter.GET("/stats", action.authRequest(router, action.stats))
+
+	// Set the get peers request
+	router.HTTPRouter.GET("/peers", action.authRequest(router, action.peers))
+
+	// Set the alert export request (/alerts/export, the router can't have it beside the :sequence parameter)
+	router.HTTPRouter.GET("/alerts/:sequence", action.authRequest(router, action.alertsAction))
+
+	// Set the get alert signatures request
+	router.HTTPRouter.GET("/alerts/:sequence/signatures", action.authRequest(router, action.signatures))
+
+	// Set the get active signing keys request
+	router.HTTPRouter.GET("/keys", action.authRequest(router, action.keys))
+
+	// Set the get audit log request
+	router.HTTPRouter.GET("/audit", action.authRequest(router, action.audit))
+
+	// Set the submit alert request
+	router.HTTPRouter.POST("/alerts", action.adminRequest(router, action.submit))
+
+	// Set the verify lazily stored alerts request
+	router.HTTPRouter.POST("/verify", action.adminRequest(router, action.verify))
+
+	// Set the reprocess unprocessed alerts request
+	router.HTTPRouter.POST("/alerts/reprocess", action.adminRequest(router, action.reprocess))
+
+	// Set the replay alert history request
+	router.HTTPRouter.POST("/alerts/replay", action.adminRequest(router, action.replay))
+
+	// Serve the Prometheus metrics, if enabled
+	if conf.MetricsEnabled {
+		router.HTTPRouter.GET("/metrics", action.requireToken(func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+			metrics.Handler().ServeHTTP(w, req)
+		}))
+	}
 }
@@ -0,0 +1,72 @@
+package base
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// AlertSignature is a signature on an alert and the public key that made it
+type AlertSignature struct {
+	Signature string `json:"signature"`
+	Signer    string `json:"signer"`
+}
+
+// SignaturesResponse is the response for the alert signatures endpoint
+type SignaturesResponse struct {
+	Sequence   uint32           `json:"sequence"`
+	Signatures []AlertSignature `json:"signatures"`
+}
+
+// signatures will return the signatures of a stored alert and the recovered signer of each
+func (a *Action) signatures(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Read params
+	sequenceNumber, ok := readSequenceParam(w, req)
+	if !ok {
+		return
+	}
+
+	// Get alert
+	alertModel, err := models.GetAlertMessageBySequenceNumber(req.Context(), sequenceNumber, model.WithAllDependencies(a.Config))
+	if errors.Is(err, models.ErrAlertNotFound) {
+		app.APIErrorResponse(w, req, http.StatusNotFound, ErrAlertNotFound)
+		return
+	} else if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+	if err = alertModel.ReadRaw(); err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, fmt.Errorf("%w: %s", ErrAlertMalformed, err.Error()))
+		return
+	}
+
+	// Recover the signer of each signature
+	response := SignaturesResponse{Sequence: alertModel.SequenceNumber, Signatures: []AlertSignature{}}
+	for _, sig := range alertModel.Signatures() {
+		var signer string
+		if signer, err = alertModel.RecoverSigner(sig); err != nil {
+			app.APIErrorResponse(w, req, http.StatusInternalServerError, fmt.Errorf("%w: %s", ErrAlertMalformed, err.Error()))
+			return
+		}
+		response.Signatures = append(response.Signatures, AlertSignature{
+			Signature: hex.EncodeToString(sig),
+			Signer:    signer,
+		})
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		response, []string{"sequence", "signatures"})
+}
@@ -0,0 +1,105 @@
+package base
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/bitcoinschema/go-bitcoin"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// saveSignedAlert will save an informational alert signed by the genesis keys and return its raw bytes
+func (ts *TestSuite) saveSignedAlert(sequence uint32) []byte {
+	a := ts.signedAlert(sequence)
+	raw := a.Serialize()
+	ts.Require().NoError(a.Save(context.Background()))
+	return raw
+}
+
+// signedAlert will create an informational alert signed by the genesis keys, without saving it
+func (ts *TestSuite) signedAlert(sequence uint32) *models.AlertMessage {
+	a := models.NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+	a.SetAlertType(models.AlertTypeInformational)
+	a.SetRawMessage([]byte{0x02, 'h', 'i'})
+	a.SequenceNumber = sequence
+	a.SetTimestamp(1700000000)
+	a.SetVersion(1)
+	a.SerializeData()
+	sigs, err := utils.SignWithGenesis(a.GetRawData())
+	ts.Require().NoError(err)
+	a.SetSignatures(sigs)
+	return a
+}
+
+// signaturesRequest will call the alert signatures endpoint through the router
+func (ts *TestSuite) signaturesRequest(sequence string) (*httptest.ResponseRecorder, *SignaturesResponse) {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(http.MethodGet, "/alerts/"+sequence+"/signatures", nil)
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+
+	response := &SignaturesResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	return w, response
+}
+
+// TestAction_Signatures will test the method signatures()
+func (ts *TestSuite) TestAction_Signatures() {
+	ts.Run("signers are the genesis keys", func() {
+		raw := ts.saveSignedAlert(1)
+
+		w, response := ts.signaturesRequest("1")
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal(uint32(1), response.Sequence)
+		ts.Require().Len(response.Signatures, 3)
+
+		expected := make([]string, 0, 3)
+		for _, key := range []string{utils.Key1, utils.Key2, utils.Key3} {
+			pub, err := bitcoin.PubKeyFromPrivateKeyString(key, true)
+			ts.Require().NoError(err)
+			expected = append(expected, pub)
+		}
+		signers := make([]string, 0, 3)
+		for _, sig := range response.Signatures {
+			signers = append(signers, sig.Signer)
+			ts.Contains(hex.EncodeToString(raw), sig.Signature)
+		}
+		ts.ElementsMatch(expected, signers)
+		ts.Subset(ts.Dependencies.GenesisKeys, signers)
+	})
+
+	ts.Run("malformed signature region", func() {
+		raw := ts.saveSignedAlert(2)
+
+		// Cut the stored alert short in the middle of the signature block
+		alert, err := models.GetAlertMessageBySequenceNumber(context.Background(), 2, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		alert.Raw = hex.EncodeToString(raw[:len(raw)-models.SignatureSize])
+		ts.Require().NoError(alert.Save(context.Background()))
+
+		w, _ := ts.signaturesRequest("2")
+		ts.Equal(http.StatusInternalServerError, w.Code)
+		ts.Contains(w.Body.String(), ErrAlertMalformed.Error())
+	})
+
+	ts.Run("unknown alert", func() {
+		w, _ := ts.signaturesRequest("99")
+		ts.Equal(http.StatusNotFound, w.Code)
+	})
+
+	ts.Run("invalid sequence", func() {
+		w, _ := ts.signaturesRequest("abc")
+		ts.Equal(http.StatusBadRequest, w.Code)
+	})
+}
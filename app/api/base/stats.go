@@ -0,0 +1,29 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// stats will return the counts of the stored alerts, for dashboards that don't need the alerts themselves
+func (a *Action) stats(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	stats, err := models.GetAlertStats(req.Context(), model.WithAllDependencies(a.Config))
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		stats, []string{"by_type", "max_sequence", "min_sequence", "processed", "total", "unprocessed"})
+}
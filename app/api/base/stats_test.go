@@ -0,0 +1,52 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	bnmodels "github.com/bsv-blockchain/go-bn/models"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// TestAction_Stats will test the method stats()
+func (ts *TestSuite) TestAction_Stats() {
+	// statsRequest will call the alert stats endpoint through the router
+	statsRequest := func() *models.AlertStats {
+		router := apirouter.New()
+		RegisterRoutes(router, ts.Dependencies, nil)
+		req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+		w := httptest.NewRecorder()
+		router.HTTPRouter.ServeHTTP(w, req)
+		ts.Require().Equal(http.StatusOK, w.Code)
+
+		response := &models.AlertStats{}
+		ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+		return response
+	}
+
+	ts.Run("no alerts", func() {
+		response := statsRequest()
+		ts.Empty(response.ByType)
+		ts.Zero(response.Total)
+	})
+
+	ts.Run("alert counts", func() {
+		fund := bnmodels.Fund{TxOut: bnmodels.TxOut{TxId: strings.Repeat("a", 64), Vout: 1}, EnforceAtHeight: []bnmodels.Enforce{{Start: 100, Stop: 200}}}
+		ts.saveFundsAlert(2, models.AlertTypeFreezeUtxo, fund)
+		ts.saveFundsAlert(3, models.AlertTypeUnfreezeUtxo, fund)
+
+		response := statsRequest()
+		ts.Equal(map[string]int64{
+			models.AlertTypeFreezeUtxo.String():   1,
+			models.AlertTypeUnfreezeUtxo.String(): 1,
+		}, response.ByType)
+		ts.Equal(int64(2), response.Total)
+		ts.Equal(int64(2), response.Unprocessed)
+		ts.Equal(uint32(2), response.MinSequence)
+		ts.Equal(uint32(3), response.MaxSequence)
+	})
+}
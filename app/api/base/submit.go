@@ -0,0 +1,80 @@
+package base
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// SubmitResponse is the response for the submit endpoint
+type SubmitResponse struct {
+	Error     string `json:"error,omitempty"` // Why the stored alert wasn't executed (or failed), empty if it was
+	Hash      string `json:"hash"`
+	Processed bool   `json:"processed"`
+	Sequence  uint32 `json:"sequence"`
+}
+
+// submit will verify, store and execute a signed alert given (hex encoded) by the raw param (see models.SubmitAlert)
+// and publish it on the topic, the same as an alert received from a peer is relayed
+//
+// The operator is trusted, so the alert is executed however many peers are connected, unless
+// p2p.min_alert_peers_for_api is set. An alert stored without being processed returns 202 Accepted,
+// the alert processing executes it later
+func (a *Action) submit(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	params := apirouter.GetParams(req)
+	var raw []byte
+	var err error
+	if params == nil {
+		app.APIErrorResponse(w, req, http.StatusBadRequest, ErrInvalidAlertRaw)
+		return
+	} else if raw, err = hex.DecodeString(params.GetString("raw")); err != nil || len(raw) == 0 {
+		app.APIErrorResponse(w, req, http.StatusBadRequest, ErrInvalidAlertRaw)
+		return
+	}
+
+	execute := !a.Config.P2P.MinAlertPeersForAPI || a.Config.P2P.MinAlertPeers <= 0 ||
+		(a.P2pServer != nil && a.P2pServer.HasAlertPeers())
+	alert, err := models.SubmitAlert(req.Context(), raw, execute, model.WithAllDependencies(a.Config))
+	if alert == nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, models.ErrAlertSequenceExists) || errors.Is(err, models.ErrAlertSequenceGap) {
+			status = http.StatusConflict
+		}
+		app.APIErrorResponse(w, req, status, err)
+		return
+	}
+
+	response := SubmitResponse{Hash: alert.Hash, Processed: alert.Processed, Sequence: alert.SequenceNumber}
+	if err != nil {
+		a.Config.Services.Log.Errorf("failed to execute submitted alert %d: %s", alert.SequenceNumber, err.Error())
+		response.Error = err.Error()
+	} else if !execute {
+		response.Error = ErrAlertDeferred.Error()
+	}
+	status := http.StatusOK
+	if !alert.Processed {
+		status = http.StatusAccepted
+	}
+
+	// The alert is stored, a peer that misses it on the topic still gets it when it syncs from this node
+	if a.P2pServer != nil {
+		if publishErr := a.P2pServer.PublishAlert(req.Context(), raw); publishErr != nil {
+			a.Config.Services.Log.Errorf("failed to publish submitted alert %d: %s", alert.SequenceNumber, publishErr.Error())
+		}
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		status,
+		json.NewEncoder(w),
+		response, []string{"error", "hash", "processed", "sequence"})
+}
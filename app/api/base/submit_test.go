@@ -0,0 +1,112 @@
+package base
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// submitRequest will call the submit endpoint through the router
+func (ts *TestSuite) submitRequest(raw, token string) (*httptest.ResponseRecorder, *SubmitResponse) {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(`{"raw": "`+raw+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	if w.Code != http.StatusOK && w.Code != http.StatusAccepted {
+		return w, nil
+	}
+	response := &SubmitResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	return w, response
+}
+
+// TestAction_Submit will test the method submit()
+func (ts *TestSuite) TestAction_Submit() {
+	ts.Dependencies.WebServer.AuthToken = testAuthToken
+	ts.Dependencies.P2P.MinAlertPeers = 2
+	ts.Require().NoError(models.CreateGenesisAlert(context.Background(), model.WithAllDependencies(ts.Dependencies)))
+
+	ts.Run("disabled without an auth token", func() {
+		ts.Dependencies.WebServer.AuthToken = ""
+		defer func() { ts.Dependencies.WebServer.AuthToken = testAuthToken }()
+
+		w, _ := ts.submitRequest(hex.EncodeToString(ts.signedAlert(1).Serialize()), testAuthToken)
+		ts.Equal(http.StatusForbidden, w.Code)
+	})
+
+	ts.Run("missing auth token", func() {
+		w, _ := ts.submitRequest(hex.EncodeToString(ts.signedAlert(1).Serialize()), "")
+		ts.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	ts.Run("executed with no peers", func() {
+		alert := ts.signedAlert(1)
+		w, response := ts.submitRequest(hex.EncodeToString(alert.Serialize()), testAuthToken)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.True(response.Processed)
+		ts.Equal(uint32(1), response.Sequence)
+		ts.Equal(alert.Hash, response.Hash)
+		ts.Empty(response.Error)
+		ts.True(ts.isProcessed(1))
+	})
+
+	ts.Run("already stored sequence", func() {
+		w, _ := ts.submitRequest(hex.EncodeToString(ts.signedAlert(1).Serialize()), testAuthToken)
+		ts.Equal(http.StatusConflict, w.Code)
+	})
+
+	ts.Run("sequence after a gap", func() {
+		w, _ := ts.submitRequest(hex.EncodeToString(ts.signedAlert(3).Serialize()), testAuthToken)
+		ts.Equal(http.StatusConflict, w.Code)
+	})
+
+	ts.Run("genesis sequence", func() {
+		w, _ := ts.submitRequest(hex.EncodeToString(ts.signedAlert(0).Serialize()), testAuthToken)
+		ts.Equal(http.StatusConflict, w.Code)
+	})
+
+	ts.Run("invalid signatures", func() {
+		raw := ts.signedAlert(2).Serialize()
+		raw[len(raw)-1] ^= 0xff
+		w, _ := ts.submitRequest(hex.EncodeToString(raw), testAuthToken)
+		ts.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	ts.Run("invalid hex", func() {
+		w, _ := ts.submitRequest("not-hex", testAuthToken)
+		ts.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	ts.Run("deferred when the peer count applies to the API", func() {
+		ts.Dependencies.P2P.MinAlertPeersForAPI = true
+		defer func() { ts.Dependencies.P2P.MinAlertPeersForAPI = false }()
+
+		w, response := ts.submitRequest(hex.EncodeToString(ts.signedAlert(2).Serialize()), testAuthToken)
+		ts.Require().Equal(http.StatusAccepted, w.Code)
+		ts.False(response.Processed)
+		ts.Equal(ErrAlertDeferred.Error(), response.Error)
+		ts.False(ts.isProcessed(2))
+	})
+
+	ts.Run("sequence after a dropped alert", func() {
+		ts.Require().NoError(models.RecordDroppedAlert(context.Background(), ts.signedAlert(3), model.WithAllDependencies(ts.Dependencies)))
+
+		w, response := ts.submitRequest(hex.EncodeToString(ts.signedAlert(4).Serialize()), testAuthToken)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.True(response.Processed)
+		ts.True(ts.isProcessed(4))
+	})
+}
@@ -0,0 +1,70 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// VerifyResponse is the response for the verify endpoint
+type VerifyResponse struct {
+	Invalid  []uint32 `json:"invalid"`
+	Pending  []uint32 `json:"pending"`
+	Verified []uint32 `json:"verified"`
+}
+
+// verify will check the signatures of the alerts stored unverified by a lazy sync
+//
+// Alerts with valid signatures have their flag cleared, invalid ones stay flagged and are never executed
+// Alerts after an unprocessed set keys alert are left pending, they may be signed by the keys it sets
+func (a *Action) verify(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	alerts, err := models.GetAllUnverifiedAlerts(req.Context(), nil, model.WithAllDependencies(a.Config))
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := VerifyResponse{Invalid: []uint32{}, Pending: []uint32{}, Verified: []uint32{}}
+	keysPending := false
+	for _, alert := range alerts {
+		if keysPending {
+			response.Pending = append(response.Pending, alert.SequenceNumber)
+			continue
+		}
+		alert.SetOptions(model.WithAllDependencies(a.Config))
+		if err = alert.ReadRaw(); err != nil {
+			app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+			return
+		}
+		if err = alert.VerifyIfUnverified(req.Context()); errors.Is(err, models.ErrAlertNotVerified) {
+			response.Invalid = append(response.Invalid, alert.SequenceNumber)
+			continue
+		} else if err != nil {
+			app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+			return
+		}
+		if err = alert.Save(req.Context()); err != nil {
+			app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+			return
+		}
+		if err = models.MarkSupersededAlert(req.Context(), alert); err != nil {
+			a.Config.Services.Log.Errorf("failed to mark alert %d as superseded by %d: %s", alert.Supersedes, alert.SequenceNumber, err.Error())
+		}
+		response.Verified = append(response.Verified, alert.SequenceNumber)
+		keysPending = alert.GetAlertType() == models.AlertTypeSetKeys && !alert.Processed
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		response, []string{"invalid", "pending", "verified"})
+}
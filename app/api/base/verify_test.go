@@ -0,0 +1,56 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	apirouter "github.com/mrz1836/go-api-router"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// verifyRequest will call the verify endpoint through the router with the method
+func (ts *TestSuite) verifyRequest(method string) (*httptest.ResponseRecorder, *VerifyResponse) {
+	router := apirouter.New()
+	RegisterRoutes(router, ts.Dependencies, nil)
+	req := httptest.NewRequest(method, "/verify", nil)
+	req.Header.Set(APIKeyHeader, testAuthToken)
+	w := httptest.NewRecorder()
+	router.HTTPRouter.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+	response := &VerifyResponse{}
+	ts.Require().NoError(json.Unmarshal(w.Body.Bytes(), response))
+	return w, response
+}
+
+// TestAction_Verify will test the method verify()
+func (ts *TestSuite) TestAction_Verify() {
+	ts.Dependencies.WebServer.AuthToken = testAuthToken
+	ts.Require().NoError(models.CreateGenesisAlert(context.Background(), model.WithAllDependencies(ts.Dependencies)))
+	ts.saveSignedAlert(1)
+	alert, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	alert.Unverified = true
+	ts.Require().NoError(alert.Save(context.Background()))
+
+	ts.Run("a GET changes nothing", func() {
+		w, _ := ts.verifyRequest(http.MethodGet)
+		ts.Equal(http.StatusMethodNotAllowed, w.Code)
+	})
+
+	ts.Run("verifies the unverified alerts", func() {
+		w, response := ts.verifyRequest(http.MethodPost)
+		ts.Require().Equal(http.StatusOK, w.Code)
+		ts.Equal([]uint32{1}, response.Verified)
+		ts.Empty(response.Invalid)
+
+		alert, err = models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.False(alert.Unverified)
+	})
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AlertTypeNames are the machine readable names of the known alert types, by type number
+// The config refers to alert types by these names or by number, see models.AlertType
+var AlertTypeNames = map[uint32]string{
+	0x01: "informational",
+	0x02: "freeze_utxo",
+	0x03: "unfreeze_utxo",
+	0x04: "confiscate_utxo",
+	0x05: "ban_peer",
+	0x06: "unban_peer",
+	0x07: "invalidate_block",
+	0x08: "set_keys",
+	0x63: "special",
+}
+
+// ParseAlertType will parse an alert type number from its name, its unknown(100) form or its number
+func ParseAlertType(s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	for alertType, name := range AlertTypeNames {
+		if strings.EqualFold(s, name) {
+			return alertType, nil
+		}
+	}
+	number := s
+	if strings.HasPrefix(s, "unknown(") && strings.HasSuffix(s, ")") {
+		number = strings.TrimSuffix(strings.TrimPrefix(s, "unknown("), ")")
+	}
+	t, err := strconv.ParseUint(number, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownAlertTypeName, s)
+	}
+	return uint32(t), nil
+}
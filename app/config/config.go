@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/mrz1836/go-datastore"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:embed envs
@@ -37,6 +38,41 @@ var (
 	}
 )
 
+// Policies for handling an alert when the node RPC is unavailable
+const (
+	NodeUnavailableDefer   = "defer"   // Leave the alert pending so it's retried once the node is back
+	NodeUnavailableProcess = "process" // Process the alert anyway, for alert types that don't need the node
+)
+
+// Encodings for informational messages that aren't valid UTF-8 when they are marshaled to JSON
+const (
+	InfoMessageEncodingBase64 = "base64" // Base64 encode the message and mark it with an encoding field
+	InfoMessageEncodingStrict = "strict" // Refuse to marshal the message
+)
+
+// Formats of the webhook request body
+const (
+	WebhookFormatRaw   = "raw"   // The alert payload JSON
+	WebhookFormatSlack = "slack" // A Slack message, with the alert type as the header and the message as the body
+)
+
+// Formats of the log lines
+const (
+	LogFormatJSON = "json" // A JSON object per line, with any structured fields alongside the message
+	LogFormatText = "text" // Free text lines
+)
+
+// Networks of the bitcoin.conf sections ([main] or a main. key prefix)
+const (
+	BitcoinNetworkMain    = "main"    // Mainnet
+	BitcoinNetworkRegtest = "regtest" // Regression test network
+	BitcoinNetworkSTN     = "stn"     // Scaling test network
+	BitcoinNetworkTest    = "test"    // Testnet
+)
+
+// alertTypeSetKeys is the set keys alert type, which can't be disabled since it rotates the keys every alert is checked against
+const alertTypeSetKeys = 0x08
+
 // Application configuration constants
 var (
 	ApplicationName                = "alert_system"                // Application name used in places where we need an application name space
@@ -46,40 +82,123 @@ var (
 	DefaultServerShutdown          = 5 * time.Second               // Default server shutdown delay time (to finish any requests or internal processes)
 	DefaultPeerDiscoveryInterval   = 10 * time.Minute              // Default peer discovery refresh interval
 	DefaultAlertProcessingInterval = 5 * time.Minute               // Default alert processing retry interval
+	DefaultMaxInFlightSyncRequests = 10                            // Default maximum number of outstanding sync requests per peer
+	DefaultSyncRequestTimeout      = 30 * time.Second              // Default time to wait for a peer to answer a sync request
+	DefaultMaxSyncRequestRetries   = 3                             // Default number of times an unanswered sync request is retried before being abandoned
+	DefaultMaxClockSkew            = 5 * time.Minute               // Default difference allowed between a peer's clock and ours
+	DefaultDialBackoffInitial      = time.Second                   // Default wait before redialing a peer after the first failed connection
+	DefaultDialBackoffMax          = 10 * time.Minute              // Default maximum wait between redialing a peer that keeps failing
+	DefaultPeerScoreThreshold      = 100.0                         // Default misbehavior score at which a sync peer is disconnected and banned
+	DefaultPeerScoreDecay          = 10.0                          // Default number of misbehavior points a peer sheds per minute
+	DefaultPeerBanDuration         = 30 * time.Minute              // Default time a misbehaving peer is refused connections
+	DefaultMaxRequestsPerMinute    = 120                           // Default number of sync requests a peer may send per minute before being penalized
+	DefaultSyncStalenessWindow     = 30 * time.Minute              // Default time the latest sequence advertised by a peer counts towards whether we are synced
+	DefaultBackfillInterval        = 5 * time.Minute               // Default interval between requesting the missing sequences from peers
+	DefaultMaxBackfillRequests     = 10                            // Default number of missing sequences requested from peers in each backfill run
+	DefaultAlertWorkers            = 4                             // Default number of workers processing the alerts received on the topics
+	DefaultAlertQueueSize          = 100                           // Default number of received alerts that can wait for a worker
+	DefaultSeenAlertCacheSize      = 1000                          // Default number of received alert hashes remembered to drop duplicate copies
+	DefaultStaticPeerInterval      = 30 * time.Second              // Default interval between checking the connections to the static peers
+	DefaultMaxInboundPeers         = 64                            // Default number of peers that may connect to us
+	DefaultMaxOutboundPeers        = 32                            // Default number of peers we dial
+	DefaultGossipFanout            = 6                             // Default number of peers a new alert is pushed to (the gossipsub mesh size)
+	MinGossipFanout                = 2                             // Fewest peers a new alert can be pushed to, gossipsub needs a mesh of at least two
+	DefaultNodeHeightCacheTTL      = time.Minute                   // Default time a node block height is cached before asking the node again
+	DefaultNodeHealthTTL           = 10 * time.Second              // Default time the result of pinging the node RPC is cached for the health checks
+	DefaultNodeRPCTimeout          = 30 * time.Second              // Default time an alert action waits for a node RPC call
+	DefaultNodeRPCMaxRetries       = 3                             // Default number of times a node RPC call that failed to reach the node is retried
+	DefaultNodeRPCBaseBackoff      = time.Second                   // Default wait before the first node RPC retry (doubled for each retry after it)
+	DefaultMaxBlocksInPast         = uint32(1008)                  // Default number of blocks an enforce height may be below the node height (~1 week)
+	DefaultMaxBlocksInFuture       = uint32(52560)                 // Default number of blocks an enforce height may be above the node height (~1 year)
+	DefaultDatastoreQueryTimeout   = 20 * time.Second              // Default time a datastore query or save may take before it's abandoned
+	DefaultMaxAlertSize            = 1 << 20                       // Default maximum size in bytes of a whole alert (header, signatures and message)
+	DefaultMaxFreezeFunds          = 10000                         // Default maximum number of funds a single freeze alert may send to the node
+	DefaultMaxInfoMessageLength    = 4096                          // Default maximum length in bytes of an informational alert message
+	DefaultWebhookDedupWindow      = time.Hour                     // Default time after the webhook for an alert is sent that it won't be sent again
+	DefaultSignatureThreshold      = 3                             // Default number of valid signatures from distinct active keys needed to accept an alert
+	DefaultWebhookMaxRetries       = 3                             // Default number of times a failed webhook delivery is retried
+	DefaultWebhookBaseBackoff      = time.Second                   // Default wait before the first webhook retry (doubled for each retry after it)
 	LocalPrivateKeyDefault         = "alert_system_private_key"    // Default local private key
 	LocalPrivateKeyDirectory       = ".bitcoin"                    // Default local private key directory
 )
 
+// Default CORS settings, for the web server CORS config that doesn't set them
+var (
+	DefaultCORSAllowedHeaders = []string{"Authorization", "Content-Type", "X-API-Key"} // Default request headers a cross-origin request may send
+	DefaultCORSAllowedMethods = []string{http.MethodGet, http.MethodPost}              // Default methods a cross-origin request may use
+)
+
 // The global configuration settings
 type (
 
 	// Config is the global configuration settings
 	Config struct {
-		AlertWebhookURL         string          `json:"alert_webhook_url" mapstructure:"alert_webhook_url"`                 // AlertWebhookURL is the URL for the alert webhook
-		GenesisKeys             []string        `json:"genesis_keys" mapstructure:"genesis_keys"`                           // GenesisKeys is a list of public keys to use for the genesis alert
-		Datastore               DatastoreConfig `json:"datastore" mapstructure:"datastore"`                                 // Datastore's configuration
-		DisableRPCVerification  bool            `json:"disable_rpc_verification" mapstructure:"disable_rpc_verification"`   // DisableRPCVerification will disable the rpc verification check on startup. Useful if bitcoind isn't running yet
-		LogOutputFile           string          `json:"log_output_file" mapstructure:"log_output_file"`                     // LogOutputFile will set an output file for the logger to write to as opposed to stdout
-		LogLevel                string          `json:"log_level" mapstructure:"log_level"`                                 // LogLevel sets the logging level
-		BitcoinConfigPath       string          `json:"bitcoin_config_path" mapstructure:"bitcoin_config_path"`             // BitcoinConfigPath is the path to the bitcoin.conf file
-		P2P                     P2PConfig       `json:"p2p" mapstructure:"p2p"`                                             // P2P is the configuration for the P2P server
-		RPCConnections          []RPCConfig     `json:"rpc_connections" mapstructure:"rpc_connections"`                     // RPCConnections is a list of RPC connections
-		RequestLogging          bool            `json:"request_logging" mapstructure:"request_logging"`                     // Toggle for verbose request logging (API requests)
-		Services                Services        `json:"-" mapstructure:"services"`                                          // Services is the global services
-		WebServer               WebServerConfig `json:"web_server" mapstructure:"web_server"`                               // WebServer is the configuration for the web HTTP Server
-		AlertProcessingInterval time.Duration   `json:"alert_processing_interval" mapstructure:"alert_processing_interval"` // AlertProcessingInterval is the interval in which the system will go through all the saved alerts and attempt to retry any unprocessed alerts
+		AlertWebhookURL         string            `json:"alert_webhook_url" mapstructure:"alert_webhook_url"`                 // AlertWebhookURL is the URL for the alert webhook
+		ConfiscationHeightCheck HeightCheckConfig `json:"confiscation_height_check" mapstructure:"confiscation_height_check"` // ConfiscationHeightCheck is the sanity check of confiscation enforce heights against the node height
+		GenesisKeys             []string          `json:"genesis_keys" mapstructure:"genesis_keys"`                           // GenesisKeys is a list of public keys to use for the genesis alert
+		GenesisKeysFile         string            `json:"genesis_keys_file" mapstructure:"genesis_keys_file"`                 // GenesisKeysFile is a file of more genesis keys, one hex public key per line (# starts a comment)
+		InfoMessageEncoding     string            `json:"info_message_encoding" mapstructure:"info_message_encoding"`         // InfoMessageEncoding is how informational messages that aren't valid UTF-8 are written to JSON (base64 or strict)
+		Datastore               DatastoreConfig   `json:"datastore" mapstructure:"datastore"`                                 // Datastore's configuration
+		DryRun                  bool              `json:"dry_run" mapstructure:"dry_run"`                                     // DryRun validates and stores alerts but skips the node RPC calls of their actions
+		DisableRPCVerification  bool              `json:"disable_rpc_verification" mapstructure:"disable_rpc_verification"`   // DisableRPCVerification will disable the rpc verification check on startup. Useful if bitcoind isn't running yet
+		DisabledAlertTypes      []uint32          `json:"disabled_alert_types" mapstructure:"disabled_alert_types"`           // DisabledAlertTypes are alert types dropped when they are received (not stored, relayed or executed)
+		ExecutedAlertTypes      []string          `json:"executed_alert_types" mapstructure:"executed_alert_types"`           // ExecutedAlertTypes are the only alert types executed (names or numbers), empty executes every type. Set keys is always executed
+		LogOutputFile           string            `json:"log_output_file" mapstructure:"log_output_file"`                     // LogOutputFile will set an output file for the logger to write to as opposed to stdout
+		LogFormat               string            `json:"log_format" mapstructure:"log_format"`                               // LogFormat is how log lines are written (text or json)
+		LogLevel                string            `json:"log_level" mapstructure:"log_level"`                                 // LogLevel sets the logging level
+		MaxAlertSize            int               `json:"max_alert_size" mapstructure:"max_alert_size"`                       // MaxAlertSize is the largest whole alert in bytes, larger alerts are rejected before their message is read
+		MaxAlertTimestampSkew   time.Duration     `json:"max_alert_timestamp_skew" mapstructure:"max_alert_timestamp_skew"`   // MaxAlertTimestampSkew is how far in the future an alert's timestamp may be when it's read, 0 disables the check so historical alerts can be replayed
+		MaxFreezeFunds          int               `json:"max_freeze_funds" mapstructure:"max_freeze_funds"`                   // MaxFreezeFunds is the most funds a single freeze alert may send to the node, across all of its RPC calls
+		MaxInfoMessageLength    int               `json:"max_info_message_length" mapstructure:"max_info_message_length"`     // MaxInfoMessageLength is the longest informational alert message in bytes, longer alerts are rejected when read
+		MetricsEnabled          bool              `json:"metrics_enabled" mapstructure:"metrics_enabled"`                     // MetricsEnabled serves the Prometheus metrics on /metrics
+		BitcoinConfigPath       string            `json:"bitcoin_config_path" mapstructure:"bitcoin_config_path"`             // BitcoinConfigPath is the path to the bitcoin.conf file
+		BitcoinNetwork          string            `json:"bitcoin_network" mapstructure:"bitcoin_network"`                     // BitcoinNetwork selects the bitcoin.conf section read on top of the global section (main, test, regtest or stn)
+		NodeHealthTTL           time.Duration     `json:"node_health_ttl" mapstructure:"node_health_ttl"`                     // NodeHealthTTL is how long the result of pinging the node RPC is cached for the health and readiness checks
+		NodeRPCBaseBackoff      time.Duration     `json:"node_rpc_base_backoff" mapstructure:"node_rpc_base_backoff"`         // NodeRPCBaseBackoff is the wait before the first node RPC retry, doubled for each retry after it
+		NodeRPCMaxRetries       int               `json:"node_rpc_max_retries" mapstructure:"node_rpc_max_retries"`           // NodeRPCMaxRetries is how many times a node RPC call that failed with a connection error or timeout is retried
+		NodeRPCTimeout          time.Duration     `json:"node_rpc_timeout" mapstructure:"node_rpc_timeout"`                   // NodeRPCTimeout is how long an alert action waits for the node before giving up on its RPC call
+		NodeUnavailablePolicies map[string]string `json:"node_unavailable_policies" mapstructure:"node_unavailable_policies"` // NodeUnavailablePolicies overrides the per alert type policy (keyed by type name or number) when the node RPC is unavailable
+		P2P                     P2PConfig         `json:"p2p" mapstructure:"p2p"`                                             // P2P is the configuration for the P2P server
+		RPCConnections          []RPCConfig       `json:"rpc_connections" mapstructure:"rpc_connections"`                     // RPCConnections is a list of RPC connections
+		RequestLogging          bool              `json:"request_logging" mapstructure:"request_logging"`                     // Toggle for verbose request logging (API requests)
+		Services                Services          `json:"-" mapstructure:"services"`                                          // Services is the global services
+		ShutdownTimeout         time.Duration     `json:"shutdown_timeout" mapstructure:"shutdown_timeout"`                   // ShutdownTimeout is how long shutdown waits for in-flight alert actions and webhook deliveries before abandoning them
+		SuppressedAlertTypes    []string          `json:"suppressed_alert_types" mapstructure:"suppressed_alert_types"`       // SuppressedAlertTypes are alert types stored and relayed but never executed (names or numbers)
+		SignatureThreshold      int               `json:"signature_threshold" mapstructure:"signature_threshold"`             // SignatureThreshold is how many valid signatures from distinct active keys an alert needs (M of N), legacy alerts need at most their 3
+		WebServer               WebServerConfig   `json:"web_server" mapstructure:"web_server"`                               // WebServer is the configuration for the web HTTP Server
+		Webhook                 WebhookConfig     `json:"webhook" mapstructure:"webhook"`                                     // Webhook is the delivery configuration for the alert webhook
+		WebhookDedupWindow      time.Duration     `json:"webhook_dedup_window" mapstructure:"webhook_dedup_window"`           // WebhookDedupWindow is how long after the webhook for an alert is sent that it won't be sent again, however many times the alert is processed
+		AlertProcessingInterval time.Duration     `json:"alert_processing_interval" mapstructure:"alert_processing_interval"` // AlertProcessingInterval is the interval in which the system will go through all the saved alerts and attempt to retry any unprocessed alerts
 	}
 
 	// DatastoreConfig is the configuration for the datastore
 	DatastoreConfig struct {
-		AutoMigrate bool                    `json:"auto_migrate" mapstructure:"auto_migrate"` // Loads a blank database
-		Debug       bool                    `json:"debug" mapstructure:"debug"`               // True for SQL statements
-		Engine      datastore.Engine        `json:"engine" mapstructure:"engine"`             // MySQL, Postgres, SQLite
-		Password    string                  `json:"password" mapstructure:"password"`
-		SQLite      *datastore.SQLiteConfig `json:"sqlite" mapstructure:"sqlite"`             // Configuration for SQLite
-		SQLRead     *datastore.SQLConfig    `json:"sql_read" mapstructure:"sql_read"`         // Configuration for MySQL or Postgres
-		SQLWrite    *datastore.SQLConfig    `json:"sql_write" mapstructure:"sql_write"`       // Configuration for MySQL or Postgres
-		TablePrefix string                  `json:"table_prefix" mapstructure:"table_prefix"` // pre_table_name (pre)
+		AutoMigrate     bool                    `json:"auto_migrate" mapstructure:"auto_migrate"`           // Loads a blank database
+		Debug           bool                    `json:"debug" mapstructure:"debug"`                         // True for SQL statements
+		Engine          datastore.Engine        `json:"engine" mapstructure:"engine"`                       // MySQL, Postgres, SQLite
+		MigrationDryRun bool                    `json:"migration_dry_run" mapstructure:"migration_dry_run"` // MigrationDryRun logs the statements the auto migration would run, without running them
+		Password        string                  `json:"password" mapstructure:"password"`
+		SQLite          *datastore.SQLiteConfig `json:"sqlite" mapstructure:"sqlite"`               // Configuration for SQLite
+		QueryTimeout    time.Duration           `json:"query_timeout" mapstructure:"query_timeout"` // QueryTimeout is how long a model query or save may take, so a stuck query can't hold a goroutine forever
+		SQLRead         *datastore.SQLConfig    `json:"sql_read" mapstructure:"sql_read"`           // Configuration for MySQL or Postgres, including its connection pool (max_open_connections, max_idle_connections, max_connection_time)
+		SQLWrite        *datastore.SQLConfig    `json:"sql_write" mapstructure:"sql_write"`         // Configuration for MySQL or Postgres, including its connection pool (max_open_connections, max_idle_connections, max_connection_time)
+		TablePrefix     string                  `json:"table_prefix" mapstructure:"table_prefix"`   // pre_table_name (pre)
+	}
+
+	// WebhookConfig is the configuration for delivering the alert webhook
+	WebhookConfig struct {
+		BaseBackoff time.Duration `json:"base_backoff" mapstructure:"base_backoff"` // BaseBackoff is the wait before the first retry, doubled (with jitter) for each retry after it
+		Format      string        `json:"format" mapstructure:"format"`             // Format is the format of the request body (raw or slack)
+		MaxRetries  int           `json:"max_retries" mapstructure:"max_retries"`   // MaxRetries is how many times a delivery that failed with a 5xx or network error is retried
+		Secret      string        `json:"secret" mapstructure:"secret"`             // Secret is the shared secret the payloads are signed with (HMAC-SHA256), unsigned if empty
+	}
+
+	// HeightCheckConfig is the configuration for checking a block height is plausible relative to the node height
+	HeightCheckConfig struct {
+		Enabled           bool          `json:"enabled" mapstructure:"enabled"`                           // Enabled turns on the check (off by default for backward compatibility)
+		MaxBlocksInPast   uint32        `json:"max_blocks_in_past" mapstructure:"max_blocks_in_past"`     // MaxBlocksInPast is how far below the node height a height may be
+		MaxBlocksInFuture uint32        `json:"max_blocks_in_future" mapstructure:"max_blocks_in_future"` // MaxBlocksInFuture is how far above the node height a height may be
+		NodeHeightTTL     time.Duration `json:"node_height_ttl" mapstructure:"node_height_ttl"`           // NodeHeightTTL is how long the node height is cached
 	}
 
 	// HTTPInterface is used for the HTTP client
@@ -96,17 +215,46 @@ type (
 
 	// P2PConfig is the configuration for the P2P server and connection
 	P2PConfig struct {
-		AlertSystemProtocolID string        `json:"alert_system_protocol_id" mapstructure:"alert_system_protocol_id"` // AlertSystemProtocolID is the protocol ID to use on the libp2p network for alert system communication
-		DHTMode               string        `json:"dht_mode"`
-		BootstrapPeer         string        `json:"bootstrap_peer" mapstructure:"bootstrap_peer"`                         // BootstrapPeer is the bootstrap peer for the libp2p network
-		BroadcastIP           string        `json:"broadcast_ip" mapstructure:"broadcast_ip"`                             // BroadcastIP is the public facing IP address to broadcast to other peers
-		IP                    string        `json:"ip" mapstructure:"ip"`                                                 // IP is the IP address for the P2P server
-		Port                  string        `json:"port" mapstructure:"port"`                                             // Port is the port for the P2P server
-		AllowPrivateIPs       bool          `json:"allow_private_ip_addresses" mapstructure:"allow_private_ip_addresses"` // AllowPrivateIPs will disable the default behavior of filtering out private IP addresses
-		PrivateKeyPath        string        `json:"private_key_path" mapstructure:"private_key_path"`                     // PrivateKeyPath is the path to the private key
-		PrivateKey            string        `json:"private_key" mapstructure:"private_key"`
-		TopicName             string        `json:"topic_name" mapstructure:"topic_name"`                           // TopicName is the name of the topic to subscribe to
-		PeerDiscoveryInterval time.Duration `json:"peer_discovery_interval" mapstructure:"peer_discovery_interval"` // PeerDiscoveryInterval is the interval in which we will refresh the peer table and check peers for missing messages
+		AlertSystemProtocolID   string        `json:"alert_system_protocol_id" mapstructure:"alert_system_protocol_id"` // AlertSystemProtocolID is the protocol ID to use on the libp2p network for alert system communication
+		DHTMode                 string        `json:"dht_mode"`
+		BootstrapPeer           string        `json:"bootstrap_peer" mapstructure:"bootstrap_peer"`                         // BootstrapPeer is the bootstrap peer for the libp2p network
+		BroadcastIP             string        `json:"broadcast_ip" mapstructure:"broadcast_ip"`                             // BroadcastIP is the public facing IP address to broadcast to other peers
+		BroadcastPort           string        `json:"broadcast_port" mapstructure:"broadcast_port"`                         // BroadcastPort is the public facing port to broadcast to other peers, defaults to Port
+		IP                      string        `json:"ip" mapstructure:"ip"`                                                 // IP is the IP address of the interface the P2P server binds to (0.0.0.0 or :: for all)
+		Port                    string        `json:"port" mapstructure:"port"`                                             // Port is the port the P2P server binds to
+		AllowPrivateIPs         bool          `json:"allow_private_ip_addresses" mapstructure:"allow_private_ip_addresses"` // AllowPrivateIPs will disable the default behavior of filtering out private IP addresses
+		PrivateKeyPath          string        `json:"private_key_path" mapstructure:"private_key_path"`                     // PrivateKeyPath is the path to the private key
+		PrivateKey              string        `json:"private_key" mapstructure:"private_key"`
+		TopicName               string        `json:"topic_name" mapstructure:"topic_name"`                                   // TopicName is the name of the topic to subscribe to
+		PeerDiscoveryInterval   time.Duration `json:"peer_discovery_interval" mapstructure:"peer_discovery_interval"`         // PeerDiscoveryInterval is the interval in which we will refresh the peer table and check peers for missing messages
+		MaxInFlightSyncRequests int           `json:"max_in_flight_sync_requests" mapstructure:"max_in_flight_sync_requests"` // MaxInFlightSyncRequests is the maximum number of outstanding sync requests tracked per peer
+		SyncRequestTimeout      time.Duration `json:"sync_request_timeout" mapstructure:"sync_request_timeout"`               // SyncRequestTimeout is how long an outstanding sync request is tracked before it is considered timed out
+		MaxSyncRequestRetries   int           `json:"max_sync_request_retries" mapstructure:"max_sync_request_retries"`       // MaxSyncRequestRetries is how many times a timed out sync request is retried before it is abandoned
+		StrictSyncMessageTypes  bool          `json:"strict_sync_message_types" mapstructure:"strict_sync_message_types"`     // StrictSyncMessageTypes rejects alerts of unknown types instead of relaying them unparsed
+		LazySyncVerification    bool          `json:"lazy_sync_verification" mapstructure:"lazy_sync_verification"`           // LazySyncVerification stores synced alerts unverified and checks the signatures before they are executed
+		MaxClockSkew            time.Duration `json:"max_clock_skew" mapstructure:"max_clock_skew"`                           // MaxClockSkew is how far a peer's clock may differ from ours before it's reported
+		DisconnectOnClockSkew   bool          `json:"disconnect_on_clock_skew" mapstructure:"disconnect_on_clock_skew"`       // DisconnectOnClockSkew disconnects peers beyond MaxClockSkew instead of only warning
+		DialBackoffInitial      time.Duration `json:"dial_backoff_initial" mapstructure:"dial_backoff_initial"`               // DialBackoffInitial is how long to wait before redialing a peer after its first failed connection
+		DialBackoffMax          time.Duration `json:"dial_backoff_max" mapstructure:"dial_backoff_max"`                       // DialBackoffMax is the most we'll wait between redialing a peer, the wait doubles on each failure up to this
+		MinAlertPeers           int           `json:"min_alert_peers" mapstructure:"min_alert_peers"`                         // MinAlertPeers is how many peers we must be connected to before an alert received over P2P is executed, it's stored unprocessed until then (0 disables)
+		MinAlertPeersForAPI     bool          `json:"min_alert_peers_for_api" mapstructure:"min_alert_peers_for_api"`         // MinAlertPeersForAPI applies MinAlertPeers to the alerts submitted through the API too, they are operator-trusted and bypass it by default
+		PeerScoreThreshold      float64       `json:"peer_score_threshold" mapstructure:"peer_score_threshold"`               // PeerScoreThreshold is the misbehavior score at which a sync peer is disconnected and banned
+		PeerScoreDecay          float64       `json:"peer_score_decay" mapstructure:"peer_score_decay"`                       // PeerScoreDecay is how many points a peer's misbehavior score drops per minute
+		PeerBanDuration         time.Duration `json:"peer_ban_duration" mapstructure:"peer_ban_duration"`                     // PeerBanDuration is how long a peer that reached PeerScoreThreshold is refused connections
+		MaxRequestsPerMinute    int           `json:"max_requests_per_minute" mapstructure:"max_requests_per_minute"`         // MaxRequestsPerMinute is how many sync requests a peer may send per minute before each extra one is penalized
+		SyncStalenessWindow     time.Duration `json:"sync_staleness_window" mapstructure:"sync_staleness_window"`             // SyncStalenessWindow is how long the latest sequence a peer advertised counts towards whether we are synced
+		BackfillInterval        time.Duration `json:"backfill_interval" mapstructure:"backfill_interval"`                     // BackfillInterval is how often the sequences missing below our latest alert are requested from peers
+		MaxBackfillRequests     int           `json:"max_backfill_requests" mapstructure:"max_backfill_requests"`             // MaxBackfillRequests is the most missing sequences requested from peers in each backfill run
+		AlertWorkers            int           `json:"alert_workers" mapstructure:"alert_workers"`                             // AlertWorkers is how many alerts received on the topics are processed at the same time
+		AlertQueueSize          int           `json:"alert_queue_size" mapstructure:"alert_queue_size"`                       // AlertQueueSize is how many received alerts can wait for a worker before the topic is no longer read
+		SeenAlertCacheSize      int           `json:"seen_alert_cache_size" mapstructure:"seen_alert_cache_size"`             // SeenAlertCacheSize is how many hashes of alerts received on the topics are remembered, copies of those alerts are dropped
+		StaticPeers             []string      `json:"static_peers" mapstructure:"static_peers"`                               // StaticPeers are the peers (multiaddrs ending in /p2p/<peer id>) dialed on startup and redialed whenever they disconnect
+		StaticPeerInterval      time.Duration `json:"static_peer_interval" mapstructure:"static_peer_interval"`               // StaticPeerInterval is how often the static peers we aren't connected to are redialed (with backoff)
+		MaxInboundPeers         int           `json:"max_inbound_peers" mapstructure:"max_inbound_peers"`                     // MaxInboundPeers is the most peers that may connect to us (static peers aren't counted)
+		MaxOutboundPeers        int           `json:"max_outbound_peers" mapstructure:"max_outbound_peers"`                   // MaxOutboundPeers is the most peers we dial (static peers aren't counted)
+		EvictInboundPeers       bool          `json:"evict_inbound_peers" mapstructure:"evict_inbound_peers"`                 // EvictInboundPeers disconnects the worst-behaved inbound peer for a new one at MaxInboundPeers, instead of refusing the new one
+		GossipFanout            int           `json:"gossip_fanout" mapstructure:"gossip_fanout"`                             // GossipFanout is how many peers a new alert is pushed to on the topics (the gossipsub mesh size)
+		RelayDisabledAlerts     bool          `json:"relay_disabled_alerts" mapstructure:"relay_disabled_alerts"`             // RelayDisabledAlerts relays alerts of a disabled type to peers, they are still never executed here
 	}
 
 	// RPCConfig is the configuration for the RPC client
@@ -121,11 +269,23 @@ type (
 		Datastore  datastore.ClientInterface // Datastore interface
 		Log        LoggerInterface           // Logger interface
 		Node       NodeInterface             // Node interface
+		NodeHealth *NodeHealth               // Cached result of pinging the node RPC
+		NodeHeight *NodeHeightCache          // Cached block height of the node
 		HTTPClient HTTPInterface             // HTTP client interface
+		Tracing    trace.TracerProvider      // Tracer provider for the alert and node RPC spans (no-op unless one is set)
+	}
+
+	// CORSConfig is the configuration for the cross-origin (CORS) headers of the API, which is off unless origins are allowed
+	CORSConfig struct {
+		AllowedHeaders []string `json:"allowed_headers" mapstructure:"allowed_headers"` // AllowedHeaders are the request headers a cross-origin request may send
+		AllowedMethods []string `json:"allowed_methods" mapstructure:"allowed_methods"` // AllowedMethods are the methods a cross-origin request may use
+		AllowedOrigins []string `json:"allowed_origins" mapstructure:"allowed_origins"` // AllowedOrigins are exact origins (https://host[:port]), "*" for any or https://*.host for any subdomain
 	}
 
 	// WebServerConfig is a configuration for the web HTTP Server
 	WebServerConfig struct {
+		AuthToken    string        `json:"auth_token" mapstructure:"auth_token"`       // AuthToken is the secret every API endpoint but the probes requires, as a bearer token or API key (open if empty, but the endpoints that change state are disabled)
+		CORS         CORSConfig    `json:"cors" mapstructure:"cors"`                   // CORS is which cross-origin (browser) requests the API allows
 		IdleTimeout  time.Duration `json:"idle_timeout" mapstructure:"idle_timeout"`   // 60s
 		Port         string        `json:"port" mapstructure:"port"`                   // 3000
 		ReadTimeout  time.Duration `json:"read_timeout" mapstructure:"read_timeout"`   // 15s
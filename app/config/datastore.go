@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/mrz1836/go-datastore"
 	"github.com/mrz1836/go-logger"
@@ -16,6 +17,9 @@ func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error
 	// Select the datastore
 	switch c.Datastore.Engine {
 	case datastore.SQLite:
+		if c.Datastore.SQLite == nil {
+			c.Datastore.SQLite = &datastore.SQLiteConfig{}
+		}
 		options = append(options, datastore.WithSQLite(&datastore.SQLiteConfig{
 			CommonConfig: datastore.CommonConfig{
 				Debug:              c.Datastore.Debug,
@@ -29,6 +33,12 @@ func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error
 	case datastore.MySQL, datastore.PostgreSQL:
 
 		// Set the pw if not set
+		if c.Datastore.SQLRead == nil {
+			c.Datastore.SQLRead = &datastore.SQLConfig{}
+		}
+		if c.Datastore.SQLWrite == nil {
+			c.Datastore.SQLWrite = &datastore.SQLConfig{}
+		}
 		if len(c.Datastore.Password) > 0 && len(c.Datastore.SQLRead.Password) == 0 {
 			c.Datastore.SQLRead.Password = c.Datastore.Password
 		}
@@ -41,7 +51,7 @@ func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error
 			{ // MASTER - WRITE
 				CommonConfig: datastore.CommonConfig{
 					Debug:                 c.Datastore.Debug,
-					MaxConnectionIdleTime: c.Datastore.SQLWrite.MaxConnectionTime,
+					MaxConnectionIdleTime: c.Datastore.SQLWrite.MaxConnectionIdleTime,
 					MaxConnectionTime:     c.Datastore.SQLWrite.MaxConnectionTime,
 					MaxIdleConnections:    c.Datastore.SQLWrite.MaxIdleConnections,
 					MaxOpenConnections:    c.Datastore.SQLWrite.MaxOpenConnections,
@@ -61,7 +71,7 @@ func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error
 			{ // READ REPLICA
 				CommonConfig: datastore.CommonConfig{
 					Debug:                 c.Datastore.Debug,
-					MaxConnectionIdleTime: c.Datastore.SQLRead.MaxConnectionTime,
+					MaxConnectionIdleTime: c.Datastore.SQLRead.MaxConnectionIdleTime,
 					MaxConnectionTime:     c.Datastore.SQLRead.MaxConnectionTime,
 					MaxIdleConnections:    c.Datastore.SQLRead.MaxIdleConnections,
 					MaxOpenConnections:    c.Datastore.SQLRead.MaxOpenConnections,
@@ -80,18 +90,20 @@ func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error
 			},
 		}))
 	case datastore.Empty, datastore.MongoDB:
-		return ErrDatastoreUnsupported
+		return fmt.Errorf("%w: %q", ErrDatastoreUnsupported, c.Datastore.Engine)
 	default:
-		return ErrDatastoreUnsupported
-	}
-
-	// Add the auto migration option if enabled
-	if c.Datastore.AutoMigrate && models != nil {
-		options = append(options, datastore.WithAutoMigrate(models...))
+		return fmt.Errorf("%w: %q, expected %s, %s or %s", ErrDatastoreUnsupported, c.Datastore.Engine, datastore.SQLite, datastore.PostgreSQL, datastore.MySQL)
 	}
 
 	// Load datastore or return an error
 	var err error
-	c.Services.Datastore, err = datastore.NewClient(ctx, options...)
-	return err
+	if c.Services.Datastore, err = datastore.NewClient(ctx, options...); err != nil {
+		return err
+	}
+
+	// Run the auto migration if enabled, or only log what it would do on a dry run
+	if models != nil && (c.Datastore.AutoMigrate || c.Datastore.MigrationDryRun) {
+		return c.migrateDatastore(ctx, models)
+	}
+	return nil
 }
@@ -21,8 +21,23 @@ func TestLoadDatastore(t *testing.T) {
 		err := c.loadDatastore(context.Background(), nil)
 
 		// Assert
-		require.Error(t, err)
-		assert.Equal(t, ErrDatastoreUnsupported, err)
+		require.ErrorIs(t, err, ErrDatastoreUnsupported)
+		assert.Contains(t, err.Error(), `"unsupported"`)
+	})
+
+	t.Run("failure - datastore not supported by the app", func(t *testing.T) {
+		for _, engine := range []datastore.Engine{datastore.Empty, datastore.MongoDB, ""} {
+			c := &Config{Datastore: DatastoreConfig{Engine: engine}}
+			err := c.loadDatastore(context.Background(), nil)
+			require.ErrorIs(t, err, ErrDatastoreUnsupported)
+			assert.Contains(t, err.Error(), `"`+engine.String()+`"`)
+		}
+	})
+
+	t.Run("success - sqlite without its config", func(t *testing.T) {
+		c := &Config{Datastore: DatastoreConfig{Engine: datastore.SQLite}}
+		require.NoError(t, c.loadDatastore(context.Background(), nil))
+		c.CloseAll(context.Background())
 	})
 
 	t.Run("success - sqlite", func(t *testing.T) {
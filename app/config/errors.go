@@ -7,15 +7,37 @@ import (
 // Configuration errors
 var (
 	ErrDatastoreRequired            = errors.New("datastore is required and was not loaded")
+	ErrInvalidDatastorePool         = errors.New("invalid datastore connection pool setting")
 	ErrDatastoreUnsupported         = errors.New("unsupported datastore engine")
 	ErrInvalidEnvironment           = errors.New("invalid environment")
+	ErrUnsupportedConfigFormat      = errors.New("unsupported config file format, expected .json, .yaml or .yml")
 	ErrNoP2PIP                      = errors.New("no p2p_ip defined")
 	ErrNoP2PPort                    = errors.New("no p2p_port defined")
+	ErrInvalidP2PIP                 = errors.New("invalid p2p ip")
+	ErrInvalidP2PPort               = errors.New("invalid p2p port")
+	ErrInvalidP2PBroadcastIP        = errors.New("invalid p2p broadcast_ip")
+	ErrInvalidP2PBroadcastPort      = errors.New("invalid p2p broadcast_port")
+	ErrInvalidGossipFanout          = errors.New("invalid p2p gossip_fanout")
+	ErrInvalidMinAlertPeers         = errors.New("invalid p2p min_alert_peers")
+	ErrUnknownAlertTypeName         = errors.New("not an alert type name or number")
+	ErrInvalidStaticPeer            = errors.New("invalid p2p static peer, expected a multiaddr ending in /p2p/<peer id>")
 	ErrNoRPCHost                    = errors.New("no rpc_host defined")
 	ErrNoRPCPassword                = errors.New("no rpc_password defined")
 	ErrNoRPCUser                    = errors.New("no rpc_user defined")
 	ErrNoRPCConnections             = errors.New("no rpc connections configured")
+	ErrInvalidRPCURL                = errors.New("invalid rpc url, expected http(s)://host:port")
 	ErrNoGenesisKeys                = errors.New("no genesis keys configured")
+	ErrInvalidPubKeyFormat          = errors.New("invalid genesis key, expected a 33-byte compressed secp256k1 public key in hex")
+	ErrDuplicateGenesisKey          = errors.New("genesis key is listed more than once")
+	ErrInvalidNodePolicy            = errors.New("invalid node unavailable policy")
+	ErrInvalidInfoMessageEncoding   = errors.New("invalid informational message encoding")
+	ErrInvalidWebhookFormat         = errors.New("invalid webhook format")
+	ErrInvalidLogFormat             = errors.New("invalid log format")
+	ErrInvalidBitcoinNetwork        = errors.New("invalid bitcoin network, expected main, test, regtest or stn")
+	ErrInvalidCORSOrigin            = errors.New("invalid cors origin, expected * or scheme://host[:port]")
+	ErrInvalidCORSMethod            = errors.New("invalid cors method")
+	ErrCannotDisableSetKeys         = errors.New("the set keys alert type can't be disabled")
+	ErrSignatureThresholdTooHigh    = errors.New("signature threshold is more than the number of genesis keys")
 	ErrRPCUserMissingFromConfig     = errors.New("rpcuser missing from bitcoin.conf file")
 	ErrRPCPasswordMissingFromConfig = errors.New("rpcpassword missing from bitcoin.conf file")
 	ErrUnexpectedPeerAddress        = errors.New("unexpected peer address")
@@ -4,18 +4,28 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
+	"maps"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/bitcoinsv/bsvd/bsvec"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/mrz1836/go-datastore"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // Added a mutex lock for a race-condition
@@ -36,36 +46,21 @@ func isValidEnvironment(testEnv string) bool {
 // models is a list of models to auto-migrate when the datastore is created
 // if testing is true, the node will be mocked
 func LoadDependencies(ctx context.Context, models []interface{}, isTesting bool) (_appConfig *Config, err error) {
-	// Load the config file
-	_appConfig, err = LoadConfigFile()
+	// Load and validate the config file (custom file path, or the embedded environment file)
+	_appConfig, err = LoadConfigFile(os.Getenv(EnvironmentCustomFilePath))
 	if err != nil {
 		return nil, err
 	}
 
-	// Require at least one RPC connection
-	if len(_appConfig.RPCConnections) == 0 {
-		return nil, ErrNoRPCConnections
-	}
-
-	// Require list of genesis keys
-	if len(_appConfig.GenesisKeys) == 0 {
-		return nil, ErrNoGenesisKeys
-	}
-
-	// Ensure the P2P configuration is valid
-	if err = requireP2P(_appConfig); err != nil {
-		return nil, err
-	}
-
 	// Set the node config (either a real node or a mock node)
 	if !isTesting {
 		// todo support multiple nodes (this is an example)
 		for i := range _appConfig.RPCConnections {
-			_appConfig.Services.Node = NewNodeConfig(
+			_appConfig.Services.Node = NewTracedNode(NewNodeConfig(
 				_appConfig.RPCConnections[i].User,
 				_appConfig.RPCConnections[i].Password,
 				_appConfig.RPCConnections[i].Host,
-			)
+			), _appConfig)
 		}
 	} else {
 		for i := range _appConfig.RPCConnections {
@@ -77,9 +72,22 @@ func LoadDependencies(ctx context.Context, models []interface{}, isTesting bool)
 		}
 	}
 
+	// Load the confiscation height check defaults and the node height cache
+	requireHeightCheck(&_appConfig.ConfiscationHeightCheck)
+	_appConfig.Services.NodeHeight = NewNodeHeightCache(_appConfig.Services.Node, _appConfig.ConfiscationHeightCheck.NodeHeightTTL)
+
+	// Load the node health check, pinged at most once per ttl
+	if _appConfig.NodeHealthTTL <= 0 {
+		_appConfig.NodeHealthTTL = DefaultNodeHealthTTL
+	}
+	_appConfig.Services.NodeHealth = NewNodeHealth(_appConfig.Services.Node, _appConfig.NodeHealthTTL)
+
 	// Load an HTTP client
 	_appConfig.Services.HTTPClient = http.DefaultClient
 
+	// Tracing is a no-op until a tracer provider is set
+	_appConfig.Services.Tracing = noop.NewTracerProvider()
+
 	// Load the datastore service
 	if err = _appConfig.loadDatastore(ctx, models); err != nil {
 		return nil, err
@@ -88,6 +96,96 @@ func LoadDependencies(ctx context.Context, models []interface{}, isTesting bool)
 	return _appConfig, nil
 }
 
+// Validate will ensure the required values of the config are set and valid, returning every problem at once
+// The errors are joined, so each one can be checked with errors.Is (ErrNoP2PPort, ErrNoGenesisKeys, etc.)
+// Missing optional values are set to their defaults, and the bitcoin.conf RPC credentials (if any) are layered on top
+func (c *Config) Validate() error {
+	var errs []error
+
+	// Load bitcoin configuration if specified (overrides the RPC connections) from the section of the bitcoin network
+	if err := requireBitcoinNetwork(c); err != nil {
+		errs = append(errs, err)
+	} else if err = c.loadBitcoinConfiguration(); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Require at least one RPC connection, with a valid URL and a user
+	errs = append(errs, requireRPCConnections(c))
+
+	// Require valid genesis keys (from the list and the keys file), and ensure the signature threshold can be met by them
+	if err := requireGenesisKeys(c); err != nil {
+		errs = append(errs, err)
+	} else {
+		errs = append(errs, requireSignatureThreshold(c))
+	}
+
+	// Ensure the policies, encodings, formats and the P2P configuration are valid
+	errs = append(errs,
+		requireNodePolicies(c),
+		requireInfoMessageEncoding(c),
+		requireWebhookFormat(c),
+		requireCORS(c),
+		requireDisabledAlertTypes(c),
+		requireDatastorePool(c),
+		requireP2P(c),
+	)
+
+	return errors.Join(errs...)
+}
+
+// requireRPCConnections will ensure there is at least one RPC connection and every connection has a valid URL and a user
+func requireRPCConnections(_appConfig *Config) error {
+	if len(_appConfig.RPCConnections) == 0 {
+		return ErrNoRPCConnections
+	}
+	var errs []error
+	for i, connection := range _appConfig.RPCConnections {
+		if len(connection.Host) == 0 {
+			errs = append(errs, fmt.Errorf("%w: rpc connection %d", ErrNoRPCHost, i))
+		} else if _, _, _, err := parseRPCURL(connection.Host); err != nil {
+			errs = append(errs, err)
+		}
+		if len(connection.User) == 0 {
+			errs = append(errs, fmt.Errorf("%w: rpc connection %d", ErrNoRPCUser, i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// requireDatastorePool will ensure the connection pools of a MySQL or Postgres datastore are positive,
+// with no more idle connections than open ones. SQLite has no connection pool to check
+func requireDatastorePool(_appConfig *Config) error {
+	if _appConfig.Datastore.Engine != datastore.MySQL && _appConfig.Datastore.Engine != datastore.PostgreSQL {
+		return nil
+	}
+	var errs []error
+	connections := []struct {
+		name string
+		sql  *datastore.SQLConfig
+	}{{"sql_read", _appConfig.Datastore.SQLRead}, {"sql_write", _appConfig.Datastore.SQLWrite}}
+	for _, connection := range connections {
+		name, sql := connection.name, connection.sql
+		if sql == nil {
+			continue
+		}
+		if sql.MaxOpenConnections <= 0 {
+			errs = append(errs, fmt.Errorf("%w: %s max_open_connections is %d", ErrInvalidDatastorePool, name, sql.MaxOpenConnections))
+		}
+		if sql.MaxIdleConnections <= 0 {
+			errs = append(errs, fmt.Errorf("%w: %s max_idle_connections is %d", ErrInvalidDatastorePool, name, sql.MaxIdleConnections))
+		} else if sql.MaxIdleConnections > sql.MaxOpenConnections {
+			errs = append(errs, fmt.Errorf("%w: %s max_idle_connections %d is more than max_open_connections %d", ErrInvalidDatastorePool, name, sql.MaxIdleConnections, sql.MaxOpenConnections))
+		}
+		if sql.MaxConnectionTime <= 0 {
+			errs = append(errs, fmt.Errorf("%w: %s max_connection_time is %s", ErrInvalidDatastorePool, name, sql.MaxConnectionTime))
+		}
+		if sql.MaxConnectionIdleTime < 0 {
+			errs = append(errs, fmt.Errorf("%w: %s max_connection_idle_time is %s", ErrInvalidDatastorePool, name, sql.MaxConnectionIdleTime))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // requireP2P will ensure the P2P configuration is valid
 func requireP2P(_appConfig *Config) error {
 	// Set the P2P alert system protocol ID if it's missing
@@ -108,34 +206,365 @@ func requireP2P(_appConfig *Config) error {
 		}
 	}
 
-	// Load bitcoin configuration if specified
-	if len(_appConfig.BitcoinConfigPath) > 0 {
-		if err := _appConfig.loadBitcoinConfiguration(); err != nil {
+	// Load the peer discovery interval
+	if _appConfig.P2P.PeerDiscoveryInterval <= 0 {
+		_appConfig.P2P.PeerDiscoveryInterval = DefaultPeerDiscoveryInterval
+	}
+
+	// Load the maximum in-flight sync requests per peer
+	if _appConfig.P2P.MaxInFlightSyncRequests <= 0 {
+		_appConfig.P2P.MaxInFlightSyncRequests = DefaultMaxInFlightSyncRequests
+	}
+
+	// Load the sync request timeout
+	if _appConfig.P2P.SyncRequestTimeout <= 0 {
+		_appConfig.P2P.SyncRequestTimeout = DefaultSyncRequestTimeout
+	}
+
+	// Load the maximum sync request retries
+	if _appConfig.P2P.MaxSyncRequestRetries <= 0 {
+		_appConfig.P2P.MaxSyncRequestRetries = DefaultMaxSyncRequestRetries
+	}
+
+	// Load the window in which a peer's advertised sequence counts towards being synced
+	if _appConfig.P2P.SyncStalenessWindow <= 0 {
+		_appConfig.P2P.SyncStalenessWindow = DefaultSyncStalenessWindow
+	}
+
+	// Load the backfill of missing sequences
+	if _appConfig.P2P.BackfillInterval <= 0 {
+		_appConfig.P2P.BackfillInterval = DefaultBackfillInterval
+	}
+	if _appConfig.P2P.MaxBackfillRequests <= 0 {
+		_appConfig.P2P.MaxBackfillRequests = DefaultMaxBackfillRequests
+	}
+
+	// Load the worker pool processing the alerts received on the topics
+	if _appConfig.P2P.AlertWorkers <= 0 {
+		_appConfig.P2P.AlertWorkers = DefaultAlertWorkers
+	}
+	if _appConfig.P2P.AlertQueueSize <= 0 {
+		_appConfig.P2P.AlertQueueSize = DefaultAlertQueueSize
+	}
+
+	// Load the cache of the alerts already received on the topics
+	if _appConfig.P2P.SeenAlertCacheSize <= 0 {
+		_appConfig.P2P.SeenAlertCacheSize = DefaultSeenAlertCacheSize
+	}
+
+	// Load the interval of redialing the static peers
+	if _appConfig.P2P.StaticPeerInterval <= 0 {
+		_appConfig.P2P.StaticPeerInterval = DefaultStaticPeerInterval
+	}
+
+	// Load the peer limits
+	if _appConfig.P2P.MaxInboundPeers <= 0 {
+		_appConfig.P2P.MaxInboundPeers = DefaultMaxInboundPeers
+	}
+	if _appConfig.P2P.MaxOutboundPeers <= 0 {
+		_appConfig.P2P.MaxOutboundPeers = DefaultMaxOutboundPeers
+	}
+
+	// Load the gossip fanout
+	if _appConfig.P2P.GossipFanout <= 0 {
+		_appConfig.P2P.GossipFanout = DefaultGossipFanout
+	}
+
+	// Load the maximum peer clock skew
+	if _appConfig.P2P.MaxClockSkew <= 0 {
+		_appConfig.P2P.MaxClockSkew = DefaultMaxClockSkew
+	}
+
+	// Load the outbound dial backoff
+	if _appConfig.P2P.DialBackoffInitial <= 0 {
+		_appConfig.P2P.DialBackoffInitial = DefaultDialBackoffInitial
+	}
+	if _appConfig.P2P.DialBackoffMax <= 0 {
+		_appConfig.P2P.DialBackoffMax = DefaultDialBackoffMax
+	}
+	if _appConfig.P2P.DialBackoffMax < _appConfig.P2P.DialBackoffInitial {
+		_appConfig.P2P.DialBackoffMax = _appConfig.P2P.DialBackoffInitial
+	}
+
+	// Load the peer misbehavior scoring
+	if _appConfig.P2P.PeerScoreThreshold <= 0 {
+		_appConfig.P2P.PeerScoreThreshold = DefaultPeerScoreThreshold
+	}
+	if _appConfig.P2P.PeerScoreDecay <= 0 {
+		_appConfig.P2P.PeerScoreDecay = DefaultPeerScoreDecay
+	}
+	if _appConfig.P2P.PeerBanDuration <= 0 {
+		_appConfig.P2P.PeerBanDuration = DefaultPeerBanDuration
+	}
+	if _appConfig.P2P.MaxRequestsPerMinute <= 0 {
+		_appConfig.P2P.MaxRequestsPerMinute = DefaultMaxRequestsPerMinute
+	}
+
+	// Load the p2p bind address (the interface and port the server listens on)
+	var errs []error
+	if _appConfig.P2P.IP = strings.TrimSpace(_appConfig.P2P.IP); _appConfig.P2P.IP == "" {
+		errs = append(errs, ErrNoP2PIP)
+	} else if err := requireP2PIP(_appConfig.P2P.IP, ErrInvalidP2PIP); err != nil {
+		errs = append(errs, err)
+	}
+	if _appConfig.P2P.Port = strings.TrimSpace(_appConfig.P2P.Port); _appConfig.P2P.Port == "" {
+		errs = append(errs, ErrNoP2PPort)
+	} else if err := requireP2PPort(_appConfig.P2P.Port, ErrInvalidP2PPort); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Load the advertised address, the port defaults to the bind port (behind NAT or in a container it can differ)
+	if _appConfig.P2P.BroadcastIP = strings.TrimSpace(_appConfig.P2P.BroadcastIP); _appConfig.P2P.BroadcastIP != "" {
+		if err := requireP2PIP(_appConfig.P2P.BroadcastIP, ErrInvalidP2PBroadcastIP); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _appConfig.P2P.BroadcastPort = strings.TrimSpace(_appConfig.P2P.BroadcastPort); _appConfig.P2P.BroadcastPort == "" {
+		_appConfig.P2P.BroadcastPort = _appConfig.P2P.Port
+	} else if err := requireP2PPort(_appConfig.P2P.BroadcastPort, ErrInvalidP2PBroadcastPort); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Load the static peers, each needs the peer ID to be dialed
+	for _, address := range _appConfig.P2P.StaticPeers {
+		if _, err := peer.AddrInfoFromString(address); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %q: %w", ErrInvalidStaticPeer, address, err))
+		}
+	}
+
+	// The gossip mesh can't be smaller than two peers
+	if _appConfig.P2P.GossipFanout < MinGossipFanout {
+		errs = append(errs, fmt.Errorf("%w: %d must be at least %d", ErrInvalidGossipFanout, _appConfig.P2P.GossipFanout, MinGossipFanout))
+	}
+
+	// The minimum peers to execute an alert is off at 0
+	if _appConfig.P2P.MinAlertPeers < 0 {
+		errs = append(errs, fmt.Errorf("%w: %d can't be negative", ErrInvalidMinAlertPeers, _appConfig.P2P.MinAlertPeers))
+	}
+
+	return errors.Join(errs...)
+}
+
+// requireP2PIP will ensure the P2P address is an IPv4 or IPv6 address, returning invalid with the address if not
+func requireP2PIP(ip string, invalid error) error {
+	if net.ParseIP(ip) == nil {
+		return fmt.Errorf("%w: %q is not an IPv4 or IPv6 address", invalid, ip)
+	}
+	return nil
+}
+
+// requireP2PPort will ensure the P2P port is a number from 1 to 65535, returning invalid with the port if not
+func requireP2PPort(port string, invalid error) error {
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("%w: port %q must be from 1 to 65535", invalid, port)
+	}
+	return nil
+}
+
+// requireNodePolicies will ensure every node unavailable policy override is for an alert type name or number,
+// and is a known policy
+func requireNodePolicies(_appConfig *Config) error {
+	for alertType, policy := range _appConfig.NodeUnavailablePolicies {
+		if _, err := ParseAlertType(alertType); err != nil {
+			return fmt.Errorf("%w: node_unavailable_policies key: %w", ErrInvalidNodePolicy, err)
+		}
+		if policy != NodeUnavailableDefer && policy != NodeUnavailableProcess {
+			return fmt.Errorf("%w: %q for alert type %s", ErrInvalidNodePolicy, policy, alertType)
+		}
+	}
+	return nil
+}
+
+// requireCORS will default the CORS methods and headers and ensure the allowed origins and methods are valid
+func requireCORS(_appConfig *Config) error {
+	cors := &_appConfig.WebServer.CORS
+	for _, origin := range cors.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil || strings.Contains(strings.TrimPrefix(u.Host, "*."), "*") {
+			return fmt.Errorf("%w: %q", ErrInvalidCORSOrigin, origin)
+		}
+	}
+
+	if len(cors.AllowedMethods) == 0 {
+		cors.AllowedMethods = slices.Clone(DefaultCORSAllowedMethods)
+	}
+	for i, method := range cors.AllowedMethods {
+		cors.AllowedMethods[i] = strings.ToUpper(method)
+		switch cors.AllowedMethods[i] {
+		case http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodPatch, http.MethodPost, http.MethodPut:
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidCORSMethod, method)
+		}
+	}
+
+	if len(cors.AllowedHeaders) == 0 {
+		cors.AllowedHeaders = slices.Clone(DefaultCORSAllowedHeaders)
+	}
+	return nil
+}
+
+// requireDisabledAlertTypes will ensure none of the disabled alert types are needed to verify other alerts
+func requireDisabledAlertTypes(_appConfig *Config) error {
+	for _, alertType := range _appConfig.DisabledAlertTypes {
+		if alertType == alertTypeSetKeys {
+			return ErrCannotDisableSetKeys
+		}
+	}
+	return nil
+}
+
+// requireGenesisKeys will add the keys of the genesis keys file to the genesis keys, and ensure there is at least one,
+// each is a 33-byte compressed secp256k1 public key and none is listed twice. The keys are stored as lowercase hex
+func requireGenesisKeys(_appConfig *Config) error {
+	keys := _appConfig.GenesisKeys
+	if _appConfig.GenesisKeysFile != "" {
+		fileKeys, err := readGenesisKeysFile(_appConfig.GenesisKeysFile)
+		if err != nil {
 			return err
 		}
+		keys = append(slices.Clip(keys), fileKeys...)
+	}
+	if len(keys) == 0 {
+		return ErrNoGenesisKeys
 	}
 
-	// Load the peer discovery interval
-	if _appConfig.P2P.PeerDiscoveryInterval <= 0 {
-		_appConfig.P2P.PeerDiscoveryInterval = DefaultPeerDiscoveryInterval
+	var errs []error
+	seen := make(map[string]bool, len(keys))
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if b, err := hex.DecodeString(key); err != nil || len(b) != bsvec.PubKeyBytesLenCompressed {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrInvalidPubKeyFormat, key))
+			continue
+		} else if _, err = bsvec.ParsePubKey(b, bsvec.S256()); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %q: %w", ErrInvalidPubKeyFormat, key, err))
+			continue
+		}
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrDuplicateGenesisKey, key))
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, key)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	_appConfig.GenesisKeys = normalized
+	return nil
+}
+
+// readGenesisKeysFile will read the genesis keys from the file, one per line, skipping blank lines and # comments
+func readGenesisKeysFile(path string) ([]string, error) {
+	file, err := os.Open(path) //nolint:gosec // This is a custom file path
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	var keys []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			keys = append(keys, line)
+		}
+	}
+	return keys, scanner.Err()
+}
+
+// requireSignatureThreshold will default the signature threshold and ensure there are enough genesis keys to meet it
+func requireSignatureThreshold(_appConfig *Config) error {
+	if _appConfig.SignatureThreshold <= 0 {
+		_appConfig.SignatureThreshold = DefaultSignatureThreshold
 	}
+	if _appConfig.SignatureThreshold > len(_appConfig.GenesisKeys) {
+		return fmt.Errorf("%w: %d of %d", ErrSignatureThresholdTooHigh, _appConfig.SignatureThreshold, len(_appConfig.GenesisKeys))
+	}
+	return nil
+}
 
-	// Load the p2p ip (local, ip address or domain name)
-	// todo better validation of what is a valid IP, domain name or local address
-	if len(_appConfig.P2P.IP) < 5 {
-		return ErrNoP2PIP
+// requireInfoMessageEncoding will default the informational message encoding and ensure it's a known encoding
+func requireInfoMessageEncoding(_appConfig *Config) error {
+	switch _appConfig.InfoMessageEncoding {
+	case "":
+		_appConfig.InfoMessageEncoding = InfoMessageEncodingBase64
+	case InfoMessageEncodingBase64, InfoMessageEncodingStrict:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidInfoMessageEncoding, _appConfig.InfoMessageEncoding)
 	}
+	return nil
+}
 
-	// Load the p2p port ( >= XX)
-	if len(_appConfig.P2P.Port) < 2 {
-		return ErrNoP2PPort
+// requireBitcoinNetwork will default the bitcoin network and ensure it's a known network
+func requireBitcoinNetwork(_appConfig *Config) error {
+	switch _appConfig.BitcoinNetwork {
+	case "":
+		_appConfig.BitcoinNetwork = BitcoinNetworkMain
+	case BitcoinNetworkMain, BitcoinNetworkRegtest, BitcoinNetworkSTN, BitcoinNetworkTest:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidBitcoinNetwork, _appConfig.BitcoinNetwork)
 	}
+	return nil
+}
 
+// requireLogFormat will default the log format and ensure it's a known format
+func requireLogFormat(_appConfig *Config) error {
+	switch _appConfig.LogFormat {
+	case "":
+		_appConfig.LogFormat = LogFormatText
+	case LogFormatText, LogFormatJSON:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidLogFormat, _appConfig.LogFormat)
+	}
 	return nil
 }
 
-// LoadConfigFile will load the config file and environment variables
-func LoadConfigFile() (_appConfig *Config, err error) {
+// requireWebhookFormat will default the webhook format and ensure it's a known format
+func requireWebhookFormat(_appConfig *Config) error {
+	switch _appConfig.Webhook.Format {
+	case "":
+		_appConfig.Webhook.Format = WebhookFormatRaw
+	case WebhookFormatRaw, WebhookFormatSlack:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidWebhookFormat, _appConfig.Webhook.Format)
+	}
+	return nil
+}
+
+// requireHeightCheck will set the defaults for any missing height check values
+func requireHeightCheck(check *HeightCheckConfig) {
+	if check.MaxBlocksInPast == 0 {
+		check.MaxBlocksInPast = DefaultMaxBlocksInPast
+	}
+	if check.MaxBlocksInFuture == 0 {
+		check.MaxBlocksInFuture = DefaultMaxBlocksInFuture
+	}
+	if check.NodeHeightTTL <= 0 {
+		check.NodeHeightTTL = DefaultNodeHeightCacheTTL
+	}
+}
+
+// configFileType will return the viper config type of the config file, detected by its extension
+func configFileType(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedConfigFormat, path)
+	}
+}
+
+// LoadConfigFile will load the config file and environment variables, then validate the config
+// path is a JSON or YAML config file (detected by the extension), if empty the embedded environment file is used
+func LoadConfigFile(path string) (_appConfig *Config, err error) {
 	// Start the configuration struct
 	_appConfig = &Config{
 		Datastore: DatastoreConfig{
@@ -178,25 +607,28 @@ func LoadConfigFile() (_appConfig *Config, err error) {
 		return nil, err
 	}
 
-	// Set the configuration type
-	viper.SetConfigType("json")
-
 	// Do we have a custom config file? (use this instead of the environment file)
-	customConfigFileWithPath := os.Getenv(EnvironmentCustomFilePath)
-	if len(customConfigFileWithPath) > 0 {
+	if len(path) > 0 {
+		var configType string
+		if configType, err = configFileType(path); err != nil {
+			return nil, err
+		}
+
 		var b []byte
 
 		// Read the file
-		if b, err = os.ReadFile(customConfigFileWithPath); err != nil { //nolint:gosec // This is a custom file path
+		if b, err = os.ReadFile(path); err != nil { //nolint:gosec // This is a custom file path
 			return nil, err
 		}
 
 		// Read the config
+		viper.SetConfigType(configType)
 		if err = viper.ReadConfig(bytes.NewBuffer(b)); err != nil {
 			return nil, err
 		}
 	} else {
 		// Loop through the various environment files
+		viper.SetConfigType("json")
 		for _, file := range files {
 			if file.Name() == environment+".json" {
 				var f fs.File
@@ -216,6 +648,11 @@ func LoadConfigFile() (_appConfig *Config, err error) {
 		return nil, err
 	}
 
+	// Ensure the log format is valid before the logger is created
+	if err = requireLogFormat(_appConfig); err != nil {
+		return nil, err
+	}
+
 	// Load the logger service (ExtendedLogger meets the LoggerInterface)
 	writer := os.Stdout
 	if _appConfig.LogOutputFile != "" {
@@ -225,9 +662,14 @@ func LoadConfigFile() (_appConfig *Config, err error) {
 		}
 	}
 
+	// A JSON line carries its own timestamp, so it gets no prefix
 	logger := log.New(writer, "bitcoin-alert-system: ", log.LstdFlags)
+	if _appConfig.LogFormat == LogFormatJSON {
+		logger = log.New(writer, "", 0)
+	}
 	_appConfig.Services.Log = &ExtendedLogger{
 		Logger:   logger,
+		format:   _appConfig.LogFormat,
 		writer:   writer,
 		logLevel: _appConfig.LogLevel,
 	}
@@ -237,9 +679,70 @@ func LoadConfigFile() (_appConfig *Config, err error) {
 		_appConfig.AlertProcessingInterval = DefaultAlertProcessingInterval
 	}
 
+	// Set default shutdown timeout if it doesn't exist
+	if _appConfig.ShutdownTimeout <= 0 {
+		_appConfig.ShutdownTimeout = DefaultServerShutdown
+	}
+
+	// A negative alert timestamp skew disables the check, the same as zero
+	if _appConfig.MaxAlertTimestampSkew < 0 {
+		_appConfig.MaxAlertTimestampSkew = 0
+	}
+
+	// Set default datastore query timeout if it doesn't exist
+	if _appConfig.Datastore.QueryTimeout <= 0 {
+		_appConfig.Datastore.QueryTimeout = DefaultDatastoreQueryTimeout
+	}
+
+	// Set default maximum funds per freeze alert if it doesn't exist
+	if _appConfig.MaxFreezeFunds <= 0 {
+		_appConfig.MaxFreezeFunds = DefaultMaxFreezeFunds
+	}
+
+	// Set default maximum alert size if it doesn't exist
+	if _appConfig.MaxAlertSize <= 0 {
+		_appConfig.MaxAlertSize = DefaultMaxAlertSize
+	}
+
+	// Set default maximum informational message length if it doesn't exist
+	if _appConfig.MaxInfoMessageLength <= 0 {
+		_appConfig.MaxInfoMessageLength = DefaultMaxInfoMessageLength
+	}
+
+	// Set default node RPC timeout if it doesn't exist
+	if _appConfig.NodeRPCTimeout <= 0 {
+		_appConfig.NodeRPCTimeout = DefaultNodeRPCTimeout
+	}
+
+	// Set default node RPC retries if they don't exist
+	if _appConfig.NodeRPCMaxRetries <= 0 {
+		_appConfig.NodeRPCMaxRetries = DefaultNodeRPCMaxRetries
+	}
+	if _appConfig.NodeRPCBaseBackoff <= 0 {
+		_appConfig.NodeRPCBaseBackoff = DefaultNodeRPCBaseBackoff
+	}
+
+	// Set default webhook de-duplication window if it doesn't exist
+	if _appConfig.WebhookDedupWindow <= 0 {
+		_appConfig.WebhookDedupWindow = DefaultWebhookDedupWindow
+	}
+
+	// Set default webhook delivery retries if they don't exist
+	if _appConfig.Webhook.MaxRetries <= 0 {
+		_appConfig.Webhook.MaxRetries = DefaultWebhookMaxRetries
+	}
+	if _appConfig.Webhook.BaseBackoff <= 0 {
+		_appConfig.Webhook.BaseBackoff = DefaultWebhookBaseBackoff
+	}
+
 	// Log the configuration that was detected and where it was loaded from
 	_appConfig.Services.Log.Debug("loaded configuration from: " + viper.ConfigFileUsed())
 
+	// Ensure the required values are set and valid
+	if err = _appConfig.Validate(); err != nil {
+		return nil, err
+	}
+
 	return _appConfig, nil
 }
 
@@ -266,32 +769,35 @@ func (c *Config) loadBitcoinConfiguration() error {
 	if err != nil {
 		return err
 	}
-	scanner := bufio.NewScanner(file)
-	scanner.Split(splitFunc)
-	confValues := map[string]string{}
-	for scanner.Scan() {
-		kv := scanner.Text()
-		keyValue := strings.Split(kv, "=")
-		if len(keyValue) != 2 {
-			continue
+	defer func() {
+		_ = file.Close()
+	}()
+	confValues, err := parseBitcoinConfig(file, c.BitcoinNetwork)
+	if err != nil {
+		return err
+	}
+	// Get the default scheme, host and port in case they are not set
+	scheme, defaultHost, defaultPort := "http", "", ""
+	if len(c.RPCConnections) > 0 {
+		if scheme, defaultHost, defaultPort, err = parseRPCURL(c.RPCConnections[0].Host); err != nil {
+			return err
 		}
-		confValues[keyValue[0]] = keyValue[1]
 	}
-	// Get the default host and ports in case they are not set
-	defaultHostPort := c.RPCConnections[0].Host
-	// Trim off http or https
-	defaultHostPortTrimmed := strings.TrimPrefix(defaultHostPort, "http://")
-	defaultHostPortTrimmed = strings.TrimPrefix(defaultHostPortTrimmed, "https://")
-	defaults := strings.Split(defaultHostPortTrimmed, ":")
 	host := confValues["rpcconnect"]
 	if host == "" {
 		c.Services.Log.Debugf("rpcconnect value not detected in bitcoin.conf")
-		host = defaults[0]
+		host = defaultHost
 	}
 	port := confValues["rpcport"]
 	if port == "" {
 		c.Services.Log.Debugf("rpcport value not detected in bitcoin.conf")
-		port = defaults[1]
+		port = defaultPort
+	}
+	if host == "" || port == "" {
+		return ErrNoRPCHost
+	}
+	if err = requireRPCPort(port); err != nil {
+		return fmt.Errorf("%w: rpcport in bitcoin.conf", err)
 	}
 
 	user := confValues["rpcuser"]
@@ -304,13 +810,91 @@ func (c *Config) loadBitcoinConfiguration() error {
 	}
 	c.RPCConnections = []RPCConfig{
 		{
-			Host:     fmt.Sprintf("http://%s", net.JoinHostPort(host, port)),
+			Host:     fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, port)),
 			Password: pass,
 			User:     user,
 		},
 	}
 
-	return file.Close()
+	return nil
+}
+
+// parseRPCURL will parse an RPC connection URL (http or https, with a host and port) into its parts
+// Bracketed IPv6 hosts are supported, the returned host is without the brackets
+func parseRPCURL(rawURL string) (scheme, host, port string, err error) {
+	var u *url.URL
+	if u, err = url.Parse(rawURL); err != nil {
+		return "", "", "", fmt.Errorf("%w: %w", ErrInvalidRPCURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", "", fmt.Errorf("%w: %q has no http or https scheme", ErrInvalidRPCURL, rawURL)
+	}
+	if host = u.Hostname(); host == "" {
+		return "", "", "", fmt.Errorf("%w: %q has no host", ErrInvalidRPCURL, rawURL)
+	} else if strings.Contains(host, ":") && !strings.HasPrefix(u.Host, "[") {
+		return "", "", "", fmt.Errorf("%w: %q has an IPv6 host without brackets", ErrInvalidRPCURL, rawURL)
+	}
+	if err = requireRPCPort(u.Port()); err != nil {
+		return "", "", "", fmt.Errorf("%w: %q", err, rawURL)
+	}
+	return u.Scheme, host, u.Port(), nil
+}
+
+// requireRPCPort will ensure the RPC port is a number from 1 to 65535
+func requireRPCPort(port string) error {
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("%w: port %q must be from 1 to 65535", ErrInvalidRPCURL, port)
+	}
+	return nil
+}
+
+// parseBitcoinConfig will read the key=value pairs of a bitcoin.conf file for the network
+// A key in the network's section ([test] header or test. key prefix) overrides the same key in the global section,
+// keys in the sections of other networks are ignored
+func parseBitcoinConfig(r io.Reader, network string) (map[string]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(splitFunc)
+	confValues, networkValues := map[string]string{}, map[string]string{}
+	section := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, value, ok := parseBitcoinConfigLine(line)
+		if !ok {
+			continue
+		}
+		keySection := section
+		if prefix, name, found := strings.Cut(key, "."); found && len(prefix) > 0 && len(name) > 0 {
+			keySection, key = prefix, name
+		}
+		switch keySection {
+		case "":
+			confValues[key] = value
+		case network:
+			networkValues[key] = value
+		}
+	}
+	maps.Copy(confValues, networkValues)
+	return confValues, scanner.Err()
+}
+
+// parseBitcoinConfigLine will parse a bitcoin.conf line into its key and value
+// Comment lines, blank lines and lines without a key are skipped, the value may contain "="
+func parseBitcoinConfigLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if len(line) == 0 || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+	if key, value, ok = strings.Cut(line, "="); !ok {
+		return "", "", false
+	}
+	if key = strings.TrimSpace(key); len(key) == 0 {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
 }
 
 func splitFunc(data []byte, atEOF bool) (advance int, token []byte, err error) {
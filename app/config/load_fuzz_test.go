@@ -1,7 +1,6 @@
 package config
 
 import (
-	"bufio"
 	"bytes"
 	"strings"
 	"testing"
@@ -100,30 +99,8 @@ rpcport=8332
 	f.Add([]byte("key=value\r"))
 
 	f.Fuzz(func(t *testing.T, data []byte) {
-		// Create a scanner with the custom split function
-		scanner := bufio.NewScanner(bytes.NewReader(data))
-		scanner.Split(splitFunc)
-
-		// Parse the config content
-		confValues := map[string]string{}
-		lineCount := 0
-
-		// Should never panic during scanning
-		for scanner.Scan() {
-			lineCount++
-			kv := scanner.Text()
-			keyValue := strings.Split(kv, "=")
-
-			// Skip lines that don't have exactly 2 parts
-			if len(keyValue) != 2 {
-				continue
-			}
-
-			confValues[keyValue[0]] = keyValue[1]
-		}
-
-		// Validate scanner completed without panic
-		err := scanner.Err()
+		// Should never panic during parsing
+		confValues, err := parseBitcoinConfig(bytes.NewReader(data), BitcoinNetworkMain)
 		if err != nil {
 			// Scanner errors are acceptable for invalid input
 			return
@@ -133,6 +110,9 @@ rpcport=8332
 		for key, value := range confValues {
 			require.NotContains(t, key, "\n", "keys should not contain newlines")
 			require.NotContains(t, value, "\n", "values should not contain newlines")
+			require.NotEmpty(t, key, "keys should not be empty")
+			require.NotContains(t, key, "=", "keys should not contain the delimiter")
+			require.False(t, strings.HasPrefix(key, "#"), "comment lines should be skipped")
 		}
 	})
 }
@@ -160,36 +140,25 @@ func FuzzConfigKeyValueParsing(f *testing.F) {
 	f.Add("key with spaces=value")
 	f.Add("key=@#$%^&*()")
 	f.Add("user@domain=pass!#$")
+	f.Add("# comment=line")
+	f.Add("  key = value  ")
 
 	f.Fuzz(func(t *testing.T, kv string) {
 		// Parse key=value pair
-		keyValue := strings.Split(kv, "=")
-
-		// Validate split result
-		require.NotNil(t, keyValue, "split should always return a slice")
-		require.GreaterOrEqual(t, len(keyValue), 1, "split should return at least one element")
-
-		// If exactly 2 parts, validate they form a valid key-value pair
-		if len(keyValue) == 2 {
-			key := keyValue[0]
-			value := keyValue[1]
-
-			// Keys and values can be empty, but shouldn't cause issues
-			_ = key
-			_ = value
-
-			// Test that we can store in a map
-			testMap := map[string]string{}
-			testMap[key] = value
-
-			require.Equal(t, value, testMap[key], "value should be retrievable from map")
+		key, value, ok := parseBitcoinConfigLine(kv)
+		if !ok {
+			// Malformed, blank and comment lines are skipped
+			require.Empty(t, key)
+			require.Empty(t, value)
+			return
 		}
 
-		// If not exactly 2 parts, it should be skipped (as per the actual code logic)
-		if len(keyValue) != 2 {
-			// This is valid behavior - malformed lines are skipped
-			require.NotEqual(t, 2, len(keyValue), "should skip lines that don't have exactly 2 parts")
-		}
+		// The key is everything before the first delimiter, the value may contain more
+		require.NotEmpty(t, key, "key should not be empty")
+		require.NotContains(t, key, "=", "key should not contain the delimiter")
+		require.Equal(t, strings.TrimSpace(key), key, "key should be trimmed")
+		require.Equal(t, strings.TrimSpace(value), value, "value should be trimmed")
+		require.Contains(t, kv, key, "key should come from the line")
 	})
 }
 
@@ -216,29 +185,20 @@ func FuzzHostPortParsing(f *testing.F) {
 	f.Add("http://localhost:not-a-port")
 	f.Add("http://[::1]:8332")
 	f.Add("http://localhost:8332:extra")
+	f.Add("http://localhost:70000")
+	f.Add("ftp://localhost:8332")
 
 	f.Fuzz(func(t *testing.T, hostPort string) {
-		// Simulate the trimming logic from loadBitcoinConfiguration
-		trimmed := strings.TrimPrefix(hostPort, "http://")
-		trimmed = strings.TrimPrefix(trimmed, "https://")
-
 		// Should never panic
-		parts := strings.Split(trimmed, ":")
-
-		// Validate split result
-		require.NotNil(t, parts, "split should always return a slice")
-		require.GreaterOrEqual(t, len(parts), 1, "split should return at least one element")
-
-		// If we have at least one part, it could be a valid host
-		if len(parts) >= 1 {
-			host := parts[0]
-			_ = host // host can be any string
+		scheme, host, port, err := parseRPCURL(hostPort)
+		if err != nil {
+			require.ErrorIs(t, err, ErrInvalidRPCURL, "every failure should be an invalid rpc url")
+			return
 		}
 
-		// If we have at least two parts, second part could be a port
-		if len(parts) >= 2 {
-			port := parts[1]
-			_ = port // port can be any string (validation happens elsewhere)
-		}
+		// A parsed URL always has every part, with a port in range
+		require.Contains(t, []string{"http", "https"}, scheme)
+		require.NotEmpty(t, host)
+		require.NoError(t, requireRPCPort(port))
 	})
 }
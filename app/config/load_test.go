@@ -2,7 +2,12 @@ package config
 
 import (
 	"context"
+	"io"
+	"io/fs"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,20 +36,75 @@ func TestLoadConfig_Success(t *testing.T) {
 		assert.Equal(t, DefaultAlertSystemProtocolID, c.P2P.AlertSystemProtocolID)
 		assert.Equal(t, DefaultPeerDiscoveryInterval, c.P2P.PeerDiscoveryInterval)
 		assert.Equal(t, DefaultAlertProcessingInterval, c.AlertProcessingInterval)
+		assert.Equal(t, DefaultMaxInFlightSyncRequests, c.P2P.MaxInFlightSyncRequests)
+		assert.Equal(t, DefaultSyncRequestTimeout, c.P2P.SyncRequestTimeout)
+		assert.Equal(t, DefaultMaxSyncRequestRetries, c.P2P.MaxSyncRequestRetries)
+		assert.Equal(t, DefaultSyncStalenessWindow, c.P2P.SyncStalenessWindow)
+		assert.Equal(t, DefaultBackfillInterval, c.P2P.BackfillInterval)
+		assert.Equal(t, DefaultStaticPeerInterval, c.P2P.StaticPeerInterval)
+		assert.Equal(t, DefaultMaxInboundPeers, c.P2P.MaxInboundPeers)
+		assert.Equal(t, DefaultMaxOutboundPeers, c.P2P.MaxOutboundPeers)
+		assert.False(t, c.P2P.EvictInboundPeers)
+		assert.Equal(t, DefaultGossipFanout, c.P2P.GossipFanout)
+		assert.False(t, c.P2P.RelayDisabledAlerts)
+		assert.Zero(t, c.P2P.MinAlertPeers)
+		assert.False(t, c.P2P.MinAlertPeersForAPI)
+		assert.Empty(t, c.P2P.StaticPeers)
+		assert.Equal(t, DefaultMaxBackfillRequests, c.P2P.MaxBackfillRequests)
+		assert.Equal(t, DefaultAlertWorkers, c.P2P.AlertWorkers)
+		assert.Equal(t, DefaultAlertQueueSize, c.P2P.AlertQueueSize)
+		assert.Equal(t, DefaultSeenAlertCacheSize, c.P2P.SeenAlertCacheSize)
+		assert.False(t, c.P2P.StrictSyncMessageTypes)
+		assert.False(t, c.P2P.LazySyncVerification)
+		assert.Equal(t, InfoMessageEncodingBase64, c.InfoMessageEncoding)
+		assert.Equal(t, DefaultMaxClockSkew, c.P2P.MaxClockSkew)
+		assert.Equal(t, DefaultDialBackoffInitial, c.P2P.DialBackoffInitial)
+		assert.Equal(t, DefaultDialBackoffMax, c.P2P.DialBackoffMax)
+		assert.InDelta(t, DefaultPeerScoreThreshold, c.P2P.PeerScoreThreshold, 0)
+		assert.InDelta(t, DefaultPeerScoreDecay, c.P2P.PeerScoreDecay, 0)
+		assert.Equal(t, DefaultPeerBanDuration, c.P2P.PeerBanDuration)
+		assert.Equal(t, DefaultMaxRequestsPerMinute, c.P2P.MaxRequestsPerMinute)
+		assert.False(t, c.P2P.DisconnectOnClockSkew)
+		assert.False(t, c.ConfiscationHeightCheck.Enabled)
+		assert.Equal(t, DefaultMaxBlocksInPast, c.ConfiscationHeightCheck.MaxBlocksInPast)
+		assert.Equal(t, DefaultMaxBlocksInFuture, c.ConfiscationHeightCheck.MaxBlocksInFuture)
+		assert.Equal(t, DefaultNodeHeightCacheTTL, c.ConfiscationHeightCheck.NodeHeightTTL)
+		assert.Zero(t, c.MaxAlertTimestampSkew)
+		assert.Equal(t, DefaultServerShutdown, c.ShutdownTimeout)
+		assert.Equal(t, DefaultDatastoreQueryTimeout, c.Datastore.QueryTimeout)
+		assert.False(t, c.Datastore.MigrationDryRun)
+		assert.Equal(t, DefaultMaxAlertSize, c.MaxAlertSize)
+		assert.Equal(t, DefaultMaxFreezeFunds, c.MaxFreezeFunds)
+		assert.Equal(t, DefaultMaxInfoMessageLength, c.MaxInfoMessageLength)
+		assert.Equal(t, DefaultNodeRPCTimeout, c.NodeRPCTimeout)
+		assert.Equal(t, DefaultNodeHealthTTL, c.NodeHealthTTL)
+		assert.NotNil(t, c.Services.NodeHealth)
+		assert.Empty(t, c.WebServer.CORS.AllowedOrigins)
+		assert.Equal(t, DefaultCORSAllowedMethods, c.WebServer.CORS.AllowedMethods)
+		assert.Equal(t, DefaultCORSAllowedHeaders, c.WebServer.CORS.AllowedHeaders)
+		assert.Equal(t, DefaultNodeRPCMaxRetries, c.NodeRPCMaxRetries)
+		assert.Equal(t, DefaultNodeRPCBaseBackoff, c.NodeRPCBaseBackoff)
+		assert.NotNil(t, c.Services.NodeHeight)
 		assert.Equal(t, "192.168.1.1", c.P2P.IP)
 		assert.Equal(t, "8000", c.P2P.Port)
+		assert.Empty(t, c.P2P.BroadcastIP)
+		assert.Equal(t, "8000", c.P2P.BroadcastPort)
 		assert.Equal(t, "https://webhook.url", c.AlertWebhookURL)
+		assert.Equal(t, DefaultWebhookDedupWindow, c.WebhookDedupWindow)
+		assert.Equal(t, DefaultWebhookMaxRetries, c.Webhook.MaxRetries)
+		assert.Equal(t, DefaultWebhookBaseBackoff, c.Webhook.BaseBackoff)
+		assert.Equal(t, DefaultSignatureThreshold, c.SignatureThreshold)
 	})
 }
 
-// TestLoadConfigFile tests the method LoadConfigFile()
+// TestLoadConfigFile tests the method LoadConfigFile("")
 func TestLoadConfigFile(t *testing.T) {
 	t.Run("no env", func(t *testing.T) {
 		err := os.Unsetenv(EnvironmentKey)
 		require.NoError(t, err)
 
 		var ac *Config
-		ac, err = LoadConfigFile()
+		ac, err = LoadConfigFile("")
 		require.Error(t, err)
 		require.Nil(t, ac)
 		assert.Contains(t, err.Error(), "invalid environment")
@@ -84,7 +144,7 @@ func TestLoadConfigFile(t *testing.T) {
 		require.Nil(t, c)
 
 		require.Error(t, err)
-		assert.Equal(t, ErrNoP2PIP, err)
+		require.ErrorIs(t, err, ErrNoP2PIP)
 	})
 
 	t.Run("missing port", func(t *testing.T) {
@@ -103,7 +163,50 @@ func TestLoadConfigFile(t *testing.T) {
 		require.Nil(t, c)
 
 		require.Error(t, err)
-		assert.Equal(t, ErrNoP2PPort, err)
+		require.ErrorIs(t, err, ErrNoP2PPort)
+	})
+
+	t.Run("malformed p2p addresses", func(t *testing.T) {
+		for _, tc := range []struct {
+			env      string
+			value    string
+			expected error
+		}{
+			{"ALERT_SYSTEM_P2P__IP", "localhost", ErrInvalidP2PIP},
+			{"ALERT_SYSTEM_P2P__IP", "300.1.1.1", ErrInvalidP2PIP},
+			{"ALERT_SYSTEM_P2P__PORT", "0", ErrInvalidP2PPort},
+			{"ALERT_SYSTEM_P2P__PORT", "65536", ErrInvalidP2PPort},
+			{"ALERT_SYSTEM_P2P__BROADCAST_IP", "my.host", ErrInvalidP2PBroadcastIP},
+			{"ALERT_SYSTEM_P2P__BROADCAST_PORT", "port", ErrInvalidP2PBroadcastPort},
+			{"ALERT_SYSTEM_P2P__STATIC_PEERS", "/ip4/203.0.113.1/tcp/9906", ErrInvalidStaticPeer},
+			{"ALERT_SYSTEM_P2P__GOSSIP_FANOUT", "1", ErrInvalidGossipFanout},
+			{"ALERT_SYSTEM_P2P__MIN_ALERT_PEERS", "-1", ErrInvalidMinAlertPeers},
+		} {
+			t.Run(tc.env+"="+tc.value, func(t *testing.T) {
+				t.Setenv(EnvironmentKey, EnvironmentTest)
+				t.Setenv(tc.env, tc.value)
+
+				c, err := LoadDependencies(context.Background(), nil, true)
+				require.Nil(t, c)
+				require.ErrorIs(t, err, tc.expected)
+				assert.Contains(t, err.Error(), tc.value)
+			})
+		}
+	})
+
+	t.Run("bind and advertised p2p addresses", func(t *testing.T) {
+		t.Setenv(EnvironmentKey, EnvironmentTest)
+		t.Setenv("ALERT_SYSTEM_P2P__IP", "::")
+		t.Setenv("ALERT_SYSTEM_P2P__BROADCAST_IP", "203.0.113.7")
+		t.Setenv("ALERT_SYSTEM_P2P__BROADCAST_PORT", "19906")
+
+		c, err := LoadDependencies(context.Background(), nil, true)
+		require.NoError(t, err)
+		defer c.CloseAll(context.Background())
+		assert.Equal(t, "::", c.P2P.IP)
+		assert.Equal(t, "8000", c.P2P.Port)
+		assert.Equal(t, "203.0.113.7", c.P2P.BroadcastIP)
+		assert.Equal(t, "19906", c.P2P.BroadcastPort)
 	})
 
 	t.Run("invalid custom file path for config", func(t *testing.T) {
@@ -148,13 +251,14 @@ func TestLoadConfigFile(t *testing.T) {
 		require.NoError(t, err)
 
 		var ac *Config
-		ac, err = LoadConfigFile()
+		ac, err = LoadConfigFile("")
 		require.NoError(t, err)
 		require.NotNil(t, ac)
 
 		defer ac.CloseAll(context.Background())
 
 		assert.True(t, ac.RequestLogging)
+		assert.Equal(t, BitcoinNetworkMain, ac.BitcoinNetwork)
 
 		// Check nested structs (Webserver)
 		assert.Equal(t, 60*time.Second, ac.WebServer.IdleTimeout)
@@ -219,3 +323,506 @@ func TestIsValidEnvironment(t *testing.T) {
 		assert.True(t, valid)
 	})
 }
+
+// TestRequireNodePolicies will test the method requireNodePolicies()
+func TestRequireNodePolicies(t *testing.T) {
+	t.Run("valid policies", func(t *testing.T) {
+		c := &Config{NodeUnavailablePolicies: map[string]string{
+			"1": NodeUnavailableDefer,
+			"2": NodeUnavailableProcess,
+		}}
+		require.NoError(t, requireNodePolicies(c))
+	})
+
+	t.Run("type names", func(t *testing.T) {
+		c := &Config{NodeUnavailablePolicies: map[string]string{
+			"freeze_utxo": NodeUnavailableProcess,
+			"special":     NodeUnavailableDefer,
+		}}
+		require.NoError(t, requireNodePolicies(c))
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		c := &Config{NodeUnavailablePolicies: map[string]string{"2": "ignore"}}
+		require.ErrorIs(t, requireNodePolicies(c), ErrInvalidNodePolicy)
+	})
+
+	t.Run("invalid alert type", func(t *testing.T) {
+		c := &Config{NodeUnavailablePolicies: map[string]string{"abc": NodeUnavailableProcess}}
+		err := requireNodePolicies(c)
+		require.ErrorIs(t, err, ErrInvalidNodePolicy)
+		require.ErrorIs(t, err, ErrUnknownAlertTypeName)
+	})
+}
+
+// TestRequireDisabledAlertTypes will test the method requireDisabledAlertTypes()
+func TestRequireDisabledAlertTypes(t *testing.T) {
+	t.Run("valid types", func(t *testing.T) {
+		c := &Config{DisabledAlertTypes: []uint32{2, 4}}
+		require.NoError(t, requireDisabledAlertTypes(c))
+	})
+
+	t.Run("set keys", func(t *testing.T) {
+		c := &Config{DisabledAlertTypes: []uint32{4, alertTypeSetKeys}}
+		require.ErrorIs(t, requireDisabledAlertTypes(c), ErrCannotDisableSetKeys)
+	})
+}
+
+// TestRequireDatastorePool will test the method requireDatastorePool()
+func TestRequireDatastorePool(t *testing.T) {
+	// pool will return a SQL connection config with the pool settings
+	pool := func(open, idle int, lifetime time.Duration) *datastore.SQLConfig {
+		return &datastore.SQLConfig{CommonConfig: datastore.CommonConfig{
+			MaxOpenConnections: open,
+			MaxIdleConnections: idle,
+			MaxConnectionTime:  lifetime,
+		}}
+	}
+
+	t.Run("valid pools", func(t *testing.T) {
+		c := &Config{Datastore: DatastoreConfig{Engine: datastore.PostgreSQL, SQLRead: pool(5, 2, time.Minute), SQLWrite: pool(5, 5, time.Minute)}}
+		require.NoError(t, requireDatastorePool(c))
+	})
+
+	t.Run("sqlite has no pool", func(t *testing.T) {
+		c := &Config{Datastore: DatastoreConfig{Engine: datastore.SQLite, SQLRead: pool(0, 0, 0)}}
+		require.NoError(t, requireDatastorePool(c))
+	})
+
+	t.Run("invalid pools", func(t *testing.T) {
+		c := &Config{Datastore: DatastoreConfig{Engine: datastore.MySQL, SQLRead: pool(0, -1, time.Minute), SQLWrite: pool(2, 5, 0)}}
+		err := requireDatastorePool(c)
+		require.ErrorIs(t, err, ErrInvalidDatastorePool)
+		assert.Contains(t, err.Error(), "sql_read max_open_connections is 0")
+		assert.Contains(t, err.Error(), "sql_read max_idle_connections is -1")
+		assert.Contains(t, err.Error(), "sql_write max_idle_connections 5 is more than max_open_connections 2")
+		assert.Contains(t, err.Error(), "sql_write max_connection_time is 0s")
+	})
+}
+
+// testGenesisKeys are valid compressed public keys for the genesis key tests
+var testGenesisKeys = []string{
+	"027276d234a138415c7d8d61e33ea9c625f0d043fd06f1c863464a58ed7939afe1",
+	"0254b81f2e1bed83e414970ae7f7e3373014706251efb6990b5292a020e3a1585c",
+	"03801e7b4077edad7ebb3fa87ced7b126ae8eb2fbcb75821001f84a0374eea4a21",
+}
+
+// TestRequireGenesisKeys will test the method requireGenesisKeys()
+func TestRequireGenesisKeys(t *testing.T) {
+	t.Run("valid keys are normalized", func(t *testing.T) {
+		c := &Config{GenesisKeys: []string{" " + strings.ToUpper(testGenesisKeys[0]) + " ", testGenesisKeys[1]}}
+		require.NoError(t, requireGenesisKeys(c))
+		assert.Equal(t, testGenesisKeys[:2], c.GenesisKeys)
+	})
+
+	t.Run("keys file is added to the list", func(t *testing.T) {
+		path := writeTestConfigFile(t, "genesis.keys", "# genesis key set\n"+testGenesisKeys[1]+"\n\n"+testGenesisKeys[2]+" # third\n")
+		c := &Config{GenesisKeys: testGenesisKeys[:1], GenesisKeysFile: path}
+		require.NoError(t, requireGenesisKeys(c))
+		assert.Equal(t, testGenesisKeys, c.GenesisKeys)
+	})
+
+	t.Run("keys file alone", func(t *testing.T) {
+		path := writeTestConfigFile(t, "genesis.keys", strings.Join(testGenesisKeys, "\n"))
+		c := &Config{GenesisKeysFile: path}
+		require.NoError(t, requireGenesisKeys(c))
+		assert.Equal(t, testGenesisKeys, c.GenesisKeys)
+	})
+
+	t.Run("missing keys file", func(t *testing.T) {
+		c := &Config{GenesisKeys: testGenesisKeys, GenesisKeysFile: filepath.Join(t.TempDir(), "missing.keys")}
+		require.ErrorIs(t, requireGenesisKeys(c), fs.ErrNotExist)
+	})
+
+	t.Run("empty set", func(t *testing.T) {
+		require.ErrorIs(t, requireGenesisKeys(&Config{}), ErrNoGenesisKeys)
+
+		path := writeTestConfigFile(t, "genesis.keys", "# no keys yet\n")
+		require.ErrorIs(t, requireGenesisKeys(&Config{GenesisKeysFile: path}), ErrNoGenesisKeys)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, key := range []string{
+			"key1",                          // Not hex
+			"04" + testGenesisKeys[0][2:],   // Not compressed
+			testGenesisKeys[0][:64],         // Too short
+			"02" + strings.Repeat("ff", 32), // Not on the curve
+		} {
+			c := &Config{GenesisKeys: []string{testGenesisKeys[0], key}}
+			err := requireGenesisKeys(c)
+			require.ErrorIs(t, err, ErrInvalidPubKeyFormat, key)
+			assert.Contains(t, err.Error(), key)
+		}
+	})
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		c := &Config{GenesisKeys: []string{testGenesisKeys[0], testGenesisKeys[1], strings.ToUpper(testGenesisKeys[0])}}
+		err := requireGenesisKeys(c)
+		require.ErrorIs(t, err, ErrDuplicateGenesisKey)
+		assert.Contains(t, err.Error(), testGenesisKeys[0])
+	})
+}
+
+// TestRequireSignatureThreshold will test the method requireSignatureThreshold()
+func TestRequireSignatureThreshold(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+
+	t.Run("defaults to three", func(t *testing.T) {
+		c := &Config{GenesisKeys: keys}
+		require.NoError(t, requireSignatureThreshold(c))
+		assert.Equal(t, DefaultSignatureThreshold, c.SignatureThreshold)
+	})
+
+	t.Run("all of the keys", func(t *testing.T) {
+		c := &Config{GenesisKeys: keys, SignatureThreshold: 5}
+		require.NoError(t, requireSignatureThreshold(c))
+		assert.Equal(t, 5, c.SignatureThreshold)
+	})
+
+	t.Run("more than the keys", func(t *testing.T) {
+		c := &Config{GenesisKeys: keys, SignatureThreshold: 6}
+		require.ErrorIs(t, requireSignatureThreshold(c), ErrSignatureThresholdTooHigh)
+	})
+}
+
+// TestRequireInfoMessageEncoding will test the method requireInfoMessageEncoding()
+func TestRequireInfoMessageEncoding(t *testing.T) {
+	t.Run("defaults to base64", func(t *testing.T) {
+		c := &Config{}
+		require.NoError(t, requireInfoMessageEncoding(c))
+		assert.Equal(t, InfoMessageEncodingBase64, c.InfoMessageEncoding)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		c := &Config{InfoMessageEncoding: InfoMessageEncodingStrict}
+		require.NoError(t, requireInfoMessageEncoding(c))
+		assert.Equal(t, InfoMessageEncodingStrict, c.InfoMessageEncoding)
+	})
+
+	t.Run("invalid encoding", func(t *testing.T) {
+		c := &Config{InfoMessageEncoding: "hex"}
+		require.ErrorIs(t, requireInfoMessageEncoding(c), ErrInvalidInfoMessageEncoding)
+	})
+}
+
+// TestRequireLogFormat will test the method requireLogFormat()
+func TestRequireLogFormat(t *testing.T) {
+	t.Run("defaults to text", func(t *testing.T) {
+		c := &Config{}
+		require.NoError(t, requireLogFormat(c))
+		assert.Equal(t, LogFormatText, c.LogFormat)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		c := &Config{LogFormat: LogFormatJSON}
+		require.NoError(t, requireLogFormat(c))
+		assert.Equal(t, LogFormatJSON, c.LogFormat)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		c := &Config{LogFormat: "xml"}
+		require.ErrorIs(t, requireLogFormat(c), ErrInvalidLogFormat)
+	})
+}
+
+// TestRequireCORS will test the method requireCORS()
+func TestRequireCORS(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c := &Config{}
+		require.NoError(t, requireCORS(c))
+		assert.Equal(t, DefaultCORSAllowedMethods, c.WebServer.CORS.AllowedMethods)
+		assert.Equal(t, DefaultCORSAllowedHeaders, c.WebServer.CORS.AllowedHeaders)
+	})
+
+	t.Run("valid origins and methods", func(t *testing.T) {
+		c := &Config{WebServer: WebServerConfig{CORS: CORSConfig{
+			AllowedMethods: []string{"get"},
+			AllowedOrigins: []string{"*", "https://dashboard.example.com", "http://localhost:8080", "https://*.example.com"},
+		}}}
+		require.NoError(t, requireCORS(c))
+		assert.Equal(t, []string{"GET"}, c.WebServer.CORS.AllowedMethods)
+	})
+
+	t.Run("invalid origins", func(t *testing.T) {
+		for _, origin := range []string{"example.com", "ftp://example.com", "https://example.com/path", "https://a.*.example.com", "https://", ""} {
+			c := &Config{WebServer: WebServerConfig{CORS: CORSConfig{AllowedOrigins: []string{origin}}}}
+			require.ErrorIs(t, requireCORS(c), ErrInvalidCORSOrigin, origin)
+		}
+	})
+
+	t.Run("invalid method", func(t *testing.T) {
+		c := &Config{WebServer: WebServerConfig{CORS: CORSConfig{AllowedMethods: []string{"FETCH"}}}}
+		require.ErrorIs(t, requireCORS(c), ErrInvalidCORSMethod)
+	})
+}
+
+// TestRequireWebhookFormat will test the method requireWebhookFormat()
+func TestRequireWebhookFormat(t *testing.T) {
+	t.Run("defaults to raw", func(t *testing.T) {
+		c := &Config{}
+		require.NoError(t, requireWebhookFormat(c))
+		assert.Equal(t, WebhookFormatRaw, c.Webhook.Format)
+	})
+
+	t.Run("slack", func(t *testing.T) {
+		c := &Config{Webhook: WebhookConfig{Format: WebhookFormatSlack}}
+		require.NoError(t, requireWebhookFormat(c))
+		assert.Equal(t, WebhookFormatSlack, c.Webhook.Format)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		c := &Config{Webhook: WebhookConfig{Format: "teams"}}
+		require.ErrorIs(t, requireWebhookFormat(c), ErrInvalidWebhookFormat)
+	})
+}
+
+// testYAMLConfig is a minimal YAML config file with the required values
+const testYAMLConfig = `
+genesis_keys:
+  - 027276d234a138415c7d8d61e33ea9c625f0d043fd06f1c863464a58ed7939afe1
+  - 0254b81f2e1bed83e414970ae7f7e3373014706251efb6990b5292a020e3a1585c
+  - 03801e7b4077edad7ebb3fa87ced7b126ae8eb2fbcb75821001f84a0374eea4a21
+p2p:
+  ip: 192.168.1.1
+  port: "8000"
+  private_key_path: /path/to/private/key
+`
+
+// writeTestConfigFile will write the config file to a temp directory and return its path
+func writeTestConfigFile(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+// TestLoadConfigFile_Path tests the method LoadConfigFile() with a config file path
+func TestLoadConfigFile_Path(t *testing.T) {
+	require.NoError(t, os.Setenv(EnvironmentKey, EnvironmentTest))
+
+	t.Run("yaml file", func(t *testing.T) {
+		path := writeTestConfigFile(t, "config.yaml", testYAMLConfig+`
+rpc_connections:
+  - host: http://localhost:8332
+    password: galt
+    user: galt
+`)
+		c, err := LoadConfigFile(path)
+		require.NoError(t, err)
+		require.NotNil(t, c)
+
+		assert.Len(t, c.GenesisKeys, 3)
+		assert.Equal(t, "192.168.1.1", c.P2P.IP)
+		assert.Equal(t, "8000", c.P2P.Port)
+		require.Len(t, c.RPCConnections, 1)
+		assert.Equal(t, "http://localhost:8332", c.RPCConnections[0].Host)
+		assert.Equal(t, DefaultTopicName, c.P2P.TopicName)
+	})
+
+	t.Run("json file", func(t *testing.T) {
+		c, err := LoadConfigFile("envs/test.json")
+		require.NoError(t, err)
+		require.NotNil(t, c)
+		assert.Equal(t, "8000", c.P2P.Port)
+	})
+
+	t.Run("bitcoin.conf rpc credentials", func(t *testing.T) {
+		conf := writeTestConfigFile(t, "bitcoin.conf", "rpcconnect=127.0.0.1\nrpcport=18332\nrpcuser=user\nrpcpassword=pass\n")
+		path := writeTestConfigFile(t, "config.yml", testYAMLConfig+"bitcoin_config_path: "+conf+"\n")
+
+		c, err := LoadConfigFile(path)
+		require.NoError(t, err)
+		require.Len(t, c.RPCConnections, 1)
+		assert.Equal(t, RPCConfig{Host: "http://127.0.0.1:18332", Password: "pass", User: "user"}, c.RPCConnections[0])
+	})
+
+	t.Run("missing rpc connections", func(t *testing.T) {
+		c, err := LoadConfigFile(writeTestConfigFile(t, "config.yaml", testYAMLConfig))
+		require.ErrorIs(t, err, ErrNoRPCConnections)
+		assert.Nil(t, c)
+	})
+
+	t.Run("missing genesis keys", func(t *testing.T) {
+		c, err := LoadConfigFile(writeTestConfigFile(t, "config.yaml", `
+p2p:
+  ip: 192.168.1.1
+  port: "8000"
+rpc_connections:
+  - host: http://localhost:8332
+`))
+		require.ErrorIs(t, err, ErrNoGenesisKeys)
+		assert.Nil(t, c)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		c, err := LoadConfigFile(writeTestConfigFile(t, "config.toml", "genesis_keys = []"))
+		require.ErrorIs(t, err, ErrUnsupportedConfigFormat)
+		assert.Nil(t, c)
+	})
+}
+
+// TestParseBitcoinConfig tests the method parseBitcoinConfig()
+func TestParseBitcoinConfig(t *testing.T) {
+	t.Run("comments and whitespace", func(t *testing.T) {
+		values, err := parseBitcoinConfig(strings.NewReader("# rpc settings\n  rpcuser = bitcoin  \n\n\t# rpcport=1\nrpcconnect=127.0.0.1\n"), BitcoinNetworkMain)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"rpcconnect": "127.0.0.1", "rpcuser": "bitcoin"}, values)
+	})
+
+	t.Run("crlf line endings", func(t *testing.T) {
+		values, err := parseBitcoinConfig(strings.NewReader("rpcuser=bitcoin\r\nrpcpassword=secret\r\n"), BitcoinNetworkMain)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"rpcpassword": "secret", "rpcuser": "bitcoin"}, values)
+	})
+
+	t.Run("values containing =", func(t *testing.T) {
+		values, err := parseBitcoinConfig(strings.NewReader("rpcpassword=a=b\nrpcauth==c\n=value\nnodelimiter\n"), BitcoinNetworkMain)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"rpcauth": "=c", "rpcpassword": "a=b"}, values)
+	})
+}
+
+// TestParseBitcoinConfig_Sections tests the network sections of the method parseBitcoinConfig()
+func TestParseBitcoinConfig_Sections(t *testing.T) {
+	conf := `rpcuser=global
+rpcpassword=global
+main.rpcport=8332
+test.rpcport=18332
+
+[test]
+rpcuser=testuser
+
+[regtest]
+rpcuser=regtestuser
+rpcport=18443
+`
+
+	t.Run("section header and prefix override the global section", func(t *testing.T) {
+		values, err := parseBitcoinConfig(strings.NewReader(conf), BitcoinNetworkTest)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"rpcpassword": "global", "rpcport": "18332", "rpcuser": "testuser"}, values)
+	})
+
+	t.Run("main network", func(t *testing.T) {
+		values, err := parseBitcoinConfig(strings.NewReader(conf), BitcoinNetworkMain)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"rpcpassword": "global", "rpcport": "8332", "rpcuser": "global"}, values)
+	})
+
+	t.Run("falls back to the global section", func(t *testing.T) {
+		values, err := parseBitcoinConfig(strings.NewReader(conf), BitcoinNetworkSTN)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"rpcpassword": "global", "rpcuser": "global"}, values)
+	})
+}
+
+// TestRequireBitcoinNetwork tests the method requireBitcoinNetwork()
+func TestRequireBitcoinNetwork(t *testing.T) {
+	t.Run("defaults to main", func(t *testing.T) {
+		c := &Config{}
+		require.NoError(t, requireBitcoinNetwork(c))
+		assert.Equal(t, BitcoinNetworkMain, c.BitcoinNetwork)
+	})
+
+	t.Run("regtest", func(t *testing.T) {
+		c := &Config{BitcoinNetwork: BitcoinNetworkRegtest}
+		require.NoError(t, requireBitcoinNetwork(c))
+		assert.Equal(t, BitcoinNetworkRegtest, c.BitcoinNetwork)
+	})
+
+	t.Run("invalid network", func(t *testing.T) {
+		require.ErrorIs(t, requireBitcoinNetwork(&Config{BitcoinNetwork: "testnet"}), ErrInvalidBitcoinNetwork)
+	})
+}
+
+// TestParseRPCURL tests the method parseRPCURL()
+func TestParseRPCURL(t *testing.T) {
+	t.Run("valid urls", func(t *testing.T) {
+		tests := []struct {
+			rawURL, scheme, host, port string
+		}{
+			{rawURL: "http://localhost:8332", scheme: "http", host: "localhost", port: "8332"},
+			{rawURL: "https://node.example.com:18332", scheme: "https", host: "node.example.com", port: "18332"},
+			{rawURL: "http://[::1]:8332", scheme: "http", host: "::1", port: "8332"},
+		}
+		for _, tt := range tests {
+			scheme, host, port, err := parseRPCURL(tt.rawURL)
+			require.NoError(t, err, tt.rawURL)
+			assert.Equal(t, tt.scheme, scheme)
+			assert.Equal(t, tt.host, host)
+			assert.Equal(t, tt.port, port)
+		}
+	})
+
+	t.Run("invalid urls", func(t *testing.T) {
+		for _, rawURL := range []string{
+			"", "localhost:8332", "ftp://localhost:8332", "http://:8332", "http://localhost",
+			"http://localhost:not-a-port", "http://localhost:0", "http://localhost:65536", "http://::1:8332",
+		} {
+			_, _, _, err := parseRPCURL(rawURL)
+			require.ErrorIs(t, err, ErrInvalidRPCURL, rawURL)
+		}
+	})
+}
+
+// TestLoadBitcoinConfiguration tests the method loadBitcoinConfiguration()
+func TestLoadBitcoinConfiguration(t *testing.T) {
+	newConfig := func(t *testing.T, conf, host string) *Config {
+		return &Config{
+			BitcoinConfigPath: writeTestConfigFile(t, "bitcoin.conf", conf),
+			BitcoinNetwork:    BitcoinNetworkMain,
+			RPCConnections:    []RPCConfig{{Host: host}},
+			Services:          Services{Log: &ExtendedLogger{Logger: log.New(io.Discard, "", 0)}},
+		}
+	}
+
+	t.Run("ipv6 default host", func(t *testing.T) {
+		c := newConfig(t, "rpcuser=user\nrpcpassword=pass\n", "https://[::1]:8332")
+		require.NoError(t, c.loadBitcoinConfiguration())
+		assert.Equal(t, "https://[::1]:8332", c.RPCConnections[0].Host)
+	})
+
+	t.Run("out of range rpcport", func(t *testing.T) {
+		c := newConfig(t, "rpcuser=user\nrpcpassword=pass\nrpcport=70000\n", "http://localhost:8332")
+		require.ErrorIs(t, c.loadBitcoinConfiguration(), ErrInvalidRPCURL)
+	})
+
+	t.Run("malformed default host", func(t *testing.T) {
+		c := newConfig(t, "rpcuser=user\nrpcpassword=pass\n", "localhost:8332")
+		require.ErrorIs(t, c.loadBitcoinConfiguration(), ErrInvalidRPCURL)
+	})
+}
+
+// TestConfig_Validate tests the method Validate()
+func TestConfig_Validate(t *testing.T) {
+	t.Run("valid config", func(t *testing.T) {
+		c := &Config{
+			GenesisKeys:    testGenesisKeys,
+			P2P:            P2PConfig{IP: "192.168.1.1", Port: "8000", PrivateKeyPath: "/path/to/private/key"},
+			RPCConnections: []RPCConfig{{Host: "http://localhost:8332", User: "user"}},
+		}
+		require.NoError(t, c.Validate())
+		assert.Equal(t, BitcoinNetworkMain, c.BitcoinNetwork)
+		assert.Equal(t, DefaultTopicName, c.P2P.TopicName)
+	})
+
+	t.Run("every problem is returned", func(t *testing.T) {
+		c := &Config{
+			BitcoinNetwork: "testnet",
+			P2P:            P2PConfig{PrivateKeyPath: "/path/to/private/key"},
+			RPCConnections: []RPCConfig{{Host: "localhost:8332"}, {}},
+			Webhook:        WebhookConfig{Format: "xml"},
+		}
+		err := c.Validate()
+		for _, expected := range []error{
+			ErrInvalidBitcoinNetwork, ErrInvalidRPCURL, ErrNoRPCHost, ErrNoRPCUser, ErrNoGenesisKeys,
+			ErrInvalidWebhookFormat, ErrNoP2PIP, ErrNoP2PPort,
+		} {
+			require.ErrorIs(t, err, expected)
+		}
+		assert.NotErrorIs(t, err, ErrSignatureThresholdTooHigh)
+	})
+
+	t.Run("no rpc connections", func(t *testing.T) {
+		require.ErrorIs(t, (&Config{}).Validate(), ErrNoRPCConnections)
+	})
+}
@@ -1,11 +1,16 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"time"
 )
 
+// LogFields are the structured fields of a log line, written alongside the message in the JSON format
+type LogFields map[string]interface{}
+
 // LoggerInterface is the interface for the logger
 // This is used to allow the logger to be mocked and tested
 // These methods are the same as the gocore.Logger methods
@@ -19,6 +24,7 @@ type LoggerInterface interface {
 	Fatalf(msg string, args ...interface{})
 	Info(args ...interface{})
 	Infof(msg string, args ...interface{})
+	InfoFields(fields LogFields, msg string, args ...interface{})
 	LogLevel() string
 	Panic(args ...interface{})
 	Panicf(msg string, args ...interface{})
@@ -33,6 +39,7 @@ type LoggerInterface interface {
 type ExtendedLogger struct {
 	*log.Logger
 
+	format   string // LogFormatText or LogFormatJSON
 	logLevel string
 	writer   *os.File
 }
@@ -44,6 +51,10 @@ func (es *ExtendedLogger) CloseWriter() error {
 
 // Printf will print the log message to the console
 func (es *ExtendedLogger) Printf(format string, v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("info", fmt.Sprintf(format, v...), nil)
+		return
+	}
 	es.Logger.Printf(format, v...)
 }
 
@@ -52,6 +63,10 @@ func (es *ExtendedLogger) Debugf(format string, v ...interface{}) {
 	if es.logLevel != "debug" {
 		return
 	}
+	if es.isJSON() {
+		es.writeJSON("debug", fmt.Sprintf(format, v...), nil)
+		return
+	}
 	es.Logger.Printf(fmt.Sprintf("\033[1;34m| DEBUG | %s\033[0m", format), v...)
 }
 
@@ -60,31 +75,55 @@ func (es *ExtendedLogger) Debug(v ...interface{}) {
 	if es.logLevel != "debug" {
 		return
 	}
+	if es.isJSON() {
+		es.writeJSON("debug", fmt.Sprint(v...), nil)
+		return
+	}
 	es.Logger.Printf("%v", v...)
 }
 
 // Error will print debug messages to the console
 func (es *ExtendedLogger) Error(v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("error", fmt.Sprint(v...), nil)
+		return
+	}
 	es.Logger.Printf("%v", v...)
 }
 
 // Errorf will print debug messages to the console
 func (es *ExtendedLogger) Errorf(format string, v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("error", fmt.Sprintf(format, v...), nil)
+		return
+	}
 	es.Logger.Printf(fmt.Sprintf("\033[1;31m| ERROR |: %s\033[0m", format), v...)
 }
 
 // ErrorWithStack will print debug messages to the console
 func (es *ExtendedLogger) ErrorWithStack(format string, v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("error", fmt.Sprintf(format, v...), nil)
+		return
+	}
 	es.Logger.Printf(format, v...)
 }
 
 // Info will print info messages to the console
 func (es *ExtendedLogger) Info(v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("info", fmt.Sprint(v...), nil)
+		return
+	}
 	es.Logger.Printf("%v", v...)
 }
 
 // Infof will print info messages to the console
 func (es *ExtendedLogger) Infof(format string, v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("info", fmt.Sprintf(format, v...), nil)
+		return
+	}
 	es.Logger.Printf(fmt.Sprintf("\033[1;32m| INFO  | %s\033[0m", format), v...)
 }
 
@@ -95,10 +134,51 @@ func (es *ExtendedLogger) LogLevel() string {
 
 // Warn will print warning messages to the console
 func (es *ExtendedLogger) Warn(v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("warn", fmt.Sprint(v...), nil)
+		return
+	}
 	es.Logger.Printf("%v", v...)
 }
 
 // Warnf will print warning messages to the console
 func (es *ExtendedLogger) Warnf(format string, v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("warn", fmt.Sprintf(format, v...), nil)
+		return
+	}
 	es.Logger.Printf(format, v...)
 }
+
+// InfoFields will print an info message with structured fields
+// The text format is the same as Infof, the fields are only written in the JSON format
+func (es *ExtendedLogger) InfoFields(fields LogFields, format string, v ...interface{}) {
+	if es.isJSON() {
+		es.writeJSON("info", fmt.Sprintf(format, v...), fields)
+		return
+	}
+	es.Infof(format, v...)
+}
+
+// isJSON returns true if the log lines are written as JSON
+func (es *ExtendedLogger) isJSON() bool {
+	return es.format == LogFormatJSON
+}
+
+// writeJSON will write the message and its fields as a single JSON line
+func (es *ExtendedLogger) writeJSON(level, msg string, fields LogFields) {
+	line := make(LogFields, len(fields)+3)
+	for key, value := range fields {
+		line[key] = value
+	}
+	line["level"] = level
+	line["msg"] = msg
+	line["time"] = time.Now().UTC().Format(time.RFC3339)
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		// A field that can't be marshaled shouldn't lose the message
+		data, _ = json.Marshal(LogFields{"level": level, "msg": msg, "time": line["time"]})
+	}
+	es.Logger.Print(string(data))
+}
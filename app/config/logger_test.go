@@ -0,0 +1,70 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger will return a logger writing to the buffer in the given format
+func newTestLogger(buf *bytes.Buffer, format string) *ExtendedLogger {
+	return &ExtendedLogger{Logger: log.New(buf, "", 0), format: format, logLevel: "info"}
+}
+
+// TestExtendedLogger_InfoFields will test the method InfoFields()
+func TestExtendedLogger_InfoFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("json format", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		newTestLogger(buf, LogFormatJSON).InfoFields(LogFields{
+			"alert_type":        "confiscate_transaction",
+			"enforce_at_height": 100,
+			"sequence":          7,
+		}, "alert [%d]", 7)
+
+		line := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		assert.Equal(t, "info", line["level"])
+		assert.Equal(t, "alert [7]", line["msg"])
+		assert.Equal(t, "confiscate_transaction", line["alert_type"])
+		assert.InDelta(t, 100, line["enforce_at_height"], 0)
+		assert.InDelta(t, 7, line["sequence"], 0)
+		assert.NotEmpty(t, line["time"])
+	})
+
+	t.Run("text format is the same as Infof", func(t *testing.T) {
+		fields, infof := &bytes.Buffer{}, &bytes.Buffer{}
+		newTestLogger(fields, LogFormatText).InfoFields(LogFields{"sequence": 7}, "alert [%d]", 7)
+		newTestLogger(infof, LogFormatText).Infof("alert [%d]", 7)
+		assert.Equal(t, infof.String(), fields.String())
+		assert.NotContains(t, fields.String(), "sequence")
+	})
+}
+
+// TestExtendedLogger_JSON will test that the other log methods write JSON lines in the json format
+func TestExtendedLogger_JSON(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := newTestLogger(buf, LogFormatJSON)
+	logger.Errorf("failed [%s]", "rpc")
+	logger.Debugf("skipped below the log level")
+	logger.Warn("warning")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	line := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(lines[0], &line))
+	assert.Equal(t, "error", line["level"])
+	assert.Equal(t, "failed [rpc]", line["msg"])
+
+	require.NoError(t, json.Unmarshal(lines[1], &line))
+	assert.Equal(t, "warn", line["level"])
+	assert.Equal(t, "warning", line["msg"])
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/mrz1836/go-datastore"
+	"gorm.io/gorm"
+)
+
+// migrationCallback is the gorm callback that records (and on a dry run, skips) the statements of a migration
+const migrationCallback = "alert_system:migration"
+
+type (
+	// migrationKey is the context key of the migration recorder, only statements run with it in their context are recorded
+	migrationKey struct{}
+
+	// migrationRecorder collects the DDL statements of a migration
+	migrationRecorder struct {
+		dryRun     bool
+		statements []string
+	}
+
+	// migrationConnPool records the statements executed through it, and only runs them if it's not a dry run
+	// Queries go to the database either way, so the migrator still sees the current schema
+	migrationConnPool struct {
+		gorm.ConnPool
+		dialector gorm.Dialector
+		recorder  *migrationRecorder
+	}
+)
+
+// ExecContext records the statement, running it unless it's a dry run
+func (p *migrationConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if p.recorder.dryRun {
+		p.recorder.statements = append(p.recorder.statements, p.dialector.Explain(query, args...))
+		return driver.RowsAffected(0), nil
+	}
+	result, err := p.ConnPool.ExecContext(ctx, query, args...)
+	if err == nil {
+		p.recorder.statements = append(p.recorder.statements, p.dialector.Explain(query, args...))
+	}
+	return result, err
+}
+
+// recordMigration will route the statement through the migration recorder, if it's part of a migration
+func recordMigration(db *gorm.DB) {
+	if db.Statement.Context == nil {
+		return
+	}
+	recorder, ok := db.Statement.Context.Value(migrationKey{}).(*migrationRecorder)
+	if !ok {
+		return
+	}
+	db.Statement.ConnPool = &migrationConnPool{
+		ConnPool:  db.Statement.ConnPool,
+		dialector: db.Dialector,
+		recorder:  recorder,
+	}
+}
+
+// migrateModels will auto migrate the models, returning the DDL statements that ran
+//
+// On a dry run nothing is changed, the statements that would have run are returned instead. The migrator still reads
+// the current schema, so only the missing tables, columns and indexes (and changed columns) are reported
+func migrateModels(ctx context.Context, client datastore.ClientInterface, dryRun bool, models ...interface{}) ([]string, error) {
+	if !datastore.IsSQLEngine(client.Engine()) {
+		return nil, ErrDatastoreUnsupported
+	}
+
+	db := client.Raw("")
+	if db.Callback().Raw().Get(migrationCallback) == nil {
+		if err := db.Callback().Raw().Before("gorm:raw").Register(migrationCallback, recordMigration); err != nil {
+			return nil, err
+		}
+	}
+
+	recorder := &migrationRecorder{dryRun: dryRun}
+	session := db.Session(&gorm.Session{
+		Context:   context.WithValue(ctx, migrationKey{}, recorder),
+		NewDB:     true,
+		SkipHooks: true,
+	})
+	if client.Engine() == datastore.MySQL {
+		session = session.Set("gorm:table_options", "ENGINE=InnoDB")
+	}
+	err := session.AutoMigrate(models...)
+	return recorder.statements, err
+}
+
+// migrateDatastore will run the auto migration of the models (or its dry run), logging the statements
+func (c *Config) migrateDatastore(ctx context.Context, models []interface{}) error {
+	dryRun := c.Datastore.MigrationDryRun
+	statements, err := migrateModels(ctx, c.Services.Datastore, dryRun, models...)
+	if c.Services.Log == nil {
+		return err
+	}
+
+	action := "ran"
+	if dryRun {
+		action = "would run"
+	}
+	for _, statement := range statements {
+		c.Services.Log.Infof("datastore migration %s: %s", action, statement)
+	}
+	if err != nil {
+		c.Services.Log.Errorf("datastore migration failed after %d statements: %s", len(statements), err.Error())
+	} else if len(statements) == 0 {
+		c.Services.Log.Infof("datastore migration: the schema is up to date")
+	} else {
+		c.Services.Log.Infof("datastore migration %s %d statements", action, len(statements))
+	}
+	return err
+}
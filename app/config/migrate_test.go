@@ -0,0 +1,120 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/mrz1836/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrationModel is a model to migrate in the tests
+type migrationModel struct {
+	ID   uint64 `gorm:"primaryKey"`
+	Name string `gorm:"index"`
+}
+
+// TableName is the table of the model
+func (migrationModel) TableName() string {
+	return "migration_models"
+}
+
+// migrationModelV2 is migrationModel with a new column
+type migrationModelV2 struct {
+	ID    uint64 `gorm:"primaryKey"`
+	Name  string `gorm:"index"`
+	Notes string
+}
+
+// TableName is the table of the model
+func (migrationModelV2) TableName() string {
+	return "migration_models"
+}
+
+// newMigrationConfig returns a config with an in memory sqlite datastore, logging to the buffer
+func newMigrationConfig(t *testing.T, logs *bytes.Buffer) *Config {
+	c := &Config{
+		Datastore: DatastoreConfig{Engine: datastore.SQLite},
+		Services:  Services{Log: &ExtendedLogger{Logger: log.New(logs, "", 0)}},
+	}
+	require.NoError(t, c.loadDatastore(context.Background(), nil))
+	t.Cleanup(func() { c.CloseAll(context.Background()) })
+	return c
+}
+
+// TestMigrateModels will test the method migrateModels()
+func TestMigrateModels(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("a dry run reports the statements without running them", func(t *testing.T) {
+		c := newMigrationConfig(t, &bytes.Buffer{})
+		statements, err := migrateModels(ctx, c.Services.Datastore, true, &migrationModel{})
+		require.NoError(t, err)
+		require.NotEmpty(t, statements)
+		assert.Contains(t, statements[0], "CREATE TABLE `migration_models`")
+		assert.False(t, c.Services.Datastore.Raw("").Migrator().HasTable("migration_models"))
+
+		// Nothing was run, so the same statements are reported again
+		again, err := migrateModels(ctx, c.Services.Datastore, true, &migrationModel{})
+		require.NoError(t, err)
+		assert.Equal(t, statements, again)
+	})
+
+	t.Run("a migration reports the statements it ran", func(t *testing.T) {
+		c := newMigrationConfig(t, &bytes.Buffer{})
+		statements, err := migrateModels(ctx, c.Services.Datastore, false, &migrationModel{})
+		require.NoError(t, err)
+		require.NotEmpty(t, statements)
+		assert.Contains(t, statements[0], "CREATE TABLE `migration_models`")
+		assert.True(t, c.Services.Datastore.Raw("").Migrator().HasTable("migration_models"))
+
+		// The schema is up to date
+		statements, err = migrateModels(ctx, c.Services.Datastore, false, &migrationModel{})
+		require.NoError(t, err)
+		assert.Empty(t, statements)
+	})
+
+	t.Run("a dry run only reports what's missing", func(t *testing.T) {
+		c := newMigrationConfig(t, &bytes.Buffer{})
+		_, err := migrateModels(ctx, c.Services.Datastore, false, &migrationModel{})
+		require.NoError(t, err)
+
+		statements, err := migrateModels(ctx, c.Services.Datastore, true, &migrationModelV2{})
+		require.NoError(t, err)
+		require.Len(t, statements, 1)
+		assert.Contains(t, statements[0], "ALTER TABLE `migration_models` ADD `notes`")
+		assert.False(t, c.Services.Datastore.Raw("").Migrator().HasColumn(&migrationModelV2{}, "notes"))
+	})
+}
+
+// TestConfig_MigrateDatastore will test the method migrateDatastore()
+func TestConfig_MigrateDatastore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("a dry run logs the statements it would run", func(t *testing.T) {
+		logs := &bytes.Buffer{}
+		c := newMigrationConfig(t, logs)
+		c.Datastore.MigrationDryRun = true
+		require.NoError(t, c.migrateDatastore(ctx, []interface{}{&migrationModel{}}))
+		assert.Contains(t, logs.String(), "datastore migration would run: CREATE TABLE `migration_models`")
+		assert.False(t, c.Services.Datastore.Raw("").Migrator().HasTable("migration_models"))
+	})
+
+	t.Run("a migration logs the statements it ran", func(t *testing.T) {
+		logs := &bytes.Buffer{}
+		c := newMigrationConfig(t, logs)
+		require.NoError(t, c.migrateDatastore(ctx, []interface{}{&migrationModel{}}))
+		assert.Contains(t, logs.String(), "datastore migration ran: CREATE TABLE `migration_models`")
+
+		logs.Reset()
+		require.NoError(t, c.migrateDatastore(ctx, []interface{}{&migrationModel{}}))
+		assert.Contains(t, logs.String(), "the schema is up to date")
+	})
+}
@@ -12,9 +12,9 @@ import (
 // TestBanPeer tests the BanPeer method
 func TestBanPeer(t *testing.T) {
 	mockNode := &mocks.Node{
-		BanPeerFunc: func(_ context.Context, peer string) error {
+		BanPeerFunc: func(_ context.Context, peer string, banTime uint64) error {
 			// Mock behavior here
-			if peer == "expected_peer_address" {
+			if peer == "expected_peer_address" && banTime == 3600 {
 				return nil
 			}
 			return ErrUnexpectedPeerAddress
@@ -22,7 +22,7 @@ func TestBanPeer(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	err := mockNode.BanPeer(ctx, "expected_peer_address")
+	err := mockNode.BanPeer(ctx, "expected_peer_address", 3600)
 	require.NoError(t, err)
 }
 
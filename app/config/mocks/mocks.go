@@ -15,8 +15,9 @@ type Node struct {
 	RPCUser     string
 
 	// Functions
-	BanPeerFunc                               func(ctx context.Context, peer string) error
+	BanPeerFunc                               func(ctx context.Context, peer string, banTime uint64) error
 	BestBlockHashFunc                         func(ctx context.Context) (string, error)
+	BlockCountFunc                            func(ctx context.Context) (uint32, error)
 	InvalidateBlockFunc                       func(ctx context.Context, hash string) error
 	UnbanPeerFunc                             func(ctx context.Context, peer string) error
 	AddToConsensusBlacklistFunc               func(ctx context.Context, funds []models.Fund) (*models.AddToConsensusBlacklistResponse, error)
@@ -40,9 +41,9 @@ func (n *Node) GetRPCHost() string {
 }
 
 // BanPeer will call the BanPeerFunc if not nil, otherwise return nil
-func (n *Node) BanPeer(ctx context.Context, peer string) error {
+func (n *Node) BanPeer(ctx context.Context, peer string, banTime uint64) error {
 	if n.BanPeerFunc != nil {
-		return n.BanPeerFunc(ctx, peer)
+		return n.BanPeerFunc(ctx, peer, banTime)
 	}
 	// Default behavior if no mock function provided
 	return nil
@@ -56,6 +57,14 @@ func (n *Node) BestBlockHash(ctx context.Context) (string, error) {
 	return "", nil
 }
 
+// BlockCount will call the BlockCountFunc
+func (n *Node) BlockCount(ctx context.Context) (uint32, error) {
+	if n.BlockCountFunc != nil {
+		return n.BlockCountFunc(ctx)
+	}
+	return 0, nil
+}
+
 // InvalidateBlock will call the InvalidateBlockFunc if not nil, otherwise return nil
 func (n *Node) InvalidateBlock(ctx context.Context, hash string) error {
 	if n.InvalidateBlockFunc != nil {
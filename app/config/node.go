@@ -11,8 +11,9 @@ import (
 
 // NodeInterface is the interface for a node
 type NodeInterface interface {
-	BanPeer(ctx context.Context, peer string) error
+	BanPeer(ctx context.Context, peer string, banTime uint64) error
 	BestBlockHash(ctx context.Context) (string, error)
+	BlockCount(ctx context.Context) (uint32, error)
 	GetRPCHost() string
 	GetRPCPassword() string
 	GetRPCUser() string
@@ -61,10 +62,14 @@ func (n *Node) InvalidateBlock(ctx context.Context, hash string) error {
 	return c.InvalidateBlock(ctx, hash)
 }
 
-// BanPeer bans a peer
-func (n *Node) BanPeer(ctx context.Context, peer string) error {
+// BanPeer bans a peer, for banTime seconds or with the node's default ban time if banTime is 0
+func (n *Node) BanPeer(ctx context.Context, peer string, banTime uint64) error {
 	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
-	return c.SetBan(ctx, peer, bn.BanActionAdd, nil)
+	var opts *models.OptsSetBan
+	if banTime > 0 {
+		opts = &models.OptsSetBan{BanTime: banTime}
+	}
+	return c.SetBan(ctx, peer, bn.BanActionAdd, opts)
 }
 
 // BestBlockHash gets the best block hash
@@ -73,6 +78,12 @@ func (n *Node) BestBlockHash(ctx context.Context) (string, error) {
 	return c.BestBlockHash(ctx)
 }
 
+// BlockCount gets the height of the best block
+func (n *Node) BlockCount(ctx context.Context) (uint32, error) {
+	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
+	return c.BlockCount(ctx)
+}
+
 // UnbanPeer unbans a peer
 func (n *Node) UnbanPeer(ctx context.Context, peer string) error {
 	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
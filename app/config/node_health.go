@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NodeHealth caches whether the node answered an RPC ping, so the health checks don't hit the RPC on every request
+// A failed ping is cached too, so a node that is down isn't pinged more often than one that is up
+type NodeHealth struct {
+	sync.Mutex
+	checkedAt   time.Time
+	lastSuccess time.Time
+	node        NodeInterface
+	reachable   bool
+	ttl         time.Duration
+}
+
+// NewNodeHealth creates a new node health check
+func NewNodeHealth(node NodeInterface, ttl time.Duration) *NodeHealth {
+	return &NodeHealth{
+		node: node,
+		ttl:  ttl,
+	}
+}
+
+// Check returns whether the node is reachable and when it last answered, pinging it (getblockcount) once the ttl has passed
+// The time is zero if the node never answered
+func (h *NodeHealth) Check(ctx context.Context) (bool, time.Time) {
+	h.Lock()
+	defer h.Unlock()
+
+	if !h.checkedAt.IsZero() && time.Since(h.checkedAt) < h.ttl {
+		return h.reachable, h.lastSuccess
+	}
+
+	_, err := h.node.BlockCount(ctx)
+	h.checkedAt = time.Now()
+	h.reachable = err == nil
+	if h.reachable {
+		h.lastSuccess = h.checkedAt
+	}
+	return h.reachable, h.lastSuccess
+}
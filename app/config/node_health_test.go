@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+)
+
+// TestNodeHealth_Check will test the method Check()
+func TestNodeHealth_Check(t *testing.T) {
+	t.Run("the result is cached until the ttl passes", func(t *testing.T) {
+		calls := 0
+		var nodeErr error
+		node := &mocks.Node{
+			BlockCountFunc: func(_ context.Context) (uint32, error) {
+				calls++
+				return 800000, nodeErr
+			},
+		}
+		health := NewNodeHealth(node, time.Hour)
+
+		reachable, lastSuccess := health.Check(context.Background())
+		assert.True(t, reachable)
+		assert.False(t, lastSuccess.IsZero())
+
+		// The node goes down, but the cached result is still returned
+		nodeErr = errors.New("node is down")
+		reachable, cached := health.Check(context.Background())
+		assert.True(t, reachable)
+		assert.Equal(t, lastSuccess, cached)
+		assert.Equal(t, 1, calls)
+
+		// Once the ttl passes the node is pinged again, and the last success is kept
+		health.checkedAt = time.Now().Add(-2 * time.Hour)
+		reachable, cached = health.Check(context.Background())
+		assert.False(t, reachable)
+		assert.Equal(t, lastSuccess, cached)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("a node that never answered has no last success", func(t *testing.T) {
+		calls := 0
+		node := &mocks.Node{
+			BlockCountFunc: func(_ context.Context) (uint32, error) {
+				calls++
+				return 0, errors.New("node is down")
+			},
+		}
+		health := NewNodeHealth(node, time.Hour)
+
+		reachable, lastSuccess := health.Check(context.Background())
+		assert.False(t, reachable)
+		assert.True(t, lastSuccess.IsZero())
+
+		// The failure is cached too
+		_, _ = health.Check(context.Background())
+		assert.Equal(t, 1, calls)
+	})
+}
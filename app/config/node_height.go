@@ -0,0 +1,42 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NodeHeightCache caches the block height of the node so repeated checks don't hit the RPC
+type NodeHeightCache struct {
+	sync.Mutex
+	fetchedAt time.Time
+	height    uint32
+	node      NodeInterface
+	ttl       time.Duration
+}
+
+// NewNodeHeightCache creates a new node height cache
+func NewNodeHeightCache(node NodeInterface, ttl time.Duration) *NodeHeightCache {
+	return &NodeHeightCache{
+		node: node,
+		ttl:  ttl,
+	}
+}
+
+// Height returns the cached node height, refreshing it from the node once the ttl has passed
+func (c *NodeHeightCache) Height(ctx context.Context) (uint32, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) < c.ttl {
+		return c.height, nil
+	}
+
+	height, err := c.node.BlockCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	c.height = height
+	c.fetchedAt = time.Now()
+	return c.height, nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+)
+
+// TestNodeHeightCache_Height will test the method Height()
+func TestNodeHeightCache_Height(t *testing.T) {
+	t.Run("height is cached until the ttl passes", func(t *testing.T) {
+		calls := 0
+		node := &mocks.Node{
+			BlockCountFunc: func(_ context.Context) (uint32, error) {
+				calls++
+				return uint32(800000 + calls), nil
+			},
+		}
+		cache := NewNodeHeightCache(node, time.Hour)
+
+		height, err := cache.Height(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint32(800001), height)
+
+		height, err = cache.Height(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint32(800001), height)
+		assert.Equal(t, 1, calls)
+
+		// Expire the cached height
+		cache.fetchedAt = time.Now().Add(-2 * time.Hour)
+		height, err = cache.Height(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint32(800002), height)
+	})
+
+	t.Run("node error is returned and not cached", func(t *testing.T) {
+		errNode := errors.New("node is down")
+		node := &mocks.Node{
+			BlockCountFunc: func(_ context.Context) (uint32, error) {
+				return 0, errNode
+			},
+		}
+		cache := NewNodeHeightCache(node, time.Hour)
+
+		_, err := cache.Height(context.Background())
+		require.ErrorIs(t, err, errNode)
+		assert.True(t, cache.fetchedAt.IsZero())
+	})
+}
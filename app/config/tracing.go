@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TracerName is the instrumentation name of the spans started by the alert system
+const TracerName = "github.com/bsv-blockchain/go-alert-system"
+
+// Tracer will return the tracer for starting spans, a no-op tracer unless a tracer provider is set in the services
+func (c *Config) Tracer() trace.Tracer {
+	if c == nil || c.Services.Tracing == nil {
+		return noop.NewTracerProvider().Tracer(TracerName)
+	}
+	return c.Services.Tracing.Tracer(TracerName)
+}
+
+// EndSpan will record the error (if any) on the span and end it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedNode wraps a node so every RPC call gets a span
+// The tracer is looked up on each call, so a tracer provider set after loading is still used
+type tracedNode struct {
+	NodeInterface
+	config *Config
+}
+
+// NewTracedNode will wrap the node so every RPC call is traced with the config's tracer
+func NewTracedNode(node NodeInterface, c *Config) NodeInterface {
+	return &tracedNode{NodeInterface: node, config: c}
+}
+
+// start will start the span for an RPC call
+func (n *tracedNode) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return n.config.Tracer().Start(ctx, "node."+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		append([]attribute.KeyValue{attribute.String("rpc.method", method)}, attrs...)...,
+	))
+}
+
+// InvalidateBlock invalidates a block
+func (n *tracedNode) InvalidateBlock(ctx context.Context, hash string) (err error) {
+	ctx, span := n.start(ctx, "InvalidateBlock", attribute.String("block.hash", hash))
+	defer func() { EndSpan(span, err) }()
+	return n.NodeInterface.InvalidateBlock(ctx, hash)
+}
+
+// BanPeer bans a peer
+func (n *tracedNode) BanPeer(ctx context.Context, peer string, banTime uint64) (err error) {
+	ctx, span := n.start(ctx, "BanPeer", attribute.String("peer", peer))
+	defer func() { EndSpan(span, err) }()
+	return n.NodeInterface.BanPeer(ctx, peer, banTime)
+}
+
+// BestBlockHash gets the best block hash
+func (n *tracedNode) BestBlockHash(ctx context.Context) (hash string, err error) {
+	ctx, span := n.start(ctx, "BestBlockHash")
+	defer func() { EndSpan(span, err) }()
+	return n.NodeInterface.BestBlockHash(ctx)
+}
+
+// BlockCount gets the height of the best block
+func (n *tracedNode) BlockCount(ctx context.Context) (height uint32, err error) {
+	ctx, span := n.start(ctx, "BlockCount")
+	defer func() { EndSpan(span, err) }()
+	return n.NodeInterface.BlockCount(ctx)
+}
+
+// UnbanPeer unbans a peer
+func (n *tracedNode) UnbanPeer(ctx context.Context, peer string) (err error) {
+	ctx, span := n.start(ctx, "UnbanPeer", attribute.String("peer", peer))
+	defer func() { EndSpan(span, err) }()
+	return n.NodeInterface.UnbanPeer(ctx, peer)
+}
+
+// AddToConsensusBlacklist adds frozen utxos to blacklist (freeze and unfreeze)
+func (n *tracedNode) AddToConsensusBlacklist(ctx context.Context, funds []models.Fund) (res *models.AddToConsensusBlacklistResponse, err error) {
+	ctx, span := n.start(ctx, "AddToConsensusBlacklist", attribute.Int("funds", len(funds)))
+	defer func() { EndSpan(span, err) }()
+	return n.NodeInterface.AddToConsensusBlacklist(ctx, funds)
+}
+
+// AddToConfiscationTransactionWhitelist adds confiscation transactions to the whitelist
+func (n *tracedNode) AddToConfiscationTransactionWhitelist(ctx context.Context, tx []models.ConfiscationTransactionDetails) (res *models.AddToConfiscationTransactionWhitelistResponse, err error) {
+	ctx, span := n.start(ctx, "AddToConfiscationTransactionWhitelist", attribute.Int("transactions", len(tx)))
+	defer func() { EndSpan(span, err) }()
+	return n.NodeInterface.AddToConfiscationTransactionWhitelist(ctx, tx)
+}
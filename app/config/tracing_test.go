@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+)
+
+// errTestRPC is a failed node RPC call
+var errTestRPC = errors.New("rpc failed")
+
+// TestConfig_Tracer will test the method Tracer()
+func TestConfig_Tracer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no-op without a tracer provider", func(t *testing.T) {
+		for _, c := range []*Config{nil, {}} {
+			_, span := c.Tracer().Start(context.Background(), "test")
+			assert.False(t, span.IsRecording())
+			span.End()
+		}
+	})
+
+	t.Run("uses the tracer provider", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		c := &Config{Services: Services{Tracing: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))}}
+
+		_, span := c.Tracer().Start(context.Background(), "test")
+		assert.True(t, span.IsRecording())
+		span.End()
+		require.Len(t, recorder.Ended(), 1)
+		assert.Equal(t, TracerName, recorder.Ended()[0].InstrumentationScope().Name)
+	})
+}
+
+// TestTracedNode will test that every RPC call of the traced node gets a span
+func TestTracedNode(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	c := &Config{Services: Services{Tracing: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))}}
+	node := NewTracedNode(&mocks.Node{
+		InvalidateBlockFunc: func(_ context.Context, _ string) error {
+			return errTestRPC
+		},
+	}, c)
+
+	require.NoError(t, node.BanPeer(context.Background(), "127.0.0.1", 0))
+	require.ErrorIs(t, node.InvalidateBlock(context.Background(), "hash"), errTestRPC)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "node.BanPeer", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("rpc.method", "BanPeer"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("peer", "127.0.0.1"))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, "node.InvalidateBlock", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), attribute.String("block.hash", "hash"))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, errTestRPC.Error(), spans[1].Status().Description)
+}
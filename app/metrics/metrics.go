@@ -0,0 +1,101 @@
+// Package metrics holds the Prometheus metrics for alert processing, P2P and the webhook
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name
+const namespace = "alert_system"
+
+// Webhook delivery results, the label values of WebhookDeliveries
+const (
+	WebhookSuccess = "success"
+	WebhookFailure = "failure"
+)
+
+// The metrics, registered with the default Prometheus registry
+var (
+	// AlertsReceived counts the alerts received from peers (gossip or sync) per alert type
+	AlertsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alerts_received_total",
+		Help:      "Alerts received from peers, by alert type",
+	}, []string{"type"})
+
+	// AlertsProcessed counts the alert actions performed successfully per alert type
+	AlertsProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alerts_processed_total",
+		Help:      "Alert actions performed successfully, by alert type",
+	}, []string{"type"})
+
+	// AlertProcessingFailures counts the alert actions that failed per alert type
+	AlertProcessingFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alert_processing_failures_total",
+		Help:      "Alert actions that failed, by alert type",
+	}, []string{"type"})
+
+	// ActivePeers is the number of peers found by the last peer discovery
+	ActivePeers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_peers",
+		Help:      "Peers connected by the last peer discovery",
+	})
+
+	// AlertQueueDepth is the number of alerts received on the topics waiting for a worker
+	AlertQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "alert_queue_depth",
+		Help:      "Alerts received on the topics waiting for a worker",
+	})
+
+	// UnprocessedAlerts is the number of stored alerts still waiting to be processed
+	UnprocessedAlerts = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "unprocessed_alerts",
+		Help:      "Stored alerts waiting for the alert processing retry",
+	})
+
+	// SyncRequestsAbandoned counts the sync requests given up on after a peer never answered them
+	SyncRequestsAbandoned = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sync_requests_abandoned_total",
+		Help:      "Sync requests abandoned after running out of retries or peers to retry against",
+	})
+
+	// WebhookDeliveries counts the webhook deliveries by result (success or failure)
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook deliveries, by result",
+	}, []string{"result"})
+)
+
+// ObserveAlertAction will count the outcome of an alert action
+func ObserveAlertAction(alertType string, err error) {
+	if err != nil {
+		AlertProcessingFailures.WithLabelValues(alertType).Inc()
+		return
+	}
+	AlertsProcessed.WithLabelValues(alertType).Inc()
+}
+
+// ObserveWebhook will count the outcome of a webhook delivery
+func ObserveWebhook(err error) {
+	if err != nil {
+		WebhookDeliveries.WithLabelValues(WebhookFailure).Inc()
+		return
+	}
+	WebhookDeliveries.WithLabelValues(WebhookSuccess).Inc()
+}
+
+// Handler returns the handler serving the default registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errTest is a failed alert action or webhook delivery
+var errTest = errors.New("test error")
+
+// scrape will return the metrics served by the handler
+func scrape(t *testing.T) string {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+// TestObserveAlertAction will test the method ObserveAlertAction()
+func TestObserveAlertAction(t *testing.T) {
+	ObserveAlertAction("test_type", nil)
+	ObserveAlertAction("test_type", nil)
+	ObserveAlertAction("test_type", errTest)
+
+	body := scrape(t)
+	assert.Contains(t, body, `alert_system_alerts_processed_total{type="test_type"} 2`)
+	assert.Contains(t, body, `alert_system_alert_processing_failures_total{type="test_type"} 1`)
+}
+
+// TestObserveWebhook will test the method ObserveWebhook()
+func TestObserveWebhook(t *testing.T) {
+	ObserveWebhook(nil)
+	ObserveWebhook(errTest)
+	ObserveWebhook(errTest)
+
+	body := scrape(t)
+	assert.Contains(t, body, `alert_system_webhook_deliveries_total{result="success"} 1`)
+	assert.Contains(t, body, `alert_system_webhook_deliveries_total{result="failure"} 2`)
+}
+
+// TestHandler will test the method Handler()
+func TestHandler(t *testing.T) {
+	AlertsReceived.WithLabelValues("test_type").Inc()
+	ActivePeers.Set(3)
+	UnprocessedAlerts.Set(2)
+	AlertQueueDepth.Set(5)
+	SyncRequestsAbandoned.Inc()
+
+	body := scrape(t)
+	assert.Contains(t, body, `alert_system_alerts_received_total{type="test_type"} 1`)
+	assert.Contains(t, body, "alert_system_active_peers 3")
+	assert.Contains(t, body, "alert_system_unprocessed_alerts 2")
+	assert.Contains(t, body, "alert_system_alert_queue_depth 5")
+	assert.Contains(t, body, "alert_system_sync_requests_abandoned_total 1")
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mrz1836/go-datastore"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// exportBatchSize is the number of alerts ExportAlerts reads from the datastore at a time
+const exportBatchSize = 100
+
+// ExportedAlert is a line of the alert export, the alert's JSON and the serialized alert (with its signatures)
+// The JSON is for reading the archive, the serialized alert is what ImportAlerts stores and verifies
+type ExportedAlert struct {
+	AlertJSON
+	Raw string `json:"raw"` // Serialized alert (hex)
+}
+
+// ExportAlerts will write the alerts with sequence numbers from and to (inclusive) to the writer as NDJSON,
+// one alert (ExportedAlert) per line, lowest sequence first
+//
+// The alerts are read in batches, so the whole history is never held in memory. A cancelled context stops
+// the export between alerts, returning the context's error
+func ExportAlerts(ctx context.Context, w io.Writer, from, to uint32, opts ...model.Options) error {
+	if from > to {
+		return fmt.Errorf("%w: %d is after %d", ErrSequenceRangeInvalid, from, to)
+	}
+
+	encoder := json.NewEncoder(w)
+	next := from
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Get the next batch, starting after the last alert written
+		conditions := &map[string]interface{}{
+			utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+				utils.ExistsCondition: false,
+			},
+			utils.FieldSequenceNumber: map[string]interface{}{
+				utils.GreaterOrEqualCondition:  next,
+				utils.LessThanOrEqualCondition: to,
+			},
+		}
+		batch := make([]*AlertMessage, 0, exportBatchSize)
+		if err := model.GetModelsByConditions(
+			ctx, model.NameAlertMessage, &batch, nil, conditions, &datastore.QueryParams{
+				Page:          1,
+				PageSize:      exportBatchSize,
+				OrderByField:  utils.FieldSequenceNumber,
+				SortDirection: utils.SortAscending,
+			}, opts...,
+		); err != nil {
+			return err
+		}
+
+		for _, alert := range batch {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			alert.SetOptions(opts...)
+			out, err := alert.alertJSON()
+			if err != nil {
+				return err
+			}
+			if err = encoder.Encode(ExportedAlert{AlertJSON: out, Raw: alert.Raw}); err != nil {
+				return err
+			}
+		}
+
+		// A short batch is the end of the range, as is the last sequence (which can't be incremented past)
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		last := batch[len(batch)-1].SequenceNumber
+		if last >= to {
+			return nil
+		}
+		next = last + 1
+	}
+}
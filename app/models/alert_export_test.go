@@ -0,0 +1,73 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// cancellingWriter cancels the context after its first write
+type cancellingWriter struct {
+	bytes.Buffer
+	cancel context.CancelFunc
+}
+
+// Write writes to the buffer and cancels the context
+func (c *cancellingWriter) Write(p []byte) (int, error) {
+	defer c.cancel()
+	return c.Buffer.Write(p)
+}
+
+// TestExportAlerts will test the method ExportAlerts()
+func (ts *TestSuite) TestExportAlerts() {
+	// exportedSequences will return the sequence of each exported line
+	exportedSequences := func(data []byte) []uint32 {
+		sequences := make([]uint32, 0)
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			var alert AlertJSON
+			ts.Require().NoError(json.Unmarshal(scanner.Bytes(), &alert))
+			ts.NotEqual("null", string(alert.Body))
+			sequences = append(sequences, alert.Sequence)
+		}
+		return sequences
+	}
+
+	funds := testFunds(3)
+	ts.saveFundsAlert(1, AlertTypeFreezeUtxo, funds[0])
+	ts.saveFundsAlert(2, AlertTypeFreezeUtxo, funds[1])
+	ts.saveFundsAlert(3, AlertTypeUnfreezeUtxo, funds[0])
+
+	ts.Run("every alert, one per line", func() {
+		var buf bytes.Buffer
+		ts.Require().NoError(ExportAlerts(context.Background(), &buf, 0, math.MaxUint32, model.WithAllDependencies(ts.Dependencies)))
+		ts.Equal([]uint32{1, 2, 3}, exportedSequences(buf.Bytes()))
+	})
+
+	ts.Run("a range of sequences", func() {
+		var buf bytes.Buffer
+		ts.Require().NoError(ExportAlerts(context.Background(), &buf, 2, 3, model.WithAllDependencies(ts.Dependencies)))
+		ts.Equal([]uint32{2, 3}, exportedSequences(buf.Bytes()))
+
+		buf.Reset()
+		ts.Require().NoError(ExportAlerts(context.Background(), &buf, 10, 20, model.WithAllDependencies(ts.Dependencies)))
+		ts.Empty(buf.Bytes())
+	})
+
+	ts.Run("the range is invalid", func() {
+		var buf bytes.Buffer
+		ts.Require().ErrorIs(ExportAlerts(context.Background(), &buf, 3, 2, model.WithAllDependencies(ts.Dependencies)), ErrSequenceRangeInvalid)
+	})
+
+	ts.Run("cancelled part way", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		writer := &cancellingWriter{cancel: cancel}
+		err := ExportAlerts(ctx, writer, 0, math.MaxUint32, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, context.Canceled)
+		ts.Equal([]uint32{1}, exportedSequences(writer.Bytes()))
+	})
+}
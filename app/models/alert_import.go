@@ -0,0 +1,129 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// ImportAlerts will store the alerts of an NDJSON export (see ExportAlerts) that aren't stored yet, to seed a node
+// from an archive instead of syncing over P2P
+//
+// The alerts are history only, they're stored as processed and never executed. With verify the signatures of each
+// alert are checked, against the active keys and then the keys of each set keys alert imported (the active keys
+// aren't changed, replaying the history rotates them, see ReplayAlerts). Without it the alerts are stored unverified,
+// to be checked by the alert verification later
+//
+// An alert already stored (by sequence number), or one that can't be imported, is skipped. The first error is
+// returned with the counts, reading the rest of the import carries on unless the reader or the datastore fails
+func ImportAlerts(ctx context.Context, r io.Reader, verify bool, opts ...model.Options) (imported, skipped int, err error) {
+	var keys []*PublicKey
+	if verify {
+		if keys, err = GetActivePublicKeys(ctx, opts...); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// skip will count a skipped alert, keeping the first error
+	skip := func(line int, reason error) {
+		skipped++
+		if reason != nil && err == nil {
+			err = fmt.Errorf("line %d: %w", line, reason)
+		}
+	}
+
+	// A line has room for the largest alert in hex and its JSON
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*NewAlertMessage(opts...).maxAlertSize())
+	for line := 1; scanner.Scan(); line++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return imported, skipped, ctxErr
+		} else if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		alert, readErr := readImportedAlert(scanner.Bytes(), opts...)
+		if readErr != nil {
+			skip(line, readErr)
+			continue
+		}
+
+		// Skip the alerts already stored
+		if _, getErr := GetAlertMessageBySequenceNumber(ctx, alert.SequenceNumber, opts...); getErr == nil {
+			skip(line, nil)
+			continue
+		} else if !errors.Is(getErr, ErrAlertNotFound) {
+			return imported, skipped, getErr
+		}
+
+		// Check the signatures, a set keys alert sets the keys of the alerts after it
+		if verify {
+			if verifyErr := alert.Verify(ctx, keys); verifyErr != nil {
+				skip(line, fmt.Errorf("alert %d: %w", alert.SequenceNumber, verifyErr))
+				continue
+			}
+			if body, bodyErr := alert.ProcessAlertMessage(); bodyErr == nil {
+				if setKeys, ok := body.(*AlertMessageSetKeys); ok {
+					keys = importedKeys(setKeys)
+				}
+			}
+		} else {
+			alert.Unverified = true
+		}
+
+		// Store the alert as history
+		alert.Processed = true
+		if saveErr := alert.Save(ctx); saveErr != nil {
+			return imported, skipped, saveErr
+		}
+		imported++
+		if markErr := MarkSupersededAlert(ctx, alert); markErr != nil && !errors.Is(markErr, ErrAlertNotFound) {
+			return imported, skipped, markErr
+		}
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		return imported, skipped, scanErr
+	}
+	return imported, skipped, err
+}
+
+// readImportedAlert will read the alert from a line of an export, checking it matches the JSON it's exported with
+func readImportedAlert(line []byte, opts ...model.Options) (*AlertMessage, error) {
+	var exported ExportedAlert
+	if err := json.Unmarshal(line, &exported); err != nil {
+		return nil, err
+	} else if exported.Raw == "" {
+		return nil, fmt.Errorf("%w: alert %d", ErrAlertImportNoRaw, exported.Sequence)
+	}
+	raw, err := hex.DecodeString(exported.Raw)
+	if err != nil {
+		return nil, err
+	}
+	alert, err := NewAlertFromBytes(raw, opts...)
+	if err != nil {
+		return nil, err
+	}
+	alert.SerializeData()
+	if alert.SequenceNumber != exported.Sequence {
+		return nil, fmt.Errorf("%w: alert %d", ErrAlertImportMismatch, exported.Sequence)
+	} else if exported.Hash != "" && alert.Hash != exported.Hash {
+		return nil, fmt.Errorf("%w: alert %d", ErrAlertHashMismatch, exported.Sequence)
+	}
+	return alert, nil
+}
+
+// importedKeys will return the keys set by a set keys alert, to verify the imported alerts after it
+func importedKeys(setKeys *AlertMessageSetKeys) []*PublicKey {
+	keys := make([]*PublicKey, 0, len(setKeys.Keys))
+	for _, key := range setKeys.Keys {
+		keys = append(keys, &PublicKey{Key: hex.EncodeToString(key[:]), Active: true})
+	}
+	return keys
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/bitcoinschema/go-bitcoin"
+	"github.com/bsv-blockchain/go-bn/models"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// saveSignedAlert will save an alert with the message, signed by the private keys
+func (ts *TestSuite) saveSignedAlert(sequence uint32, alertType AlertType, message []byte, keys []string) {
+	a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+	a.SetAlertType(alertType)
+	a.SetRawMessage(message)
+	a.SequenceNumber = sequence
+	a.SetTimestamp(1700000000)
+	a.SetVersion(AlertVersionCurrent)
+	a.SerializeData()
+	sigs, err := utils.SignWithKeys(a.GetRawData(), keys)
+	ts.Require().NoError(err)
+	a.SetSignatures(sigs)
+
+	parsed, err := NewAlertFromBytes(a.Serialize(), model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().NoError(parsed.Save(context.Background()))
+}
+
+// newKeySet will create the private keys of a new key set, and the message of a set keys alert rotating to them
+func (ts *TestSuite) newKeySet() ([]string, []byte) {
+	privateKeys := make([]string, 0, SetKeysCount)
+	message := make([]byte, 0, SetKeysMessageSize)
+	for i := 0; i < SetKeysCount; i++ {
+		seed := sha256.Sum256([]byte{byte(i)})
+		privateKeys = append(privateKeys, hex.EncodeToString(seed[:]))
+		pubKey, err := bitcoin.PubKeyFromPrivateKeyString(privateKeys[i], true)
+		ts.Require().NoError(err)
+		key, err := hex.DecodeString(pubKey)
+		ts.Require().NoError(err)
+		message = append(message, key...)
+	}
+	return privateKeys, message
+}
+
+// resetDatastore will start over with a new node's datastore, holding only the genesis alert and keys
+func (ts *TestSuite) resetDatastore() {
+	ts.Dependencies.CloseAll(context.Background())
+	var err error
+	ts.Dependencies, err = config.LoadDependencies(context.Background(), BaseModels, true)
+	ts.Require().NoError(err)
+	ts.Require().NoError(CreateGenesisAlert(context.Background(), model.WithAllDependencies(ts.Dependencies)))
+}
+
+// exportAndReset will export the stored alerts after the genesis alert, then start over with a new node's datastore
+func (ts *TestSuite) exportAndReset() []byte {
+	var buf bytes.Buffer
+	ts.Require().NoError(ExportAlerts(context.Background(), &buf, 1, math.MaxUint32, model.WithAllDependencies(ts.Dependencies)))
+	ts.resetDatastore()
+	return buf.Bytes()
+}
+
+// TestImportAlerts will test the method ImportAlerts()
+func (ts *TestSuite) TestImportAlerts() {
+	ctx := context.Background()
+	funds := testFunds(2)
+
+	ts.Run("an export is restored as history", func() {
+		ts.resetDatastore()
+		ts.saveFundsAlert(1, AlertTypeFreezeUtxo, funds[0])
+		ts.saveFundsAlert(2, AlertTypeUnfreezeUtxo, funds[0])
+		export := ts.exportAndReset()
+
+		imported, skipped, err := ImportAlerts(ctx, bytes.NewReader(export), true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Equal(2, imported)
+		ts.Zero(skipped)
+
+		alerts, err := GetAllAlerts(ctx, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(alerts, 3) // With the genesis alert
+		for _, alert := range alerts[1:] {
+			ts.True(alert.Processed)
+			ts.False(alert.Unverified)
+		}
+
+		// The same export again changes nothing
+		imported, skipped, err = ImportAlerts(ctx, bytes.NewReader(export), true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Zero(imported)
+		ts.Equal(2, skipped)
+	})
+
+	ts.Run("unverified without verify", func() {
+		ts.resetDatastore()
+		ts.saveSignedAlert(3, AlertTypeFreezeUtxo, mustSerializeFunds(ts, funds[1]), []string{utils.Key4})
+		export := ts.exportAndReset()
+
+		imported, skipped, err := ImportAlerts(ctx, bytes.NewReader(export), false, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Equal(1, imported)
+		ts.Zero(skipped)
+
+		alert, err := GetAlertMessageBySequenceNumber(ctx, 3, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.True(alert.Unverified)
+		ts.True(alert.Processed)
+	})
+
+	ts.Run("invalid signatures are skipped with verify", func() {
+		ts.resetDatastore()
+		ts.saveFundsAlert(1, AlertTypeFreezeUtxo, funds[0])
+		ts.saveSignedAlert(2, AlertTypeFreezeUtxo, mustSerializeFunds(ts, funds[1]), []string{utils.Key4})
+		export := ts.exportAndReset()
+
+		imported, skipped, err := ImportAlerts(ctx, bytes.NewReader(export), true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrInsufficientValidSignatures)
+		ts.Contains(err.Error(), "line 2")
+		ts.Equal(1, imported)
+		ts.Equal(1, skipped)
+	})
+
+	ts.Run("alerts after a set keys alert are verified with its keys", func() {
+		ts.resetDatastore()
+		privateKeys, message := ts.newKeySet()
+		ts.saveSignedAlert(1, AlertTypeSetKeys, message, []string{utils.Key1, utils.Key2, utils.Key3})
+		ts.saveSignedAlert(2, AlertTypeFreezeUtxo, mustSerializeFunds(ts, funds[0]), privateKeys[:3])
+		export := ts.exportAndReset()
+
+		imported, skipped, err := ImportAlerts(ctx, bytes.NewReader(export), true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Equal(2, imported)
+		ts.Zero(skipped)
+
+		// The active keys are left as they were
+		keys, err := GetActivePublicKeys(ctx, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		for _, key := range keys {
+			ts.NotContains(hex.EncodeToString(message), key.Key)
+		}
+	})
+
+	ts.Run("lines that can't be imported are skipped", func() {
+		ts.resetDatastore()
+		ts.saveFundsAlert(5, AlertTypeFreezeUtxo, funds[0])
+		export := ts.exportAndReset()
+		noRaw := strings.Replace(string(export), `"raw":"`, `"raw":"","old":"`, 1)
+		wrongSequence := strings.Replace(string(export), `"sequence":5`, `"sequence":6`, 1)
+		wrongHash := regexp.MustCompile(`"hash":"[0-9a-f]+"`).ReplaceAllString(string(export), `"hash":"`+strings.Repeat("ab", 32)+`"`)
+		lines := "not json\n" + noRaw + wrongSequence + "\n" + string(export)
+
+		imported, skipped, err := ImportAlerts(ctx, strings.NewReader(lines), true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().Error(err)
+		ts.Contains(err.Error(), "line 1")
+		ts.Equal(1, imported)
+		ts.Equal(3, skipped)
+
+		_, _, err = ImportAlerts(ctx, strings.NewReader(noRaw), true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrAlertImportNoRaw)
+		_, _, err = ImportAlerts(ctx, strings.NewReader(wrongSequence), true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrAlertImportMismatch)
+		_, _, err = ImportAlerts(ctx, strings.NewReader(wrongHash), true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrAlertHashMismatch)
+	})
+
+	ts.Run("a cancelled import stops", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, _, err := ImportAlerts(cancelled, strings.NewReader("{}\n"), false, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, context.Canceled)
+	})
+}
+
+// mustSerializeFunds will serialize the funds as the message of a freeze or unfreeze alert
+func mustSerializeFunds(ts *TestSuite, funds ...models.Fund) []byte {
+	message, err := serializeFunds(funds)
+	ts.Require().NoError(err)
+	return message
+}
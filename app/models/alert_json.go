@@ -0,0 +1,169 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// AlertJSON is the JSON form of an alert, as written by AlertMessage.MarshalJSON
+//
+// It's the documented shape returned by the API, so it doesn't change when the model's fields do
+type AlertJSON struct {
+	AlertType  string          `json:"alert_type"`           // Name of the alert type, unknown(100) for a type this node doesn't know
+	Body       json.RawMessage `json:"body"`                 // Decoded alert body, null if the type is unknown or the alert can't be read
+	Hash       string          `json:"hash"`                 // Hash of the signed alert data
+	Sequence   uint32          `json:"sequence"`             // Sequence number of the alert
+	Supersedes uint32          `json:"supersedes,omitempty"` // Sequence number of the earlier alert this one supersedes
+	Timestamp  string          `json:"timestamp"`            // Time of the alert (RFC3339, UTC)
+	Version    uint32          `json:"version"`              // Version of the alert
+}
+
+// MarshalJSON will write the alert as an AlertJSON, an alert that wasn't read yet is read from Raw first
+//
+// It has a value receiver, so an alert held by value (like in the health response) is written the same way
+func (m AlertMessage) MarshalJSON() ([]byte, error) {
+	out, err := m.alertJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// alertJSON will build the AlertJSON of the alert, reading it from Raw if it wasn't read yet
+func (m AlertMessage) alertJSON() (AlertJSON, error) {
+	readable := m.version != 0
+	if !readable && m.Raw != "" {
+		m.message = nil
+		readable = m.ReadRaw() == nil
+	}
+
+	out := AlertJSON{
+		AlertType:  AlertType(m.AlertType).String(),
+		Body:       json.RawMessage("null"),
+		Hash:       m.Hash,
+		Sequence:   m.SequenceNumber,
+		Supersedes: m.Supersedes,
+		Timestamp:  alertTime(m.timestamp).Format(time.RFC3339),
+		Version:    m.version,
+	}
+
+	// An alert that can't be read still has its stored fields, it just has no body
+	if readable {
+		if body, err := m.ProcessAlertMessage(); err == nil && body != nil {
+			if out.Body, err = json.Marshal(body); err != nil {
+				return out, err
+			}
+		}
+	}
+	return out, nil
+}
+
+// UnmarshalJSON will read an alert written by MarshalJSON
+//
+// The alert message is rebuilt from the body, and has to give the same hash. Signatures aren't part
+// of the JSON, so the alert can't be verified or written back to the wire
+func (m *AlertMessage) UnmarshalJSON(data []byte) error {
+	var in AlertJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	alertType, err := ParseAlertType(in.AlertType)
+	if err != nil {
+		return err
+	}
+	timestamp, err := time.Parse(time.RFC3339, in.Timestamp)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrAlertJSONTimestamp, err.Error())
+	} else if timestamp.Unix() < 0 {
+		return fmt.Errorf("%w: %s", ErrAlertJSONTimestamp, in.Timestamp)
+	}
+
+	m.SetAlertType(alertType)
+	m.Hash = in.Hash
+	m.SequenceNumber = in.Sequence
+	m.Supersedes = in.Supersedes
+	m.timestamp = uint64(timestamp.Unix()) //nolint:gosec // checked above
+	m.version = in.Version
+	m.message = nil
+
+	// Without a body (an unknown alert type) the hash is taken as it is
+	if len(in.Body) == 0 || string(in.Body) == "null" {
+		return nil
+	}
+	parser, ok := alertParsers[alertType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrAlertTypeUnknown, alertType)
+	}
+	body := parser(m)
+	if err = json.Unmarshal(in.Body, body); err != nil {
+		return err
+	}
+	serializer, ok := body.(interface{ Serialize() ([]byte, error) })
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrAlertTypeUnknown, alertType)
+	}
+	if m.message, err = serializer.Serialize(); err != nil {
+		return err
+	}
+	m.SerializeData()
+	if in.Hash != "" && in.Hash != m.Hash {
+		return fmt.Errorf("%w: %s is not %s", ErrAlertJSONHashMismatch, m.Hash, in.Hash)
+	}
+	return nil
+}
+
+// alertTime will convert the timestamp of an alert (unix seconds) to a time, a timestamp out of range is capped
+func alertTime(timestamp uint64) time.Time {
+	if timestamp > math.MaxInt64 {
+		timestamp = math.MaxInt64
+	}
+	return time.Unix(int64(timestamp), 0).UTC()
+}
+
+// marshalBody will marshal the fields of an alert body, without the alert it's embedded in
+//
+// Each body needs its own MarshalJSON, otherwise the one of the embedded AlertMessage is promoted
+// and the body is written as the alert
+func marshalBody(body interface{}) ([]byte, error) {
+	value := reflect.ValueOf(body).Elem()
+	fields := make(map[string]interface{}, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		if field := value.Type().Field(i); !field.Anonymous && field.IsExported() {
+			fields[bodyFieldName(field)] = value.Field(i).Interface()
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// unmarshalBody will read the fields of an alert body written by marshalBody
+func unmarshalBody(data []byte, body interface{}) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	value := reflect.ValueOf(body).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		if raw, ok := fields[bodyFieldName(field)]; ok {
+			if err := json.Unmarshal(raw, value.Field(i).Addr().Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bodyFieldName is the JSON name of a body field, from its json tag or its name
+func bodyFieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
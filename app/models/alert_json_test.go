@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAlertMessage_JSON will test the methods MarshalJSON() and UnmarshalJSON()
+func TestAlertMessage_JSON(t *testing.T) {
+	for _, src := range alertVectorSources {
+		t.Run(src.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(alertVectorsDir, src.name+".json")) //nolint:gosec // the path is built from the vector name
+			require.NoError(t, err)
+			var golden alertVector
+			require.NoError(t, json.Unmarshal(data, &golden))
+
+			// A stored alert only has its raw hex, it's read when it's written
+			stored := AlertMessage{Raw: golden.Raw, AlertType: uint32(src.alertType), SequenceNumber: golden.Sequence}
+			data, err = json.Marshal(stored)
+			require.NoError(t, err)
+
+			var out AlertJSON
+			require.NoError(t, json.Unmarshal(data, &out))
+			assert.Equal(t, golden.AlertType, out.AlertType)
+			assert.Equal(t, golden.Sequence, out.Sequence)
+			assert.Equal(t, golden.Supersedes, out.Supersedes)
+			assert.Equal(t, golden.Version, out.Version)
+			assert.Equal(t, "2023-11-14T22:13:20Z", out.Timestamp)
+			assert.Len(t, out.Hash, 64)
+			assert.NotContains(t, string(data), `"raw"`)
+			if golden.Decoded == nil || string(golden.Decoded) == "null" {
+				assert.JSONEq(t, "null", string(out.Body))
+			} else {
+				assert.NotContains(t, string(out.Body), "sequence_number")
+			}
+
+			// Reading it back gives the same alert message and hash
+			read := &AlertMessage{}
+			require.NoError(t, json.Unmarshal(data, read))
+			assert.Equal(t, out.Hash, read.Hash)
+			assert.Equal(t, src.alertType, read.GetAlertType())
+			assert.Equal(t, uint64(alertVectorTimestamp), read.Timestamp())
+			if golden.Decoded != nil && string(golden.Decoded) != "null" {
+				assert.Equal(t, golden.Message, hex.EncodeToString(read.GetRawMessage()))
+			}
+		})
+	}
+
+	t.Run("alert that can't be read has no body", func(t *testing.T) {
+		data, err := json.Marshal(AlertMessage{Raw: "zz", AlertType: uint32(AlertTypeInformational), SequenceNumber: 3})
+		require.NoError(t, err)
+
+		var out AlertJSON
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, uint32(3), out.Sequence)
+		assert.Equal(t, AlertTypeInformational.String(), out.AlertType)
+		assert.JSONEq(t, "null", string(out.Body))
+	})
+
+	t.Run("body that doesn't match the hash", func(t *testing.T) {
+		a := NewAlertMessage()
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SetTimestamp(alertVectorTimestamp)
+		a.SetVersion(1)
+		a.SequenceNumber = 1
+		a.SerializeData()
+		data, err := json.Marshal(a)
+		require.NoError(t, err)
+
+		tampered := strings.Replace(string(data), `"message":"hi"`, `"message":"ho"`, 1)
+		require.NotEqual(t, string(data), tampered)
+		require.ErrorIs(t, json.Unmarshal([]byte(tampered), &AlertMessage{}), ErrAlertJSONHashMismatch)
+	})
+
+	t.Run("timestamp that isn't RFC3339", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"alert_type":"informational","timestamp":"1700000000"}`), &AlertMessage{})
+		require.ErrorIs(t, err, ErrAlertJSONTimestamp)
+	})
+}
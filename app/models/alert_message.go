@@ -1,45 +1,74 @@
 package models
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/bitcoinschema/go-bitcoin"
-	"github.com/bitcoinsv/bsvd/bsvec"
 	"github.com/bitcoinsv/bsvutil"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/go-sdk/util"
 	"github.com/mrz1836/go-datastore"
 
+	"github.com/bsv-blockchain/go-alert-system/app/config"
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
 	"github.com/bsv-blockchain/go-alert-system/utils"
 )
 
 // AlertMessage is an object representing an alert message
+//
+// On the wire the legacy layout (versions 1 and 2) ends with a fixed 195 byte block of three signatures,
+// while the versioned layout (version 3 and later) puts a varint count and that many signatures straight
+// after the fixed header. Either way the signed data is the header, the superseded sequence and the message
 type AlertMessage struct {
 	// Base model
 	model.Model `bson:",inline"`
 
 	// Model specific fields
 	ID             uint64 `json:"id" toml:"id" yaml:"id" bson:"_id" gorm:"primaryKey;comment:This is a unique identifier"`
+	AlertType      uint32 `json:"alert_type" toml:"alert_type" yaml:"alert_type" bson:"alert_type" gorm:"<-;type:int8;index;comment:This is the alert type"`
 	Hash           string `json:"hash" toml:"hash" yaml:"hash" bson:"hash" gorm:"<-;type:char(64);index;comment:This is the hash"`
 	SequenceNumber uint32 `json:"sequence_number" toml:"sequence_number" yaml:"sequence_number" bson:"sequence_number" gorm:"<-;type:int8;index;comment:This is the alert sequence number"`
 	Raw            string `json:"raw" toml:"raw" yaml:"raw" bson:"raw" gorm:"<-;type:text;comment:This is the raw alert message"`
 	Processed      bool   `json:"processed" toml:"processed" yaml:"processed" bson:"processed" gorm:"<-;type:boolean;comment:This determine if the alert was processed"`
+	Supersedes     uint32 `json:"supersedes" toml:"supersedes" yaml:"supersedes" bson:"supersedes" gorm:"<-;type:int8;comment:This is the sequence number of the earlier alert this one supersedes"`
+	SupersededBy   uint32 `json:"superseded_by" toml:"superseded_by" yaml:"superseded_by" bson:"superseded_by" gorm:"<-;type:int8;comment:This is the sequence number of the later alert that supersedes this one"`
+	Superseded     bool   `json:"superseded" toml:"superseded" yaml:"superseded" bson:"superseded" gorm:"<-;type:boolean;default:false;index;comment:This flags an alert replaced by a later alert, it's no longer enforced"`
+	DryRun         bool   `json:"dry_run" toml:"dry_run" yaml:"dry_run" bson:"dry_run" gorm:"<-;type:boolean;comment:This flags an alert processed in dry-run mode, its node RPC calls were never made"`
+	Unverified     bool   `json:"unverified" toml:"unverified" yaml:"unverified" bson:"unverified" gorm:"<-;type:boolean;comment:This flags an alert stored before its signatures were verified"`
 
 	// Private fields (never to be exported)
 	alertType  AlertType
 	data       []byte
 	message    []byte
-	signatures [][]byte
+	rpcResult  interface{}
+	signatures [][]byte // Fixed three (legacy) or counted (version 3 and later), see wire_format.go
+	suppressed bool     // The action was skipped since execution of the alert type is disabled
 	timestamp  uint64
 	version    uint32
 }
 
+// Validatable is an alert body that checks the rules of its alert type before it's executed
+type Validatable interface {
+	Validate(ctx context.Context) error
+}
+
+// AlertBody is the parsed body of an alert, as returned by ProcessAlertMessage
+type AlertBody = AlertMessageInterface
+
 // AlertMessageInterface is the interface for alert messages
+//
+// Read only parses the message, so an alert can be inspected without being checked,
+// Validate applies the rules of the alert type before it's executed
 type AlertMessageInterface interface {
+	Validatable
 	Read(msg []byte) error
 	Do(ctx context.Context) error
 	ToJSON(ctx context.Context) []byte
@@ -54,12 +83,20 @@ func NewAlertMessage(opts ...model.Options) *AlertMessage {
 }
 
 // NewAlertFromBytes creates a new alert from bytes
+//
+// It's how an alert is received (P2P, sync, the API and imports), so an alert larger than the maximum alert size,
+// or timestamped further ahead than the configured skew, is refused here. ReadRaw of a stored alert checks neither,
+// so changing the limits never makes the alerts already stored unreadable
 func NewAlertFromBytes(ak []byte, opts ...model.Options) (*AlertMessage, error) {
 	opts = append(opts, model.New())
 	newAlert := NewAlertMessage(opts...)
+	if err := newAlert.checkSize(uint64(len(ak))); err != nil {
+		return nil, err
+	}
 	newAlert.SetRawMessage(ak)
-	err := newAlert.ReadRaw()
-	if err != nil {
+	if err := newAlert.ReadRaw(); err != nil {
+		return nil, err
+	} else if err = newAlert.checkTimestamp(newAlert.timestamp, time.Now()); err != nil {
 		return nil, err
 	}
 
@@ -102,9 +139,10 @@ func (m *AlertMessage) Save(ctx context.Context) error {
 	return model.Save(ctx, m)
 }
 
-// SetAlertType will set the alert type
+// SetAlertType will set the alert type (and the stored alert type it can be queried by)
 func (m *AlertMessage) SetAlertType(t AlertType) {
 	m.alertType = t
+	m.AlertType = uint32(t)
 }
 
 // GetAlertType will get the alert type
@@ -122,6 +160,47 @@ func (m *AlertMessage) GetRawMessage() []byte {
 	return m.message
 }
 
+// logEvent will log an alert action event, the alert type, sequence and event are added to the structured fields
+func (m *AlertMessage) logEvent(event string, fields config.LogFields, format string, args ...interface{}) {
+	if m.Config() == nil || m.Config().Services.Log == nil {
+		return
+	}
+	if fields == nil {
+		fields = config.LogFields{}
+	}
+	fields["alert_type"] = m.GetAlertType().String()
+	fields["event"] = event
+	fields["sequence"] = m.SequenceNumber
+	m.Config().Services.Log.InfoFields(fields, format, args...)
+}
+
+// nodeContext will return the context for a node RPC call of the alert action, limited to the configured node RPC timeout
+func (m *AlertMessage) nodeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := config.DefaultNodeRPCTimeout
+	if m.Config() != nil && m.Config().NodeRPCTimeout > 0 {
+		timeout = m.Config().NodeRPCTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// wrapTimeout will wrap the error of a node RPC call that timed out with the alert type and sequence
+func (m *AlertMessage) wrapTimeout(err error) error {
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %s alert %d: %w", ErrNodeRPCTimeout, m.GetAlertType(), m.SequenceNumber, err)
+}
+
+// RPCResult will get the node RPC response from the last time the alert action was performed (nil if there was none)
+func (m *AlertMessage) RPCResult() interface{} {
+	return m.rpcResult
+}
+
+// setRPCResult will keep the node RPC response of the alert action for the audit log
+func (m *AlertMessage) setRPCResult(res interface{}) {
+	m.rpcResult = res
+}
+
 // GetRawData will get the raw data
 func (m *AlertMessage) GetRawData() []byte {
 	return m.data
@@ -129,118 +208,329 @@ func (m *AlertMessage) GetRawData() []byte {
 
 // SerializeData serializes the data
 func (m *AlertMessage) SerializeData() {
-	var ret []byte
-	ret = binary.LittleEndian.AppendUint32(ret, m.version)
-	ret = binary.LittleEndian.AppendUint32(ret, m.SequenceNumber)
-	ret = binary.LittleEndian.AppendUint64(ret, m.timestamp)
-	ret = binary.LittleEndian.AppendUint32(ret, uint32(m.alertType))
-	ret = append(ret, m.message...)
-	m.data = ret
+	m.data = utils.PooledSerialize(func(ret []byte) []byte {
+		ret = binary.LittleEndian.AppendUint32(ret, m.version)
+		ret = binary.LittleEndian.AppendUint32(ret, m.SequenceNumber)
+		ret = binary.LittleEndian.AppendUint64(ret, m.timestamp)
+		ret = binary.LittleEndian.AppendUint32(ret, uint32(m.alertType))
+		if hasSupersedes(m.version) {
+			ret = binary.LittleEndian.AppendUint32(ret, m.Supersedes)
+		}
+		return append(ret, m.message...)
+	})
 	m.Hash = chainhash.DoubleHashH(m.data).String()
 }
 
+// VerifyHash will recompute the hash from Raw and return ErrAlertHashMismatch if it isn't Hash
+// The hash keys the cache of alerts already received, so one that doesn't match the alert is never trusted
+func (m *AlertMessage) VerifyHash() error {
+	raw, err := hex.DecodeString(m.Raw)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAlertHashMismatch, err)
+	}
+	recomputed := &AlertMessage{}
+	recomputed.SetRawMessage(raw)
+	if err = recomputed.ReadRaw(); err != nil {
+		return fmt.Errorf("%w: %w", ErrAlertHashMismatch, err)
+	} else if recomputed.Hash != m.Hash {
+		return fmt.Errorf("%w: %q is not %s", ErrAlertHashMismatch, m.Hash, recomputed.Hash)
+	}
+	return nil
+}
+
 // Serialize serializes the alert
+//
+// Signatures are written in the order they are held: SetSignatures sorts them into canonical order,
+// while alerts read with ReadRaw keep the order they were received in, so their Raw is not rewritten
+//
+// Before version 3 the signatures are appended to the signed data, from version 3 they follow the fixed
+// header with a varint count (see wire_format.go)
 func (m *AlertMessage) Serialize() []byte {
 	m.SerializeData()
-	data := m.data
-	for _, sig := range m.signatures {
-		data = append(data, sig...)
-	}
+	data := utils.PooledSerialize(func(ret []byte) []byte {
+		if !hasSignatureCount(m.version) {
+			ret = append(ret, m.data...)
+			for _, sig := range m.signatures {
+				ret = append(ret, sig...)
+			}
+			return append(ret, make([]byte, signaturePadding(m.alertType, len(m.signatures)))...)
+		}
+		ret = append(ret, m.data[:AlertHeaderSize]...)
+		ret = append(ret, util.VarInt(len(m.signatures)).Bytes()...)
+		for _, sig := range m.signatures {
+			ret = append(ret, sig...)
+		}
+		return append(ret, m.data[AlertHeaderSize:]...)
+	})
 	m.Raw = hex.EncodeToString(data)
 	return data
 }
 
-// SetSignatures sets the signatures on the alert
+// SetSignatures sets the signatures on the alert (in canonical order)
 func (m *AlertMessage) SetSignatures(sigs [][]byte) {
-	m.signatures = sigs
+	m.signatures = sortSignatures(sigs)
 }
 
-// AreSignaturesValid checks if the signatures are valid
+// Signatures returns the signatures on the alert, in the order they are held
+func (m *AlertMessage) Signatures() [][]byte {
+	return m.signatures
+}
+
+// RecoverSigner will recover the public key (compressed, hex encoded) that made the signature over the alert data
+func (m *AlertMessage) RecoverSigner(sig []byte) (string, error) {
+	if len(sig) != SignatureSize {
+		return "", ErrAlertSignatureMalformed
+	}
+	pub, _, err := bitcoin.PubKeyFromSignature(base64.StdEncoding.EncodeToString(sig), hex.EncodeToString(m.data))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrAlertSignatureMalformed, err.Error())
+	}
+	return hex.EncodeToString(pub.SerializeCompressed()), nil
+}
+
+// sortSignatures returns a copy of the signatures sorted by their raw bytes
+//
+// Signatures are deterministic (RFC6979) for a given key and message, so ordering
+// by the raw bytes is a stable canonical order regardless of how they were supplied
+func sortSignatures(sigs [][]byte) [][]byte {
+	if len(sigs) == 0 {
+		return sigs
+	}
+	sorted := slices.Clone(sigs)
+	slices.SortFunc(sorted, bytes.Compare)
+	return sorted
+}
+
+// AreSignaturesValid checks the signatures against the active public keys
+// Returns false (and no error) if fewer than the quorum of signatures are valid
 func (m *AlertMessage) AreSignaturesValid(ctx context.Context) (bool, error) {
-	keys, err := GetActivePublicKey(ctx, nil, model.WithAllDependencies(m.Config()))
+	keys, err := GetActivePublicKeys(ctx, model.WithAllDependencies(m.Config()))
 	if err != nil {
 		return false, err
-	} else if len(keys) == 0 {
-		return false, ErrNoActivePublicKeys
 	}
+	if err = m.Verify(ctx, keys); errors.Is(err, ErrInsufficientValidSignatures) {
+		m.Config().Services.Log.Debugf("alert %d: %s", m.SequenceNumber, err.Error())
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	// Loop through all signatures
-	for _, sig := range m.signatures {
-		b64Sig := base64.StdEncoding.EncodeToString(sig)
-		valid := false
+// Verify will check that at least the signature threshold of the signatures are valid, each from a different key
+//
+// The signed digest is the serialized alert data (version, sequence, timestamp, type and message)
+// A key only counts once, so one key signing the alert several times doesn't make a quorum
+func (m *AlertMessage) Verify(_ context.Context, keys []*PublicKey) error {
+	signers, err := m.SignedBy(keys)
+	if err != nil {
+		return err
+	}
+	if threshold := m.signatureThreshold(); len(signers) < threshold {
+		return fmt.Errorf("%w: %d of %d needed", ErrInsufficientValidSignatures, len(signers), threshold)
+	}
+	return nil
+}
 
-		// Loop through all keys
-		for _, key := range keys {
+// SignedBy will return the keys that have a valid signature on the alert, in the order of the keys
+// Each key is matched to one signature at most, so a key that signed several times is only returned once
+func (m *AlertMessage) SignedBy(keys []*PublicKey) ([]*PublicKey, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoActivePublicKeys
+	}
 
-			// Get the public key
-			var pub *bsvec.PublicKey
-			if pub, err = bitcoin.PubKeyFromString(key.Key); err != nil {
-				return false, err
-			}
+	// Get the address of each key
+	addresses := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pub, err := bitcoin.PubKeyFromString(key.Key)
+		if err != nil {
+			return nil, err
+		}
+		var addr *bsvutil.LegacyAddressPubKeyHash
+		if addr, err = bitcoin.GetAddressFromPubKey(pub, true); err != nil {
+			return nil, err
+		} else if addr == nil {
+			return nil, ErrFailedToConvertPubKey
+		}
+		addresses = append(addresses, addr.String())
+	}
 
-			// Get the address
-			var addr *bsvutil.LegacyAddressPubKeyHash
-			if addr, err = bitcoin.GetAddressFromPubKey(pub, true); err != nil {
-				return false, err
-			} else if addr == nil {
-				return false, ErrFailedToConvertPubKey
+	// Match each signature to a key that hasn't signed yet
+	message := hex.EncodeToString(m.data)
+	signed := make(map[string]bool, len(addresses))
+	for _, sig := range m.signatures {
+		b64Sig := base64.StdEncoding.EncodeToString(sig)
+		for _, addr := range addresses {
+			if signed[addr] {
+				continue
 			}
-
-			// Verify the message
-			if err = bitcoin.VerifyMessage(addr.String(), b64Sig, hex.EncodeToString(m.data)); err != nil {
-				m.Config().Services.Log.Debugf("error verifying signature %x: %v", sig, err)
+			if err := bitcoin.VerifyMessage(addr, b64Sig, message); err != nil {
 				continue
 			}
-			valid = true
+			signed[addr] = true
 			break
 		}
-		if !valid {
-			return false, nil
+	}
+
+	// A key listed twice is still only one signer
+	signers := make([]*PublicKey, 0, len(signed))
+	for i, key := range keys {
+		if signed[addresses[i]] {
+			signers = append(signers, key)
+			delete(signed, addresses[i])
 		}
 	}
+	return signers, nil
+}
 
-	return true, nil
+// signatureThreshold returns the configured number of valid signatures needed to accept an alert
+// The special alert (type 99) only has room for one signature, so one valid signature from an active key is enough
+// A legacy alert carries exactly SignatureCount signatures, so a higher threshold is capped at that for it
+func (m *AlertMessage) signatureThreshold() int {
+	if m.GetAlertType() == AlertTypeSpecial {
+		return SpecialSignatureCount
+	}
+	threshold := SignatureThreshold(m.Config())
+	if !hasSignatureCount(m.version) {
+		return min(threshold, SignatureCount)
+	}
+	return threshold
 }
 
-// ProcessAlertMessage processes the alert message and converts to an alert message interface
-func (m *AlertMessage) ProcessAlertMessage() AlertMessageInterface {
-	switch m.alertType {
-	case AlertTypeInformational:
-		return &AlertMessageInformational{
-			AlertMessage: *m,
-		}
-	case AlertTypeFreezeUtxo:
-		return &AlertMessageFreezeUtxo{
-			AlertMessage: *m,
-		}
-	case AlertTypeUnfreezeUtxo:
-		return &AlertMessageUnfreezeUtxo{
-			AlertMessage: *m,
-		}
-	case AlertTypeConfiscateUtxo:
-		return &AlertMessageConfiscateTransaction{
-			AlertMessage: *m,
-		}
-	case AlertTypeBanPeer:
-		return &AlertMessageBanPeer{
-			AlertMessage: *m,
-		}
-	case AlertTypeUnbanPeer:
-		return &AlertMessageUnbanPeer{
-			AlertMessage: *m,
+// SignatureThreshold returns the number of valid signatures needed to accept an alert with the config
+// Falls back to SignatureQuorum if there is no config or no threshold configured
+func SignatureThreshold(c *config.Config) int {
+	if c != nil && c.SignatureThreshold > 0 {
+		return c.SignatureThreshold
+	}
+	return SignatureQuorum
+}
+
+// VerifyIfUnverified will check the signatures of an alert that was stored unverified, clearing the flag if they are valid
+//
+// Alerts that were verified before being stored are not checked again
+func (m *AlertMessage) VerifyIfUnverified(ctx context.Context) error {
+	if !m.Unverified {
+		return nil
+	}
+	valid, err := m.AreSignaturesValid(ctx)
+	if err != nil {
+		return err
+	} else if !valid {
+		return ErrAlertNotVerified
+	}
+	m.Unverified = false
+	return nil
+}
+
+// checkTimestamp will return ErrAlertTimestampInFuture if the timestamp is further ahead of now than the configured skew
+// The check is off unless MaxAlertTimestampSkew is configured, so historical alerts can be replayed
+func (m *AlertMessage) checkTimestamp(timestamp uint64, now time.Time) error {
+	c := m.Config()
+	if c == nil || c.MaxAlertTimestampSkew <= 0 {
+		return nil
+	}
+	if latest := now.Add(c.MaxAlertTimestampSkew).Unix(); latest >= 0 && timestamp > uint64(latest) {
+		return fmt.Errorf("%w: %s is more than %s ahead", ErrAlertTimestampInFuture, alertTime(timestamp).Format(time.RFC3339), c.MaxAlertTimestampSkew)
+	}
+	return nil
+}
+
+// checkSize will return ErrAlertTooLong if the whole alert is larger than the maximum alert size
+// It's checked before anything is read from the alert, so an oversized alert allocates nothing more
+func (m *AlertMessage) checkSize(size uint64) error {
+	if limit := m.maxAlertSize(); size > uint64(limit) {
+		return fmt.Errorf("%w: %d bytes, the limit is %d", ErrAlertTooLong, size, limit)
+	}
+	return nil
+}
+
+// maxAlertSize will return the configured maximum alert size, or the default one without a config
+func (m *AlertMessage) maxAlertSize() int {
+	if c := m.Config(); c != nil && c.MaxAlertSize > 0 {
+		return c.MaxAlertSize
+	}
+	return config.DefaultMaxAlertSize
+}
+
+// CheckTypeEnabled will return ErrAlertTypeDisabled if the alert's type is configured to be dropped when received
+// A disabled type is dropped entirely: it's never stored, relayed or executed
+func (m *AlertMessage) CheckTypeEnabled() error {
+	c := m.Config()
+	if c == nil {
+		return nil
+	}
+	for _, alertType := range c.DisabledAlertTypes {
+		if AlertType(alertType) == m.GetAlertType() {
+			return fmt.Errorf("%w: type %d at sequence %d", ErrAlertTypeDisabled, alertType, m.SequenceNumber)
 		}
-	case AlertTypeInvalidateBlock:
-		return &AlertMessageInvalidateBlock{
-			AlertMessage: *m,
+	}
+	return nil
+}
+
+// ExecutionSuppressed returns true if the alert's type is configured to be stored and relayed but not executed
+// The set keys alert is always executed, since it rotates the keys every alert is checked against
+func (m *AlertMessage) ExecutionSuppressed() bool {
+	c := m.Config()
+	if c == nil || m.GetAlertType() == AlertTypeSetKeys {
+		return false
+	}
+	if containsAlertType(c.SuppressedAlertTypes, m.GetAlertType()) {
+		return true
+	}
+	return len(c.ExecutedAlertTypes) > 0 && !containsAlertType(c.ExecutedAlertTypes, m.GetAlertType())
+}
+
+// CheckExecutionTypes will ensure the executed and suppressed alert types are alert type names or numbers,
+// and that the set keys alert isn't suppressed
+func CheckExecutionTypes(c *config.Config) error {
+	for _, names := range [][]string{c.ExecutedAlertTypes, c.SuppressedAlertTypes} {
+		for _, name := range names {
+			if _, err := ParseAlertType(name); err != nil {
+				return err
+			}
 		}
-	case AlertTypeSetKeys:
-		return &AlertMessageSetKeys{
-			AlertMessage: *m,
-			Hash:         m.Hash,
+	}
+	if containsAlertType(c.SuppressedAlertTypes, AlertTypeSetKeys) {
+		return ErrCannotSuppressSetKeys
+	}
+	return nil
+}
+
+// containsAlertType returns true if one of the alert type names or numbers is the alert type
+func containsAlertType(names []string, alertType AlertType) bool {
+	for _, name := range names {
+		if t, err := ParseAlertType(name); err == nil && t == alertType {
+			return true
 		}
-	default:
-		return nil
 	}
+	return false
+}
+
+// SupportedVersion returns true if this node understands the alert version
+// Alerts of a newer version are stored and relayed for forward compatibility, but never executed
+func (m *AlertMessage) SupportedVersion() bool {
+	return m.version <= AlertVersionCurrent
+}
+
+// KnownType returns true if this node can parse and execute the alert
+// False if the alert type is unknown or the alert version is newer than this node understands
+func (m *AlertMessage) KnownType() bool {
+	_, ok := alertParsers[m.alertType]
+	return ok && m.SupportedVersion()
+}
+
+// ProcessAlertMessage processes the alert message into its alert body, already read from the raw message
+// Returns nil (and no error) if the alert type is unknown or the alert version is newer than this node understands
+func (m *AlertMessage) ProcessAlertMessage() (AlertBody, error) {
+	if !m.KnownType() {
+		return nil, nil //nolint:nilnil // an unknown alert has no body, it's not an error
+	}
+	body := alertParsers[m.alertType](m)
+	if err := body.Read(m.GetRawMessage()); err != nil {
+		return nil, err
+	}
+	return body, nil
 }
 
 // SetVersion sets the version of the message
@@ -258,6 +548,15 @@ func (m *AlertMessage) SetTimestamp(ts uint64) {
 	m.timestamp = ts
 }
 
+// SetSupersedes sets the sequence number of the earlier alert this one supersedes
+// The superseded sequence is only on the wire from version 2, so the version is raised if needed
+func (m *AlertMessage) SetSupersedes(sequence uint32) {
+	m.Supersedes = sequence
+	if m.version < AlertVersionSupersedes {
+		m.version = AlertVersionSupersedes
+	}
+}
+
 // Timestamp returns the timestamp of the message
 func (m *AlertMessage) Timestamp() uint64 {
 	return m.timestamp
@@ -273,59 +572,111 @@ func (m *AlertMessage) ReadRaw() error {
 		m.SetRawMessage(ak)
 	}
 
-	if len(m.GetRawMessage()) < 20 {
-		// Minimum length: version(4) + sequence(4) + timestamp(8) + alertType(4) = 20 bytes
+	if len(m.GetRawMessage()) < AlertHeaderSize {
 		return ErrAlertTooShort
 	}
 	ak := m.GetRawMessage()
-	version := binary.LittleEndian.Uint32(ak[:4])
-	sequenceNumber := binary.LittleEndian.Uint32(ak[4:8])
-	timestamp := binary.LittleEndian.Uint64(ak[8:16])
-	alertType := binary.LittleEndian.Uint32(ak[16:20])
+	version := binary.LittleEndian.Uint32(ak[:alertSequenceOffset])
+	if version == 0 {
+		return ErrAlertVersionZero
+	}
+	sequenceNumber := binary.LittleEndian.Uint32(ak[alertSequenceOffset:alertTimestampOffset])
+	timestamp := binary.LittleEndian.Uint64(ak[alertTimestampOffset:alertTypeOffset])
+	alertType := binary.LittleEndian.Uint32(ak[alertTypeOffset:AlertHeaderSize])
+
+	// From version 3 the counted signature block follows the fixed header
+	rest := ak[AlertHeaderSize:]
+	var sigs [][]byte
+	if hasSignatureCount(version) {
+		var err error
+		if sigs, rest, err = readSignatureBlock(rest); err != nil {
+			return err
+		}
+	}
+
+	// Read the superseded sequence number (version 2 and later)
+	supersedesLen := headerSize(version) - AlertHeaderSize
+	var supersedes uint32
+	if supersedesLen > 0 {
+		if len(rest) < supersedesLen {
+			return ErrAlertTooShort
+		}
+		supersedes = binary.LittleEndian.Uint32(rest[:supersedesLen])
+		if supersedes >= sequenceNumber {
+			return ErrSupersedesNotEarlier
+		}
+	}
 
-	alertAndSignature := ak[20:]
+	alertAndSignature := rest[supersedesLen:]
 
-	// Assume 3 signatures, maybe disable alert will require 2 (0x09)
-	sigLen := 195
-	switch alertType {
-	case uint32(99):
-		sigLen = 128
+	// Before version 3 the alert ends with a fixed block of 3 signatures, maybe disable alert will require 2 (0x09)
+	sigLen := 0
+	if !hasSignatureCount(version) {
+		sigLen = signatureBlockSize(AlertType(alertType))
 	}
 
 	// This is the minimum length this data should be. Signature byte length + 2 bytes
 	// This would imply an informational alert with a message 1 byte long... not practical
 	// but possible. Regardless, let's just error out now if this length is lower. At least
 	// allows us to grab the expected signature.
-	if len(alertAndSignature) < sigLen+2 {
+	if len(alertAndSignature) < sigLen+MinAlertMessageSize {
 		return ErrAlertMessageInvalidLength
 	}
 
 	// Get alert message bytes
 	alert := alertAndSignature[:len(alertAndSignature)-sigLen]
 
-	// Get signature bytes
-	signatures := alertAndSignature[len(alertAndSignature)-sigLen:]
-	var sigs [][]byte
-
-	// Loop through all signatures and create an array
-	for i := 0; i < sigLen/65; i++ {
-		sigs = append(sigs, signatures[:65])
-		signatures = signatures[65:]
+	// Get signature bytes, and loop through all signatures and create an array
+	if sigLen > 0 {
+		block := alertAndSignature[len(alertAndSignature)-sigLen:]
+		sigs = splitSignatures(block)
 	}
 
-	dataLen := 20 + len(alert)
+	// The signed data is the fixed header, the superseded sequence and the message, without the signatures
+	data := ak[:AlertHeaderSize+supersedesLen+len(alert)]
+	if hasSignatureCount(version) {
+		data = append(slices.Clip(ak[:AlertHeaderSize]), rest...)
+	}
 
 	m.SetAlertType(AlertType(alertType))
 	m.message = alert
 	m.SequenceNumber = sequenceNumber
 	m.timestamp = timestamp
 	m.version = version
-	m.data = ak[:dataLen]
+	m.Supersedes = supersedes
+	m.data = data
 	m.signatures = sigs
 	_ = m.Serialize()
 	return nil
 }
 
+// readSignatureBlock will read the counted signature block (version 3 and later) and return the rest of the alert
+func readSignatureBlock(b []byte) ([][]byte, []byte, error) {
+	reader := util.NewReader(b)
+	count, err := reader.ReadVarInt()
+	if err != nil {
+		return nil, nil, ErrAlertTooShort
+	}
+	if count == 0 || count > MaxSignatureCount {
+		return nil, nil, fmt.Errorf("%w: %d", ErrInvalidSignatureCount, count)
+	}
+	var signatures []byte
+	if signatures, err = reader.ReadBytes(int(count) * SignatureSize); err != nil {
+		return nil, nil, ErrAlertTooShort
+	}
+	return splitSignatures(signatures), reader.ReadRemaining(), nil
+}
+
+// splitSignatures will split a block of signatures into each signature
+func splitSignatures(block []byte) [][]byte {
+	sigs := make([][]byte, 0, len(block)/SignatureSize)
+	for len(block) >= SignatureSize {
+		sigs = append(sigs, block[:SignatureSize])
+		block = block[SignatureSize:]
+	}
+	return sigs
+}
+
 // GetAlertMessageBySequenceNumber will get the model with the given conditions
 func GetAlertMessageBySequenceNumber(ctx context.Context, sequenceNumber uint32, opts ...model.Options) (*AlertMessage, error) {
 	// Get the record
@@ -334,7 +685,7 @@ func GetAlertMessageBySequenceNumber(ctx context.Context, sequenceNumber uint32,
 	conditions := make(map[string]interface{})
 	conditions["sequence_number"] = sequenceNumber
 	if err := model.Get(
-		ctx, message, conditions, model.DefaultDatabaseReadTimeout, true, // In-case an update is occurring
+		ctx, message, conditions, 0, true, // In-case an update is occurring (0 is the configured query timeout)
 	); err != nil {
 		if errors.Is(err, datastore.ErrNoResults) {
 			return nil, ErrAlertNotFound
@@ -345,6 +696,78 @@ func GetAlertMessageBySequenceNumber(ctx context.Context, sequenceNumber uint32,
 	return message, nil
 }
 
+// IsSequenceProcessed will return true if an alert with the sequence number was stored as processed
+// An alert processed in dry-run mode doesn't count, since its action was never executed
+func IsSequenceProcessed(ctx context.Context, sequenceNumber uint32, opts ...model.Options) (bool, error) {
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+		utils.FieldSequenceNumber: sequenceNumber,
+		"processed":               true,
+	}
+
+	modelItems := make([]*AlertMessage, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAlertMessage, &modelItems, nil, conditions, nil, opts...,
+	); err != nil {
+		return false, err
+	}
+	for _, item := range modelItems {
+		if !item.DryRun {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MaxMissingSequenceRange is the most sequence numbers FindMissingSequences will check in one call
+const MaxMissingSequenceRange = 100000
+
+// FindMissingSequences will return the sequence numbers from and to (inclusive) that aren't stored, lowest first
+// An empty slice is returned if every sequence in the range is stored
+func FindMissingSequences(ctx context.Context, from, to uint32, opts ...model.Options) ([]uint32, error) {
+	if from > to {
+		return nil, fmt.Errorf("%w: %d is after %d", ErrSequenceRangeInvalid, from, to)
+	} else if to-from >= MaxMissingSequenceRange {
+		return nil, fmt.Errorf("%w: %d to %d is more than %d sequences", ErrSequenceRangeTooLarge, from, to, MaxMissingSequenceRange)
+	}
+
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+		utils.FieldSequenceNumber: map[string]interface{}{
+			utils.GreaterOrEqualCondition:  from,
+			utils.LessThanOrEqualCondition: to,
+		},
+	}
+
+	// Get the records
+	modelItems := make([]*AlertMessage, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAlertMessage, &modelItems, nil, conditions, nil, opts...,
+	); err != nil {
+		return nil, err
+	}
+	stored := make(map[uint32]bool, len(modelItems))
+	for _, item := range modelItems {
+		stored[item.SequenceNumber] = true
+	}
+
+	missing := make([]uint32, 0)
+	for sequence := from; ; sequence++ {
+		if !stored[sequence] {
+			missing = append(missing, sequence)
+		}
+		if sequence == to {
+			break
+		}
+	}
+	return missing, nil
+}
+
 // GetLatestAlert will get the model with the given conditions
 func GetLatestAlert(ctx context.Context, metadata *model.Metadata, opts ...model.Options) (*AlertMessage, error) {
 	// Set the conditions
@@ -376,6 +799,129 @@ func GetLatestAlert(ctx context.Context, metadata *model.Metadata, opts ...model
 	return modelItems[0], nil
 }
 
+// MaxAlertMessagesLimit is the most alerts GetAlertMessages will return in one page
+const MaxAlertMessagesLimit = 1000
+
+// GetAlertMessages will get a page of alerts, newest sequence first, and the total number of alerts
+//
+// The datastore only reads whole pages, so an offset that isn't a multiple of the limit reads the two pages around it
+func GetAlertMessages(ctx context.Context, limit, offset int, opts ...model.Options) ([]*AlertMessage, int, error) {
+	if limit <= 0 || limit > MaxAlertMessagesLimit {
+		return nil, 0, fmt.Errorf("%w: %d is not between 1 and %d", ErrInvalidPageLimit, limit, MaxAlertMessagesLimit)
+	} else if offset < 0 {
+		return nil, 0, fmt.Errorf("%w: %d", ErrNegativePageOffset, offset)
+	}
+
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	// Get the total
+	total, err := model.GetModelCountByConditions(ctx, model.NameAlertMessage, &AlertMessage{}, nil, conditions, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Get the page the offset falls in, and the next page if the offset is part way through it
+	page := offset/limit + 1
+	skip := offset % limit
+	pages := 1
+	if skip > 0 {
+		pages = 2
+	}
+	modelItems := make([]*AlertMessage, 0, pages*limit)
+	for p := page; p < page+pages; p++ {
+		pageItems := make([]*AlertMessage, 0, limit)
+		if err = model.GetModelsByConditions(
+			ctx, model.NameAlertMessage, &pageItems, nil, conditions, &datastore.QueryParams{
+				Page:          p,
+				PageSize:      limit,
+				OrderByField:  utils.FieldSequenceNumber,
+				SortDirection: utils.SortDescending,
+			}, opts...,
+		); err != nil {
+			return nil, 0, err
+		}
+		modelItems = append(modelItems, pageItems...)
+	}
+
+	// Trim to the requested window
+	if skip >= len(modelItems) {
+		return []*AlertMessage{}, int(total), nil
+	}
+	modelItems = modelItems[skip:]
+	if len(modelItems) > limit {
+		modelItems = modelItems[:limit]
+	}
+	return modelItems, int(total), nil
+}
+
+// GetAlertMessagesByType will get the alerts of the given type, newest sequence first
+// The number of alerts is capped with model.WithLimit, and an empty slice is returned if none match
+func GetAlertMessagesByType(ctx context.Context, alertType AlertType, opts ...model.Options) ([]*AlertMessage, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+		utils.FieldAlertType: uint32(alertType),
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  utils.FieldSequenceNumber,
+		SortDirection: utils.SortDescending,
+	}
+	if limit := model.NewBaseModel(model.NameAlertMessage, opts...).Limit(); limit > 0 {
+		queryParams.Page = 1
+		queryParams.PageSize = limit
+	}
+
+	// Get the records
+	modelItems := make([]*AlertMessage, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAlertMessage, &modelItems, nil, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+	return modelItems, nil
+}
+
+// BackfillAlertTypes will set the stored alert type of alerts saved before it was recorded, from their raw header
+func BackfillAlertTypes(ctx context.Context, opts ...model.Options) error {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+		utils.FieldAlertType: uint32(0),
+	}
+
+	// Get the records
+	modelItems := make([]*AlertMessage, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAlertMessage, &modelItems, nil, conditions, nil, opts...,
+	); err != nil {
+		return err
+	}
+
+	for _, alert := range modelItems {
+		raw, err := hex.DecodeString(alert.Raw)
+		if err != nil || len(raw) < AlertHeaderSize {
+			continue
+		}
+		alert.SetOptions(opts...)
+		alert.AlertType = binary.LittleEndian.Uint32(raw[alertTypeOffset:AlertHeaderSize])
+		if err = alert.Save(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetAllAlerts returns all alerts in the database
 func GetAllAlerts(ctx context.Context, metadata *model.Metadata, opts ...model.Options) ([]*AlertMessage, error) {
 	// Set the conditions
@@ -405,6 +951,33 @@ func GetAllAlerts(ctx context.Context, metadata *model.Metadata, opts ...model.O
 	return modelItems, nil
 }
 
+// GetActiveAlerts will get all alerts that are still enforced, leaving out the ones superseded by a later alert
+func GetActiveAlerts(ctx context.Context, metadata *model.Metadata, opts ...model.Options) ([]*AlertMessage, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+		utils.FieldSuperseded: false,
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  utils.FieldSequenceNumber,
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the records
+	modelItems := make([]*AlertMessage, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAlertMessage, &modelItems, metadata, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
+
 // GetAllUnprocessedAlerts will get all alerts that weren't successfully processed
 func GetAllUnprocessedAlerts(ctx context.Context, metadata *model.Metadata, opts ...model.Options) ([]*AlertMessage, error) {
 	// Set the conditions
@@ -434,3 +1007,53 @@ func GetAllUnprocessedAlerts(ctx context.Context, metadata *model.Metadata, opts
 	// Return the first item (only item)
 	return modelItems, nil
 }
+
+// GetAllUnverifiedAlerts will get all alerts that were stored before their signatures were verified
+func GetAllUnverifiedAlerts(ctx context.Context, metadata *model.Metadata, opts ...model.Options) ([]*AlertMessage, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+		"unverified": true,
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  utils.FieldSequenceNumber,
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the record
+	modelItems := make([]*AlertMessage, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAlertMessage, &modelItems, metadata, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	} else if len(modelItems) == 0 {
+		return nil, nil
+	}
+
+	return modelItems, nil
+}
+
+// MarkSupersededAlert will flag the earlier alert the given alert supersedes (if any) as superseded by its sequence number
+//
+// Nothing is deleted, the earlier alert is only left out of the active alerts. Unverified alerts are not trusted to mark anything
+func MarkSupersededAlert(ctx context.Context, alert *AlertMessage) error {
+	if alert.Supersedes == 0 || alert.Unverified {
+		return nil
+	}
+	return markAlertSuperseded(ctx, alert.Supersedes, alert.SequenceNumber, model.WithAllDependencies(alert.Config()))
+}
+
+// markAlertSuperseded will flag the alert with the sequence number as superseded by the later alert
+func markAlertSuperseded(ctx context.Context, sequenceNumber, supersededBy uint32, opts ...model.Options) error {
+	earlier, err := GetAlertMessageBySequenceNumber(ctx, sequenceNumber, opts...)
+	if err != nil {
+		return err
+	}
+	earlier.Superseded = true
+	earlier.SupersededBy = supersededBy
+	return earlier.Save(ctx)
+}
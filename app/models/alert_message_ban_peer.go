@@ -2,20 +2,33 @@ package models
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 
 	"github.com/bsv-blockchain/go-sdk/util"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
 )
 
 // AlertMessageBanPeer is the message for ban peer
+//
+// The legacy layout is the peer and the reason, the extended layout adds the ban duration after the reason:
+//
+//	peer length(varint) | peer | reason length(varint) | reason | ban duration seconds(8, optional)
+//
+// A ban without a duration is permanent, as it always was
 type AlertMessageBanPeer struct {
 	AlertMessage
 
-	Peer         []byte `json:"peer"`
-	PeerLength   uint64 `json:"peer_length"`
-	Reason       []byte `json:"reason"`
-	ReasonLength uint64 `json:"reason_length"`
+	BanDurationSeconds uint64 `json:"ban_duration_seconds"`
+	Peer               []byte `json:"peer"`
+	PeerLength         uint64 `json:"peer_length"`
+	Reason             []byte `json:"reason"`
+	ReasonLength       uint64 `json:"reason_length"`
 }
 
 // Read reads the payload from the byte slice
@@ -37,6 +50,9 @@ func (a *AlertMessageBanPeer) Read(alert []byte) error {
 		}
 		peer = append(peer, b)
 	}
+	if err = checkPeerAddress(peer); err != nil {
+		return err
+	}
 	a.PeerLength = peerLength
 	a.Peer = peer
 
@@ -56,19 +72,94 @@ func (a *AlertMessageBanPeer) Read(alert []byte) error {
 
 	a.Reason = reason
 	a.ReasonLength = reasonLength
+
+	// read the ban duration (extended layout), anything after it is ignored as before
+	a.BanDurationSeconds = 0
+	if !reader.IsComplete() {
+		var duration []byte
+		if duration, err = reader.ReadBytes(BanDurationSize); err != nil {
+			return fmt.Errorf("%w: %s", ErrFailedToReadBanDuration, err.Error())
+		}
+		a.BanDurationSeconds = binary.LittleEndian.Uint64(duration)
+	}
+	return nil
+}
+
+// checkPeerAddress will check the peer is an IP, a subnet or a host:port
+// The host of a host:port can be an IPv4 address, a bracketed IPv6 address or a hostname
+func checkPeerAddress(peer []byte) error {
+	address := string(peer)
+	if net.ParseIP(address) != nil {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(address); err == nil {
+		return nil
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %s", ErrInvalidPeerAddress, address, err.Error())
+	}
+	if _, err = strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("%w: %q has an invalid port", ErrInvalidPeerAddress, address)
+	}
+	if net.ParseIP(host) == nil && !isHostname(host) {
+		return fmt.Errorf("%w: %q has an invalid host", ErrInvalidPeerAddress, address)
+	}
+	return nil
+}
+
+// isHostname returns true if the host is a valid DNS hostname
+func isHostname(host string) bool {
+	if len(host) == 0 || len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Serialize writes the alert body back to the bytes Read consumes
+// A permanent ban is written in the legacy layout, so it reads the same on nodes without ban durations
+func (a *AlertMessageBanPeer) Serialize() ([]byte, error) {
+	writer := util.NewWriter()
+	writer.WriteVarInt(uint64(len(a.Peer)))
+	writer.WriteBytes(a.Peer)
+	writer.WriteVarInt(uint64(len(a.Reason)))
+	writer.WriteBytes(a.Reason)
+	if a.BanDurationSeconds > 0 {
+		writer.WriteBytes(binary.LittleEndian.AppendUint64(nil, a.BanDurationSeconds))
+	}
+	return writer.Buf, nil
+}
+
+// Validate checks the alert, there are no rules beyond what Read parses
+func (a *AlertMessageBanPeer) Validate(_ context.Context) error {
 	return nil
 }
 
 // Do execute the alert
 func (a *AlertMessageBanPeer) Do(ctx context.Context) error {
-	return a.Config().Services.Node.BanPeer(ctx, string(a.Peer))
+	a.logEvent("ban_peer", config.LogFields{"ban_duration_seconds": a.BanDurationSeconds, "peer": string(a.Peer)},
+		"BanPeer alert; peer [%s]; duration [%d]", a.Peer, a.BanDurationSeconds)
+	rpcCtx, cancel := a.nodeContext(ctx)
+	defer cancel()
+	return a.wrapTimeout(a.Config().Services.Node.BanPeer(rpcCtx, string(a.Peer), a.BanDurationSeconds))
 }
 
 // ToJSON is the alert in JSON format
 func (a *AlertMessageBanPeer) ToJSON(_ context.Context) []byte {
-	m := a.ProcessAlertMessage()
-	// TODO: Come back and add a message interface for each alert
-	_ = m.Read(a.GetRawMessage())
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
 	data, err := json.MarshalIndent(m, "", "    ")
 	if err != nil {
 		return []byte{}
@@ -76,7 +167,20 @@ func (a *AlertMessageBanPeer) ToJSON(_ context.Context) []byte {
 	return data
 }
 
+// MarshalJSON writes the fields of the alert body, the alert it's embedded in is written on its own
+func (a *AlertMessageBanPeer) MarshalJSON() ([]byte, error) {
+	return marshalBody(a)
+}
+
+// UnmarshalJSON reads the fields of the alert body written by MarshalJSON
+func (a *AlertMessageBanPeer) UnmarshalJSON(data []byte) error {
+	return unmarshalBody(data, a)
+}
+
 // MessageString executes the alert
 func (a *AlertMessageBanPeer) MessageString() string {
-	return fmt.Sprintf("Banning peer [%s]; reason [%s].", a.Peer, a.Reason)
+	if a.BanDurationSeconds > 0 {
+		return fmt.Sprintf("[%s] Banning peer [%s] for [%d] seconds; reason [%s].", AlertTypeBanPeer, a.Peer, a.BanDurationSeconds, a.Reason)
+	}
+	return fmt.Sprintf("[%s] Banning peer [%s]; reason [%s].", AlertTypeBanPeer, a.Peer, a.Reason)
 }
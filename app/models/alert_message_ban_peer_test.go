@@ -1,12 +1,17 @@
 package models
 
 import (
+	"context"
+	"encoding/binary"
 	"encoding/hex"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
 )
 
@@ -101,8 +106,8 @@ func (ts *TestSuite) TestMessageBanPeer_Read() {
 			}
 			alert := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
 			alert.SetAlertType(AlertTypeBanPeer)
-			a := alert.ProcessAlertMessage()
-			if err = a.Read(alertBytes); (err != nil) != tt.wantErr {
+			alert.SetRawMessage(alertBytes)
+			if _, err = alert.ProcessAlertMessage(); (err != nil) != tt.wantErr {
 				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			/*if !bytes.Equal(a.Peer, tt.fields.Peer) && !tt.wantErr {
@@ -286,3 +291,94 @@ func encodeVarInt(value uint64) []byte {
 	}
 	return buf
 }
+
+// TestAlertMessageBanPeer_PeerAddress will test the peer address check in Read()
+func TestAlertMessageBanPeer_PeerAddress(t *testing.T) {
+	valid := []string{
+		"127.0.0.1",
+		"127.0.0.1/24",
+		"127.0.0.1:8333",
+		"2001:db8::1",
+		"[2001:db8::1]:8333",
+		"seed.bitcoinsv.io:8333",
+	}
+	for _, peer := range valid {
+		t.Run("valid "+peer, func(t *testing.T) {
+			raw, err := (&AlertMessageBanPeer{Peer: []byte(peer), Reason: []byte("test")}).Serialize()
+			require.NoError(t, err)
+			require.NoError(t, (&AlertMessageBanPeer{}).Read(raw))
+			require.NoError(t, (&AlertMessageUnbanPeer{}).Read(raw))
+		})
+	}
+
+	invalid := []string{
+		"",
+		"not a peer",
+		"127.0.0.1:",
+		"127.0.0.1:port",
+		"127.0.0.1:65536",
+		"2001:db8::1:8333:x",
+		"bad_host.io:8333",
+		"-host.io:8333",
+		string([]byte{0x00, 0xff, 0x3a, 0x31}),
+	}
+	for _, peer := range invalid {
+		t.Run("invalid "+peer, func(t *testing.T) {
+			raw, err := (&AlertMessageBanPeer{Peer: []byte(peer), Reason: []byte("test")}).Serialize()
+			require.NoError(t, err)
+			require.ErrorIs(t, (&AlertMessageBanPeer{}).Read(raw), ErrInvalidPeerAddress)
+			require.ErrorIs(t, (&AlertMessageUnbanPeer{}).Read(raw), ErrInvalidPeerAddress)
+		})
+	}
+}
+
+// TestAlertMessageBanPeer_BanDuration will test the optional ban duration after the reason
+func TestAlertMessageBanPeer_BanDuration(t *testing.T) {
+	legacy, err := hex.DecodeString("093132372e302e302e310474657374")
+	require.NoError(t, err)
+
+	t.Run("legacy layout is a permanent ban", func(t *testing.T) {
+		alert := &AlertMessageBanPeer{}
+		require.NoError(t, alert.Read(legacy))
+		assert.Equal(t, uint64(0), alert.BanDurationSeconds)
+
+		raw, err := alert.Serialize()
+		require.NoError(t, err)
+		assert.Equal(t, legacy, raw)
+	})
+
+	t.Run("extended layout", func(t *testing.T) {
+		alert := &AlertMessageBanPeer{}
+		require.NoError(t, alert.Read(binary.LittleEndian.AppendUint64(slices.Clone(legacy), 3600)))
+		assert.Equal(t, uint64(3600), alert.BanDurationSeconds)
+		assert.Equal(t, []byte("test"), alert.Reason)
+
+		raw, err := alert.Serialize()
+		require.NoError(t, err)
+		assert.Len(t, raw, len(legacy)+BanDurationSize)
+	})
+
+	t.Run("truncated duration", func(t *testing.T) {
+		err := (&AlertMessageBanPeer{}).Read(append(slices.Clone(legacy), 0x10, 0x0e))
+		require.ErrorIs(t, err, ErrFailedToReadBanDuration)
+	})
+
+	t.Run("duration is passed to the node", func(t *testing.T) {
+		var banned string
+		var banTime uint64
+		deps := &config.Config{Services: config.Services{Node: &mocks.Node{
+			BanPeerFunc: func(_ context.Context, peer string, duration uint64) error {
+				banned, banTime = peer, duration
+				return nil
+			},
+		}}}
+		alert := &AlertMessageBanPeer{
+			AlertMessage:       *NewAlertMessage(model.WithAllDependencies(deps)),
+			BanDurationSeconds: 3600,
+			Peer:               []byte("127.0.0.1"),
+		}
+		require.NoError(t, alert.Do(context.Background()))
+		assert.Equal(t, "127.0.0.1", banned)
+		assert.Equal(t, uint64(3600), banTime)
+	})
+}
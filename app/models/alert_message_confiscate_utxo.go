@@ -10,6 +10,8 @@ import (
 
 	"github.com/bsv-blockchain/go-bn/models"
 	"github.com/bsv-blockchain/go-sdk/util"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
 )
 
 // AlertMessageConfiscateTransaction is a confiscate utxo alert
@@ -27,13 +29,13 @@ type ConfiscateTransaction struct {
 
 // Read reads the alert
 func (a *AlertMessageConfiscateTransaction) Read(raw []byte) error {
-	if len(raw) < 9 {
+	if len(raw) < MinConfiscationSize {
 		return ErrConfiscationAlertTooShort
 	}
 	// TODO: assume for now only 1 confiscation tx in the alert for simplicity
 	details := make([]models.ConfiscationTransactionDetails, 0, 1)
-	enforceAtHeight := binary.LittleEndian.Uint64(raw[0:8])
-	reader := util.NewReader(raw[8:])
+	enforceAtHeight := binary.LittleEndian.Uint64(raw[:EnforceAtHeightSize])
+	reader := util.NewReader(raw[EnforceAtHeightSize:])
 
 	length, err := reader.ReadVarInt()
 	if err != nil {
@@ -69,13 +71,54 @@ func (a *AlertMessageConfiscateTransaction) Read(raw []byte) error {
 	return nil
 }
 
+// Serialize writes the alert body back to the bytes Read consumes
+func (a *AlertMessageConfiscateTransaction) Serialize() ([]byte, error) {
+	if len(a.Transactions) != 1 {
+		return nil, fmt.Errorf("%w, got %d", ErrConfiscationTxCount, len(a.Transactions))
+	}
+	tx := a.Transactions[0].ConfiscationTransaction
+	if tx.EnforceAtHeight < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrNegativeEnforceAtHeight, tx.EnforceAtHeight)
+	}
+	rawTx, err := hex.DecodeString(tx.Hex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFailedToReadTxHex, err.Error())
+	}
+	raw := binary.LittleEndian.AppendUint64(make([]byte, 0, EnforceAtHeightSize), uint64(tx.EnforceAtHeight))
+	writer := util.NewWriter()
+	writer.WriteBytes(raw)
+	writer.WriteVarInt(uint64(len(rawTx)))
+	writer.WriteBytes(rawTx)
+	return writer.Buf, nil
+}
+
+// Validate checks each confiscation transaction is given as non-empty hex
+func (a *AlertMessageConfiscateTransaction) Validate(_ context.Context) error {
+	for _, tx := range a.Transactions {
+		if len(tx.ConfiscationTransaction.Hex) == 0 {
+			return ErrNoConfiscationTx
+		}
+		if _, err := hex.DecodeString(tx.ConfiscationTransaction.Hex); err != nil {
+			return fmt.Errorf("%w: %s", ErrFailedToReadTxHex, err.Error())
+		}
+	}
+	return nil
+}
+
 // Do execute the alert
 func (a *AlertMessageConfiscateTransaction) Do(ctx context.Context) error {
-	a.Config().Services.Log.Infof("ConfiscateTransaction alert; enforceAt [%d]; hex [%s]", a.Transactions[0].ConfiscationTransaction.EnforceAtHeight, hex.EncodeToString(a.GetRawMessage()))
-	res, err := a.Config().Services.Node.AddToConfiscationTransactionWhitelist(ctx, a.Transactions)
+	enforceAt := a.Transactions[0].ConfiscationTransaction.EnforceAtHeight
+	a.logEvent("confiscate_transaction", config.LogFields{"enforce_at_height": enforceAt, "transactions": len(a.Transactions)},
+		"ConfiscateTransaction alert; enforceAt [%d]; hex [%s]", enforceAt, hex.EncodeToString(a.GetRawMessage()))
+	var res *models.AddToConfiscationTransactionWhitelistResponse
+	err := a.callNode(ctx, func(ctx context.Context) (err error) {
+		res, err = a.Config().Services.Node.AddToConfiscationTransactionWhitelist(ctx, a.Transactions)
+		return err
+	})
 	if err != nil {
 		return err
 	}
+	a.setRPCResult(res)
 	if len(res.NotProcessed) > 0 {
 		// we can safely assume this is just one not processed tx because we are only publishing one tx with the alert right now
 		return fmt.Errorf("%w; reason: %s", ErrConfiscationAlertRPCError, res.NotProcessed[0].Reason)
@@ -83,11 +126,52 @@ func (a *AlertMessageConfiscateTransaction) Do(ctx context.Context) error {
 	return nil
 }
 
+// CheckEnforceAtHeight will check the enforce at heights against the node height, if the check is enabled
+//
+// This only makes sense for a newly received alert: historical alerts replayed during sync are expected
+// to have enforce heights far below the current tip, so it's not part of Do
+func (a *AlertMessageConfiscateTransaction) CheckEnforceAtHeight(ctx context.Context) error {
+	check := a.Config().ConfiscationHeightCheck
+	if !check.Enabled || a.Config().Services.NodeHeight == nil {
+		return nil
+	}
+	height, err := a.Config().Services.NodeHeight.Height(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tx := range a.Transactions {
+		if err = validateEnforceAtHeight(tx.ConfiscationTransaction.EnforceAtHeight, height, check); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckReceivedAlert will run the sanity checks that only apply to alerts as they are received from the network
+func CheckReceivedAlert(ctx context.Context, action AlertMessageInterface) error {
+	if confiscation, ok := action.(*AlertMessageConfiscateTransaction); ok {
+		return confiscation.CheckEnforceAtHeight(ctx)
+	}
+	return nil
+}
+
+// validateEnforceAtHeight will return an error if the enforce at height is implausible relative to the node height
+func validateEnforceAtHeight(enforceAtHeight int64, nodeHeight uint32, check config.HeightCheckConfig) error {
+	if enforceAtHeight < int64(nodeHeight)-int64(check.MaxBlocksInPast) {
+		return fmt.Errorf("%w: enforce at [%d]; node height [%d]", ErrEnforceAtHeightTooFarPast, enforceAtHeight, nodeHeight)
+	}
+	if enforceAtHeight > int64(nodeHeight)+int64(check.MaxBlocksInFuture) {
+		return fmt.Errorf("%w: enforce at [%d]; node height [%d]", ErrEnforceAtHeightTooFarAway, enforceAtHeight, nodeHeight)
+	}
+	return nil
+}
+
 // ToJSON is the alert in JSON format
 func (a *AlertMessageConfiscateTransaction) ToJSON(_ context.Context) []byte {
-	m := a.ProcessAlertMessage()
-	// TODO: Come back and add a message interface for each alert
-	_ = m.Read(a.GetRawMessage())
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
 	data, err := json.MarshalIndent(m, "", "    ")
 	if err != nil {
 		return []byte{}
@@ -95,10 +179,20 @@ func (a *AlertMessageConfiscateTransaction) ToJSON(_ context.Context) []byte {
 	return data
 }
 
+// MarshalJSON writes the fields of the alert body, the alert it's embedded in is written on its own
+func (a *AlertMessageConfiscateTransaction) MarshalJSON() ([]byte, error) {
+	return marshalBody(a)
+}
+
+// UnmarshalJSON reads the fields of the alert body written by MarshalJSON
+func (a *AlertMessageConfiscateTransaction) UnmarshalJSON(data []byte) error {
+	return unmarshalBody(data, a)
+}
+
 // MessageString executes the alert
 func (a *AlertMessageConfiscateTransaction) MessageString() string {
 	if len(a.Transactions) == 0 {
-		return "Confiscation alert: alert message contains no transaction data."
+		return fmt.Sprintf("[%s] Confiscation alert: alert message contains no transaction data.", AlertTypeConfiscateUtxo)
 	}
-	return fmt.Sprintf("Adding confiscation transaction [%x] to whitelist enforcing at height [%d].", a.Transactions[0].ConfiscationTransaction.Hex, a.Transactions[0].ConfiscationTransaction.EnforceAtHeight)
+	return fmt.Sprintf("[%s] Adding confiscation transaction [%x] to whitelist enforcing at height [%d].", AlertTypeConfiscateUtxo, a.Transactions[0].ConfiscationTransaction.Hex, a.Transactions[0].ConfiscationTransaction.EnforceAtHeight)
 }
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// TestValidateEnforceAtHeight will test the method validateEnforceAtHeight()
+func TestValidateEnforceAtHeight(t *testing.T) {
+	check := config.HeightCheckConfig{
+		Enabled:           true,
+		MaxBlocksInPast:   100,
+		MaxBlocksInFuture: 1000,
+	}
+
+	tests := []struct {
+		name            string
+		enforceAtHeight int64
+		nodeHeight      uint32
+		expectedErr     error
+	}{
+		{name: "in range, at node height", enforceAtHeight: 800000, nodeHeight: 800000},
+		{name: "in range, edge of the past", enforceAtHeight: 799900, nodeHeight: 800000},
+		{name: "in range, edge of the future", enforceAtHeight: 801000, nodeHeight: 800000},
+		{name: "in range, node below the past window", enforceAtHeight: 0, nodeHeight: 50},
+		{name: "far past", enforceAtHeight: 799899, nodeHeight: 800000, expectedErr: ErrEnforceAtHeightTooFarPast},
+		{name: "far past, height zero", enforceAtHeight: 0, nodeHeight: 800000, expectedErr: ErrEnforceAtHeightTooFarPast},
+		{name: "far future", enforceAtHeight: 801001, nodeHeight: 800000, expectedErr: ErrEnforceAtHeightTooFarAway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEnforceAtHeight(tt.enforceAtHeight, tt.nodeHeight, check)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestAlertMessageConfiscateTransaction_Do will test the method Do()
+func (ts *TestSuite) TestAlertMessageConfiscateTransaction_Do() {
+	newAlert := func(enforceAtHeight int64) (*AlertMessageConfiscateTransaction, *bool) {
+		whitelisted := false
+		ts.Dependencies.Services.Node = &mocks.Node{
+			BlockCountFunc: func(_ context.Context) (uint32, error) {
+				return 800000, nil
+			},
+			AddToConfiscationTransactionWhitelistFunc: func(_ context.Context, _ []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error) {
+				whitelisted = true
+				return &models.AddToConfiscationTransactionWhitelistResponse{}, nil
+			},
+		}
+		ts.Dependencies.Services.NodeHeight = config.NewNodeHeightCache(ts.Dependencies.Services.Node, config.DefaultNodeHeightCacheTTL)
+
+		a := &AlertMessageConfiscateTransaction{
+			AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()),
+			Transactions: []models.ConfiscationTransactionDetails{{
+				ConfiscationTransaction: models.ConfiscationTransaction{EnforceAtHeight: enforceAtHeight},
+			}},
+		}
+		return a, &whitelisted
+	}
+
+	ts.Run("replayed alert with a historical height is sent to the node", func() {
+		ts.Dependencies.ConfiscationHeightCheck.Enabled = true
+		a, whitelisted := newAlert(100)
+		ts.Require().NoError(a.Do(context.Background()))
+		ts.True(*whitelisted)
+	})
+
+	ts.Run("hung node RPC times out", func() {
+		ts.Dependencies.NodeRPCTimeout = 10 * time.Millisecond
+		ts.Dependencies.NodeRPCBaseBackoff = time.Millisecond
+		defer func() {
+			ts.Dependencies.NodeRPCTimeout = 0
+			ts.Dependencies.NodeRPCBaseBackoff = 0
+		}()
+		a, _ := newAlert(100)
+		a.SetAlertType(AlertTypeConfiscateUtxo)
+		a.SequenceNumber = 7
+		ts.Dependencies.Services.Node = &mocks.Node{
+			AddToConfiscationTransactionWhitelistFunc: func(ctx context.Context, _ []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		}
+
+		err := a.Do(context.Background())
+		ts.Require().ErrorIs(err, ErrNodeRPCRetriesExhausted)
+		ts.Require().ErrorIs(err, ErrNodeRPCTimeout)
+		ts.Require().ErrorIs(err, context.DeadlineExceeded)
+		ts.Contains(err.Error(), AlertTypeConfiscateUtxo.String()+" alert 7")
+	})
+
+	ts.Run("check disabled, far future height passes", func() {
+		ts.Dependencies.ConfiscationHeightCheck.Enabled = false
+		a, _ := newAlert(5000000)
+		ts.Require().NoError(CheckReceivedAlert(context.Background(), a))
+	})
+
+	ts.Run("check enabled, in range height passes", func() {
+		ts.Dependencies.ConfiscationHeightCheck.Enabled = true
+		a, _ := newAlert(800100)
+		ts.Require().NoError(CheckReceivedAlert(context.Background(), a))
+	})
+
+	ts.Run("check enabled, far past height is rejected", func() {
+		ts.Dependencies.ConfiscationHeightCheck.Enabled = true
+		a, _ := newAlert(100)
+		ts.Require().ErrorIs(CheckReceivedAlert(context.Background(), a), ErrEnforceAtHeightTooFarPast)
+	})
+
+	ts.Run("check enabled, far future height is rejected", func() {
+		ts.Dependencies.ConfiscationHeightCheck.Enabled = true
+		a, _ := newAlert(5000000)
+		ts.Require().ErrorIs(CheckReceivedAlert(context.Background(), a), ErrEnforceAtHeightTooFarAway)
+	})
+
+	ts.Run("other alert types are not checked", func() {
+		ts.Dependencies.ConfiscationHeightCheck.Enabled = true
+		a := &AlertMessageInformational{AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())}
+		ts.Require().NoError(CheckReceivedAlert(context.Background(), a))
+	})
+}
+
+// TestAlertMessageConfiscateTransaction_Validate will test the method Validate()
+func TestAlertMessageConfiscateTransaction_Validate(t *testing.T) {
+	a := &AlertMessageConfiscateTransaction{}
+	require.NoError(t, a.Read(append(make([]byte, EnforceAtHeightSize), 0x02, 0x01, 0x00)))
+	require.NoError(t, a.Validate(context.Background()))
+
+	// A transaction of no bytes parses, but there is nothing to confiscate
+	a = &AlertMessageConfiscateTransaction{}
+	require.NoError(t, a.Read(append(make([]byte, EnforceAtHeightSize), 0x00)))
+	require.ErrorIs(t, a.Validate(context.Background()), ErrNoConfiscationTx)
+
+	a.Transactions[0].ConfiscationTransaction.Hex = "zz"
+	require.ErrorIs(t, a.Validate(context.Background()), ErrFailedToReadTxHex)
+}
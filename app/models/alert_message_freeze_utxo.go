@@ -9,6 +9,9 @@ import (
 	"math"
 
 	"github.com/bsv-blockchain/go-bn/models"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
 )
 
 // AlertMessageFreezeUtxo is the message for freezing UTXOs
@@ -42,30 +45,45 @@ func (f *Fund) Serialize() []byte {
 	return raw
 }
 
+// checkBounds will check the vout and enforce at heights fit in an int before they are converted,
+// so a value over math.MaxInt can't wrap around (on 32-bit builds from 2^31)
+func (f *Fund) checkBounds() error {
+	if f.Vout > math.MaxInt {
+		return fmt.Errorf("%w: vout %d", ErrValueExceedsMaxInt, f.Vout)
+	}
+	if f.EnforceAtHeightStart > math.MaxInt {
+		return fmt.Errorf("%w: start %d", ErrEnforceAtHeightOverflow, f.EnforceAtHeightStart)
+	}
+	if f.EnforceAtHeightEnd > math.MaxInt {
+		return fmt.Errorf("%w: stop %d", ErrEnforceAtHeightOverflow, f.EnforceAtHeightEnd)
+	}
+	return nil
+}
+
 // Read reads the message
 func (a *AlertMessageFreezeUtxo) Read(raw []byte) error {
-	if len(raw) < 57 {
+	if len(raw) < FundSize {
 		return fmt.Errorf("%w, got %d bytes; raw: %x", ErrFreezeAlertTooShort, len(raw), raw)
 	}
-	if len(raw)%57 != 0 {
+	if len(raw)%FundSize != 0 {
 		return fmt.Errorf("%w, got %d bytes; raw: %x", ErrFreezeAlertInvalidLength, len(raw), raw)
 	}
-	fundCount := len(raw) / 57
+	fundCount := len(raw) / FundSize
 	var funds []models.Fund
 	for i := 0; i < fundCount; i++ {
 		fund := Fund{
-			TransactionOutID:     [32]byte(raw[0:32]),
-			Vout:                 binary.LittleEndian.Uint64(raw[32:40]),
-			EnforceAtHeightStart: binary.LittleEndian.Uint64(raw[40:48]),
-			EnforceAtHeightEnd:   binary.LittleEndian.Uint64(raw[48:56]),
+			TransactionOutID:     [32]byte(raw[:fundVoutOffset]),
+			Vout:                 binary.LittleEndian.Uint64(raw[fundVoutOffset:fundStartOffset]),
+			EnforceAtHeightStart: binary.LittleEndian.Uint64(raw[fundStartOffset:fundEndOffset]),
+			EnforceAtHeightEnd:   binary.LittleEndian.Uint64(raw[fundEndOffset:fundPolicyOffset]),
 		}
-		enforceByte := raw[56]
+		enforceByte := raw[fundPolicyOffset]
 
 		if enforceByte != uint8(0) {
 			fund.PolicyExpiresWithConsensus = true
 		}
-		if fund.Vout > math.MaxInt || fund.EnforceAtHeightStart > math.MaxInt || fund.EnforceAtHeightEnd > math.MaxInt {
-			return ErrValueExceedsMaxInt
+		if err := fund.checkBounds(); err != nil {
+			return err
 		}
 		funds = append(funds, models.Fund{
 			TxOut: models.TxOut{
@@ -80,27 +98,105 @@ func (a *AlertMessageFreezeUtxo) Read(raw []byte) error {
 			},
 			PolicyExpiresWithConsensus: fund.PolicyExpiresWithConsensus,
 		})
-		raw = raw[57:]
+		raw = raw[FundSize:]
 	}
 	a.Funds = funds
 
 	return nil
 }
 
+// Serialize writes the alert body back to the bytes Read consumes, FundSize bytes per fund
+func (a *AlertMessageFreezeUtxo) Serialize() ([]byte, error) {
+	return serializeFunds(a.Funds)
+}
+
+// serializeFunds will write each fund in its FundSize wire format
+func serializeFunds(funds []models.Fund) ([]byte, error) {
+	raw := make([]byte, 0, len(funds)*FundSize)
+	for _, fund := range funds {
+		txID, err := hex.DecodeString(fund.TxOut.TxId)
+		if err != nil || len(txID) != 32 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFundTxID, fund.TxOut.TxId)
+		}
+		if len(fund.EnforceAtHeight) != 1 {
+			return nil, fmt.Errorf("%w, got %d", ErrInvalidFundEnforceAtHeight, len(fund.EnforceAtHeight))
+		}
+		enforce := fund.EnforceAtHeight[0]
+		if fund.TxOut.Vout < 0 || enforce.Start < 0 || enforce.Stop < 0 {
+			return nil, fmt.Errorf("%w: %s:%d", ErrNegativeFundValue, fund.TxOut.TxId, fund.TxOut.Vout)
+		}
+		f := Fund{
+			TransactionOutID:           [32]byte(txID),
+			Vout:                       uint64(fund.TxOut.Vout),
+			EnforceAtHeightStart:       uint64(enforce.Start),
+			EnforceAtHeightEnd:         uint64(enforce.Stop),
+			PolicyExpiresWithConsensus: fund.PolicyExpiresWithConsensus,
+		}
+		raw = append(raw, f.Serialize()...)
+	}
+	return raw, nil
+}
+
+// Validate checks the enforce at height range and that no fund is listed twice
+func (a *AlertMessageFreezeUtxo) Validate(_ context.Context) error {
+	return validateFunds(a.Funds)
+}
+
+// validateFunds will check each fund's enforce at height range is in order and no outpoint is listed twice
+// A range that stops at 0 has no end, so any start is allowed
+func validateFunds(funds []models.Fund) error {
+	seen := make(map[models.TxOut]bool, len(funds))
+	for _, fund := range funds {
+		for _, enforce := range fund.EnforceAtHeight {
+			if enforce.Stop != 0 && enforce.Start > enforce.Stop {
+				return fmt.Errorf("%w: %s:%d from %d to %d", ErrEnforceAtHeightInverted, fund.TxOut.TxId, fund.TxOut.Vout, enforce.Start, enforce.Stop)
+			}
+		}
+		if seen[fund.TxOut] {
+			return fmt.Errorf("%w: %s:%d", ErrDuplicateFundInAlert, fund.TxOut.TxId, fund.TxOut.Vout)
+		}
+		seen[fund.TxOut] = true
+	}
+	return nil
+}
+
 // Do perform the message
+//
+// An alert with more funds than the configured maximum is refused before anything is sent to the node
 func (a *AlertMessageFreezeUtxo) Do(ctx context.Context) error {
-	_, err := a.Config().Services.Node.AddToConsensusBlacklist(ctx, a.Funds)
+	if maxFunds := a.Config().MaxFreezeFunds; maxFunds > 0 && len(a.Funds) > maxFunds {
+		return fmt.Errorf("%w: %d funds, maximum is %d", ErrTooManyFunds, len(a.Funds), maxFunds)
+	}
+
+	a.logEvent("freeze_utxo", config.LogFields{"funds": len(a.Funds)}, "FreezeUtxo alert; funds [%d]", len(a.Funds))
+	var res *models.AddToConsensusBlacklistResponse
+	err := a.callNode(ctx, func(ctx context.Context) (err error) {
+		res, err = a.Config().Services.Node.AddToConsensusBlacklist(ctx, a.Funds)
+		return err
+	})
 	if err != nil {
 		return err
 	}
+
+	a.setRPCResult(res)
+
+	// Store the funds the node processed, even if it rejected others
+	processed, rejected := splitProcessedFunds(a.Funds, res)
+	if err = saveFrozenOutpoints(ctx, processed, a.SequenceNumber, model.WithAllDependencies(a.Config())); err != nil {
+		return err
+	}
+	if len(rejected) > 0 {
+		return &PartialSuccessError{Cause: ErrFreezeAlertRPCError, Processed: len(processed), Rejected: rejected}
+	}
 	return nil
 }
 
 // ToJSON is the alert in JSON format
 func (a *AlertMessageFreezeUtxo) ToJSON(_ context.Context) []byte {
-	m := a.ProcessAlertMessage()
-	// TODO: Come back and add a message interface for each alert
-	_ = m.Read(a.GetRawMessage())
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
 	data, err := json.MarshalIndent(m, "", "    ")
 	if err != nil {
 		return []byte{}
@@ -108,10 +204,20 @@ func (a *AlertMessageFreezeUtxo) ToJSON(_ context.Context) []byte {
 	return data
 }
 
+// MarshalJSON writes the fields of the alert body, the alert it's embedded in is written on its own
+func (a *AlertMessageFreezeUtxo) MarshalJSON() ([]byte, error) {
+	return marshalBody(a)
+}
+
+// UnmarshalJSON reads the fields of the alert body written by MarshalJSON
+func (a *AlertMessageFreezeUtxo) UnmarshalJSON(data []byte) error {
+	return unmarshalBody(data, a)
+}
+
 // MessageString executes the alert
 func (a *AlertMessageFreezeUtxo) MessageString() string {
 	if len(a.Funds) == 0 || len(a.Funds[0].EnforceAtHeight) == 0 {
-		return "Freezing utxo: alert message contains no fund data."
+		return fmt.Sprintf("[%s] Freezing utxo: alert message contains no fund data.", AlertTypeFreezeUtxo)
 	}
-	return fmt.Sprintf("Freezing utxo id [%x]; vout: [%d], enforcing at height start [%d], end [%d].", a.Funds[0].TxOut.TxId, a.Funds[0].TxOut.Vout, a.Funds[0].EnforceAtHeight[0].Start, a.Funds[0].EnforceAtHeight[0].Stop)
+	return fmt.Sprintf("[%s] Freezing utxo id [%x]; vout: [%d], enforcing at height start [%d], end [%d].", AlertTypeFreezeUtxo, a.Funds[0].TxOut.TxId, a.Funds[0].TxOut.Vout, a.Funds[0].EnforceAtHeight[0].Start, a.Funds[0].EnforceAtHeight[0].Stop)
 }
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAlertMessageFreezeUtxo_Validate will test the method Validate()
+func TestAlertMessageFreezeUtxo_Validate(t *testing.T) {
+	txID := [32]byte([]byte(strings.Repeat("a", 32)))
+
+	t.Run("valid range", func(t *testing.T) {
+		f := Fund{TransactionOutID: txID, Vout: 1, EnforceAtHeightStart: 100, EnforceAtHeightEnd: 200}
+		a := &AlertMessageFreezeUtxo{}
+		require.NoError(t, a.Read(f.Serialize()))
+		require.NoError(t, a.Validate(context.Background()))
+	})
+
+	t.Run("inverted range parses but is invalid", func(t *testing.T) {
+		f := Fund{TransactionOutID: txID, Vout: 1, EnforceAtHeightStart: 200, EnforceAtHeightEnd: 100}
+		a := &AlertMessageFreezeUtxo{}
+		require.NoError(t, a.Read(f.Serialize()))
+		require.Len(t, a.Funds, 1)
+		assert.Equal(t, 200, a.Funds[0].EnforceAtHeight[0].Start)
+		require.ErrorIs(t, a.Validate(context.Background()), ErrEnforceAtHeightInverted)
+	})
+
+	t.Run("range without an end", func(t *testing.T) {
+		f := Fund{TransactionOutID: txID, Vout: 1, EnforceAtHeightStart: 200}
+		a := &AlertMessageFreezeUtxo{}
+		require.NoError(t, a.Read(f.Serialize()))
+		require.NoError(t, a.Validate(context.Background()))
+	})
+
+	t.Run("duplicate outpoint", func(t *testing.T) {
+		f := Fund{TransactionOutID: txID, Vout: 1, EnforceAtHeightStart: 100, EnforceAtHeightEnd: 200}
+		a := &AlertMessageFreezeUtxo{}
+		require.NoError(t, a.Read(append(f.Serialize(), f.Serialize()...)))
+		require.ErrorIs(t, a.Validate(context.Background()), ErrDuplicateFundInAlert)
+
+		// The same outpoint in an unfreeze alert
+		u := &AlertMessageUnfreezeUtxo{}
+		require.NoError(t, u.Read(append(f.Serialize(), f.Serialize()...)))
+		require.ErrorIs(t, u.Validate(context.Background()), ErrDuplicateFundInAlert)
+	})
+}
+
+// TestAlertMessageFreezeUtxo_ReadBounds will test values that don't fit in an int are rejected by freeze and unfreeze
+func TestAlertMessageFreezeUtxo_ReadBounds(t *testing.T) {
+	txID := [32]byte([]byte(strings.Repeat("a", 32)))
+	tests := []struct {
+		name     string
+		fund     Fund
+		expected error
+	}{
+		{"vout", Fund{TransactionOutID: txID, Vout: math.MaxUint64}, ErrValueExceedsMaxInt},
+		{"start height", Fund{TransactionOutID: txID, EnforceAtHeightStart: math.MaxUint64}, ErrEnforceAtHeightOverflow},
+		{"stop height", Fund{TransactionOutID: txID, EnforceAtHeightStart: 1, EnforceAtHeightEnd: math.MaxUint64}, ErrEnforceAtHeightOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, (&AlertMessageFreezeUtxo{}).Read(tt.fund.Serialize()), tt.expected)
+			require.ErrorIs(t, (&AlertMessageUnfreezeUtxo{}).Read(tt.fund.Serialize()), tt.expected)
+		})
+	}
+
+	t.Run("largest int", func(t *testing.T) {
+		f := Fund{TransactionOutID: txID, Vout: math.MaxInt, EnforceAtHeightStart: math.MaxInt, EnforceAtHeightEnd: math.MaxInt}
+		a := &AlertMessageFreezeUtxo{}
+		require.NoError(t, a.Read(f.Serialize()))
+		assert.Equal(t, math.MaxInt, a.Funds[0].EnforceAtHeight[0].Stop)
+	})
+}
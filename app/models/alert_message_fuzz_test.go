@@ -64,6 +64,18 @@ func FuzzNewAlertFromBytes(f *testing.F) {
 	specialAlert = append(specialAlert, make([]byte, 128)...)
 	f.Add(specialAlert)
 
+	// Seed with a version 3 alert, with the counted signature block after the header
+	countedAlert := make([]byte, 0)
+	countedAlert = binary.LittleEndian.AppendUint32(countedAlert, AlertVersionSignatureCount)
+	countedAlert = binary.LittleEndian.AppendUint32(countedAlert, 2)
+	countedAlert = binary.LittleEndian.AppendUint64(countedAlert, 0)
+	countedAlert = binary.LittleEndian.AppendUint32(countedAlert, uint32(AlertTypeInformational))
+	countedAlert = append(countedAlert, 4)                                // signature count
+	countedAlert = append(countedAlert, make([]byte, 4*SignatureSize)...) // signatures
+	countedAlert = binary.LittleEndian.AppendUint32(countedAlert, 1)      // supersedes
+	countedAlert = append(countedAlert, []byte("test")...)
+	f.Add(countedAlert)
+
 	// Seed with edge cases
 	f.Add([]byte{})           // empty
 	f.Add([]byte{0})          // single byte
@@ -131,9 +143,10 @@ func FuzzAlertMessageReadRaw(f *testing.F) {
 // FuzzAlertMessageSerialize tests round-trip serialization consistency
 func FuzzAlertMessageSerialize(f *testing.F) {
 	// Seed with valid alert components
-	f.Add(uint32(1), uint32(1), uint64(1234567890), uint32(AlertTypeInformational), []byte("test"))
+	f.Add(uint32(1), uint32(1), uint64(1234567890), uint32(AlertTypeInformational), []byte("test"), uint8(3))
+	f.Add(uint32(AlertVersionSignatureCount), uint32(2), uint64(1234567890), uint32(AlertTypeInformational), []byte("test"), uint8(5))
 
-	f.Fuzz(func(t *testing.T, version, sequence uint32, timestamp uint64, alertType uint32, message []byte) {
+	f.Fuzz(func(t *testing.T, version, sequence uint32, timestamp uint64, alertType uint32, message []byte, signatureCount uint8) {
 		// Create alert
 		alert := NewAlertMessage()
 		alert.SetVersion(version)
@@ -160,12 +173,16 @@ func FuzzAlertMessageSerialize(f *testing.F) {
 		}
 		alert.SetRawMessage(message)
 
-		// Add dummy signatures (3 x 65 bytes)
-		alert.SetSignatures([][]byte{
-			make([]byte, 65),
-			make([]byte, 65),
-			make([]byte, 65),
-		})
+		// Add dummy signatures, always 3 on the legacy layout and counted from version 3
+		count := SignatureCount
+		if hasSignatureCount(version) {
+			count = int(signatureCount)%MaxSignatureCount + 1
+		}
+		sigs := make([][]byte, 0, count)
+		for i := 0; i < count; i++ {
+			sigs = append(sigs, make([]byte, SignatureSize))
+		}
+		alert.SetSignatures(sigs)
 
 		// Serialize should never panic
 		serialized := alert.Serialize()
@@ -177,5 +194,11 @@ func FuzzAlertMessageSerialize(f *testing.F) {
 
 		// Validate the serialized data structure
 		require.GreaterOrEqual(t, len(serialized), 20, "serialized data should include header")
+
+		// Whatever parses back has the same signatures and signed data
+		if parsed, err := NewAlertFromBytes(serialized); err == nil {
+			require.Len(t, parsed.Signatures(), count)
+			require.Equal(t, alert.GetRawData(), parsed.GetRawData())
+		}
 	})
 }
@@ -2,10 +2,14 @@ package models
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/bsv-blockchain/go-sdk/util"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
 )
 
 // AlertMessageInformational is an informational alert
@@ -28,6 +32,9 @@ func (a *AlertMessageInformational) Read(alert []byte) error {
 	if length > uint64(len(reader.Data)) {
 		return ErrInfoMessageLengthTooLong
 	}
+	if maxLength := a.maxMessageLength(); length > uint64(maxLength) {
+		return fmt.Errorf("%w: %d bytes, the limit is %d", ErrInfoMessageTooLong, length, maxLength)
+	}
 
 	// read the message
 	var msg []byte
@@ -46,6 +53,27 @@ func (a *AlertMessageInformational) Read(alert []byte) error {
 	return nil
 }
 
+// maxMessageLength returns the configured limit of the message length, or the default without a config
+func (a *AlertMessageInformational) maxMessageLength() int {
+	if c := a.Config(); c != nil && c.MaxInfoMessageLength > 0 {
+		return c.MaxInfoMessageLength
+	}
+	return config.DefaultMaxInfoMessageLength
+}
+
+// Serialize writes the alert body back to the bytes Read consumes
+func (a *AlertMessageInformational) Serialize() ([]byte, error) {
+	writer := util.NewWriter()
+	writer.WriteVarInt(uint64(len(a.Message)))
+	writer.WriteBytes(a.Message)
+	return writer.Buf, nil
+}
+
+// Validate checks the alert, there are no rules beyond what Read parses
+func (a *AlertMessageInformational) Validate(_ context.Context) error {
+	return nil
+}
+
 // Do execute the alert
 func (a *AlertMessageInformational) Do(_ context.Context) error {
 	a.Config().Services.Log.Infof("[informational alert]: %s", a.Message)
@@ -54,9 +82,10 @@ func (a *AlertMessageInformational) Do(_ context.Context) error {
 
 // ToJSON is the alert in JSON format
 func (a *AlertMessageInformational) ToJSON(_ context.Context) []byte {
-	m := a.ProcessAlertMessage()
-	// TODO: Come back and add a message interface for each alert
-	_ = m.Read(a.GetRawMessage())
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
 	data, err := json.MarshalIndent(m, "", "    ")
 	if err != nil {
 		return []byte{}
@@ -66,5 +95,52 @@ func (a *AlertMessageInformational) ToJSON(_ context.Context) []byte {
 
 // MessageString executes the alert
 func (a *AlertMessageInformational) MessageString() string {
-	return fmt.Sprintf("Informational: %s", a.Message)
+	if a.useBase64() {
+		return fmt.Sprintf("[%s] Informational (base64): %s", AlertTypeInformational, base64.StdEncoding.EncodeToString(a.Message))
+	}
+	return fmt.Sprintf("[%s] Informational: %s", AlertTypeInformational, a.Message)
+}
+
+// informationalJSON is the informational alert body as it's written to JSON
+type informationalJSON struct {
+	Encoding      string `json:"encoding,omitempty"`
+	Message       string `json:"message"`
+	MessageLength uint64 `json:"message_length"`
+}
+
+// MarshalJSON writes the message as a string, a message that isn't valid UTF-8 is base64 encoded
+// and marked with "encoding": "base64" (unless the strict encoding is configured, then it's an error)
+func (a *AlertMessageInformational) MarshalJSON() ([]byte, error) {
+	out := informationalJSON{Message: string(a.Message), MessageLength: a.MessageLength}
+	if a.useBase64() {
+		out.Message, out.Encoding = base64.StdEncoding.EncodeToString(a.Message), config.InfoMessageEncodingBase64
+	} else if !utf8.Valid(a.Message) {
+		return nil, ErrInfoMessageNotUTF8
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads the message written by MarshalJSON, decoding it if it's marked as base64
+func (a *AlertMessageInformational) UnmarshalJSON(data []byte) error {
+	var in informationalJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	a.Message, a.MessageLength = []byte(in.Message), in.MessageLength
+	if in.Encoding == config.InfoMessageEncodingBase64 {
+		message, err := base64.StdEncoding.DecodeString(in.Message)
+		if err != nil {
+			return err
+		}
+		a.Message = message
+	}
+	return nil
+}
+
+// useBase64 will check if the message has to be base64 encoded to be written as text
+func (a *AlertMessageInformational) useBase64() bool {
+	if utf8.Valid(a.Message) {
+		return false
+	}
+	return a.Config() == nil || a.Config().InfoMessageEncoding != config.InfoMessageEncodingStrict
 }
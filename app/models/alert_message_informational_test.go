@@ -1,10 +1,16 @@
 package models
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
 )
 
 // TestAlertMessageInformational_Read will test the method Read()
@@ -51,6 +57,33 @@ func TestAlertMessageInformational_Read(t *testing.T) {
 	}
 }
 
+// TestAlertMessageInformational_ReadMaxLength will test the limit of the message length
+func TestAlertMessageInformational_ReadMaxLength(t *testing.T) {
+	// newMessage will serialize an informational message of the given length
+	newMessage := func(length int) []byte {
+		raw, err := (&AlertMessageInformational{Message: bytes.Repeat([]byte{'a'}, length)}).Serialize()
+		require.NoError(t, err)
+		return raw
+	}
+
+	t.Run("default limit without a config", func(t *testing.T) {
+		require.NoError(t, (&AlertMessageInformational{}).Read(newMessage(config.DefaultMaxInfoMessageLength)))
+		require.ErrorIs(t, (&AlertMessageInformational{}).Read(newMessage(config.DefaultMaxInfoMessageLength+1)), ErrInfoMessageTooLong)
+	})
+
+	t.Run("configured limit", func(t *testing.T) {
+		a := &AlertMessageInformational{AlertMessage: *NewAlertMessage(model.WithAllDependencies(&config.Config{MaxInfoMessageLength: 5}))}
+		require.NoError(t, a.Read(newMessage(5)))
+		require.ErrorIs(t, a.Read(newMessage(6)), ErrInfoMessageTooLong)
+	})
+
+	t.Run("length longer than the buffer is a separate error", func(t *testing.T) {
+		err := (&AlertMessageInformational{}).Read([]byte{0xfd, 0x00, 0x20, 'a'})
+		require.ErrorIs(t, err, ErrInfoMessageLengthTooLong)
+		require.NotErrorIs(t, err, ErrInfoMessageTooLong)
+	})
+}
+
 func TestAlertMessageInformational_MessageString(t *testing.T) {
 	type fields struct {
 		AlertMessage  AlertMessage
@@ -70,7 +103,7 @@ func TestAlertMessageInformational_MessageString(t *testing.T) {
 				},
 				Message: []byte("testing"),
 			},
-			want: "Informational: testing",
+			want: "[informational] Informational: testing",
 		},
 
 		// TODO: Add test cases.
@@ -86,3 +119,56 @@ func TestAlertMessageInformational_MessageString(t *testing.T) {
 		})
 	}
 }
+
+// TestAlertMessageInformational_MarshalJSON will test the method MarshalJSON()
+func TestAlertMessageInformational_MarshalJSON(t *testing.T) {
+	binary := []byte{0xff, 0xfe, 'h', 'i', 0x80}
+
+	// marshal will marshal the message with the given encoding configured
+	marshal := func(t *testing.T, encoding string, message []byte) (map[string]interface{}, error) {
+		a := &AlertMessageInformational{
+			AlertMessage:  *NewAlertMessage(model.WithAllDependencies(&config.Config{InfoMessageEncoding: encoding})),
+			MessageLength: uint64(len(message)),
+			Message:       message,
+		}
+		data, err := json.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		fields := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(data, &fields))
+		return fields, nil
+	}
+
+	t.Run("utf-8 message is plain text", func(t *testing.T) {
+		fields, err := marshal(t, config.InfoMessageEncodingBase64, []byte("hello ✓"))
+		require.NoError(t, err)
+		assert.Equal(t, "hello ✓", fields["message"])
+		assert.NotContains(t, fields, "encoding")
+		assert.InDelta(t, float64(len("hello ✓")), fields["message_length"], 0)
+	})
+
+	t.Run("binary message is base64", func(t *testing.T) {
+		fields, err := marshal(t, config.InfoMessageEncodingBase64, binary)
+		require.NoError(t, err)
+		assert.Equal(t, "base64", fields["encoding"])
+		decoded, err := base64.StdEncoding.DecodeString(fields["message"].(string))
+		require.NoError(t, err)
+		assert.Equal(t, binary, decoded)
+	})
+
+	t.Run("binary message is rejected in strict mode", func(t *testing.T) {
+		_, err := marshal(t, config.InfoMessageEncodingStrict, binary)
+		require.ErrorIs(t, err, ErrInfoMessageNotUTF8)
+
+		// Valid messages are unaffected
+		fields, err := marshal(t, config.InfoMessageEncodingStrict, []byte("hello"))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", fields["message"])
+	})
+
+	t.Run("message string", func(t *testing.T) {
+		a := &AlertMessageInformational{Message: binary}
+		assert.Equal(t, "[informational] Informational (base64): "+base64.StdEncoding.EncodeToString(binary), a.MessageString())
+	})
+}
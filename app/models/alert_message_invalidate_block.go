@@ -20,25 +20,22 @@ type AlertMessageInvalidateBlock struct {
 
 // Read reads the alert
 func (a *AlertMessageInvalidateBlock) Read(alert []byte) error {
-	if len(alert) < 32 {
-		return fmt.Errorf("%w: need at least 32 bytes for block hash, got %d", ErrAlertTooShort, len(alert))
+	if len(alert) < BlockHashSize {
+		return fmt.Errorf("%w: need at least %d bytes for block hash, got %d", ErrAlertTooShort, BlockHashSize, len(alert))
 	}
 
-	blockHash, err := chainhash.NewHash(alert[:32])
+	blockHash, err := chainhash.NewHash(alert[:BlockHashSize])
 	if err != nil {
 		return err
 	}
 
-	reader := util.NewReader(alert[32:])
+	reader := util.NewReader(alert[BlockHashSize:])
 
 	// read the reason length
 	var length uint64
 	if length, err = reader.ReadVarInt(); err != nil {
 		return err
 	}
-	if length == 0 {
-		return ErrNoReasonMessageProvided
-	}
 	var msg []byte
 	for i := uint64(0); i < length; i++ {
 		var b byte
@@ -56,17 +53,40 @@ func (a *AlertMessageInvalidateBlock) Read(alert []byte) error {
 	return nil
 }
 
+// Serialize writes the alert body back to the bytes Read consumes
+func (a *AlertMessageInvalidateBlock) Serialize() ([]byte, error) {
+	if a.BlockHash == nil {
+		return nil, ErrNoBlockHash
+	}
+	writer := util.NewWriter()
+	writer.WriteBytes(a.BlockHash[:])
+	writer.WriteVarInt(uint64(len(a.Reason)))
+	writer.WriteBytes(a.Reason)
+	return writer.Buf, nil
+}
+
+// Validate checks a reason was given for invalidating the block
+func (a *AlertMessageInvalidateBlock) Validate(_ context.Context) error {
+	if a.ReasonLength == 0 {
+		return ErrNoReasonMessageProvided
+	}
+	return nil
+}
+
 // Do execute the alert
 func (a *AlertMessageInvalidateBlock) Do(ctx context.Context) error {
 	a.Config().Services.Log.Infof("InvalidateBlock alert; hash [%s]; reason [%s]", a.BlockHash, a.Reason)
-	return a.Config().Services.Node.InvalidateBlock(ctx, a.BlockHash.String())
+	rpcCtx, cancel := a.nodeContext(ctx)
+	defer cancel()
+	return a.wrapTimeout(a.Config().Services.Node.InvalidateBlock(rpcCtx, a.BlockHash.String()))
 }
 
 // ToJSON is the alert in JSON format
 func (a *AlertMessageInvalidateBlock) ToJSON(_ context.Context) []byte {
-	m := a.ProcessAlertMessage()
-	// TODO: Come back and add a message interface for each alert
-	_ = m.Read(a.GetRawMessage())
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
 	data, err := json.MarshalIndent(m, "", "    ")
 	if err != nil {
 		return []byte{}
@@ -74,7 +94,17 @@ func (a *AlertMessageInvalidateBlock) ToJSON(_ context.Context) []byte {
 	return data
 }
 
+// MarshalJSON writes the fields of the alert body, the alert it's embedded in is written on its own
+func (a *AlertMessageInvalidateBlock) MarshalJSON() ([]byte, error) {
+	return marshalBody(a)
+}
+
+// UnmarshalJSON reads the fields of the alert body written by MarshalJSON
+func (a *AlertMessageInvalidateBlock) UnmarshalJSON(data []byte) error {
+	return unmarshalBody(data, a)
+}
+
 // MessageString executes the alert
 func (a *AlertMessageInvalidateBlock) MessageString() string {
-	return fmt.Sprintf("Invalidating block hash [%s]; reason [%s].", a.BlockHash, a.Reason)
+	return fmt.Sprintf("[%s] Invalidating block hash [%s]; reason [%s].", AlertTypeInvalidateBlock, a.BlockHash, a.Reason)
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"encoding/hex"
 	"testing"
 
@@ -63,7 +64,9 @@ func TestAlertMessageInvalidateBlock_Read(t *testing.T) {
 			require.NoError(t, err)
 			alertBytes = util.ReverseBytes(alertBytes)
 			alertBytes = append(alertBytes, tt.reason...)
-			err = a.Read(alertBytes)
+			if err = a.Read(alertBytes); err == nil {
+				err = a.Validate(context.Background())
+			}
 			if tt.expectError {
 				require.Error(t, err)
 			} else {
@@ -80,3 +83,15 @@ func TestAlertMessageInvalidateBlock_Read(t *testing.T) {
 		})
 	}
 }
+
+// TestAlertMessageInvalidateBlock_Validate will test the method Validate()
+func TestAlertMessageInvalidateBlock_Validate(t *testing.T) {
+	a := &AlertMessageInvalidateBlock{}
+	require.NoError(t, a.Read(append(make([]byte, 32), 0x05, 'h', 'e', 'l', 'l', 'o')))
+	require.NoError(t, a.Validate(context.Background()))
+
+	// An empty reason parses, but isn't a valid alert
+	a = &AlertMessageInvalidateBlock{}
+	require.NoError(t, a.Read(append(make([]byte, 32), 0x00)))
+	require.ErrorIs(t, a.Validate(context.Background()), ErrNoReasonMessageProvided)
+}
@@ -3,12 +3,16 @@ package models
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/bitcoinsv/bsvd/bsvec"
 	"github.com/mrz1836/go-datastore"
 
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
@@ -25,15 +29,15 @@ type AlertMessageSetKeys struct {
 // Read reads the message
 func (a *AlertMessageSetKeys) Read(alert []byte) error {
 	// Check the length
-	if len(alert) != 165 {
+	if len(alert) != SetKeysMessageSize {
 		return fmt.Errorf("%w, got %d bytes, not valid", ErrSetKeysAlertInvalidLength, len(alert))
 	}
 	buf := bytes.NewReader(alert[:])
 
 	// Read the message hash
-	for key := 0; key < 5; key++ {
+	for key := 0; key < SetKeysCount; key++ {
 		var pubKey []byte
-		for i := uint64(0); i < 33; i++ {
+		for i := uint64(0); i < PublicKeySize; i++ {
 			b, err := buf.ReadByte()
 			if err != nil {
 				return fmt.Errorf("%w: %s", ErrFailedToReadPubKey, err.Error())
@@ -46,36 +50,142 @@ func (a *AlertMessageSetKeys) Read(alert []byte) error {
 	return nil
 }
 
+// Serialize writes the alert body back to the bytes Read consumes, always SetKeysMessageSize bytes
+func (a *AlertMessageSetKeys) Serialize() ([]byte, error) {
+	if len(a.Keys) != SetKeysCount {
+		return nil, fmt.Errorf("%w, got %d keys", ErrSetKeysAlertInvalidLength, len(a.Keys))
+	}
+	raw := make([]byte, 0, SetKeysMessageSize)
+	for _, key := range a.Keys {
+		raw = append(raw, key[:]...)
+	}
+	return raw, nil
+}
+
+// Validate checks every key is a valid compressed public key and no key is listed twice
+func (a *AlertMessageSetKeys) Validate(_ context.Context) error {
+	seen := make(map[[33]byte]bool, len(a.Keys))
+	for _, key := range a.Keys {
+		if _, err := bsvec.ParsePubKey(key[:], bsvec.S256()); err != nil {
+			return fmt.Errorf("%w: %x", ErrInvalidPubKeyFormat, key)
+		}
+		if seen[key] {
+			return fmt.Errorf("%w: %x", ErrDuplicatePubKey, key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
 // Do execute the alert
+//
+// The keys are checked, then the active key set is replaced in a single datastore transaction,
+// while the cached keys are locked, so no alert is verified against a partial or mixed set
 func (a *AlertMessageSetKeys) Do(ctx context.Context) error {
-	err := ClearActivePublicKeys(ctx, a.Config().Services.Datastore)
+	if err := a.Validate(ctx); err != nil {
+		return err
+	}
+	return activePublicKeys.swap(func() error {
+		return a.rotateKeys(ctx)
+	})
+}
+
+// rotateKeys will revoke the current keys and activate the keys of the alert, or change nothing on an error
+func (a *AlertMessageSetKeys) rotateKeys(ctx context.Context) error {
+	opts := model.WithAllDependencies(a.Config())
+	current, err := GetActivePublicKey(ctx, nil, opts)
 	if err != nil {
 		return err
 	}
+
+	// Every key row that changes, in the order they're saved
+	changed := make([]*PublicKey, 0, len(current)+len(a.Keys))
+	byKey := make(map[string]*PublicKey, len(current)+len(a.Keys))
+	oldKeys := make([]string, 0, len(current))
+	now := time.Now().UTC()
+	for _, pk := range current {
+		pk.SetOptions(opts)
+		pk.revoke(now)
+		changed = append(changed, pk)
+		byKey[pk.Key] = pk
+		oldKeys = append(oldKeys, pk.Key)
+	}
+	newKeys := make([]string, 0, len(a.Keys))
 	for _, key := range a.Keys {
-		pk := NewPublicKey(model.WithAllDependencies(a.Config()))
-		conditions := map[string]interface{}{
-			"key": hex.EncodeToString(key[:]),
-		}
-		err := model.Get(ctx, pk, conditions, 5*time.Second, false)
-		if !errors.Is(err, datastore.ErrNoResults) && err != nil {
-			return err
+		newKeys = append(newKeys, hex.EncodeToString(key[:]))
+	}
+	if sameKeys(oldKeys, newKeys) {
+		// Running the alert again won't change anything either, so it's invalid rather than failed
+		return fmt.Errorf("%w: %w", ErrAlertInvalid, ErrSetKeysUnchanged)
+	}
+
+	for _, key := range newKeys {
+		pk, ok := byKey[key]
+		if !ok {
+			pk = NewPublicKey(opts)
+			if err = model.Get(ctx, pk, map[string]interface{}{"key": key}, 5*time.Second, false); errors.Is(err, datastore.ErrNoResults) {
+				pk = NewPublicKey(opts, model.New())
+			} else if err != nil {
+				return err
+			}
+			changed = append(changed, pk)
+			byKey[key] = pk
 		}
-		pk.Key = hex.EncodeToString(key[:])
-		pk.Active = true
-		pk.LastUpdateHash = a.Hash
-		if err = pk.Save(ctx); err != nil {
-			return err
+		pk.Key = key
+		pk.activate()
+		pk.LastUpdateHash = a.AlertMessage.Hash
+	}
+
+	if err = a.Config().Services.Datastore.NewTx(ctx, func(tx *datastore.Transaction) error {
+		saved := make([]model.BaseInterface, 0, len(changed))
+		for _, pk := range changed {
+			toSave, txErr := pk.BeginSaveWithTx(ctx, tx)
+			if txErr != nil {
+				_ = tx.Rollback()
+				return txErr
+			}
+			saved = append(saved, toSave...)
 		}
+		return model.CompleteSaveWithTx(ctx, tx, saved)
+	}); err != nil {
+		return err
 	}
+
+	a.Config().Services.Log.Infof(
+		"alert %d: rotated the public keys from [%s] to [%s]",
+		a.SequenceNumber, keyFingerprints(oldKeys), keyFingerprints(newKeys),
+	)
 	return nil
 }
 
+// sameKeys returns true if both lists have the same keys, in any order
+func sameKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// keyFingerprints will return a short fingerprint of each key, to log the keys without their full hex
+func keyFingerprints(keys []string) string {
+	fingerprints := make([]string, 0, len(keys))
+	for _, key := range keys {
+		raw, _ := hex.DecodeString(key)
+		sum := sha256.Sum256(raw)
+		fingerprints = append(fingerprints, hex.EncodeToString(sum[:4]))
+	}
+	return strings.Join(fingerprints, ", ")
+}
+
 // ToJSON is the alert in JSON format
 func (a *AlertMessageSetKeys) ToJSON(_ context.Context) []byte {
-	m := a.ProcessAlertMessage()
-	// TODO: Come back and add a message interface for each alert
-	_ = m.Read(a.GetRawMessage())
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
 	data, err := json.MarshalIndent(m, "", "    ")
 	if err != nil {
 		return []byte{}
@@ -83,10 +193,20 @@ func (a *AlertMessageSetKeys) ToJSON(_ context.Context) []byte {
 	return data
 }
 
+// MarshalJSON writes the fields of the alert body, the alert it's embedded in is written on its own
+func (a *AlertMessageSetKeys) MarshalJSON() ([]byte, error) {
+	return marshalBody(a)
+}
+
+// UnmarshalJSON reads the fields of the alert body written by MarshalJSON
+func (a *AlertMessageSetKeys) UnmarshalJSON(data []byte) error {
+	return unmarshalBody(data, a)
+}
+
 // MessageString executes the alert
 func (a *AlertMessageSetKeys) MessageString() string {
 	if len(a.Keys) < 5 {
-		return "Setting keys: alert message contains an incomplete key set."
+		return fmt.Sprintf("[%s] Setting keys: alert message contains an incomplete key set.", AlertTypeSetKeys)
 	}
-	return fmt.Sprintf("Setting keys: %x, %x, %x, %x, %x", a.Keys[0], a.Keys[1], a.Keys[2], a.Keys[3], a.Keys[4])
+	return fmt.Sprintf("[%s] Setting keys: %x, %x, %x, %x, %x", AlertTypeSetKeys, a.Keys[0], a.Keys[1], a.Keys[2], a.Keys[3], a.Keys[4])
 }
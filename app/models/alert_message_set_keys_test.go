@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/bitcoinschema/go-bitcoin"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// TestAlertMessageSetKeys_Do will test rotating the active public keys
+func (ts *TestSuite) TestAlertMessageSetKeys_Do() {
+	ctx := context.Background()
+	opts := model.WithAllDependencies(ts.Dependencies)
+	newSetKeys := func(keys ...string) *AlertMessageSetKeys {
+		a := &AlertMessageSetKeys{AlertMessage: *NewAlertMessage(opts), Keys: vectorPublicKeys(keys...)}
+		a.AlertMessage.Hash = "rotation-hash"
+		return a
+	}
+	mainKeys := []string{utils.MainKey1, utils.MainKey2, utils.MainKey3, utils.MainKey4, utils.MainKey5}
+
+	ts.Run("rotates the keys", func() {
+		ts.Require().NoError(newSetKeys(mainKeys...).Do(ctx))
+
+		keys, err := GetActivePublicKey(ctx, nil, opts)
+		ts.Require().NoError(err)
+		ts.Require().Len(keys, SetKeysCount)
+		for i, key := range keys {
+			ts.Equal(mainKeys[i], key.Key)
+			ts.Equal("rotation-hash", key.LastUpdateHash)
+		}
+	})
+
+	ts.Run("a rotation to the same keys is rejected", func() {
+		reordered := []string{utils.MainKey5, utils.MainKey4, utils.MainKey3, utils.MainKey2, utils.MainKey1}
+		err := newSetKeys(reordered...).Do(ctx)
+		ts.Require().ErrorIs(err, ErrSetKeysUnchanged)
+		ts.Require().ErrorIs(err, ErrAlertInvalid)
+	})
+
+	ts.Run("an invalid key changes nothing", func() {
+		invalid := "04" + hex.EncodeToString(make([]byte, 32))
+		ts.Require().ErrorIs(newSetKeys(utils.MainKey1, utils.MainKey2, utils.MainKey3, utils.MainKey4, invalid).Do(ctx), ErrInvalidPubKeyFormat)
+
+		keys, err := GetActivePublicKey(ctx, nil, opts)
+		ts.Require().NoError(err)
+		ts.Len(keys, SetKeysCount)
+	})
+
+	ts.Run("keys kept across a rotation stay active", func() {
+		newKey, err := bitcoin.PubKeyFromPrivateKeyString(utils.Key1, true)
+		ts.Require().NoError(err)
+		rotated := []string{utils.MainKey1, utils.MainKey2, utils.MainKey3, utils.MainKey4, newKey}
+		ts.Require().NoError(newSetKeys(rotated...).Do(ctx))
+
+		keys, err := GetActivePublicKey(ctx, nil, opts)
+		ts.Require().NoError(err)
+		active := make([]string, 0, len(keys))
+		for _, key := range keys {
+			active = append(active, key.Key)
+		}
+		ts.ElementsMatch(rotated, active)
+
+		// The replaced key is kept, revoked
+		history, err := GetPublicKeyHistory(ctx, nil, opts)
+		ts.Require().NoError(err)
+		ts.Require().Len(history, SetKeysCount+1)
+		ts.Equal(utils.MainKey5, history[SetKeysCount-1].Key)
+		ts.False(history[SetKeysCount-1].Active)
+		ts.True(history[SetKeysCount-1].RevokedAt.Valid)
+	})
+}
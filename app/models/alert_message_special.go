@@ -0,0 +1,68 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// AlertMessageSpecial is the special alert (type 99)
+//
+// Its message is opaque to this node and it has no action, it's only stored and relayed. Before version 3 it has a
+// shorter signature block than the other types, a single signature padded to 128 bytes (see wire_format.go)
+type AlertMessageSpecial struct {
+	AlertMessage
+
+	Message []byte `json:"message"`
+}
+
+// Read reads the alert message from the byte slice
+func (a *AlertMessageSpecial) Read(alert []byte) error {
+	a.Message = bytes.Clone(alert)
+	return nil
+}
+
+// Serialize writes the alert body back to the bytes Read consumes
+func (a *AlertMessageSpecial) Serialize() ([]byte, error) {
+	return bytes.Clone(a.Message), nil
+}
+
+// Validate checks the alert, there are no rules beyond what Read parses
+func (a *AlertMessageSpecial) Validate(_ context.Context) error {
+	return nil
+}
+
+// Do execute the alert, the special alert has no action
+func (a *AlertMessageSpecial) Do(_ context.Context) error {
+	a.Config().Services.Log.Infof("[special alert]: %d bytes, no action taken", len(a.Message))
+	return nil
+}
+
+// ToJSON is the alert in JSON format
+func (a *AlertMessageSpecial) ToJSON(_ context.Context) []byte {
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
+	data, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return []byte{}
+	}
+	return data
+}
+
+// MarshalJSON writes the fields of the alert body, the alert it's embedded in is written on its own
+func (a *AlertMessageSpecial) MarshalJSON() ([]byte, error) {
+	return marshalBody(a)
+}
+
+// UnmarshalJSON reads the fields of the alert body written by MarshalJSON
+func (a *AlertMessageSpecial) UnmarshalJSON(data []byte) error {
+	return unmarshalBody(data, a)
+}
+
+// MessageString executes the alert
+func (a *AlertMessageSpecial) MessageString() string {
+	return fmt.Sprintf("[%s] Special alert; message [%x].", AlertTypeSpecial, a.Message)
+}
@@ -1,13 +1,23 @@
 package models
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"time"
 
+	"github.com/bitcoinschema/go-bitcoin"
+	"github.com/bsv-blockchain/go-sdk/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/bsv-blockchain/go-alert-system/app/config"
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
 )
 
 const (
@@ -108,6 +118,51 @@ func (ts *TestSuite) TestAlertMessage_GetLatestAlert() {
 	ts.Require().Equal(uint32(2), message.SequenceNumber)
 }
 
+// TestFindMissingSequences will test finding the sequence numbers that aren't stored
+func (ts *TestSuite) TestFindMissingSequences() {
+	ctx := context.Background()
+	opts := model.WithAllDependencies(ts.Dependencies)
+	for _, sequence := range []uint32{1, 2, 4, 7} {
+		message := NewAlertMessage(opts, model.New())
+		message.Hash = fmt.Sprintf("%s%d", testAlertHash, sequence)
+		message.Raw = testAlertRaw
+		message.SequenceNumber = sequence
+		ts.Require().NoError(message.Save(ctx))
+	}
+
+	ts.Run("gaps in the range", func() {
+		missing, err := FindMissingSequences(ctx, 1, 8, opts)
+		ts.Require().NoError(err)
+		ts.Equal([]uint32{3, 5, 6, 8}, missing)
+	})
+
+	ts.Run("range fully stored", func() {
+		missing, err := FindMissingSequences(ctx, 1, 2, opts)
+		ts.Require().NoError(err)
+		ts.NotNil(missing)
+		ts.Empty(missing)
+	})
+
+	ts.Run("single sequence", func() {
+		missing, err := FindMissingSequences(ctx, 3, 3, opts)
+		ts.Require().NoError(err)
+		ts.Equal([]uint32{3}, missing)
+	})
+
+	ts.Run("range ending at the last sequence number", func() {
+		missing, err := FindMissingSequences(ctx, math.MaxUint32-1, math.MaxUint32, opts)
+		ts.Require().NoError(err)
+		ts.Equal([]uint32{math.MaxUint32 - 1, math.MaxUint32}, missing)
+	})
+
+	ts.Run("invalid ranges", func() {
+		_, err := FindMissingSequences(ctx, 5, 4, opts)
+		ts.Require().ErrorIs(err, ErrSequenceRangeInvalid)
+		_, err = FindMissingSequences(ctx, 1, MaxMissingSequenceRange+1, opts)
+		ts.Require().ErrorIs(err, ErrSequenceRangeTooLarge)
+	})
+}
+
 // TestAlertMessage_SerializeData will test serializing the data
 func (ts *TestSuite) TestAlertMessage_SerializeData() {
 	message := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
@@ -129,3 +184,612 @@ func (ts *TestSuite) TestAlertMessage_SerializeData() {
 	ts.Equal("0000000001000000000000000000000001000000", hex.EncodeToString(message.GetRawData()))
 	ts.Equal(AlertTypeInformational, message.GetAlertType())
 }
+
+// TestAlertMessage_SerializeSignatureOrder will test that signature order does not change the serialized alert
+//
+// The hash only covers the header and message (never the signatures), so only the raw bytes are compared
+func (ts *TestSuite) TestAlertMessage_SerializeSignatureOrder() {
+	newAlert := func() *AlertMessage {
+		a := NewAlertMessage()
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage([]byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+		a.SequenceNumber = 1
+		a.SetTimestamp(1700000000)
+		a.SetVersion(1)
+		a.SerializeData()
+		return a
+	}
+
+	first := newAlert()
+	sigs, err := utils.SignWithGenesis(first.GetRawData())
+	ts.Require().NoError(err)
+	ts.Require().Len(sigs, 3)
+
+	first.SetSignatures([][]byte{sigs[0], sigs[1], sigs[2]})
+	firstRaw := first.Serialize()
+
+	second := newAlert()
+	second.SetSignatures([][]byte{sigs[2], sigs[0], sigs[1]})
+	secondRaw := second.Serialize()
+
+	ts.Equal(firstRaw, secondRaw)
+	ts.Equal(first.Raw, second.Raw)
+
+	ts.Run("re-parsed alert keeps the received bytes", func() {
+		// Build the raw alert with the signatures out of canonical order
+		received := append(bytes.Clone(first.GetRawData()), sigs[2]...)
+		received = append(received, sigs[0]...)
+		received = append(received, sigs[1]...)
+
+		parsed, err := NewAlertFromBytes(received)
+		ts.Require().NoError(err)
+		ts.Equal(received, parsed.Serialize())
+		ts.Equal(hex.EncodeToString(received), parsed.Raw)
+	})
+}
+
+// TestAlertMessage_Version will test the handling of the alert header version
+func (ts *TestSuite) TestAlertMessage_Version() {
+	// newVersionedAlert will create a signed informational alert with the given version
+	newVersionedAlert := func(version uint32, message []byte) []byte {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage(message)
+		a.SequenceNumber = 3
+		a.SetTimestamp(1700000000)
+		a.SetVersion(version)
+		a.SerializeData()
+
+		sigs, err := utils.SignWithGenesis(a.GetRawData())
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		return a.Serialize()
+	}
+
+	ts.Run("current version", func() {
+		parsed, err := NewAlertFromBytes(newVersionedAlert(AlertVersionCurrent, []byte{0x02, 'h', 'i'}), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.True(parsed.SupportedVersion())
+		body, err := parsed.ProcessAlertMessage()
+		ts.Require().NoError(err)
+		ts.NotNil(body)
+	})
+
+	ts.Run("version 0 is rejected", func() {
+		_, err := NewAlertFromBytes(newVersionedAlert(0, []byte{0x02, 'h', 'i'}))
+		ts.Require().ErrorIs(err, ErrAlertVersionZero)
+	})
+
+	ts.Run("future version is kept but never executed", func() {
+		// The unknown header fields of the future version are part of the opaque message
+		raw := newVersionedAlert(AlertVersionCurrent+1, []byte{0xaa, 0xbb, 0xcc, 0xdd, 0x02, 'h', 'i'})
+		parsed, err := NewAlertFromBytes(raw, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.False(parsed.SupportedVersion())
+		ts.False(parsed.KnownType())
+		body, err := parsed.ProcessAlertMessage()
+		ts.Require().NoError(err)
+		ts.Nil(body)
+		ts.Equal(uint32(3), parsed.SequenceNumber)
+		ts.Equal(uint32(0), parsed.Supersedes)
+
+		// Relayed byte for byte, and the signed data is unchanged so the signatures still verify
+		signatureBlock := 1 + SignatureBlockSize // the varint count and the signatures
+		ts.Equal(append(slices.Clone(raw[:AlertHeaderSize]), raw[AlertHeaderSize+signatureBlock:]...), parsed.GetRawData())
+		ts.Equal(raw, parsed.Serialize())
+	})
+}
+
+// TestAlertMessage_Timestamp will test the check of far future alert timestamps when an alert is read
+func (ts *TestSuite) TestAlertMessage_Timestamp() {
+	// newTimestampAlert will create a signed informational alert with the given timestamp
+	newTimestampAlert := func(timestamp time.Time) []byte {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SequenceNumber = 3
+		a.SetTimestamp(uint64(timestamp.Unix())) //nolint:gosec // test timestamps are after 1970
+		a.SetVersion(AlertVersionCurrent)
+		a.SerializeData()
+
+		sigs, err := utils.SignWithGenesis(a.GetRawData())
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		return a.Serialize()
+	}
+	farFuture := newTimestampAlert(time.Now().Add(365 * 24 * time.Hour))
+
+	ts.Run("not checked by default", func() {
+		_, err := NewAlertFromBytes(farFuture, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+	})
+
+	ts.Run("checked with a skew", func() {
+		ts.Dependencies.MaxAlertTimestampSkew = 2 * time.Hour
+		defer func() {
+			ts.Dependencies.MaxAlertTimestampSkew = 0
+		}()
+
+		_, err := NewAlertFromBytes(farFuture, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrAlertTimestampInFuture)
+
+		// Within the skew, and in the past, are fine
+		_, err = NewAlertFromBytes(newTimestampAlert(time.Now().Add(time.Hour)), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		_, err = NewAlertFromBytes(newTimestampAlert(time.Unix(1700000000, 0)), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+	})
+
+	ts.Run("a stored alert is read after the skew is lowered", func() {
+		stored, err := NewAlertFromBytes(newTimestampAlert(time.Now().Add(time.Hour)), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NoError(stored.Save(context.Background()))
+
+		ts.Dependencies.MaxAlertTimestampSkew = time.Minute
+		defer func() {
+			ts.Dependencies.MaxAlertTimestampSkew = 0
+		}()
+		read, err := GetAlertMessageBySequenceNumber(context.Background(), 3, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NoError(read.ReadRaw())
+		ts.Equal(stored.Timestamp(), read.Timestamp())
+	})
+}
+
+// TestAlertMessage_MaxSize will test the check of the configured maximum alert size when an alert is read
+func (ts *TestSuite) TestAlertMessage_MaxSize() {
+	// newSizedAlert will create a signed informational alert with a message of the given length
+	newSizedAlert := func(length int) []byte {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage(append(util.VarInt(length).Bytes(), bytes.Repeat([]byte{'a'}, length)...))
+		a.SequenceNumber = 3
+		a.SetTimestamp(1700000000)
+		a.SetVersion(AlertVersionCurrent)
+		a.SerializeData()
+
+		sigs, err := utils.SignWithGenesis(a.GetRawData())
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		return a.Serialize()
+	}
+	alert := newSizedAlert(1000)
+
+	defer func(size int) {
+		ts.Dependencies.MaxAlertSize = size
+	}(ts.Dependencies.MaxAlertSize)
+
+	ts.Run("at the limit", func() {
+		ts.Dependencies.MaxAlertSize = len(alert)
+		_, err := NewAlertFromBytes(alert, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+	})
+
+	ts.Run("over the limit", func() {
+		ts.Dependencies.MaxAlertSize = len(alert) - 1
+		_, err := NewAlertFromBytes(alert, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrAlertTooLong)
+	})
+
+	ts.Run("a stored alert is read after the limit is lowered", func() {
+		ts.Dependencies.MaxAlertSize = len(alert)
+		stored, err := NewAlertFromBytes(alert, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NoError(stored.Save(context.Background()))
+
+		ts.Dependencies.MaxAlertSize = len(alert) - 1
+		read, err := GetAlertMessageBySequenceNumber(context.Background(), 3, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NoError(read.ReadRaw())
+		ts.Equal(stored.GetRawMessage(), read.GetRawMessage())
+	})
+
+	ts.Run("the default limit when unset", func() {
+		ts.Dependencies.MaxAlertSize = 0
+		_, err := NewAlertFromBytes(alert, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+
+		_, err = NewAlertFromBytes(make([]byte, config.DefaultMaxAlertSize+1), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrAlertTooLong)
+	})
+}
+
+// TestAlertMessage_Supersedes will test reading, writing and marking a superseded alert
+func (ts *TestSuite) TestAlertMessage_Supersedes() {
+	// newSupersedingAlert will create a signed informational alert superseding the given sequence
+	newSupersedingAlert := func(sequence, supersedes uint32) []byte {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage([]byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+		a.SequenceNumber = sequence
+		a.SetTimestamp(1700000000)
+		a.SetVersion(1)
+		a.SetSupersedes(supersedes)
+		a.SerializeData()
+
+		sigs, err := utils.SignWithGenesis(a.GetRawData())
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		return a.Serialize()
+	}
+
+	ts.Run("round trip", func() {
+		parsed, err := NewAlertFromBytes(newSupersedingAlert(3, 2))
+		ts.Require().NoError(err)
+		ts.Equal(uint32(AlertVersionSupersedes), parsed.Version())
+		ts.Equal(uint32(2), parsed.Supersedes)
+		ts.Equal([]byte{0x05, 'h', 'e', 'l', 'l', 'o'}, parsed.GetRawMessage())
+		ts.Len(parsed.GetRawData(), AlertHeaderSize+AlertSupersedesSize+6)
+	})
+
+	ts.Run("version 1 alerts supersede nothing", func() {
+		a := NewAlertMessage()
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SetVersion(1)
+		a.SerializeData()
+		ts.Len(a.GetRawData(), AlertHeaderSize+3)
+		ts.Equal(uint32(0), a.Supersedes)
+	})
+
+	ts.Run("only an earlier sequence can be superseded", func() {
+		_, err := NewAlertFromBytes(newSupersedingAlert(3, 3))
+		ts.Require().ErrorIs(err, ErrSupersedesNotEarlier)
+	})
+
+	ts.Run("marks the earlier alert", func() {
+		earlier, err := NewAlertFromBytes(newSupersedingAlert(4, 0), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NoError(earlier.Save(context.Background()))
+
+		var later *AlertMessage
+		later, err = NewAlertFromBytes(newSupersedingAlert(5, 4), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NoError(later.Save(context.Background()))
+		ts.Require().NoError(MarkSupersededAlert(context.Background(), later))
+
+		earlier, err = GetAlertMessageBySequenceNumber(context.Background(), 4, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.True(earlier.Superseded)
+		ts.Equal(uint32(5), earlier.SupersededBy)
+		ts.Equal(uint32(0), earlier.Supersedes)
+	})
+
+	ts.Run("unverified alerts mark nothing", func() {
+		later, err := NewAlertFromBytes(newSupersedingAlert(7, 6), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		later.Unverified = true
+		ts.Require().NoError(MarkSupersededAlert(context.Background(), later))
+	})
+}
+
+// TestAlertMessage_Verify will test the method Verify()
+func (ts *TestSuite) TestAlertMessage_Verify() {
+	// publicKeys will get the public keys of the private keys
+	publicKeys := func(privateKeys ...string) []*PublicKey {
+		keys := make([]*PublicKey, 0, len(privateKeys))
+		for _, privateKey := range privateKeys {
+			pub, err := bitcoin.PubKeyFromPrivateKeyString(privateKey, true)
+			ts.Require().NoError(err)
+			keys = append(keys, &PublicKey{Key: pub})
+		}
+		return keys
+	}
+	activeKeys := publicKeys(utils.Key1, utils.Key2, utils.Key3, utils.Key4, utils.Key5)
+
+	// newSignedAlert will create an informational alert signed with the private keys
+	newSignedAlert := func(privateKeys ...string) *AlertMessage {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies))
+		a.SetVersion(AlertVersionSignatureCount)
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SequenceNumber = 5
+		a.SerializeData()
+		sigs, err := utils.SignWithKeys(a.GetRawData(), privateKeys)
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		return a
+	}
+
+	ts.Run("quorum of distinct active keys", func() {
+		ts.Require().NoError(newSignedAlert(utils.Key1, utils.Key4, utils.Key5).Verify(context.Background(), activeKeys))
+	})
+
+	ts.Run("no active keys", func() {
+		ts.Require().ErrorIs(newSignedAlert(utils.Key1, utils.Key2, utils.Key3).Verify(context.Background(), nil), ErrNoActivePublicKeys)
+	})
+
+	ts.Run("signature from a key that isn't active", func() {
+		a := newSignedAlert(utils.Key1, utils.Key2, utils.Key3)
+		ts.Require().ErrorIs(a.Verify(context.Background(), publicKeys(utils.Key1, utils.Key2, utils.Key4)), ErrInsufficientValidSignatures)
+	})
+
+	ts.Run("signed by the keys in order", func() {
+		signers, err := newSignedAlert(utils.Key4, utils.Key1).SignedBy(activeKeys)
+		ts.Require().NoError(err)
+		ts.Equal([]*PublicKey{activeKeys[0], activeKeys[3]}, signers)
+	})
+
+	ts.Run("a key listed twice is one signer", func() {
+		keys := publicKeys(utils.Key1, utils.Key1, utils.Key2)
+		a := newSignedAlert(utils.Key1, utils.Key1, utils.Key2)
+		signers, err := a.SignedBy(keys)
+		ts.Require().NoError(err)
+		ts.Len(signers, 2)
+		ts.Require().ErrorIs(a.Verify(context.Background(), keys), ErrInsufficientValidSignatures)
+	})
+
+	ts.Run("one key signing more than once", func() {
+		a := newSignedAlert(utils.Key1, utils.Key1, utils.Key2)
+		ts.Require().ErrorIs(a.Verify(context.Background(), activeKeys), ErrInsufficientValidSignatures)
+	})
+
+	ts.Run("tampered message", func() {
+		a := newSignedAlert(utils.Key1, utils.Key2, utils.Key3)
+		a.SequenceNumber = 6
+		a.SerializeData()
+		ts.Require().ErrorIs(a.Verify(context.Background(), activeKeys), ErrInsufficientValidSignatures)
+	})
+
+	ts.Run("configured threshold", func() {
+		threshold := ts.Dependencies.SignatureThreshold
+		ts.Dependencies.SignatureThreshold = 4
+		defer func() {
+			ts.Dependencies.SignatureThreshold = threshold
+		}()
+
+		ts.Require().ErrorIs(newSignedAlert(utils.Key1, utils.Key2, utils.Key3).Verify(context.Background(), activeKeys), ErrInsufficientValidSignatures)
+		ts.Require().NoError(newSignedAlert(utils.Key1, utils.Key2, utils.Key3, utils.Key5).Verify(context.Background(), activeKeys))
+	})
+
+	ts.Run("legacy alert with a threshold above its signatures", func() {
+		threshold := ts.Dependencies.SignatureThreshold
+		ts.Dependencies.SignatureThreshold = 4
+		defer func() {
+			ts.Dependencies.SignatureThreshold = threshold
+		}()
+
+		// The legacy layout only has room for three signatures, so three valid ones are enough
+		a := newSignedAlert()
+		a.SetVersion(1)
+		a.SerializeData()
+		sigs, err := utils.SignWithKeys(a.GetRawData(), []string{utils.Key1, utils.Key2, utils.Key3})
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		ts.Require().NoError(a.Verify(context.Background(), activeKeys))
+
+		read, err := NewAlertFromBytes(a.Serialize(), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(read.Signatures(), SignatureCount)
+		ts.Require().NoError(read.Verify(context.Background(), activeKeys))
+
+		sigs, err = utils.SignWithKeys(a.GetRawData(), []string{utils.Key1, utils.Key2, utils.Key1})
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		ts.Require().ErrorIs(a.Verify(context.Background(), activeKeys), ErrInsufficientValidSignatures)
+	})
+
+	ts.Run("special alert needs its single signature", func() {
+		a := newSignedAlert(utils.Key2)
+		ts.Require().ErrorIs(a.Verify(context.Background(), activeKeys), ErrInsufficientValidSignatures)
+
+		a.SetAlertType(AlertTypeSpecial)
+		a.SetVersion(1)
+		a.SerializeData()
+		sigs, err := utils.SignWithKeys(a.GetRawData(), []string{utils.Key2})
+		ts.Require().NoError(err)
+		a.SetSignatures(sigs)
+		ts.Require().NoError(a.Verify(context.Background(), activeKeys))
+		ts.Require().ErrorIs(a.Verify(context.Background(), publicKeys(utils.Key1)), ErrInsufficientValidSignatures)
+	})
+}
+
+// TestAlertMessage_Special will test reading and writing the special alert (type 99)
+func TestAlertMessage_Special(t *testing.T) {
+	a := NewAlertMessage()
+	a.SetVersion(1)
+	a.SetAlertType(AlertTypeSpecial)
+	a.SetRawMessage([]byte("special"))
+	a.SequenceNumber = 3
+	a.SerializeData()
+	sigs, err := utils.SignWithKeys(a.GetRawData(), []string{utils.Key1})
+	require.NoError(t, err)
+	a.SetSignatures(sigs)
+
+	t.Run("legacy block is one signature and padding", func(t *testing.T) {
+		raw := a.Serialize()
+		require.Len(t, raw, AlertHeaderSize+len("special")+AlertType99SignatureBlock)
+
+		read, err := NewAlertFromBytes(raw)
+		require.NoError(t, err)
+		assert.Equal(t, AlertTypeSpecial, read.GetAlertType())
+		assert.Equal(t, []byte("special"), read.GetRawMessage())
+		require.Len(t, read.Signatures(), SpecialSignatureCount)
+		assert.Equal(t, raw, read.Serialize())
+
+		body, err := read.ProcessAlertMessage()
+		require.NoError(t, err)
+		special, ok := body.(*AlertMessageSpecial)
+		require.True(t, ok)
+		assert.Equal(t, []byte("special"), special.Message)
+	})
+
+	t.Run("padding isn't checked", func(t *testing.T) {
+		raw := a.Serialize()
+		raw[len(raw)-1] = 0x01
+		read, err := NewAlertFromBytes(raw)
+		require.NoError(t, err)
+		require.Len(t, read.Signatures(), SpecialSignatureCount)
+		assert.Equal(t, a.Signatures(), read.Signatures())
+	})
+}
+
+// TestGetAlertMessagesByType will test the method GetAlertMessagesByType()
+func (ts *TestSuite) TestGetAlertMessagesByType() {
+	// saveAlert will store an alert of the given type
+	saveAlert := func(sequence uint32, alertType AlertType) {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(alertType)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SequenceNumber = sequence
+		a.SetVersion(1)
+		a.SetSignatures([][]byte{make([]byte, SignatureSize)})
+		_ = a.Serialize()
+		ts.Require().NoError(a.Save(context.Background()))
+	}
+	saveAlert(1, AlertTypeInformational)
+	saveAlert(2, AlertTypeBanPeer)
+	saveAlert(3, AlertTypeInformational)
+	saveAlert(4, AlertTypeInformational)
+
+	// sequences will get the sequence numbers of the alerts
+	sequences := func(alerts []*AlertMessage) []uint32 {
+		seqs := make([]uint32, 0, len(alerts))
+		for _, a := range alerts {
+			seqs = append(seqs, a.SequenceNumber)
+		}
+		return seqs
+	}
+
+	ts.Run("newest first", func() {
+		alerts, err := GetAlertMessagesByType(context.Background(), AlertTypeInformational, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Equal([]uint32{4, 3, 1}, sequences(alerts))
+	})
+
+	ts.Run("limit", func() {
+		alerts, err := GetAlertMessagesByType(context.Background(), AlertTypeInformational, model.WithAllDependencies(ts.Dependencies), model.WithLimit(2))
+		ts.Require().NoError(err)
+		ts.Equal([]uint32{4, 3}, sequences(alerts))
+	})
+
+	ts.Run("none match", func() {
+		alerts, err := GetAlertMessagesByType(context.Background(), AlertTypeConfiscateUtxo, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.NotNil(alerts)
+		ts.Empty(alerts)
+	})
+
+	ts.Run("backfill alerts stored without a type", func() {
+		a, err := GetAlertMessageBySequenceNumber(context.Background(), 2, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		a.AlertType = 0
+		ts.Require().NoError(a.Save(context.Background()))
+
+		ts.Require().NoError(BackfillAlertTypes(context.Background(), model.WithAllDependencies(ts.Dependencies)))
+		alerts, err := GetAlertMessagesByType(context.Background(), AlertTypeBanPeer, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Equal([]uint32{2}, sequences(alerts))
+	})
+}
+
+// TestGetAlertMessages will test the method GetAlertMessages()
+func (ts *TestSuite) TestGetAlertMessages() {
+	for sequence := uint32(1); sequence <= 5; sequence++ {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SequenceNumber = sequence
+		a.SetVersion(1)
+		_ = a.Serialize()
+		ts.Require().NoError(a.Save(context.Background()))
+	}
+
+	// page will get the sequence numbers and total of a page of alerts
+	page := func(limit, offset int) ([]uint32, int) {
+		alerts, total, err := GetAlertMessages(context.Background(), limit, offset, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NotNil(alerts)
+		seqs := make([]uint32, 0, len(alerts))
+		for _, a := range alerts {
+			seqs = append(seqs, a.SequenceNumber)
+		}
+		return seqs, total
+	}
+
+	ts.Run("pages", func() {
+		seqs, total := page(2, 0)
+		ts.Equal([]uint32{5, 4}, seqs)
+		ts.Equal(5, total)
+
+		seqs, _ = page(2, 2)
+		ts.Equal([]uint32{3, 2}, seqs)
+
+		seqs, _ = page(2, 4)
+		ts.Equal([]uint32{1}, seqs)
+	})
+
+	ts.Run("offset part way through a page", func() {
+		seqs, _ := page(2, 1)
+		ts.Equal([]uint32{4, 3}, seqs)
+
+		seqs, _ = page(3, 4)
+		ts.Equal([]uint32{1}, seqs)
+	})
+
+	ts.Run("past the end", func() {
+		seqs, total := page(2, 10)
+		ts.Empty(seqs)
+		ts.Equal(5, total)
+	})
+
+	ts.Run("invalid limit or offset", func() {
+		_, _, err := GetAlertMessages(context.Background(), 0, 0, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrInvalidPageLimit)
+		_, _, err = GetAlertMessages(context.Background(), MaxAlertMessagesLimit+1, 0, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrInvalidPageLimit)
+		_, _, err = GetAlertMessages(context.Background(), 10, -1, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, ErrNegativePageOffset)
+	})
+}
+
+// TestCheckExecutionTypes will test the method CheckExecutionTypes()
+func TestCheckExecutionTypes(t *testing.T) {
+	t.Run("names and numbers", func(t *testing.T) {
+		c := &config.Config{ExecutedAlertTypes: []string{"informational", "2"}, SuppressedAlertTypes: []string{"ban_peer", "special", "unknown(100)"}}
+		require.NoError(t, CheckExecutionTypes(c))
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		require.ErrorIs(t, CheckExecutionTypes(&config.Config{ExecutedAlertTypes: []string{"freeze"}}), ErrUnknownAlertTypeName)
+		require.ErrorIs(t, CheckExecutionTypes(&config.Config{SuppressedAlertTypes: []string{"ban"}}), ErrUnknownAlertTypeName)
+	})
+
+	t.Run("set keys can't be suppressed", func(t *testing.T) {
+		require.ErrorIs(t, CheckExecutionTypes(&config.Config{SuppressedAlertTypes: []string{"8"}}), ErrCannotSuppressSetKeys)
+	})
+}
+
+// TestAlertMessage_VerifyHash will test the method VerifyHash()
+func TestAlertMessage_VerifyHash(t *testing.T) {
+	a := NewAlertMessage()
+	a.SetVersion(1)
+	a.SetAlertType(AlertTypeInformational)
+	a.SetRawMessage([]byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+	a.SequenceNumber = 1
+	a.SerializeData()
+	sigs, err := utils.SignWithGenesis(a.GetRawData())
+	require.NoError(t, err)
+	a.SetSignatures(sigs)
+
+	read, err := NewAlertFromBytes(a.Serialize())
+	require.NoError(t, err)
+	read.SerializeData()
+	require.NoError(t, read.VerifyHash())
+
+	t.Run("mislabeled hash", func(t *testing.T) {
+		mislabeled := *read
+		mislabeled.Hash = testAlertHash
+		err = mislabeled.VerifyHash()
+		require.ErrorIs(t, err, ErrAlertHashMismatch)
+		assert.Contains(t, err.Error(), testAlertHash)
+	})
+
+	t.Run("raw that isn't an alert", func(t *testing.T) {
+		for _, raw := range []string{"", "zz", "0100"} {
+			broken := *read
+			broken.Raw = raw
+			require.ErrorIs(t, broken.VerifyHash(), ErrAlertHashMismatch, raw)
+		}
+	})
+}
@@ -1,9 +1,12 @@
 package models
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"testing"
 
+	"github.com/bsv-blockchain/go-bn/models"
 	"github.com/bsv-blockchain/go-sdk/util"
 	"github.com/stretchr/testify/require"
 )
@@ -50,6 +53,20 @@ func assertLengthFieldValid(t *testing.T, lengthField uint64, actualData, inputD
 	require.Equal(t, lengthField, uint64(len(actualData)), "%s length should match %s data", fieldName, fieldName)
 }
 
+// serializableAlert is an alert body that can be written back to the wire
+type serializableAlert interface {
+	Read(raw []byte) error
+	Serialize() ([]byte, error)
+}
+
+// assertSerializeRoundTrip checks that reading the serialized alert gives back the alert that was parsed
+func assertSerializeRoundTrip(t *testing.T, parsed, reread serializableAlert) {
+	raw, err := parsed.Serialize()
+	require.NoError(t, err)
+	require.NoError(t, reread.Read(raw))
+	require.Equal(t, parsed, reread)
+}
+
 // buildUtxoAlertMessage builds a 57-byte UTXO freeze/unfreeze alert message
 func buildUtxoAlertMessage(vout, startHeight, endHeight uint64, expireFlag byte) []byte {
 	msg := make([]byte, 57)
@@ -61,6 +78,21 @@ func buildUtxoAlertMessage(vout, startHeight, endHeight uint64, expireFlag byte)
 	return msg
 }
 
+// assertDuplicateFunds will check the result of validating the funds agrees with whether an outpoint is repeated
+func assertDuplicateFunds(t *testing.T, funds []models.Fund, validateErr error) {
+	seen := make(map[models.TxOut]bool, len(funds))
+	duplicate := false
+	for _, fund := range funds {
+		duplicate = duplicate || seen[fund.TxOut]
+		seen[fund.TxOut] = true
+	}
+	if validateErr == nil {
+		require.False(t, duplicate, "an alert with a repeated outpoint should not validate")
+	} else if errors.Is(validateErr, ErrDuplicateFundInAlert) {
+		require.True(t, duplicate, "only a repeated outpoint should be a duplicate fund")
+	}
+}
+
 // buildHeightPlusVarIntMessage builds a message with an 8-byte height followed by VarInt-prefixed data
 func buildHeightPlusVarIntMessage(height uint64, data []byte) []byte {
 	heightBytes := make([]byte, 8)
@@ -92,6 +124,9 @@ func FuzzAlertMessageBanPeerRead(f *testing.F) {
 	validMsg := buildVarIntMessage(peerData, reasonData)
 	f.Add(validMsg)
 
+	// Seed with a ban duration after the reason (extended layout)
+	f.Add(binary.LittleEndian.AppendUint64(buildVarIntMessage(peerData, reasonData), 3600))
+
 	// Seed with edge cases
 	addCommonEdgeCases(f)
 
@@ -117,6 +152,14 @@ func FuzzAlertMessageBanPeerRead(f *testing.F) {
 		// If successful, validate the parsed data
 		assertLengthFieldValid(t, alert.PeerLength, alert.Peer, data, "peer")
 		assertLengthFieldValid(t, alert.ReasonLength, alert.Reason, data, "reason")
+
+		// Nothing after the reason is a permanent ban
+		if len(data) == len(buildVarIntMessage(alert.Peer, alert.Reason)) {
+			require.Zero(t, alert.BanDurationSeconds)
+		}
+
+		// Serializing the parsed alert gives bytes that read back the same
+		assertSerializeRoundTrip(t, alert, &AlertMessageBanPeer{})
 	})
 }
 
@@ -148,6 +191,9 @@ func FuzzAlertMessageUnbanPeerRead(f *testing.F) {
 		// Validate parsed data
 		assertLengthFieldValid(t, alert.PeerLength, alert.Peer, data, "peer")
 		assertLengthFieldValid(t, alert.ReasonLength, alert.Reason, data, "reason")
+
+		// Serializing the parsed alert gives bytes that read back the same
+		assertSerializeRoundTrip(t, alert, &AlertMessageUnbanPeer{})
 	})
 }
 
@@ -187,6 +233,9 @@ func FuzzAlertMessageInformationalRead(f *testing.F) {
 
 		// Validate successful parse
 		assertLengthFieldValid(t, alert.MessageLength, alert.Message, data, "message")
+
+		// Serializing the parsed alert gives bytes that read back the same
+		assertSerializeRoundTrip(t, alert, &AlertMessageInformational{})
 	})
 }
 
@@ -197,10 +246,13 @@ func FuzzAlertMessageFreezeUtxoRead(f *testing.F) {
 	validMsg := buildUtxoAlertMessage(0, 100000, 200000, 1)
 	f.Add(validMsg)
 
-	// Multiple funds (114 bytes = 2 funds)
+	// Multiple funds (114 bytes = 2 funds), the same outpoint twice
 	multipleMsg := append(validMsg, validMsg...)
 	f.Add(multipleMsg)
 
+	// Two different outpoints
+	f.Add(append(buildUtxoAlertMessage(0, 100000, 200000, 0), buildUtxoAlertMessage(1, 100000, 200000, 0)...))
+
 	// Edge cases
 	f.Add([]byte{})          // empty
 	f.Add(make([]byte, 56))  // one byte short
@@ -212,6 +264,11 @@ func FuzzAlertMessageFreezeUtxoRead(f *testing.F) {
 	overflowMsg := buildUtxoAlertMessage(^uint64(0), 100000, 200000, 0)
 	f.Add(overflowMsg)
 
+	// Max uint64 enforce at heights, and the first value over a 32-bit int
+	f.Add(buildUtxoAlertMessage(0, ^uint64(0), 200000, 0))
+	f.Add(buildUtxoAlertMessage(0, 100000, ^uint64(0), 0))
+	f.Add(buildUtxoAlertMessage(0, 1<<31, 1<<31, 0))
+
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Guard against oversized inputs that would trip Go's fuzztime context
 		if len(data) > maxFuzzInputSize {
@@ -237,6 +294,12 @@ func FuzzAlertMessageFreezeUtxoRead(f *testing.F) {
 			require.LessOrEqual(t, fund.EnforceAtHeight[0].Start, int(^uint(0)>>1), "start height should not overflow int")
 			require.LessOrEqual(t, fund.EnforceAtHeight[0].Stop, int(^uint(0)>>1), "end height should not overflow int")
 		}
+
+		// A repeated outpoint is found by Validate, and nothing else is reported as one
+		assertDuplicateFunds(t, alert.Funds, alert.Validate(context.Background()))
+
+		// Serializing the parsed alert gives bytes that read back the same
+		assertSerializeRoundTrip(t, alert, &AlertMessageFreezeUtxo{})
 	})
 }
 
@@ -248,7 +311,12 @@ func FuzzAlertMessageUnfreezeUtxoRead(f *testing.F) {
 	f.Add([]byte{})
 	f.Add(make([]byte, 56))
 	f.Add(make([]byte, 58))
-	f.Add(make([]byte, 114))
+	f.Add(make([]byte, 114)) // the same outpoint twice
+
+	// Max uint64 vout and enforce at heights, to trigger the overflow checks
+	f.Add(buildUtxoAlertMessage(^uint64(0), 100000, 200000, 0))
+	f.Add(buildUtxoAlertMessage(0, ^uint64(0), 200000, 0))
+	f.Add(buildUtxoAlertMessage(0, 100000, ^uint64(0), 0))
 
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Guard against oversized inputs that would trip Go's fuzztime context
@@ -267,6 +335,19 @@ func FuzzAlertMessageUnfreezeUtxoRead(f *testing.F) {
 		// Validate successful parse
 		expectedFunds := len(data) / 57
 		require.Len(t, alert.Funds, expectedFunds, "number of funds should match data length / 57")
+
+		// Validate no overflow occurred
+		for _, fund := range alert.Funds {
+			require.GreaterOrEqual(t, fund.TxOut.Vout, 0, "vout should be non-negative")
+			require.GreaterOrEqual(t, fund.EnforceAtHeight[0].Start, 0, "start height should not overflow int")
+			require.GreaterOrEqual(t, fund.EnforceAtHeight[0].Stop, 0, "end height should not overflow int")
+		}
+
+		// A repeated outpoint is found by Validate, and nothing else is reported as one
+		assertDuplicateFunds(t, alert.Funds, alert.Validate(context.Background()))
+
+		// Serializing the parsed alert gives bytes that read back the same
+		assertSerializeRoundTrip(t, alert, &AlertMessageUnfreezeUtxo{})
 	})
 }
 
@@ -313,6 +394,9 @@ func FuzzAlertMessageConfiscateTransactionRead(f *testing.F) {
 		require.Len(t, alert.Transactions, 1, "should parse exactly one transaction")
 		require.GreaterOrEqual(t, alert.Transactions[0].ConfiscationTransaction.EnforceAtHeight, int64(0), "height should be non-negative")
 		// Hex can be empty (zero-length transaction is valid in the parser)
+
+		// Serializing the parsed alert gives bytes that read back the same
+		assertSerializeRoundTrip(t, alert, &AlertMessageConfiscateTransaction{})
 	})
 }
 
@@ -352,6 +436,9 @@ func FuzzAlertMessageInvalidateBlockRead(f *testing.F) {
 		// Validate successful parse
 		require.Len(t, alert.BlockHash, 32, "block hash should be 32 bytes")
 		assertLengthFieldValid(t, alert.ReasonLength, alert.Reason, data, "reason")
+
+		// Serializing the parsed alert gives bytes that read back the same
+		assertSerializeRoundTrip(t, alert, &AlertMessageInvalidateBlock{})
 	})
 }
 
@@ -396,5 +483,8 @@ func FuzzAlertMessageSetKeysRead(f *testing.F) {
 		for _, key := range alert.Keys {
 			require.Len(t, key, 33, "each key should be 33 bytes")
 		}
+
+		// Serializing the parsed alert gives bytes that read back the same
+		assertSerializeRoundTrip(t, alert, &AlertMessageSetKeys{})
 	})
 }
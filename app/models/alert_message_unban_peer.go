@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/bsv-blockchain/go-sdk/util"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
 )
 
 // AlertMessageUnbanPeer is the message for unbanned peer
@@ -37,6 +39,9 @@ func (a *AlertMessageUnbanPeer) Read(alert []byte) error {
 		}
 		peer = append(peer, b)
 	}
+	if err = checkPeerAddress(peer); err != nil {
+		return err
+	}
 	a.PeerLength = peerLength
 	a.Peer = peer
 
@@ -62,16 +67,35 @@ func (a *AlertMessageUnbanPeer) Read(alert []byte) error {
 	return nil
 }
 
+// Serialize writes the alert body back to the bytes Read consumes
+func (a *AlertMessageUnbanPeer) Serialize() ([]byte, error) {
+	writer := util.NewWriter()
+	writer.WriteVarInt(uint64(len(a.Peer)))
+	writer.WriteBytes(a.Peer)
+	writer.WriteVarInt(uint64(len(a.Reason)))
+	writer.WriteBytes(a.Reason)
+	return writer.Buf, nil
+}
+
+// Validate checks the alert, there are no rules beyond what Read parses
+func (a *AlertMessageUnbanPeer) Validate(_ context.Context) error {
+	return nil
+}
+
 // Do execute the alert
 func (a *AlertMessageUnbanPeer) Do(ctx context.Context) error {
-	return a.Config().Services.Node.UnbanPeer(ctx, string(a.Peer))
+	a.logEvent("unban_peer", config.LogFields{"peer": string(a.Peer)}, "UnbanPeer alert; peer [%s]", a.Peer)
+	rpcCtx, cancel := a.nodeContext(ctx)
+	defer cancel()
+	return a.wrapTimeout(a.Config().Services.Node.UnbanPeer(rpcCtx, string(a.Peer)))
 }
 
 // ToJSON is the alert in JSON format
 func (a *AlertMessageUnbanPeer) ToJSON(_ context.Context) []byte {
-	m := a.ProcessAlertMessage()
-	// TODO: Come back and add a message interface for each alert
-	_ = m.Read(a.GetRawMessage())
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
 	data, err := json.MarshalIndent(m, "", "    ")
 	if err != nil {
 		return []byte{}
@@ -79,7 +103,17 @@ func (a *AlertMessageUnbanPeer) ToJSON(_ context.Context) []byte {
 	return data
 }
 
+// MarshalJSON writes the fields of the alert body, the alert it's embedded in is written on its own
+func (a *AlertMessageUnbanPeer) MarshalJSON() ([]byte, error) {
+	return marshalBody(a)
+}
+
+// UnmarshalJSON reads the fields of the alert body written by MarshalJSON
+func (a *AlertMessageUnbanPeer) UnmarshalJSON(data []byte) error {
+	return unmarshalBody(data, a)
+}
+
 // MessageString executes the alert
 func (a *AlertMessageUnbanPeer) MessageString() string {
-	return fmt.Sprintf("Unbanning peer [%s]; reason [%s].", a.Peer, a.Reason)
+	return fmt.Sprintf("[%s] Unbanning peer [%s]; reason [%s].", AlertTypeUnbanPeer, a.Peer, a.Reason)
 }
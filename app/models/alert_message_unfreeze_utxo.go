@@ -6,9 +6,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"math"
 
 	"github.com/bsv-blockchain/go-bn/models"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
 )
 
 // AlertMessageUnfreezeUtxo is the message for unfreezing a UTXO
@@ -21,28 +23,28 @@ type AlertMessageUnfreezeUtxo struct {
 
 // Read reads the message from the byte slice
 func (a *AlertMessageUnfreezeUtxo) Read(raw []byte) error {
-	if len(raw) < 57 {
+	if len(raw) < FundSize {
 		return fmt.Errorf("%w, got %d bytes; raw: %x", ErrUnfreezeAlertTooShort, len(raw), raw)
 	}
-	if len(raw)%57 != 0 {
+	if len(raw)%FundSize != 0 {
 		return fmt.Errorf("%w, got %d bytes; raw: %x", ErrUnfreezeAlertInvalidLength, len(raw), raw)
 	}
-	fundCount := len(raw) / 57
+	fundCount := len(raw) / FundSize
 	var funds []models.Fund
 	for i := 0; i < fundCount; i++ {
 		fund := Fund{
-			TransactionOutID:     [32]byte(raw[0:32]),
-			Vout:                 binary.LittleEndian.Uint64(raw[32:40]),
-			EnforceAtHeightStart: binary.LittleEndian.Uint64(raw[40:48]),
-			EnforceAtHeightEnd:   binary.LittleEndian.Uint64(raw[48:56]),
+			TransactionOutID:     [32]byte(raw[:fundVoutOffset]),
+			Vout:                 binary.LittleEndian.Uint64(raw[fundVoutOffset:fundStartOffset]),
+			EnforceAtHeightStart: binary.LittleEndian.Uint64(raw[fundStartOffset:fundEndOffset]),
+			EnforceAtHeightEnd:   binary.LittleEndian.Uint64(raw[fundEndOffset:fundPolicyOffset]),
 		}
-		enforceByte := raw[56]
+		enforceByte := raw[fundPolicyOffset]
 
 		if enforceByte != uint8(0) {
 			fund.PolicyExpiresWithConsensus = true
 		}
-		if fund.Vout > math.MaxInt || fund.EnforceAtHeightStart > math.MaxInt || fund.EnforceAtHeightEnd > math.MaxInt {
-			return ErrValueExceedsMaxInt
+		if err := fund.checkBounds(); err != nil {
+			return err
 		}
 		funds = append(funds, models.Fund{
 			TxOut: models.TxOut{
@@ -57,27 +59,76 @@ func (a *AlertMessageUnfreezeUtxo) Read(raw []byte) error {
 			},
 			PolicyExpiresWithConsensus: fund.PolicyExpiresWithConsensus,
 		})
-		raw = raw[57:]
+		raw = raw[FundSize:]
 	}
 	a.Funds = funds
 
 	return nil
 }
 
+// Serialize writes the alert body back to the bytes Read consumes, FundSize bytes per fund
+func (a *AlertMessageUnfreezeUtxo) Serialize() ([]byte, error) {
+	return serializeFunds(a.Funds)
+}
+
+// Validate checks the enforce at height range and that no fund is listed twice
+func (a *AlertMessageUnfreezeUtxo) Validate(_ context.Context) error {
+	return validateFunds(a.Funds)
+}
+
 // Do execute the message
 func (a *AlertMessageUnfreezeUtxo) Do(ctx context.Context) error {
-	_, err := a.Config().Services.Node.AddToConsensusBlacklist(ctx, a.Funds)
+	a.logEvent("unfreeze_utxo", config.LogFields{"funds": len(a.Funds)}, "UnfreezeUtxo alert; funds [%d]", len(a.Funds))
+	var res *models.AddToConsensusBlacklistResponse
+	err := a.callNode(ctx, func(ctx context.Context) (err error) {
+		res, err = a.Config().Services.Node.AddToConsensusBlacklist(ctx, a.Funds)
+		return err
+	})
 	if err != nil {
 		return err
 	}
+
+	a.setRPCResult(res)
+
+	// Store the funds the node processed, even if it rejected others
+	// A fund that wasn't frozen is already unfrozen, so the node rejecting it isn't a failure
+	processed, rejected := splitProcessedFunds(a.Funds, res)
+	notFrozen, rejected := splitNotFrozen(rejected)
+	a.logFundOutcomes(processed, notFrozen, rejected)
+	for _, fund := range notFrozen {
+		processed = append(processed, models.Fund{TxOut: models.TxOut{TxId: fund.TxID, Vout: fund.Vout}})
+	}
+	if err = removeFrozenOutpoints(ctx, processed, a.SequenceNumber, model.WithAllDependencies(a.Config())); err != nil {
+		return err
+	}
+	if len(rejected) > 0 {
+		return &PartialSuccessError{Cause: ErrUnfreezeAlertRPCError, Processed: len(processed), Rejected: rejected}
+	}
 	return nil
 }
 
+// logFundOutcomes will log what happened to each fund of the alert
+func (a *AlertMessageUnfreezeUtxo) logFundOutcomes(unfrozen []models.Fund, notFrozen, rejected []RejectedFund) {
+	for _, fund := range unfrozen {
+		a.logEvent("unfreeze_utxo_fund", config.LogFields{"tx_id": fund.TxOut.TxId, "vout": fund.TxOut.Vout, "outcome": "unfrozen"},
+			"UnfreezeUtxo alert; %s:%d unfrozen", fund.TxOut.TxId, fund.TxOut.Vout)
+	}
+	for _, fund := range notFrozen {
+		a.logEvent("unfreeze_utxo_fund", config.LogFields{"tx_id": fund.TxID, "vout": fund.Vout, "outcome": "not_frozen", "reason": fund.Reason},
+			"UnfreezeUtxo alert; %s:%d was not frozen (%s)", fund.TxID, fund.Vout, fund.Reason)
+	}
+	for _, fund := range rejected {
+		a.logEvent("unfreeze_utxo_fund", config.LogFields{"tx_id": fund.TxID, "vout": fund.Vout, "outcome": "rejected", "reason": fund.Reason},
+			"UnfreezeUtxo alert; %s:%d rejected by the node (%s)", fund.TxID, fund.Vout, fund.Reason)
+	}
+}
+
 // ToJSON is the alert in JSON format
 func (a *AlertMessageUnfreezeUtxo) ToJSON(_ context.Context) []byte {
-	m := a.ProcessAlertMessage()
-	// TODO: Come back and add a message interface for each alert
-	_ = m.Read(a.GetRawMessage())
+	m, err := a.ProcessAlertMessage()
+	if err != nil || m == nil {
+		return []byte{}
+	}
 	data, err := json.MarshalIndent(m, "", "    ")
 	if err != nil {
 		return []byte{}
@@ -85,10 +136,20 @@ func (a *AlertMessageUnfreezeUtxo) ToJSON(_ context.Context) []byte {
 	return data
 }
 
+// MarshalJSON writes the fields of the alert body, the alert it's embedded in is written on its own
+func (a *AlertMessageUnfreezeUtxo) MarshalJSON() ([]byte, error) {
+	return marshalBody(a)
+}
+
+// UnmarshalJSON reads the fields of the alert body written by MarshalJSON
+func (a *AlertMessageUnfreezeUtxo) UnmarshalJSON(data []byte) error {
+	return unmarshalBody(data, a)
+}
+
 // MessageString executes the alert
 func (a *AlertMessageUnfreezeUtxo) MessageString() string {
 	if len(a.Funds) == 0 || len(a.Funds[0].EnforceAtHeight) == 0 {
-		return "Unfreezing utxo: alert message contains no fund data."
+		return fmt.Sprintf("[%s] Unfreezing utxo: alert message contains no fund data.", AlertTypeUnfreezeUtxo)
 	}
-	return fmt.Sprintf("Unfreezing utxo id [%x]; vout: [%d], by setting enforce height at start [%d], end [%d].", a.Funds[0].TxOut.TxId, a.Funds[0].TxOut.Vout, a.Funds[0].EnforceAtHeight[0].Start, a.Funds[0].EnforceAtHeight[0].Stop)
+	return fmt.Sprintf("[%s] Unfreezing utxo id [%x]; vout: [%d], by setting enforce height at start [%d], end [%d].", AlertTypeUnfreezeUtxo, a.Funds[0].TxOut.TxId, a.Funds[0].TxOut.Vout, a.Funds[0].EnforceAtHeight[0].Start, a.Funds[0].EnforceAtHeight[0].Stop)
 }
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// Outcomes of replaying an alert
+const (
+	ReplayApplied = "applied" // The alert's action was executed
+	ReplayFailed  = "failed"  // The alert's action failed, replaying from its sequence tries it again
+	ReplaySkipped = "skipped" // The alert isn't replayed (see ReplayAlerts)
+	ReplayValid   = "valid"   // Dry run: the alert would be executed
+)
+
+// ReplayResult is the outcome of replaying an alert
+type ReplayResult struct {
+	AlertType string `json:"alert_type"`        // Type name of the alert
+	Error     string `json:"error,omitempty"`   // Why the alert failed
+	Outcome   string `json:"outcome"`           // ReplayApplied, ReplayFailed, ReplaySkipped or ReplayValid
+	Reason    string `json:"reason,omitempty"`  // Why the alert was skipped
+	Sequence  uint32 `json:"sequence"`          // Sequence number of the alert
+	Summary   string `json:"summary,omitempty"` // Summary of the alert's action
+}
+
+// ReplayAlerts will execute the action of each active (not superseded) stored alert again, lowest sequence first,
+// starting at the sequence from, to apply an existing alert history (freezes, bans...) to a new node
+//
+// The genesis and informational alerts are skipped (they don't change the node), as are the alerts of disabled,
+// suppressed or unknown types. A set keys alert rotates the active keys, unless the keys were set by it or a later
+// alert already, so a node seeded by ImportAlerts ends up verifying new alerts with the latest keys of the history.
+// With dryRun each alert is only validated
+//
+// An alert that fails, or a stored alert that doesn't match its hash, is reported and the replay carries on, it can be resumed from the failed sequence.
+// A cancelled context stops the replay between alerts, returning the results so far with the context's error
+func ReplayAlerts(ctx context.Context, from uint32, dryRun bool, opts ...model.Options) ([]ReplayResult, error) {
+	alerts, err := GetActiveAlerts(ctx, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	keysSequence, err := activeKeysSequence(ctx, alerts, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ReplayResult, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.SequenceNumber < from {
+			continue
+		} else if err = ctx.Err(); err != nil {
+			return results, err
+		}
+		alert.SetOptions(opts...)
+		result, replayErr := replayAlert(ctx, alert, dryRun, keysSequence)
+		if replayErr != nil {
+			return results, replayErr
+		}
+		if result.Outcome == ReplayApplied && alert.GetAlertType() == AlertTypeSetKeys {
+			keysSequence = alert.SequenceNumber
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// activeKeysSequence returns the sequence of the set keys alert that set the active keys, or 0 for the genesis keys
+func activeKeysSequence(ctx context.Context, alerts []*AlertMessage, opts ...model.Options) (uint32, error) {
+	keys, err := GetActivePublicKeys(ctx, opts...)
+	if err != nil || len(keys) == 0 || keys[0].LastUpdateHash == "" {
+		return 0, err
+	}
+	for _, alert := range alerts {
+		if alert.Hash == keys[0].LastUpdateHash {
+			return alert.SequenceNumber, nil
+		}
+	}
+	return 0, nil
+}
+
+// replayAlert will execute the action of a stored alert again (see ReplayAlerts), keysSequence is the sequence of the
+// alert that set the active keys. The error is only returned if the alert can't be saved, a failed action is reported in the result
+func replayAlert(ctx context.Context, alert *AlertMessage, dryRun bool, keysSequence uint32) (ReplayResult, error) {
+	result := ReplayResult{Outcome: ReplaySkipped, Sequence: alert.SequenceNumber}
+	if alert.SequenceNumber == 0 {
+		result.AlertType, result.Reason = AlertTypeSetKeys.String(), "the genesis alert isn't replayed"
+		return result, nil
+	} else if err := alert.VerifyHash(); err != nil {
+		result.Outcome, result.Error = ReplayFailed, err.Error()
+		return result, nil
+	} else if err = alert.ReadRaw(); err != nil {
+		result.Outcome, result.Error = ReplayFailed, err.Error()
+		return result, nil
+	}
+	alert.SerializeData()
+	result.AlertType = alert.GetAlertType().String()
+
+	// Skip the alerts that don't change the node, or that this node doesn't execute
+	switch {
+	case alert.GetAlertType() == AlertTypeInformational:
+		result.Reason = "informational alerts aren't replayed"
+		return result, nil
+	case alert.GetAlertType() == AlertTypeSetKeys && alert.SequenceNumber <= keysSequence:
+		result.Reason = "the active keys were set by this or a later alert"
+		return result, nil
+	case alert.CheckTypeEnabled() != nil:
+		result.Reason = "the alert type is disabled"
+		return result, nil
+	case alert.ExecutionSuppressed():
+		result.Reason = "execution of the alert type is disabled"
+		return result, nil
+	}
+	action, err := alert.ProcessAlertMessage()
+	if err != nil {
+		result.Outcome, result.Error = ReplayFailed, err.Error()
+		return result, nil
+	} else if action == nil {
+		result.Reason = ErrAlertTypeUnknown.Error()
+		return result, nil
+	}
+	result.Summary = action.MessageString()
+
+	// A dry run only validates the alert
+	if dryRun {
+		if err = action.Validate(ctx); err != nil {
+			result.Outcome, result.Error = ReplayFailed, err.Error()
+		} else {
+			result.Outcome = ReplayValid
+		}
+		return result, nil
+	}
+
+	unlock := LockSequence(alert.SequenceNumber)
+	defer unlock()
+	if err = executeAlertAction(ctx, alert, action, AuditSourceReplay, false); errors.Is(err, ErrSetKeysUnchanged) {
+		result.Reason = "the keys are already active"
+	} else if err != nil {
+		result.Outcome, result.Error = ReplayFailed, err.Error()
+	} else {
+		result.Outcome = ReplayApplied
+	}
+
+	// An alert synced but never executed is processed now
+	if !alert.Processed && (err == nil || errors.Is(err, ErrPartialSuccess) || errors.Is(err, ErrAlertInvalid)) {
+		alert.Processed = true
+		if err = alert.Save(ctx); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
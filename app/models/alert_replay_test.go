@@ -0,0 +1,183 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/bsv-blockchain/go-bn/models"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// TestReplayAlerts will test the method ReplayAlerts()
+func (ts *TestSuite) TestReplayAlerts() {
+	ctx := context.Background()
+	funds := testFunds(2)
+	var blacklisted [][]models.Fund
+	ts.Dependencies.Services.Node = &mocks.Node{
+		AddToConsensusBlacklistFunc: func(_ context.Context, f []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
+			blacklisted = append(blacklisted, f)
+			return &models.AddToConsensusBlacklistResponse{}, nil
+		},
+	}
+
+	ts.Run("active alerts are executed again", func() {
+		ts.saveFundsAlert(1, AlertTypeFreezeUtxo, funds[0])
+		ts.saveSignedAlert(2, AlertTypeInformational, []byte{0x02, 'h', 'i'}, []string{utils.Key1, utils.Key2, utils.Key3})
+		processed := ts.saveFundsAlert(3, AlertTypeFreezeUtxo, funds[1])
+		processed.Processed = true
+		ts.Require().NoError(processed.Save(ctx))
+
+		results, err := ReplayAlerts(ctx, 0, false, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(results, 3)
+		ts.Equal(ReplayApplied, results[0].Outcome)
+		ts.Equal(AlertTypeFreezeUtxo.String(), results[0].AlertType)
+		ts.Equal(ReplaySkipped, results[1].Outcome)
+		ts.NotEmpty(results[1].Reason)
+		ts.Equal(ReplayApplied, results[2].Outcome)
+		ts.Len(blacklisted, 2)
+
+		// The alert that was never executed is processed now
+		alert, err := GetAlertMessageBySequenceNumber(ctx, 1, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.True(alert.Processed)
+
+		// Each execution is audited as a replay
+		entries, err := GetAuditEntries(ctx, &AuditFilter{}, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(entries, 2)
+		for _, entry := range entries {
+			ts.Equal(AuditSourceReplay, entry.Source)
+		}
+	})
+
+	ts.Run("a dry run only validates", func() {
+		blacklisted = nil
+		results, err := ReplayAlerts(ctx, 0, true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(results, 3)
+		ts.Equal(ReplayValid, results[0].Outcome)
+		ts.Equal(ReplayValid, results[2].Outcome)
+		ts.Empty(blacklisted)
+	})
+
+	ts.Run("resumes from a sequence", func() {
+		results, err := ReplayAlerts(ctx, 3, true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(results, 1)
+		ts.Equal(uint32(3), results[0].Sequence)
+	})
+
+	ts.Run("types not executed are skipped", func() {
+		ts.Dependencies.ExecutedAlertTypes = []string{AlertTypeBanPeer.String()}
+		defer func() { ts.Dependencies.ExecutedAlertTypes = nil }()
+
+		results, err := ReplayAlerts(ctx, 0, true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		for _, result := range results {
+			ts.Equal(ReplaySkipped, result.Outcome)
+		}
+	})
+
+	ts.Run("a failed alert is reported and the replay carries on", func() {
+		ts.Dependencies.Services.Node = rejectingNode(funds[0])
+		results, err := ReplayAlerts(ctx, 0, false, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(results, 3)
+		ts.Equal(ReplayFailed, results[0].Outcome)
+		ts.NotEmpty(results[0].Error)
+		ts.Equal(ReplayApplied, results[2].Outcome)
+	})
+
+	ts.Run("an alert that doesn't match its stored hash isn't executed", func() {
+		ts.Dependencies.Services.Node = &mocks.Node{
+			AddToConsensusBlacklistFunc: func(_ context.Context, f []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
+				blacklisted = append(blacklisted, f)
+				return &models.AddToConsensusBlacklistResponse{}, nil
+			},
+		}
+		tampered, err := GetAlertMessageBySequenceNumber(ctx, 1, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		tampered.Hash = strings.Repeat("ab", 32)
+		ts.Require().NoError(tampered.Save(ctx))
+
+		blacklisted = nil
+		results, err := ReplayAlerts(ctx, 0, false, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(results, 3)
+		ts.Equal(ReplayFailed, results[0].Outcome)
+		ts.Contains(results[0].Error, ErrAlertHashMismatch.Error())
+		ts.Equal(ReplayApplied, results[2].Outcome)
+		ts.Len(blacklisted, 1)
+	})
+
+	ts.Run("a cancelled replay stops", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		results, err := ReplayAlerts(cancelled, 0, true, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().ErrorIs(err, context.Canceled)
+		ts.Empty(results)
+	})
+}
+
+// TestReplayAlerts_SetKeys will test the method ReplayAlerts() with an imported history that rotated the keys
+func (ts *TestSuite) TestReplayAlerts_SetKeys() {
+	ctx := context.Background()
+	ts.Dependencies.Services.Node = &mocks.Node{
+		AddToConsensusBlacklistFunc: func(_ context.Context, _ []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
+			return &models.AddToConsensusBlacklistResponse{}, nil
+		},
+	}
+	ts.resetDatastore()
+	privateKeys, message := ts.newKeySet()
+	ts.saveSignedAlert(1, AlertTypeSetKeys, message, []string{utils.Key1, utils.Key2, utils.Key3})
+	ts.saveSignedAlert(2, AlertTypeInformational, []byte{0x02, 'h', 'i'}, privateKeys[:3])
+	export := ts.exportAndReset()
+
+	_, _, err := ImportAlerts(ctx, bytes.NewReader(export), true, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+
+	// newAlert will create a new alert signed with the private keys
+	newAlert := func(keys []string) *AlertMessage {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies))
+		a.SetVersion(AlertVersionCurrent)
+		a.SetAlertType(AlertTypeInformational)
+		a.SetRawMessage([]byte{0x02, 'h', 'i'})
+		a.SequenceNumber = 3
+		a.SerializeData()
+		sigs, sigErr := utils.SignWithKeys(a.GetRawData(), keys)
+		ts.Require().NoError(sigErr)
+		a.SetSignatures(sigs)
+		return a
+	}
+
+	// Imported, the history doesn't change the keys
+	valid, err := newAlert(privateKeys[:3]).AreSignaturesValid(ctx)
+	ts.Require().NoError(err)
+	ts.False(valid)
+
+	// Replayed, the rotation is applied
+	results, err := ReplayAlerts(ctx, 0, false, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Len(results, 3) // With the genesis alert
+	ts.Equal(ReplayApplied, results[1].Outcome)
+	ts.Equal(AlertTypeSetKeys.String(), results[1].AlertType)
+
+	valid, err = newAlert(privateKeys[:3]).AreSignaturesValid(ctx)
+	ts.Require().NoError(err)
+	ts.True(valid)
+	valid, err = newAlert([]string{utils.Key1, utils.Key2, utils.Key3}).AreSignaturesValid(ctx)
+	ts.Require().NoError(err)
+	ts.False(valid)
+
+	// Replayed again, the keys are already set
+	results, err = ReplayAlerts(ctx, 0, false, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Len(results, 3) // With the genesis alert
+	ts.Equal(ReplaySkipped, results[1].Outcome)
+	ts.NotEmpty(results[1].Reason)
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// AlertStats are the counts of the stored alerts
+type AlertStats struct {
+	ByType      map[string]int64 `json:"by_type"`      // Alerts per type, keyed by the type name (AlertType.String())
+	MaxSequence uint32           `json:"max_sequence"` // Highest sequence number stored (0 without alerts)
+	MinSequence uint32           `json:"min_sequence"` // Lowest sequence number stored (0 without alerts)
+	Processed   int64            `json:"processed"`    // Alerts whose action was executed
+	Total       int64            `json:"total"`        // All alerts
+	Unprocessed int64            `json:"unprocessed"`  // Alerts not processed yet
+}
+
+// alertTotals is a row of the alert totals query
+type alertTotals struct {
+	MaxSequence *int64
+	MinSequence *int64
+	Processed   *int64
+	Total       int64
+}
+
+// alertTypeCount is a row of the alerts per type query
+type alertTypeCount struct {
+	AlertType uint32
+	Count     int64
+}
+
+// GetAlertStats will get the counts of the stored alerts, computed by the datastore (the alerts aren't loaded)
+func GetAlertStats(ctx context.Context, opts ...model.Options) (AlertStats, error) {
+	stats := AlertStats{ByType: map[string]int64{}}
+
+	base := model.NewBaseModel(model.NameAlertMessage, opts...)
+	ctx, cancel := context.WithTimeout(ctx, model.QueryTimeout(base.Config()))
+	defer cancel()
+
+	// alerts will start a query on the alerts that aren't deleted
+	db := base.Datastore().Raw("")
+	alerts := func() *gorm.DB {
+		return db.Session(&gorm.Session{NewDB: true, Context: ctx}).
+			Model(&AlertMessage{}).
+			Where(utils.FieldDeletedAt + " IS NULL")
+	}
+
+	// Get the totals
+	var totals alertTotals
+	if err := alerts().Select(
+		"COUNT(*) AS total, " +
+			"SUM(CASE WHEN processed THEN 1 ELSE 0 END) AS processed, " +
+			"MIN(" + utils.FieldSequenceNumber + ") AS min_sequence, " +
+			"MAX(" + utils.FieldSequenceNumber + ") AS max_sequence",
+	).Scan(&totals).Error; err != nil {
+		return stats, err
+	}
+	stats.Total = totals.Total
+	if totals.Processed != nil {
+		stats.Processed = *totals.Processed
+	}
+	stats.Unprocessed = stats.Total - stats.Processed
+	if totals.MinSequence != nil {
+		stats.MinSequence = uint32(*totals.MinSequence) //nolint:gosec // sequence numbers are stored from uint32
+	}
+	if totals.MaxSequence != nil {
+		stats.MaxSequence = uint32(*totals.MaxSequence) //nolint:gosec // sequence numbers are stored from uint32
+	}
+
+	// Get the count per type
+	var counts []alertTypeCount
+	if err := alerts().Select(
+		"alert_type, COUNT(*) AS count",
+	).Group("alert_type").Scan(&counts).Error; err != nil {
+		return stats, err
+	}
+	for _, count := range counts {
+		stats.ByType[AlertType(count.AlertType).String()] += count.Count
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// TestGetAlertStats will test the method GetAlertStats()
+func (ts *TestSuite) TestGetAlertStats() {
+	ctx := context.Background()
+
+	ts.Run("no alerts", func() {
+		stats, err := GetAlertStats(ctx, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Equal(AlertStats{ByType: map[string]int64{}}, stats)
+	})
+
+	ts.Run("counts per type and processed", func() {
+		funds := testFunds(3)
+		ts.saveFundsAlert(4, AlertTypeFreezeUtxo, funds[0])
+		ts.saveFundsAlert(5, AlertTypeFreezeUtxo, funds[1])
+		unfreeze := ts.saveFundsAlert(9, AlertTypeUnfreezeUtxo, funds[0])
+		unfreeze.Processed = true
+		ts.Require().NoError(unfreeze.Save(ctx))
+
+		stats, err := GetAlertStats(ctx, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Equal(AlertStats{
+			ByType: map[string]int64{
+				AlertTypeFreezeUtxo.String():   2,
+				AlertTypeUnfreezeUtxo.String(): 1,
+			},
+			MaxSequence: 9,
+			MinSequence: 4,
+			Processed:   1,
+			Total:       3,
+			Unprocessed: 2,
+		}, stats)
+	})
+}
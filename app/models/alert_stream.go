@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/util"
+)
+
+// Alerts are streamed as the serialized alert prefixed with its length (varint):
+//
+//	length(varint) | alert(length)
+//
+// WriteTo, ReadFrom and ReadAlertFrom are a library API for streaming alerts over a connection, the P2P sync
+// doesn't call them. Its sync messages are read whole (see the p2p frames), and the alerts of an IGotBatch,
+// framed the same way, are split into raw alerts when the message is parsed, before the alerts are, so an
+// alert that doesn't parse is refused (and its peer penalized) at its own sequence
+var (
+	_ io.WriterTo   = (*AlertMessage)(nil)
+	_ io.ReaderFrom = (*AlertMessage)(nil)
+)
+
+// WriteTo will write the alert to the writer, prefixed with its length
+// The pieces are written straight from the alert, the whole serialized alert isn't built first
+func (m *AlertMessage) WriteTo(w io.Writer) (int64, error) {
+	m.SerializeData()
+	pieces := make([][]byte, 0, len(m.signatures)+5)
+	if !hasSignatureCount(m.version) {
+		pieces = append(pieces, m.data)
+		pieces = append(pieces, m.signatures...)
+		if padding := signaturePadding(m.alertType, len(m.signatures)); padding > 0 {
+			pieces = append(pieces, make([]byte, padding))
+		}
+	} else {
+		pieces = append(pieces, m.data[:AlertHeaderSize], util.VarInt(len(m.signatures)).Bytes())
+		pieces = append(pieces, m.signatures...)
+		pieces = append(pieces, m.data[AlertHeaderSize:])
+	}
+	size := 0
+	for _, piece := range pieces {
+		size += len(piece)
+	}
+
+	var n int64
+	for _, piece := range append([][]byte{util.VarInt(size).Bytes()}, pieces...) {
+		written, err := w.Write(piece)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ReadFrom will read the alert written by WriteTo from the reader until EOF, replacing the alert
+// The stream must carry exactly the one alert, use ReadAlertFrom to read the alerts of a stream one at a time
+func (m *AlertMessage) ReadFrom(r io.Reader) (int64, error) {
+	n, err := m.ReadAlertFrom(r)
+	if errors.Is(err, io.EOF) {
+		return n, fmt.Errorf("%w: the stream is empty", ErrAlertTooShort)
+	} else if err != nil {
+		return n, err
+	}
+
+	// Anything after the alert means the stream isn't the one alert
+	var next [1]byte
+	if read, err := io.ReadAtLeast(r, next[:], 1); read > 0 {
+		return n + int64(read), fmt.Errorf("%w: after %d bytes", ErrAlertStreamTrailingData, n)
+	} else if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+	return n, nil
+}
+
+// ReadAlertFrom will read one alert written by WriteTo from the reader, replacing the alert
+// Unlike ReadFrom it stops after the alert, so the alerts of a stream are read with one call each
+//
+// An alert announced larger than the maximum alert size is refused before it's read, so a peer can't make us
+// allocate an arbitrary buffer, and one timestamped too far ahead is refused as NewAlertFromBytes does. The reader can return the alert in any number of pieces. A reader that ends before the alert starts
+// returns io.EOF, one that ends part way through returns ErrAlertTooShort
+func (m *AlertMessage) ReadAlertFrom(r io.Reader) (int64, error) {
+	counter := &countingReader{reader: r}
+	var size util.VarInt
+	if _, err := size.ReadFrom(counter); err != nil {
+		if counter.n == 0 && errors.Is(err, io.EOF) {
+			return 0, io.EOF
+		} else if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return counter.n, fmt.Errorf("%w: the stream ended in the length", ErrAlertTooShort)
+		}
+		return counter.n, err
+	}
+	if err := m.checkSize(uint64(size)); err != nil {
+		return counter.n, err
+	}
+
+	ak := make([]byte, size)
+	if read, err := io.ReadFull(counter, ak); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return counter.n, fmt.Errorf("%w: the stream ended after %d of %d bytes", ErrAlertTooShort, read, size)
+	} else if err != nil {
+		return counter.n, err
+	}
+	m.Raw = ""
+	m.SetRawMessage(ak)
+	if err := m.ReadRaw(); err != nil {
+		return counter.n, err
+	}
+	return counter.n, m.checkTimestamp(m.timestamp, time.Now())
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	n      int64
+	reader io.Reader
+}
+
+// Read reads from the underlying reader, counting the bytes
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.n += int64(n)
+	return n, err
+}
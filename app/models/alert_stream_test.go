@@ -0,0 +1,189 @@
+package models
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bsv-blockchain/go-sdk/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// streamVectors will read the alerts of the golden vectors
+func streamVectors(t *testing.T) map[string]*AlertMessage {
+	alerts := make(map[string]*AlertMessage, len(alertVectorSources))
+	for _, src := range alertVectorSources {
+		data, err := os.ReadFile(filepath.Join(alertVectorsDir, src.name+".json")) //nolint:gosec // the path is built from the vector name
+		require.NoError(t, err)
+		var golden alertVector
+		require.NoError(t, json.Unmarshal(data, &golden))
+		raw, err := hex.DecodeString(golden.Raw)
+		require.NoError(t, err)
+		alerts[src.name], err = NewAlertFromBytes(raw)
+		require.NoError(t, err)
+	}
+	return alerts
+}
+
+// TestAlertMessage_WriteTo will test the method WriteTo()
+func TestAlertMessage_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	for name, alert := range streamVectors(t) {
+		t.Run(name, func(t *testing.T) {
+			serialized := alert.Serialize()
+			expected := append(util.VarInt(len(serialized)).Bytes(), serialized...)
+
+			var buf bytes.Buffer
+			n, err := alert.WriteTo(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(expected)), n)
+			assert.Equal(t, hex.EncodeToString(expected), hex.EncodeToString(buf.Bytes()))
+		})
+	}
+
+	t.Run("a failed write returns the bytes written", func(t *testing.T) {
+		alert := streamVectors(t)["set_keys"]
+		n, err := alert.WriteTo(&limitedWriter{limit: 10})
+		require.ErrorIs(t, err, io.ErrShortWrite)
+		assert.Equal(t, int64(10), n)
+	})
+}
+
+// TestAlertMessage_ReadFrom will test the method ReadFrom()
+func TestAlertMessage_ReadFrom(t *testing.T) {
+	t.Parallel()
+
+	alerts := streamVectors(t)
+	var buf bytes.Buffer
+	_, err := alerts["informational_v3"].WriteTo(&buf)
+	require.NoError(t, err)
+	stream := buf.Bytes()
+
+	t.Run("the one alert of the stream", func(t *testing.T) {
+		alert := &AlertMessage{}
+		n, err := alert.ReadFrom(iotest.OneByteReader(bytes.NewReader(stream)))
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(stream)), n)
+		assert.Equal(t, alerts["informational_v3"].Hash, alert.Hash)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		n, err := (&AlertMessage{}).ReadFrom(bytes.NewReader(nil))
+		require.ErrorIs(t, err, ErrAlertTooShort)
+		assert.Zero(t, n)
+	})
+
+	t.Run("data after the alert", func(t *testing.T) {
+		n, err := (&AlertMessage{}).ReadFrom(bytes.NewReader(append(append([]byte{}, stream...), stream...)))
+		require.ErrorIs(t, err, ErrAlertStreamTrailingData)
+		assert.Equal(t, int64(len(stream)+1), n)
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		_, err := (&AlertMessage{}).ReadFrom(bytes.NewReader(stream[:len(stream)-1]))
+		require.ErrorIs(t, err, ErrAlertTooShort)
+	})
+}
+
+// TestAlertMessage_ReadAlertFrom will test the method ReadAlertFrom()
+func TestAlertMessage_ReadAlertFrom(t *testing.T) {
+	t.Parallel()
+
+	alerts := streamVectors(t)
+
+	t.Run("alerts read back in one piece at a time", func(t *testing.T) {
+		var buf bytes.Buffer
+		for _, src := range alertVectorSources {
+			_, err := alerts[src.name].WriteTo(&buf)
+			require.NoError(t, err)
+		}
+		total := int64(buf.Len())
+
+		r := iotest.OneByteReader(&buf)
+		var read int64
+		for _, src := range alertVectorSources {
+			alert := &AlertMessage{}
+			n, err := alert.ReadAlertFrom(r)
+			require.NoError(t, err, src.name)
+			read += n
+			assert.Equal(t, alerts[src.name].Hash, alert.Hash, src.name)
+			assert.Equal(t, alerts[src.name].Serialize(), alert.Serialize(), src.name)
+		}
+		assert.Equal(t, total, read)
+
+		// The stream ended between alerts
+		n, err := (&AlertMessage{}).ReadAlertFrom(r)
+		require.ErrorIs(t, err, io.EOF)
+		assert.Zero(t, n)
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := alerts["informational_v3"].WriteTo(&buf)
+		require.NoError(t, err)
+		stream := buf.Bytes()
+
+		for i := 1; i < len(stream); i++ {
+			n, err := (&AlertMessage{}).ReadAlertFrom(bytes.NewReader(stream[:i]))
+			require.ErrorIs(t, err, ErrAlertTooShort, "%d bytes", i)
+			assert.Equal(t, int64(i), n)
+		}
+	})
+
+	t.Run("truncated length", func(t *testing.T) {
+		stream := util.VarInt(config.DefaultMaxAlertSize).Bytes()
+		_, err := (&AlertMessage{}).ReadAlertFrom(bytes.NewReader(stream[:2]))
+		require.ErrorIs(t, err, ErrAlertTooShort)
+	})
+
+	t.Run("alert too large", func(t *testing.T) {
+		stream := util.VarInt(config.DefaultMaxAlertSize + 1).Bytes()
+		_, err := (&AlertMessage{}).ReadAlertFrom(bytes.NewReader(stream))
+		require.ErrorIs(t, err, ErrAlertTooLong)
+	})
+
+	t.Run("alert over the configured size", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := alerts["informational_v3"].WriteTo(&buf)
+		require.NoError(t, err)
+		size := len(alerts["informational_v3"].Serialize())
+
+		alert := NewAlertMessage(model.WithAllDependencies(&config.Config{MaxAlertSize: size - 1}))
+		_, err = alert.ReadAlertFrom(&buf)
+		require.ErrorIs(t, err, ErrAlertTooLong)
+	})
+
+	t.Run("reader error", func(t *testing.T) {
+		errRead := errors.New("read failed")
+		_, err := (&AlertMessage{}).ReadAlertFrom(iotest.ErrReader(errRead))
+		require.ErrorIs(t, err, errRead)
+	})
+}
+
+// limitedWriter accepts up to limit bytes
+type limitedWriter struct {
+	limit   int
+	written int
+}
+
+// Write writes the bytes that fit under the limit
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.written+len(p) > w.limit {
+		n := w.limit - w.written
+		w.written = w.limit
+		return n, io.ErrShortWrite
+	}
+	w.written += len(p)
+	return len(p), nil
+}
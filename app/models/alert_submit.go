@@ -0,0 +1,93 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// SubmitAlert will verify, store and execute a serialized alert submitted by the operator (through the API),
+// its action is audited as AuditSourceAPI. The caller publishes the stored alert to the peers
+//
+// The alert must be signed by the active keys and be the sequence after a stored (or dropped) alert. With execute false it's
+// stored unprocessed, for the alert processing to execute later. An alert that breaks the rules of its type is stored
+// processed without being executed, one whose action fails is stored unprocessed, either way the error is returned
+// with the stored alert. Without a stored alert, the error is why the alert was refused
+func SubmitAlert(ctx context.Context, raw []byte, execute bool, opts ...model.Options) (*AlertMessage, error) {
+	alert, err := NewAlertFromBytes(raw, append(opts, model.New())...)
+	if err != nil {
+		return nil, err
+	}
+	alert.SerializeData()
+	if err = alert.CheckTypeEnabled(); err != nil {
+		return nil, err
+	}
+	var valid bool
+	if valid, err = alert.AreSignaturesValid(ctx); err != nil {
+		return nil, err
+	} else if !valid {
+		return nil, ErrAlertNotVerified
+	}
+	action, err := alert.ProcessAlertMessage()
+	if err != nil {
+		return nil, err
+	} else if action == nil {
+		return nil, fmt.Errorf("%w: %d", ErrAlertTypeUnknown, alert.GetAlertType())
+	}
+
+	// The sequence is locked until the alert is saved, so the same alert arriving from a peer at the same time runs once
+	unlock := LockSequence(alert.SequenceNumber)
+	defer unlock()
+	if _, err = GetAlertMessageBySequenceNumber(ctx, alert.SequenceNumber, opts...); err == nil {
+		return nil, fmt.Errorf("%w: %d", ErrAlertSequenceExists, alert.SequenceNumber)
+	} else if !errors.Is(err, ErrAlertNotFound) {
+		return nil, err
+	}
+	if err = checkSequenceFollows(ctx, alert.SequenceNumber, opts...); err != nil {
+		return nil, err
+	}
+
+	// Sanity check the new alert, a rejected alert is stored (so it's not synced and run later) but never executed
+	var actionErr error
+	if execute {
+		if actionErr = CheckReceivedAlert(ctx, action); actionErr != nil {
+			actionErr = fmt.Errorf("%w: %w", ErrAlertInvalid, actionErr)
+			if auditErr := RecordAuditEntry(ctx, alert, action, AuditSourceAPI, actionErr); auditErr != nil {
+				alert.Config().Services.Log.Errorf("failed to record audit entry for alert %d: %s", alert.SequenceNumber, auditErr.Error())
+			}
+		} else {
+			actionErr = ExecuteAlertAction(ctx, alert, action, AuditSourceAPI)
+		}
+		alert.Processed = actionErr == nil || errors.Is(actionErr, ErrPartialSuccess) || errors.Is(actionErr, ErrAlertInvalid)
+	}
+
+	// Store the alert
+	if err = alert.Save(ctx); err != nil {
+		return nil, err
+	}
+	if err = MarkSupersededAlert(ctx, alert); err != nil && !errors.Is(err, ErrAlertNotFound) {
+		alert.Config().Services.Log.Errorf("failed to mark alert %d as superseded by %d: %s", alert.Supersedes, alert.SequenceNumber, err.Error())
+	}
+	return alert, actionErr
+}
+
+// checkSequenceFollows will check the alert before the sequence is stored, or was dropped because its type is disabled
+// Sequence 0 is the genesis alert, it's never submitted
+func checkSequenceFollows(ctx context.Context, sequenceNumber uint32, opts ...model.Options) error {
+	if sequenceNumber == 0 {
+		return fmt.Errorf("%w: %d", ErrAlertSequenceExists, sequenceNumber)
+	}
+	_, err := GetAlertMessageBySequenceNumber(ctx, sequenceNumber-1, opts...)
+	if !errors.Is(err, ErrAlertNotFound) {
+		return err
+	}
+	var dropped bool
+	if dropped, err = IsSequenceDropped(ctx, sequenceNumber-1, opts...); err != nil {
+		return err
+	} else if !dropped {
+		return fmt.Errorf("%w: %d", ErrAlertSequenceGap, sequenceNumber-1)
+	}
+	return nil
+}
@@ -1,29 +1,27 @@
 package models
 
+import (
+	"fmt"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
 // AlertType is the type of alert
 type AlertType uint32
 
-// Name returns the name of the alert type as a string
-func (a AlertType) Name() string {
-	switch a {
-	case AlertTypeInformational:
-		return "Informational"
-	case AlertTypeFreezeUtxo:
-		return "Freeze"
-	case AlertTypeUnfreezeUtxo:
-		return "Unfreeze"
-	case AlertTypeConfiscateUtxo:
-		return "Confiscate"
-	case AlertTypeBanPeer:
-		return "Ban Peer"
-	case AlertTypeUnbanPeer:
-		return "Unban Peer"
-	case AlertTypeInvalidateBlock:
-		return "Invalidate Block"
-	case AlertTypeSetKeys:
-		return "Set Keys"
+// String returns the machine readable name of the alert type (freeze_utxo, ban_peer, etc.)
+// Any other type is written as unknown(100), so it still reads back with ParseAlertType
+func (a AlertType) String() string {
+	if name, ok := config.AlertTypeNames[uint32(a)]; ok {
+		return name
 	}
-	return ""
+	return fmt.Sprintf("unknown(%d)", uint32(a))
+}
+
+// ParseAlertType will parse an alert type from its name, its unknown(100) form or its number
+func ParseAlertType(s string) (AlertType, error) {
+	t, err := config.ParseAlertType(s)
+	return AlertType(t), err
 }
 
 // AlertTypeInformational an alert type for informational alerts
@@ -49,3 +47,6 @@ const AlertTypeInvalidateBlock AlertType = 0x07
 
 // AlertTypeSetKeys is an alert type for setting keys
 const AlertTypeSetKeys AlertType = 0x08
+
+// AlertTypeSpecial is the special alert type, it has no action and a shorter signature block (see wire_format.go)
+const AlertTypeSpecial AlertType = 0x63
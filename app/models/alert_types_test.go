@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAlertType_String will test the method String()
+func TestAlertType_String(t *testing.T) {
+	assert.Equal(t, "informational", AlertTypeInformational.String())
+	assert.Equal(t, "freeze_utxo", AlertTypeFreezeUtxo.String())
+	assert.Equal(t, "ban_peer", AlertTypeBanPeer.String())
+	assert.Equal(t, "set_keys", AlertTypeSetKeys.String())
+	assert.Equal(t, "special", AlertTypeSpecial.String())
+	assert.Equal(t, "unknown(100)", AlertType(100).String())
+	assert.Equal(t, "unknown(0)", AlertType(0).String())
+}
+
+// TestParseAlertType will test the method ParseAlertType()
+func TestParseAlertType(t *testing.T) {
+	t.Run("every type round trips", func(t *testing.T) {
+		for _, alertType := range []AlertType{0, AlertTypeInformational, AlertTypeInvalidateBlock, AlertTypeSetKeys, 99, 1 << 31} {
+			parsed, err := ParseAlertType(alertType.String())
+			require.NoError(t, err)
+			assert.Equal(t, alertType, parsed)
+		}
+	})
+
+	t.Run("numbers and names", func(t *testing.T) {
+		parsed, err := ParseAlertType("4")
+		require.NoError(t, err)
+		assert.Equal(t, AlertTypeConfiscateUtxo, parsed)
+
+		parsed, err = ParseAlertType("Unban_Peer")
+		require.NoError(t, err)
+		assert.Equal(t, AlertTypeUnbanPeer, parsed)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"", "freeze", "-1", "unknown()", "unknown(x)", "4294967296"} {
+			_, err := ParseAlertType(s)
+			require.ErrorIs(t, err, ErrUnknownAlertTypeName, s)
+		}
+	})
+}
@@ -0,0 +1,273 @@
+package models
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// update will regenerate the golden alert vectors: go test ./app/models -run TestAlertVectors -update
+var update = flag.Bool("update", false, "regenerate the golden files in testdata")
+
+// alertVectorsDir is where the golden alert vectors are kept, one file per vector
+const alertVectorsDir = "testdata/vectors"
+
+// alertVectorTimestamp is the timestamp of every vector, so they are reproducible
+const alertVectorTimestamp = 1700000000
+
+// alertVector is a known-good encoded alert and what it decodes to
+type alertVector struct {
+	AlertType  string          `json:"alert_type"`
+	Decoded    json.RawMessage `json:"decoded"` // The fields of the alert body, null for an unknown type
+	Message    string          `json:"message"` // The alert body (hex)
+	Raw        string          `json:"raw"`     // The whole alert (hex)
+	Sequence   uint32          `json:"sequence"`
+	Serialized string          `json:"serialized,omitempty"` // Set when writing the alert back doesn't give the raw bytes
+	Signatures int             `json:"signatures"`
+	Supersedes uint32          `json:"supersedes,omitempty"`
+	Timestamp  uint64          `json:"timestamp"`
+	Version    uint32          `json:"version"`
+}
+
+// alertVectorSource is how a vector is built when the golden files are regenerated
+type alertVectorSource struct {
+	name       string
+	alertType  AlertType
+	body       interface{ Serialize() ([]byte, error) }
+	message    []byte // Used when there is no body
+	sequence   uint32
+	supersedes uint32
+	version    uint32
+}
+
+// alertVectorSources are the vectors, covering each alert type, the special type 99 and each signature layout
+var alertVectorSources = []alertVectorSource{
+	{
+		name:      "informational_v1",
+		alertType: AlertTypeInformational,
+		body:      &AlertMessageInformational{Message: []byte("legacy signature block")},
+		sequence:  1,
+		version:   1,
+	},
+	{
+		name:       "informational_v3",
+		alertType:  AlertTypeInformational,
+		body:       &AlertMessageInformational{Message: []byte("counted signature block")},
+		sequence:   2,
+		supersedes: 1,
+		version:    AlertVersionCurrent,
+	},
+	{
+		name:      "freeze_utxo",
+		alertType: AlertTypeFreezeUtxo,
+		body: &AlertMessageFreezeUtxo{Funds: []models.Fund{{
+			TxOut:           models.TxOut{TxId: "1111111111111111111111111111111111111111111111111111111111111111", Vout: 1},
+			EnforceAtHeight: []models.Enforce{{Start: 800000, Stop: 900000}},
+		}, {
+			TxOut:                      models.TxOut{TxId: "2222222222222222222222222222222222222222222222222222222222222222", Vout: 0},
+			EnforceAtHeight:            []models.Enforce{{Start: 800000, Stop: 800100}},
+			PolicyExpiresWithConsensus: true,
+		}}},
+		sequence: 3,
+		version:  AlertVersionCurrent,
+	},
+	{
+		name:      "unfreeze_utxo",
+		alertType: AlertTypeUnfreezeUtxo,
+		body: &AlertMessageUnfreezeUtxo{Funds: []models.Fund{{
+			TxOut:           models.TxOut{TxId: "1111111111111111111111111111111111111111111111111111111111111111", Vout: 1},
+			EnforceAtHeight: []models.Enforce{{Start: 800000, Stop: 850000}},
+		}}},
+		sequence: 4,
+		version:  AlertVersionCurrent,
+	},
+	{
+		name:      "confiscate_utxo",
+		alertType: AlertTypeConfiscateUtxo,
+		body: &AlertMessageConfiscateTransaction{Transactions: []models.ConfiscationTransactionDetails{{
+			ConfiscationTransaction: models.ConfiscationTransaction{EnforceAtHeight: 810000, Hex: "0100000000000000000000"},
+		}}},
+		sequence: 5,
+		version:  AlertVersionCurrent,
+	},
+	{
+		name:      "ban_peer",
+		alertType: AlertTypeBanPeer,
+		body:      &AlertMessageBanPeer{Peer: []byte("192.0.2.1:8333"), Reason: []byte("misbehaving"), BanDurationSeconds: 86400},
+		sequence:  6,
+		version:   AlertVersionCurrent,
+	},
+	{
+		name:      "unban_peer",
+		alertType: AlertTypeUnbanPeer,
+		body:      &AlertMessageUnbanPeer{Peer: []byte("192.0.2.1:8333"), Reason: []byte("resolved")},
+		sequence:  7,
+		version:   AlertVersionCurrent,
+	},
+	{
+		name:      "invalidate_block",
+		alertType: AlertTypeInvalidateBlock,
+		body:      &AlertMessageInvalidateBlock{BlockHash: &chainhash.Hash{0x01, 0x02, 0x03}, Reason: []byte("invalid block")},
+		sequence:  8,
+		version:   AlertVersionCurrent,
+	},
+	{
+		name:      "set_keys",
+		alertType: AlertTypeSetKeys,
+		body:      &AlertMessageSetKeys{Keys: vectorPublicKeys(utils.MainKey1, utils.MainKey2, utils.MainKey3, utils.MainKey4, utils.MainKey5)},
+		sequence:  9,
+		version:   AlertVersionCurrent,
+	},
+	{
+		name:      "type_99",
+		alertType: AlertTypeSpecial,
+		message:   []byte("special alert"),
+		sequence:  10,
+		version:   1,
+	},
+}
+
+// vectorPublicKeys will decode the compressed public keys of a set keys alert
+func vectorPublicKeys(keys ...string) [][33]byte {
+	decoded := make([][33]byte, 0, len(keys))
+	for _, key := range keys {
+		b, _ := hex.DecodeString(key)
+		decoded = append(decoded, [33]byte(b))
+	}
+	return decoded
+}
+
+// buildAlertVector will encode and sign the alert of a vector source
+func buildAlertVector(t *testing.T, src alertVectorSource) *alertVector {
+	message := src.message
+	if src.body != nil {
+		var err error
+		message, err = src.body.Serialize()
+		require.NoError(t, err)
+	}
+
+	a := NewAlertMessage()
+	a.SetVersion(src.version)
+	a.SetAlertType(src.alertType)
+	a.SetTimestamp(alertVectorTimestamp)
+	if src.supersedes > 0 {
+		a.SetSupersedes(src.supersedes)
+	}
+	a.SequenceNumber = src.sequence
+	a.SetRawMessage(message)
+	a.SerializeData()
+
+	sigs, err := utils.SignWithGenesis(a.GetRawData())
+	require.NoError(t, err)
+	if src.alertType == AlertTypeSpecial {
+		// Type 99 has room for a single signature, the rest of its block is padding
+		sigs = sigs[:SpecialSignatureCount]
+	}
+	a.SetSignatures(sigs)
+
+	v := &alertVector{
+		AlertType:  src.alertType.String(),
+		Message:    hex.EncodeToString(message),
+		Raw:        hex.EncodeToString(a.Serialize()),
+		Sequence:   src.sequence,
+		Supersedes: src.supersedes,
+		Timestamp:  alertVectorTimestamp,
+		Version:    src.version,
+	}
+
+	// The decoded fields and the re-encoding come from reading the alert back
+	parsed, err := NewAlertFromBytes(a.Serialize())
+	require.NoError(t, err)
+	v.Signatures = len(parsed.Signatures())
+	if serialized := hex.EncodeToString(parsed.Serialize()); serialized != v.Raw {
+		v.Serialized = serialized
+	}
+	body, err := parsed.ProcessAlertMessage()
+	require.NoError(t, err)
+	v.Decoded = decodedFields(t, body)
+	return v
+}
+
+// decodedFields will marshal the fields of an alert body, without the alert it's embedded in
+func decodedFields(t *testing.T, body AlertBody) json.RawMessage {
+	if body == nil {
+		return json.RawMessage("null")
+	}
+	value := reflect.ValueOf(body).Elem()
+	fields := make(map[string]interface{}, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		if field := value.Type().Field(i); !field.Anonymous {
+			fields[field.Name] = value.Field(i).Interface()
+		}
+	}
+	data, err := json.Marshal(fields)
+	require.NoError(t, err)
+	return data
+}
+
+// TestAlertVectors will decode the golden alert vectors and write them back
+func TestAlertVectors(t *testing.T) {
+	for _, src := range alertVectorSources {
+		t.Run(src.name, func(t *testing.T) {
+			path := filepath.Join(alertVectorsDir, src.name+".json")
+			built := buildAlertVector(t, src)
+			if *update {
+				data, err := json.MarshalIndent(built, "", "  ")
+				require.NoError(t, err)
+				require.NoError(t, os.MkdirAll(alertVectorsDir, 0o750))
+				require.NoError(t, os.WriteFile(path, append(data, '\n'), 0o600))
+			}
+
+			data, err := os.ReadFile(path) //nolint:gosec // the path is built from the vector name
+			require.NoError(t, err, "run with -update to create the golden file")
+			var golden alertVector
+			require.NoError(t, json.Unmarshal(data, &golden))
+
+			// Encoding the source must still give the known-good bytes
+			assert.Equal(t, golden.Raw, built.Raw)
+
+			// Read the header and signatures
+			raw, err := hex.DecodeString(golden.Raw)
+			require.NoError(t, err)
+			parsed, err := NewAlertFromBytes(raw)
+			require.NoError(t, err)
+			assert.Equal(t, golden.AlertType, parsed.GetAlertType().String())
+			assert.Equal(t, golden.Version, parsed.Version())
+			assert.Equal(t, golden.Sequence, parsed.SequenceNumber)
+			assert.Equal(t, golden.Supersedes, parsed.Supersedes)
+			assert.Equal(t, golden.Timestamp, parsed.Timestamp())
+			assert.Len(t, parsed.Signatures(), golden.Signatures)
+			assert.Equal(t, golden.Message, hex.EncodeToString(parsed.GetRawMessage()))
+
+			// Read the body, and write it back
+			body, err := parsed.ProcessAlertMessage()
+			require.NoError(t, err)
+			assert.JSONEq(t, string(golden.Decoded), string(decodedFields(t, body)))
+			if body != nil {
+				serializer, ok := body.(interface{ Serialize() ([]byte, error) })
+				require.True(t, ok)
+				message, err := serializer.Serialize()
+				require.NoError(t, err)
+				assert.Equal(t, golden.Message, hex.EncodeToString(message))
+			}
+
+			// Write the whole alert back
+			serialized := golden.Serialized
+			if serialized == "" {
+				serialized = golden.Raw
+			}
+			assert.Equal(t, serialized, hex.EncodeToString(parsed.Serialize()))
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/metrics"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// Audit entry sources (who or what triggered the alert action)
+const (
+	AuditSourceAPI    = "api"    // Alert submitted or re-processed on request through the API
+	AuditSourceGossip = "gossip" // Alert received on the pubsub topic
+	AuditSourceReplay = "replay" // Alert executed again by replaying the alert history
+	AuditSourceRetry  = "retry"  // Alert re-processed by the processing cron
+	AuditSourceSync   = "sync"   // Alert received while syncing from a peer
+
+	auditResultDryRun     = "dry run, not executed"
+	auditResultSuccess    = "success"
+	auditResultSuppressed = "suppressed, execution of the alert type is disabled"
+
+	// DefaultAuditEntriesLimit is the default number of audit entries returned by a query
+	DefaultAuditEntriesLimit = 100
+
+	// auditVerifyPageSize is the number of entries read at a time when verifying the stored chain
+	auditVerifyPageSize = 500
+)
+
+// auditLock serializes audit writes so the hash chain stays linear
+var auditLock sync.Mutex
+
+// auditWrites tracks the audit entries still being written, so shutdown can wait for them
+var auditWrites = &utils.InFlight{}
+
+// alertActions tracks the alert actions still being executed, so shutdown can wait for them
+var alertActions = &utils.InFlight{}
+
+// AuditEntry is an append-only record of an executed alert action
+//
+// There is one entry per execution attempt: an alert that fails and is retried has an entry for each attempt
+// Each entry includes the hash of the previous entry, so any edit or deletion breaks the chain
+type AuditEntry struct {
+	// Base model
+	model.Model `bson:",inline"`
+
+	// Model specific fields
+	ID             uint64    `json:"id" toml:"id" yaml:"id" bson:"_id" gorm:"primaryKey;comment:This is a unique identifier"`
+	ExecutedAt     time.Time `json:"executed_at" toml:"executed_at" yaml:"executed_at" bson:"executed_at" gorm:"<-;index;comment:This is when the alert action was executed"`
+	SequenceNumber uint32    `json:"sequence_number" toml:"sequence_number" yaml:"sequence_number" bson:"sequence_number" gorm:"<-;type:int8;index;comment:This is the alert sequence number"`
+	AlertType      uint32    `json:"alert_type" toml:"alert_type" yaml:"alert_type" bson:"alert_type" gorm:"<-;type:int8;index;comment:This is the alert type"`
+	Summary        string    `json:"summary" toml:"summary" yaml:"summary" bson:"summary" gorm:"<-;type:text;comment:This is the summary of the alert action"`
+	Result         string    `json:"result" toml:"result" yaml:"result" bson:"result" gorm:"<-;type:text;comment:This is the result of the alert action"`
+	RPCResult      string    `json:"rpc_result" toml:"rpc_result" yaml:"rpc_result" bson:"rpc_result" gorm:"<-;type:text;comment:This is the node RPC response (JSON)"`
+	Success        bool      `json:"success" toml:"success" yaml:"success" bson:"success" gorm:"<-;type:boolean;comment:This is if the alert action succeeded"`
+	Source         string    `json:"source" toml:"source" yaml:"source" bson:"source" gorm:"<-;type:varchar(128);comment:This is the source that triggered the alert action"`
+	PreviousHash   string    `json:"previous_hash" toml:"previous_hash" yaml:"previous_hash" bson:"previous_hash" gorm:"<-;type:char(64);comment:This is the hash of the previous audit entry"`
+	Hash           string    `json:"hash" toml:"hash" yaml:"hash" bson:"hash" gorm:"<-;type:char(64);index;comment:This is the hash of the audit entry"`
+}
+
+// AuditFilter is the set of filters for querying the audit log
+type AuditFilter struct {
+	AlertType *AlertType
+	From      time.Time
+	Limit     int // Defaults to DefaultAuditEntriesLimit
+	To        time.Time
+}
+
+// NewAuditEntry creates a new audit entry
+func NewAuditEntry(opts ...model.Options) *AuditEntry {
+	return &AuditEntry{
+		Model: *model.NewBaseModel(model.NameAuditEntry, opts...),
+	}
+}
+
+// Name will get the name of the model
+func (m *AuditEntry) Name() string {
+	return model.NameAuditEntry.String()
+}
+
+// GetTableName will get the database table name of the model
+func (m *AuditEntry) GetTableName() string {
+	return model.TableAuditEntries
+}
+
+// GetID will get the model ID
+func (m *AuditEntry) GetID() uint64 {
+	return m.ID
+}
+
+// Display filter the model for display
+func (m *AuditEntry) Display() interface{} {
+	return m
+}
+
+// Migrate will run model-specific migrations on startup
+func (m *AuditEntry) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(model.TableAuditEntries), model.MetadataField)
+}
+
+// BeginSaveWithTx will start saving the model into the Datastore with the provided transaction
+func (m *AuditEntry) BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction) ([]model.BaseInterface, error) {
+	return model.BeginSaveWithTx(ctx, tx, m)
+}
+
+// Save will save the model into the Datastore
+func (m *AuditEntry) Save(ctx context.Context) error {
+	return model.Save(ctx, m)
+}
+
+// ComputeHash will compute the hash of the entry, chained to the previous entry
+//
+// The fields are JSON encoded so free text (summary, result) can't be confused with a field boundary
+func (m *AuditEntry) ComputeHash() string {
+	fields, _ := json.Marshal([]interface{}{
+		m.PreviousHash, m.ExecutedAt.Unix(), m.SequenceNumber, m.AlertType, m.Summary, m.Result, m.RPCResult, m.Success, m.Source,
+	})
+	h := sha256.Sum256(fields)
+	return hex.EncodeToString(h[:])
+}
+
+// ExecuteAlertAction will perform the alert action and record the outcome in the audit log
+//
+// An alert stored unverified (lazy sync verification) has its signatures checked first and is never executed if they are invalid
+// If the alert needs the node and the node is unavailable, the action is skipped and ErrNodeUnavailable is returned
+// An alert that fails Validate is never executed, the audit entry records why and ErrAlertInvalid is returned
+// If the sequence was already processed (the alert was delivered twice) the action is skipped and ErrSequenceProcessed is returned,
+// callers hold LockSequence while executing and saving the alert so two deliveries can't both pass this check
+// An alert of a type whose execution is disabled (see ExecutionSuppressed) is validated and logged but its action is skipped
+// In dry-run mode an alert that needs the node is validated and logged but its action is skipped, and it's flagged as DryRun
+// The error from the action is returned as-is, failing to write the audit entry is only logged
+func ExecuteAlertAction(ctx context.Context, alert *AlertMessage, action AlertMessageInterface, source string) error {
+	return executeAlertAction(ctx, alert, action, source, true)
+}
+
+// executeAlertAction will perform the alert action (see ExecuteAlertAction), only once per sequence if once is set
+// Replaying the alert history executes alerts that were processed before, so it doesn't check the sequence
+func executeAlertAction(ctx context.Context, alert *AlertMessage, action AlertMessageInterface, source string, once bool) (err error) {
+	alertActions.Begin()
+	defer alertActions.End()
+
+	ctx, span := alert.Config().Tracer().Start(ctx, "alert.execute", trace.WithAttributes(
+		attribute.String("alert.type", alert.GetAlertType().String()),
+		attribute.Int64("alert.sequence", int64(alert.SequenceNumber)),
+		attribute.String("alert.source", source),
+	))
+	defer func() {
+		config.EndSpan(span, err)
+		metrics.ObserveAlertAction(alert.GetAlertType().String(), err)
+	}()
+	if err = alert.VerifyIfUnverified(ctx); err != nil {
+		return err
+	}
+	if once {
+		var processed bool
+		if processed, err = IsSequenceProcessed(ctx, alert.SequenceNumber, model.WithAllDependencies(alert.Config())); err != nil {
+			return err
+		} else if processed {
+			alert.Config().Services.Log.Debugf("alert %d was already processed, skipping its action", alert.SequenceNumber)
+			return fmt.Errorf("%w: %d", ErrSequenceProcessed, alert.SequenceNumber)
+		}
+	}
+	if err = checkNodeAvailable(ctx, alert); err != nil {
+		return err
+	}
+	err = action.Validate(ctx)
+	alert.suppressed = err == nil && alert.ExecutionSuppressed()
+	alert.DryRun = err == nil && !alert.suppressed && alert.Config().DryRun && needsNode(alert.GetAlertType())
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrAlertInvalid, err)
+	} else if alert.suppressed {
+		alert.Config().Services.Log.Infof("suppressed %s alert %d, execution of its type is disabled: %s", alert.GetAlertType(), alert.SequenceNumber, action.MessageString())
+	} else if alert.DryRun {
+		alert.Config().Services.Log.Infof("[dry run] skipping %s alert %d: %s", alert.GetAlertType(), alert.SequenceNumber, action.MessageString())
+	} else {
+		err = action.Do(ctx)
+	}
+	if auditErr := RecordAuditEntry(ctx, alert, action, source, err); auditErr != nil {
+		alert.Config().Services.Log.Errorf("failed to record audit entry for alert %d: %s", alert.SequenceNumber, auditErr.Error())
+	}
+	return err
+}
+
+// ReprocessAlert will perform the action of a stored alert again, saving it as processed if it succeeded
+//
+// A partially applied, invalid or already processed alert is saved as processed too (performing it again won't change the outcome),
+// its error is still returned. An alert of a type this node can't parse returns ErrAlertTypeUnknown, and a stored alert
+// that doesn't match its hash returns ErrAlertHashMismatch without being performed
+func ReprocessAlert(ctx context.Context, alert *AlertMessage, source string) error {
+	if err := alert.VerifyHash(); err != nil {
+		return err
+	} else if err = alert.ReadRaw(); err != nil {
+		return err
+	}
+	alert.SerializeData()
+	action, err := alert.ProcessAlertMessage()
+	if err != nil {
+		return err
+	} else if action == nil {
+		return fmt.Errorf("%w: %d", ErrAlertTypeUnknown, alert.GetAlertType())
+	}
+
+	unlock := LockSequence(alert.SequenceNumber)
+	defer unlock()
+	err = ExecuteAlertAction(ctx, alert, action, source)
+	alert.Processed = err == nil || errors.Is(err, ErrPartialSuccess) || errors.Is(err, ErrAlertInvalid) || errors.Is(err, ErrSequenceProcessed)
+	if alert.Processed {
+		if saveErr := alert.Save(ctx); saveErr != nil {
+			return saveErr
+		}
+	}
+	return err
+}
+
+// RecordAuditEntry will append an audit entry for an executed alert action
+func RecordAuditEntry(ctx context.Context, alert *AlertMessage, action AlertMessageInterface, source string, actionErr error) error {
+	auditWrites.Begin()
+	defer auditWrites.End()
+
+	auditLock.Lock()
+	defer auditLock.Unlock()
+
+	entry := NewAuditEntry(model.WithAllDependencies(alert.Config()), model.New())
+	entry.ExecutedAt = time.Now().UTC().Truncate(time.Second)
+	entry.SequenceNumber = alert.SequenceNumber
+	entry.AlertType = uint32(alert.GetAlertType())
+	entry.Summary = action.MessageString()
+	entry.Source = source
+	entry.Success = actionErr == nil
+	entry.Result = auditResultSuccess
+	if actionErr != nil {
+		entry.Result = actionErr.Error()
+	} else if alert.suppressed {
+		entry.Result = auditResultSuppressed
+	} else if alert.DryRun {
+		entry.Result = auditResultDryRun
+	}
+	if holder, ok := action.(interface{ RPCResult() interface{} }); ok && holder.RPCResult() != nil {
+		rpcResult, err := json.Marshal(holder.RPCResult())
+		if err != nil {
+			return err
+		}
+		entry.RPCResult = string(rpcResult)
+	}
+
+	// Chain to the previous entry
+	previous, err := GetLatestAuditEntry(ctx, nil, model.WithAllDependencies(alert.Config()))
+	if err != nil {
+		return err
+	} else if previous != nil {
+		entry.PreviousHash = previous.Hash
+	}
+	entry.Hash = entry.ComputeHash()
+
+	return entry.Save(ctx)
+}
+
+// FlushAuditLog will wait for the audit entries still being written to be persisted
+//
+// This is called on shutdown so the last alert actions before a restart aren't missing from the log
+// If the context is done first, ErrAuditFlushIncomplete is returned
+func FlushAuditLog(ctx context.Context) error {
+	select {
+	case <-auditWrites.Wait():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrAuditFlushIncomplete, ctx.Err())
+	}
+}
+
+// DrainAlertActions will wait for the alert actions still being executed to finish, or for the context to be done
+//
+// This is called on shutdown, after no new alerts are accepted, so an action isn't cut off part way through its RPC calls
+// It returns how many of the running actions finished and how many were still running when the context was done
+func DrainAlertActions(ctx context.Context) (drained, abandoned int) {
+	return alertActions.Drain(ctx)
+}
+
+// VerifyAuditChain will check that the entries (in order) form an unbroken hash chain
+func VerifyAuditChain(entries []*AuditEntry) error {
+	for i, entry := range entries {
+		if entry.Hash != entry.ComputeHash() {
+			return fmt.Errorf("%w: entry %d hash mismatch", ErrAuditChainBroken, entry.ID)
+		}
+		if i > 0 && entry.PreviousHash != entries[i-1].Hash {
+			return fmt.Errorf("%w: entry %d does not follow entry %d", ErrAuditChainBroken, entry.ID, entries[i-1].ID)
+		}
+	}
+	return nil
+}
+
+// VerifyStoredAuditChain will verify the whole stored audit log, a page at a time
+func VerifyStoredAuditChain(ctx context.Context, opts ...model.Options) error {
+	var previous *AuditEntry
+	for page := 1; ; page++ {
+		queryParams := &datastore.QueryParams{
+			Page:          page,
+			PageSize:      auditVerifyPageSize,
+			OrderByField:  utils.FieldID,
+			SortDirection: utils.SortAscending,
+		}
+		modelItems := make([]*AuditEntry, 0)
+		if err := model.GetModelsByConditions(
+			ctx, model.NameAuditEntry, &modelItems, nil, nil, queryParams, opts...,
+		); err != nil {
+			return err
+		} else if len(modelItems) == 0 {
+			return nil
+		}
+
+		// Carry the last entry of the previous page so the link between pages is checked
+		if previous != nil {
+			modelItems = append([]*AuditEntry{previous}, modelItems...)
+		}
+		if err := VerifyAuditChain(modelItems); err != nil {
+			return err
+		}
+		previous = modelItems[len(modelItems)-1]
+	}
+}
+
+// GetLatestAuditEntry will get the most recent audit entry, or nil if the log is empty
+func GetLatestAuditEntry(ctx context.Context, metadata *model.Metadata, opts ...model.Options) (*AuditEntry, error) {
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		Page:          1,
+		PageSize:      1,
+		OrderByField:  utils.FieldID,
+		SortDirection: utils.SortDescending,
+	}
+
+	// Get the record
+	modelItems := make([]*AuditEntry, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAuditEntry, &modelItems, metadata, nil, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	} else if len(modelItems) == 0 {
+		return nil, nil
+	}
+
+	return modelItems[0], nil
+}
+
+// GetAuditEntries will get the audit entries matching the filter, oldest first (up to the filter limit)
+func GetAuditEntries(ctx context.Context, filter *AuditFilter, metadata *model.Metadata, opts ...model.Options) ([]*AuditEntry, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+	if filter != nil {
+		executedAt := map[string]interface{}{}
+		if !filter.From.IsZero() {
+			executedAt[utils.GreaterOrEqualCondition] = filter.From
+		}
+		if !filter.To.IsZero() {
+			executedAt[utils.LessThanOrEqualCondition] = filter.To
+		}
+		if len(executedAt) > 0 {
+			(*conditions)[utils.FieldExecutedAt] = executedAt
+		}
+		if filter.AlertType != nil {
+			(*conditions)[utils.FieldAlertType] = uint32(*filter.AlertType)
+		}
+	}
+
+	// Set the query params
+	limit := DefaultAuditEntriesLimit
+	if filter != nil && filter.Limit > 0 {
+		limit = filter.Limit
+	}
+	queryParams := &datastore.QueryParams{
+		Page:          1,
+		PageSize:      limit,
+		OrderByField:  utils.FieldID,
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the records
+	modelItems := make([]*AuditEntry, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAuditEntry, &modelItems, metadata, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
@@ -0,0 +1,352 @@
+package models
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// errTestNodeDown is returned by the mock node in the audit tests
+var errTestNodeDown = errors.New("node is down")
+
+// newTestAuditAlert will create an alert of the given type with the given message body
+func (ts *TestSuite) newTestAuditAlert(sequence uint32, alertType AlertType, body []byte) (*AlertMessage, AlertMessageInterface) {
+	alert := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+	alert.SequenceNumber = sequence
+	alert.SetAlertType(alertType)
+	alert.SetRawMessage(body)
+
+	action, err := alert.ProcessAlertMessage()
+	ts.Require().NoError(err)
+	return alert, action
+}
+
+// TestExecuteAlertAction will test the method ExecuteAlertAction()
+func (ts *TestSuite) TestExecuteAlertAction() {
+	ts.Run("each execution attempt produces one audit entry", func() {
+		ts.Dependencies.Services.Node = &mocks.Node{
+			InvalidateBlockFunc: func(_ context.Context, _ string) error {
+				return errTestNodeDown
+			},
+		}
+		invalidateBody := append(make([]byte, 32), 0x04, 't', 'e', 's', 't')
+
+		info, infoAction := ts.newTestAuditAlert(10, AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), info, infoAction, AuditSourceGossip+":peer-a"))
+
+		invalidate, invalidateAction := ts.newTestAuditAlert(11, AlertTypeInvalidateBlock, invalidateBody)
+		ts.Require().ErrorIs(ExecuteAlertAction(context.Background(), invalidate, invalidateAction, AuditSourceRetry), errTestNodeDown)
+
+		entries, err := GetAuditEntries(context.Background(), nil, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(entries, 2)
+
+		// Successful informational alert
+		ts.Equal(uint32(10), entries[0].SequenceNumber)
+		ts.Equal(uint32(AlertTypeInformational), entries[0].AlertType)
+		ts.Equal(infoAction.MessageString(), entries[0].Summary)
+		ts.Equal(auditResultSuccess, entries[0].Result)
+		ts.True(entries[0].Success)
+		ts.Equal(AuditSourceGossip+":peer-a", entries[0].Source)
+		ts.False(entries[0].ExecutedAt.IsZero())
+		ts.Empty(entries[0].PreviousHash)
+
+		// Failed invalidate block alert records the RPC error
+		ts.Equal(uint32(11), entries[1].SequenceNumber)
+		ts.Equal(uint32(AlertTypeInvalidateBlock), entries[1].AlertType)
+		ts.Equal(invalidateAction.MessageString(), entries[1].Summary)
+		ts.Equal(errTestNodeDown.Error(), entries[1].Result)
+		ts.False(entries[1].Success)
+		ts.Equal(AuditSourceRetry, entries[1].Source)
+		ts.Equal(entries[0].Hash, entries[1].PreviousHash)
+
+		ts.Require().NoError(VerifyAuditChain(entries))
+		ts.Require().NoError(VerifyStoredAuditChain(context.Background(), model.WithAllDependencies(ts.Dependencies)))
+	})
+
+	ts.Run("node RPC result is recorded", func() {
+		ts.Dependencies.Services.Node = rejectingNode(testFunds(2)[1])
+		body := make([]byte, 0, 2*FundSize)
+		for _, fund := range testFunds(2) {
+			txID, err := hex.DecodeString(fund.TxOut.TxId)
+			ts.Require().NoError(err)
+			f := Fund{TransactionOutID: [32]byte(txID), Vout: uint64(fund.TxOut.Vout), EnforceAtHeightStart: 100, EnforceAtHeightEnd: 200}
+			body = append(body, f.Serialize()...)
+		}
+		freeze, freezeAction := ts.newTestAuditAlert(12, AlertTypeFreezeUtxo, body)
+		ts.Require().ErrorIs(ExecuteAlertAction(context.Background(), freeze, freezeAction, AuditSourceGossip), ErrPartialSuccess)
+
+		latest, err := GetLatestAuditEntry(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NotNil(latest)
+		ts.Contains(latest.RPCResult, "already frozen")
+		ts.Contains(latest.RPCResult, testFunds(2)[1].TxOut.TxId)
+	})
+
+	ts.Run("invalid alert is recorded but never executed", func() {
+		called := false
+		ts.Dependencies.Services.Node = &mocks.Node{
+			AddToConsensusBlacklistFunc: func(_ context.Context, _ []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
+				called = true
+				return &models.AddToConsensusBlacklistResponse{}, nil
+			},
+		}
+		f := Fund{TransactionOutID: [32]byte(make([]byte, 32)), EnforceAtHeightStart: 200, EnforceAtHeightEnd: 100}
+		freeze, freezeAction := ts.newTestAuditAlert(13, AlertTypeFreezeUtxo, f.Serialize())
+		err := ExecuteAlertAction(context.Background(), freeze, freezeAction, AuditSourceGossip)
+		ts.Require().ErrorIs(err, ErrAlertInvalid)
+		ts.Require().ErrorIs(err, ErrEnforceAtHeightInverted)
+		ts.False(called)
+
+		latest, err := GetLatestAuditEntry(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NotNil(latest)
+		ts.Equal(uint32(13), latest.SequenceNumber)
+		ts.False(latest.Success)
+		ts.Contains(latest.Result, ErrEnforceAtHeightInverted.Error())
+	})
+
+	ts.Run("the action and its node calls are traced", func() {
+		recorder := tracetest.NewSpanRecorder()
+		ts.Dependencies.Services.Tracing = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		ts.Dependencies.Services.Node = config.NewTracedNode(&mocks.Node{}, ts.Dependencies)
+
+		invalidate, invalidateAction := ts.newTestAuditAlert(14, AlertTypeInvalidateBlock, append(make([]byte, 32), 0x04, 't', 'e', 's', 't'))
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), invalidate, invalidateAction, AuditSourceRetry))
+
+		spans := recorder.Ended()
+		ts.Require().Len(spans, 2)
+		rpc, execute := spans[0], spans[1]
+		ts.Equal("alert.execute", execute.Name())
+		ts.Contains(execute.Attributes(), attribute.String("alert.type", AlertTypeInvalidateBlock.String()))
+		ts.Contains(execute.Attributes(), attribute.Int64("alert.sequence", 14))
+		ts.Contains(execute.Attributes(), attribute.String("alert.source", AuditSourceRetry))
+
+		// The node call is a child of the alert action
+		ts.Equal("node.InvalidateBlock", rpc.Name())
+		ts.Equal(execute.SpanContext().SpanID(), rpc.Parent().SpanID())
+	})
+
+	ts.Run("an already processed sequence is not executed again", func() {
+		called := false
+		ts.Dependencies.Services.Node = &mocks.Node{
+			InvalidateBlockFunc: func(_ context.Context, _ string) error {
+				called = true
+				return nil
+			},
+		}
+		body := append(make([]byte, 32), 0x04, 't', 'e', 's', 't')
+
+		first, firstAction := ts.newTestAuditAlert(17, AlertTypeInvalidateBlock, body)
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), first, firstAction, AuditSourceGossip))
+		ts.True(called)
+		first.Processed = true
+		ts.Require().NoError(first.Save(context.Background()))
+
+		processed, err := IsSequenceProcessed(context.Background(), 17, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.True(processed)
+
+		called = false
+		second, secondAction := ts.newTestAuditAlert(17, AlertTypeInvalidateBlock, body)
+		ts.Require().ErrorIs(ExecuteAlertAction(context.Background(), second, secondAction, AuditSourceSync), ErrSequenceProcessed)
+		ts.False(called)
+
+		latest, err := GetLatestAuditEntry(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NotNil(latest)
+		ts.Equal(AuditSourceGossip, latest.Source)
+	})
+
+	ts.Run("dry run skips the node calls", func() {
+		ts.Dependencies.DryRun = true
+		defer func() {
+			ts.Dependencies.DryRun = false
+		}()
+		called := false
+		ts.Dependencies.Services.Node = &mocks.Node{
+			InvalidateBlockFunc: func(_ context.Context, _ string) error {
+				called = true
+				return nil
+			},
+		}
+
+		invalidate, invalidateAction := ts.newTestAuditAlert(15, AlertTypeInvalidateBlock, append(make([]byte, 32), 0x04, 't', 'e', 's', 't'))
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), invalidate, invalidateAction, AuditSourceGossip))
+		ts.False(called)
+		ts.True(invalidate.DryRun)
+
+		latest, err := GetLatestAuditEntry(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NotNil(latest)
+		ts.Equal(uint32(15), latest.SequenceNumber)
+		ts.True(latest.Success)
+		ts.Equal(auditResultDryRun, latest.Result)
+
+		// Alerts that don't need the node are still executed
+		info, infoAction := ts.newTestAuditAlert(16, AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), info, infoAction, AuditSourceGossip))
+		ts.False(info.DryRun)
+	})
+
+	ts.Run("a suppressed alert type is not executed", func() {
+		ts.Dependencies.ExecutedAlertTypes = []string{"informational", "set_keys"}
+		ts.Dependencies.SuppressedAlertTypes = []string{"ban_peer"}
+		defer func() {
+			ts.Dependencies.ExecutedAlertTypes = nil
+			ts.Dependencies.SuppressedAlertTypes = nil
+		}()
+		called := false
+		ts.Dependencies.Services.Node = &mocks.Node{
+			InvalidateBlockFunc: func(_ context.Context, _ string) error {
+				called = true
+				return nil
+			},
+		}
+
+		// Not in the executed types
+		invalidate, invalidateAction := ts.newTestAuditAlert(20, AlertTypeInvalidateBlock, append(make([]byte, 32), 0x04, 't', 'e', 's', 't'))
+		ts.True(invalidate.ExecutionSuppressed())
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), invalidate, invalidateAction, AuditSourceGossip))
+		ts.False(called)
+		ts.False(invalidate.DryRun)
+
+		latest, err := GetLatestAuditEntry(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NotNil(latest)
+		ts.Equal(uint32(20), latest.SequenceNumber)
+		ts.True(latest.Success)
+		ts.Equal(auditResultSuppressed, latest.Result)
+
+		// The suppressed types win over the executed types, and set keys is always executed
+		ban := NewAlertMessage(model.WithAllDependencies(ts.Dependencies))
+		ban.SetAlertType(AlertTypeBanPeer)
+		ts.True(ban.ExecutionSuppressed())
+		ts.Dependencies.ExecutedAlertTypes = nil
+		ts.True(ban.ExecutionSuppressed())
+		ban.SetAlertType(AlertTypeSetKeys)
+		ts.False(ban.ExecutionSuppressed())
+
+		info, infoAction := ts.newTestAuditAlert(21, AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+		ts.False(info.ExecutionSuppressed())
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), info, infoAction, AuditSourceGossip))
+	})
+}
+
+// TestGetAuditEntries will test the method GetAuditEntries()
+func (ts *TestSuite) TestGetAuditEntries() {
+	info, infoAction := ts.newTestAuditAlert(20, AlertTypeInformational, []byte{0x02, 'h', 'i'})
+	ts.Require().NoError(ExecuteAlertAction(context.Background(), info, infoAction, AuditSourceSync))
+
+	ts.Run("filter by type", func() {
+		alertType := AlertTypeInformational
+		entries, err := GetAuditEntries(context.Background(), &AuditFilter{AlertType: &alertType}, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(entries, 1)
+
+		alertType = AlertTypeBanPeer
+		entries, err = GetAuditEntries(context.Background(), &AuditFilter{AlertType: &alertType}, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Empty(entries)
+	})
+
+	ts.Run("filter by time", func() {
+		entries, err := GetAuditEntries(context.Background(), &AuditFilter{From: time.Now().Add(-time.Hour)}, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Len(entries, 1)
+
+		entries, err = GetAuditEntries(context.Background(), &AuditFilter{To: time.Now().Add(-time.Hour)}, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Empty(entries)
+	})
+
+	ts.Run("limit", func() {
+		second, secondAction := ts.newTestAuditAlert(21, AlertTypeInformational, []byte{0x02, 'h', 'o'})
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), second, secondAction, AuditSourceSync))
+
+		entries, err := GetAuditEntries(context.Background(), &AuditFilter{Limit: 1}, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(entries, 1)
+		ts.Equal(uint32(20), entries[0].SequenceNumber)
+	})
+}
+
+// TestVerifyAuditChain will test the method VerifyAuditChain()
+func (ts *TestSuite) TestVerifyAuditChain() {
+	first := &AuditEntry{ID: 1, SequenceNumber: 1, Summary: "first", Result: auditResultSuccess, Success: true}
+	first.Hash = first.ComputeHash()
+	second := &AuditEntry{ID: 2, SequenceNumber: 2, Summary: "second", Result: auditResultSuccess, Success: true, PreviousHash: first.Hash}
+	second.Hash = second.ComputeHash()
+
+	ts.Run("valid chain", func() {
+		ts.NoError(VerifyAuditChain([]*AuditEntry{first, second}))
+	})
+
+	ts.Run("edited entry", func() {
+		edited := *first
+		edited.Result = "tampered"
+		ts.ErrorIs(VerifyAuditChain([]*AuditEntry{&edited, second}), ErrAuditChainBroken)
+	})
+
+	ts.Run("field separators in free text", func() {
+		// The same characters moved between fields must not produce the same hash
+		a := &AuditEntry{Summary: "a|b", Result: "c"}
+		b := &AuditEntry{Summary: "a", Result: "b|c"}
+		ts.NotEqual(a.ComputeHash(), b.ComputeHash())
+	})
+
+	ts.Run("deleted entry", func() {
+		third := &AuditEntry{ID: 3, Summary: "third", PreviousHash: second.Hash}
+		third.Hash = third.ComputeHash()
+		ts.ErrorIs(VerifyAuditChain([]*AuditEntry{first, third}), ErrAuditChainBroken)
+	})
+}
+
+// TestFlushAuditLog will test the method FlushAuditLog()
+func (ts *TestSuite) TestFlushAuditLog() {
+	ts.Run("nothing being written", func() {
+		ts.NoError(FlushAuditLog(context.Background()))
+	})
+
+	ts.Run("entries written just before shutdown are persisted", func() {
+		info, infoAction := ts.newTestAuditAlert(30, AlertTypeInformational, []byte{0x02, 'h', 'i'})
+
+		// Hold the writes up until shutdown has started
+		auditLock.Lock()
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				errs <- RecordAuditEntry(context.Background(), info, infoAction, AuditSourceRetry, nil)
+			}()
+		}
+		ts.Eventually(func() bool {
+			return auditWrites.Count() == 2
+		}, time.Second, time.Millisecond)
+
+		// The grace period ends before the writes are done
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		ts.ErrorIs(FlushAuditLog(ctx), ErrAuditFlushIncomplete)
+
+		// The writes finish within the grace period
+		auditLock.Unlock()
+		ts.Require().NoError(FlushAuditLog(context.Background()))
+		ts.Require().NoError(<-errs)
+		ts.Require().NoError(<-errs)
+
+		entries, err := GetAuditEntries(context.Background(), nil, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(entries, 2)
+		ts.NoError(VerifyAuditChain(entries))
+	})
+}
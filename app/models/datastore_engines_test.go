@@ -0,0 +1,90 @@
+package models
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// testPostgresKey is the environment variable that runs the datastore engine tests against Postgres too
+// The connection is the sql_read/sql_write config of test.json, overridden by the usual environment variables
+// (ALERT_SYSTEM_DATASTORE__SQL_WRITE__HOST and so on)
+const testPostgresKey = "ALERT_SYSTEM_TEST_POSTGRES"
+
+// datastoreEngines are the engines the core queries are tested against, SQLite always and Postgres when enabled
+func datastoreEngines() []datastore.Engine {
+	engines := []datastore.Engine{datastore.SQLite}
+	if enabled, _ := strconv.ParseBool(os.Getenv(testPostgresKey)); enabled {
+		engines = append(engines, datastore.PostgreSQL)
+	}
+	return engines
+}
+
+// TestDatastoreEngines will test the core alert queries against each datastore engine
+func TestDatastoreEngines(t *testing.T) {
+	for _, engine := range datastoreEngines() {
+		t.Run(engine.String(), func(t *testing.T) {
+			// A table prefix of its own, so a shared database doesn't hold alerts from earlier runs
+			t.Setenv(config.EnvironmentKey, config.EnvironmentTest)
+			t.Setenv("ALERT_SYSTEM_DATASTORE__ENGINE", engine.String())
+			t.Setenv("ALERT_SYSTEM_DATASTORE__TABLE_PREFIX", "engine_test_"+strconv.FormatInt(time.Now().UnixNano(), 36))
+
+			ctx := context.Background()
+			deps, err := config.LoadDependencies(ctx, BaseModels, true)
+			require.NoError(t, err)
+			defer deps.CloseAll(ctx)
+			require.Equal(t, engine, deps.Services.Datastore.Engine())
+
+			for _, sequence := range []uint32{1, 2, 3} {
+				a := NewAlertMessage(model.WithAllDependencies(deps), model.New())
+				a.SequenceNumber = sequence
+				a.SetAlertType(AlertTypeInformational)
+				a.Processed = sequence == 2
+				require.NoError(t, a.Save(ctx))
+			}
+
+			latest, err := GetLatestAlert(ctx, nil, model.WithAllDependencies(deps))
+			require.NoError(t, err)
+			assert.Equal(t, uint32(3), latest.SequenceNumber)
+
+			alert, err := GetAlertMessageBySequenceNumber(ctx, 2, model.WithAllDependencies(deps))
+			require.NoError(t, err)
+			assert.True(t, alert.Processed)
+
+			_, err = GetAlertMessageBySequenceNumber(ctx, 4, model.WithAllDependencies(deps))
+			require.ErrorIs(t, err, ErrAlertNotFound)
+
+			unprocessed, err := GetAllUnprocessedAlerts(ctx, nil, model.WithAllDependencies(deps))
+			require.NoError(t, err)
+			sequences := make([]uint32, 0, len(unprocessed))
+			for _, a := range unprocessed {
+				sequences = append(sequences, a.SequenceNumber)
+			}
+			assert.Equal(t, []uint32{1, 3}, sequences)
+		})
+	}
+}
+
+// TestQueryTimeout will test the configured query timeout abandons model queries and saves
+func (ts *TestSuite) TestQueryTimeout() {
+	ts.Dependencies.Datastore.QueryTimeout = time.Nanosecond
+	defer func() {
+		ts.Dependencies.Datastore.QueryTimeout = config.DefaultDatastoreQueryTimeout
+	}()
+
+	a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+	a.SequenceNumber = 1
+	ts.Require().ErrorIs(a.Save(context.Background()), context.DeadlineExceeded)
+
+	_, err := GetAllUnprocessedAlerts(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().ErrorIs(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"context"
+
+	"github.com/mrz1836/go-datastore"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// DroppedAlert marks an alert dropped on receipt because its type is disabled
+// The alert itself is never stored, the marker keeps its sequence from being a gap to fill after a restart
+type DroppedAlert struct {
+	// Base model
+	model.Model `bson:",inline"`
+
+	// Model specific fields
+	ID             uint64 `json:"id" toml:"id" yaml:"id" bson:"_id" gorm:"primaryKey;comment:This is a unique identifier"`
+	SequenceNumber uint32 `json:"sequence_number" toml:"sequence_number" yaml:"sequence_number" bson:"sequence_number" gorm:"<-;type:int8;index;comment:This is the sequence number of the dropped alert"`
+	AlertType      uint32 `json:"alert_type" toml:"alert_type" yaml:"alert_type" bson:"alert_type" gorm:"<-;type:int8;comment:This is the disabled alert type"`
+	Hash           string `json:"hash" toml:"hash" yaml:"hash" bson:"hash" gorm:"<-;type:char(64);comment:This is the hash of the dropped alert"`
+}
+
+// NewDroppedAlert creates a new dropped alert marker
+func NewDroppedAlert(opts ...model.Options) *DroppedAlert {
+	return &DroppedAlert{
+		Model: *model.NewBaseModel(model.NameDroppedAlert, opts...),
+	}
+}
+
+// Name will get the name of the model
+func (m *DroppedAlert) Name() string {
+	return model.NameDroppedAlert.String()
+}
+
+// GetTableName will get the database table name of the model
+func (m *DroppedAlert) GetTableName() string {
+	return model.TableDroppedAlerts
+}
+
+// GetID will get the model ID
+func (m *DroppedAlert) GetID() uint64 {
+	return m.ID
+}
+
+// Display filter the model for display
+func (m *DroppedAlert) Display() interface{} {
+	return m
+}
+
+// Migrate will run model-specific migrations on startup
+func (m *DroppedAlert) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(model.TableDroppedAlerts), model.MetadataField)
+}
+
+// BeginSaveWithTx will start saving the model into the Datastore with the provided transaction
+func (m *DroppedAlert) BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction) ([]model.BaseInterface, error) {
+	return model.BeginSaveWithTx(ctx, tx, m)
+}
+
+// Save will save the model into the Datastore
+func (m *DroppedAlert) Save(ctx context.Context) error {
+	return model.Save(ctx, m)
+}
+
+// RecordDroppedAlert will store the marker of an alert dropped on receipt, a sequence already marked is left as is
+func RecordDroppedAlert(ctx context.Context, alert *AlertMessage, opts ...model.Options) error {
+	// The same alert can be synced or gossiped again
+	if dropped, err := IsSequenceDropped(ctx, alert.SequenceNumber, opts...); err != nil || dropped {
+		return err
+	}
+
+	dropped := NewDroppedAlert(append(opts, model.New())...)
+	dropped.SequenceNumber = alert.SequenceNumber
+	dropped.AlertType = uint32(alert.GetAlertType())
+	dropped.Hash = alert.Hash
+	return dropped.Save(ctx)
+}
+
+// IsSequenceDropped will return true if the alert at the sequence was dropped on receipt
+func IsSequenceDropped(ctx context.Context, sequenceNumber uint32, opts ...model.Options) (bool, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldSequenceNumber: sequenceNumber,
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	modelItems := make([]*DroppedAlert, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameDroppedAlert, &modelItems, nil, conditions, &datastore.QueryParams{Page: 1, PageSize: 1}, opts...,
+	); err != nil {
+		return false, err
+	}
+	return len(modelItems) > 0, nil
+}
+
+// GetDroppedAlerts will get the markers of every alert dropped on receipt, by sequence number
+func GetDroppedAlerts(ctx context.Context, opts ...model.Options) ([]*DroppedAlert, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  utils.FieldSequenceNumber,
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the records
+	modelItems := make([]*DroppedAlert, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameDroppedAlert, &modelItems, nil, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// TestRecordDroppedAlert will test the method RecordDroppedAlert()
+func (ts *TestSuite) TestRecordDroppedAlert() {
+	// drop will record an alert of the given type as dropped
+	drop := func(sequence uint32, alertType AlertType) {
+		alert := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		alert.SequenceNumber = sequence
+		alert.SetAlertType(alertType)
+		alert.SetRawMessage([]byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+		alert.SerializeData()
+		ts.Require().NoError(RecordDroppedAlert(context.Background(), alert, model.WithAllDependencies(ts.Dependencies)))
+	}
+
+	drop(7, AlertTypeInformational)
+	drop(3, AlertTypeBanPeer)
+
+	// The same alert synced or gossiped again keeps its one marker
+	drop(7, AlertTypeInformational)
+
+	dropped, err := GetDroppedAlerts(context.Background(), model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Len(dropped, 2)
+	ts.Equal(uint32(3), dropped[0].SequenceNumber)
+	ts.Equal(uint32(AlertTypeBanPeer), dropped[0].AlertType)
+	ts.Equal(uint32(7), dropped[1].SequenceNumber)
+	ts.Equal(uint32(AlertTypeInformational), dropped[1].AlertType)
+	ts.Len(dropped[1].Hash, 64)
+}
@@ -1,24 +1,66 @@
 package models
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
 
 // Static errors for the models package
 var (
 	// AlertMessage errors
 	ErrNoActivePublicKeys        = errors.New("no active public keys found")
+	ErrNodeUnavailable           = errors.New("node is unavailable")
+	ErrNodeRPCTimeout            = errors.New("node RPC call timed out")
+	ErrNodeRPCRetriesExhausted   = errors.New("node RPC call failed")
 	ErrFailedToConvertPubKey     = errors.New("failed to convert pub key to address")
+	ErrAlertTooLong              = errors.New("alert is longer than the configured maximum alert size")
 	ErrAlertTooShort             = errors.New("alert needs to be at least 16 bytes")
+	ErrAlertStreamTrailingData   = errors.New("alert stream has data after the alert")
 	ErrAlertMessageInvalidLength = errors.New("alert message is invalid - too short length")
+	ErrAlertNotVerified          = errors.New("alert signatures are not valid")
+	ErrAlertSignatureMalformed   = errors.New("alert signature is malformed")
+	ErrSupersedesNotEarlier      = errors.New("alert can only supersede an earlier sequence number")
+	ErrAlertTypeDisabled         = errors.New("alert type is disabled")
+	ErrCannotSuppressSetKeys     = errors.New("the set keys alert type can't be suppressed")
+	ErrAlertVersionZero          = errors.New("alert version 0 is malformed")
+	ErrAlertTypeUnknown          = errors.New("alert type is unknown to this node")
+	ErrAlertSequenceExists       = errors.New("an alert is already stored at this sequence")
+	ErrAlertSequenceGap          = errors.New("the alert before this sequence isn't stored")
+	ErrAlertTimestampInFuture    = errors.New("alert timestamp is too far in the future")
+	ErrAlertInvalid              = errors.New("alert failed validation")
+	ErrSequenceProcessed         = errors.New("alert sequence was already processed")
+	ErrUnknownAlertTypeName      = config.ErrUnknownAlertTypeName
+	ErrInvalidPageLimit          = errors.New("page limit is out of range")
+	ErrNegativePageOffset        = errors.New("page offset can't be negative")
+	ErrSequenceRangeInvalid      = errors.New("sequence range start is after its end")
+	ErrSequenceRangeTooLarge     = errors.New("sequence range is too large")
+	ErrAlertJSONTimestamp        = errors.New("alert JSON timestamp is not a valid RFC3339 time")
+	ErrAlertJSONHashMismatch     = errors.New("alert JSON body doesn't match its hash")
+	ErrAlertImportNoRaw          = errors.New("imported alert has no serialized alert")
+	ErrAlertImportMismatch       = errors.New("imported alert doesn't match its serialized alert")
+	ErrAlertHashMismatch         = errors.New("alert hash doesn't match its serialized alert")
+
+	// Signature errors
+	ErrInsufficientValidSignatures = errors.New("not enough valid signatures from distinct active keys")
+	ErrInvalidSignatureCount       = errors.New("alert signature count is out of range")
 
 	// AlertMessageBanPeer errors
-	ErrFailedToReadPeer   = errors.New("failed to read peer")
-	ErrFailedToReadReason = errors.New("failed to read reason")
+	ErrFailedToReadPeer        = errors.New("failed to read peer")
+	ErrFailedToReadReason      = errors.New("failed to read reason")
+	ErrFailedToReadBanDuration = errors.New("failed to read ban duration")
+	ErrInvalidPeerAddress      = errors.New("peer is not a valid IP, subnet or host:port")
 
 	// AlertMessageConfiscateUtxo errors
 	ErrConfiscationAlertTooShort = errors.New("confiscation alert is less than 9 bytes")
 	ErrTxHexLengthTooLong        = errors.New("tx hex length is longer than the remaining buffer")
 	ErrFailedToReadTxHex         = errors.New("failed to read tx hex")
 	ErrConfiscationAlertRPCError = errors.New("confiscation alert RPC response returned an error")
+	ErrEnforceAtHeightTooFarPast = errors.New("confiscation enforce at height is too far below the node height")
+	ErrEnforceAtHeightTooFarAway = errors.New("confiscation enforce at height is too far above the node height")
+	ErrConfiscationTxCount       = errors.New("confiscation alert needs exactly one transaction")
+	ErrNegativeEnforceAtHeight   = errors.New("enforce at height is negative")
+	ErrNoConfiscationTx          = errors.New("confiscation alert has no transaction")
 
 	// AlertMessageFreezeUtxo errors
 	ErrFreezeAlertTooShort        = errors.New("freeze alert is less than 57 bytes")
@@ -29,23 +71,37 @@ var (
 	ErrFailedToReadEnforceAtStart = errors.New("failed to read enforce at height start")
 	ErrFailedToReadEnforceAtEnd   = errors.New("failed to read enforce at height end")
 	ErrFreezeAlertRPCError        = errors.New("freeze alert RPC response returned an error")
+	ErrTooManyFunds               = errors.New("freeze alert has more funds than the node will be sent")
+	ErrEnforceAtHeightInverted    = errors.New("enforce at height start is after the end")
+	ErrDuplicateFundInAlert       = errors.New("fund is listed more than once")
+	ErrInvalidFundTxID            = errors.New("fund txid is not 32 bytes of hex")
+	ErrInvalidFundEnforceAtHeight = errors.New("fund needs exactly one enforce at height range")
+	ErrNegativeFundValue          = errors.New("fund vout or enforce at height is negative")
+
+	// Partial success errors
+	ErrPartialSuccess = errors.New("node only processed some of the funds")
 
 	// AlertMessageInformational errors
 	ErrInfoMessageLengthTooLong = errors.New("info message length is longer than buffer")
+	ErrInfoMessageTooLong       = errors.New("info message is longer than the maximum length")
 	ErrFailedToReadMessage      = errors.New("failed to read message")
 	ErrTooManyBytesInAlert      = errors.New("too many bytes in alert message")
+	ErrInfoMessageNotUTF8       = errors.New("info message is not valid UTF-8")
 
 	// AlertMessageInvalidateBlock errors
 	ErrInvalidateBlockTooShort      = errors.New("invalidate block alert is less than 32 bytes")
 	ErrFailedToReadBlockHash        = errors.New("failed to read block hash")
 	ErrNoReasonMessageProvided      = errors.New("no reason message provided")
 	ErrFailedToReadReasonInvalidate = errors.New("failed to read reason")
+	ErrNoBlockHash                  = errors.New("no block hash to invalidate")
 
 	// AlertMessageSetKeys errors
 	ErrSetKeysAlertInvalidLength = errors.New("alert is not 165 bytes long")
 	ErrFailedToReadPubKey        = errors.New("failed to read pubKey")
 	ErrInvalidPubKeyFormat       = errors.New("invalid public key format")
 	ErrSetKeysRPCError           = errors.New("set keys alert RPC response returned an error")
+	ErrDuplicatePubKey           = errors.New("public key is listed more than once")
+	ErrSetKeysUnchanged          = errors.New("set keys alert doesn't change the active public keys")
 
 	// AlertMessageUnbanPeer errors
 	ErrFailedToReadPeerUnban   = errors.New("failed to read peer")
@@ -56,6 +112,10 @@ var (
 	ErrUnfreezeAlertInvalidLength = errors.New("unfreeze alert is not a multiple of 57 bytes")
 	ErrUnfreezeAlertRPCError      = errors.New("unfreeze alert RPC response returned an error")
 
+	// AuditEntry errors
+	ErrAuditChainBroken     = errors.New("audit log hash chain is broken")
+	ErrAuditFlushIncomplete = errors.New("audit entries were still being written at shutdown")
+
 	// Overflow errors
 	ErrEnforceAtHeightOverflow = errors.New("enforce at height exceeds maximum value")
 	ErrValueExceedsMaxInt      = errors.New("value exceeds maximum int size")
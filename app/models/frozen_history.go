@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bsv-blockchain/go-bn/models"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// frozenUtxo is a UTXO frozen while replaying the alert history, with the alert that last froze it
+type frozenUtxo struct {
+	fund     models.Fund
+	index    int // Position of the fund in the alert
+	sequence uint32
+}
+
+// GetCurrentlyFrozenUtxos will replay the stored freeze and unfreeze alerts in sequence order, returning the UTXOs
+// still frozen with the enforce at heights of the alert that last froze them
+//
+// The set comes from the alert history alone, unlike the frozen outpoints it doesn't depend on what the node
+// accepted. Unverified alerts are not trusted, and an unfreeze of a UTXO that was never frozen is logged and skipped
+func GetCurrentlyFrozenUtxos(ctx context.Context, opts ...model.Options) ([]models.Fund, error) {
+	alerts, err := GetAllAlerts(ctx, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen := make(map[models.TxOut]frozenUtxo)
+	for _, alert := range alerts {
+		alertType := AlertType(alert.AlertType)
+		if alert.Unverified || (alertType != AlertTypeFreezeUtxo && alertType != AlertTypeUnfreezeUtxo) {
+			continue
+		}
+		alert.SetOptions(opts...)
+		if err = alert.ReadRaw(); err != nil {
+			alert.Config().Services.Log.Errorf("failed to read stored alert %d, skipping it: %s", alert.SequenceNumber, err.Error())
+			continue
+		}
+		var body AlertMessageInterface
+		if body, err = alert.ProcessAlertMessage(); err != nil {
+			alert.Config().Services.Log.Errorf("failed to read stored alert %d, skipping it: %s", alert.SequenceNumber, err.Error())
+			continue
+		}
+
+		switch b := body.(type) {
+		case *AlertMessageFreezeUtxo:
+			for i, fund := range b.Funds {
+				frozen[fund.TxOut] = frozenUtxo{fund: fund, index: i, sequence: alert.SequenceNumber}
+			}
+		case *AlertMessageUnfreezeUtxo:
+			for _, fund := range b.Funds {
+				if _, ok := frozen[fund.TxOut]; !ok {
+					alert.Config().Services.Log.Warnf("unfreeze alert %d references %s:%d which was never frozen, skipping it", alert.SequenceNumber, fund.TxOut.TxId, fund.TxOut.Vout)
+					continue
+				}
+				delete(frozen, fund.TxOut)
+			}
+		}
+	}
+
+	// In the order they were frozen
+	utxos := make([]frozenUtxo, 0, len(frozen))
+	for _, utxo := range frozen {
+		utxos = append(utxos, utxo)
+	}
+	sort.Slice(utxos, func(i, j int) bool {
+		if utxos[i].sequence != utxos[j].sequence {
+			return utxos[i].sequence < utxos[j].sequence
+		}
+		return utxos[i].index < utxos[j].index
+	})
+	funds := make([]models.Fund, 0, len(utxos))
+	for _, utxo := range utxos {
+		funds = append(funds, utxo.fund)
+	}
+	return funds, nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"context"
+
+	"github.com/bsv-blockchain/go-bn/models"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// saveFundsAlert will save a signed freeze or unfreeze alert for the funds
+func (ts *TestSuite) saveFundsAlert(sequence uint32, alertType AlertType, funds ...models.Fund) *AlertMessage {
+	message, err := serializeFunds(funds)
+	ts.Require().NoError(err)
+
+	a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+	a.SetAlertType(alertType)
+	a.SetRawMessage(message)
+	a.SequenceNumber = sequence
+	a.SetTimestamp(1700000000)
+	a.SetVersion(AlertVersionCurrent)
+	a.SerializeData()
+	sigs, err := utils.SignWithGenesis(a.GetRawData())
+	ts.Require().NoError(err)
+	a.SetSignatures(sigs)
+
+	parsed, err := NewAlertFromBytes(a.Serialize(), model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().NoError(parsed.Save(context.Background()))
+	return parsed
+}
+
+// TestGetCurrentlyFrozenUtxos will test the method GetCurrentlyFrozenUtxos()
+func (ts *TestSuite) TestGetCurrentlyFrozenUtxos() {
+	funds := testFunds(4)
+
+	// frozenUtxos will return the outpoints currently frozen
+	frozenUtxos := func() []models.TxOut {
+		frozen, err := GetCurrentlyFrozenUtxos(context.Background(), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		outpoints := make([]models.TxOut, 0, len(frozen))
+		for _, fund := range frozen {
+			outpoints = append(outpoints, fund.TxOut)
+		}
+		return outpoints
+	}
+
+	ts.Run("no alerts", func() {
+		ts.Empty(frozenUtxos())
+	})
+
+	ts.Run("freezes net of unfreezes", func() {
+		ts.saveFundsAlert(1, AlertTypeFreezeUtxo, funds[0], funds[1])
+		ts.saveFundsAlert(2, AlertTypeFreezeUtxo, funds[2])
+		ts.saveFundsAlert(3, AlertTypeUnfreezeUtxo, funds[1])
+		ts.Equal([]models.TxOut{funds[0].TxOut, funds[2].TxOut}, frozenUtxos())
+	})
+
+	ts.Run("refreezing takes the later enforce at heights", func() {
+		refrozen := funds[0]
+		refrozen.EnforceAtHeight = []models.Enforce{{Start: 300, Stop: 400}}
+		ts.saveFundsAlert(4, AlertTypeFreezeUtxo, refrozen)
+
+		frozen, err := GetCurrentlyFrozenUtxos(context.Background(), model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().Len(frozen, 2)
+		ts.Equal(funds[2].TxOut, frozen[0].TxOut)
+		ts.Equal(refrozen.TxOut, frozen[1].TxOut)
+		ts.Equal([]models.Enforce{{Start: 300, Stop: 400}}, frozen[1].EnforceAtHeight)
+	})
+
+	ts.Run("unfreeze of a utxo never frozen is skipped", func() {
+		ts.saveFundsAlert(5, AlertTypeUnfreezeUtxo, funds[3], funds[2])
+		ts.Equal([]models.TxOut{funds[0].TxOut}, frozenUtxos())
+	})
+
+	ts.Run("unverified alerts are not trusted", func() {
+		unverified := ts.saveFundsAlert(6, AlertTypeFreezeUtxo, funds[3])
+		unverified.Unverified = true
+		ts.Require().NoError(unverified.Save(context.Background()))
+		ts.Equal([]models.TxOut{funds[0].TxOut}, frozenUtxos())
+	})
+}
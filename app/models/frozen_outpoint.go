@@ -0,0 +1,302 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/mrz1836/go-datastore"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// FrozenOutpoint is an outpoint the node has confirmed as frozen
+type FrozenOutpoint struct {
+	// Base model
+	model.Model `bson:",inline"`
+
+	// Model specific fields
+	ID                         uint64 `json:"id" toml:"id" yaml:"id" bson:"_id" gorm:"primaryKey;comment:This is a unique identifier"`
+	TxID                       string `json:"tx_id" toml:"tx_id" yaml:"tx_id" bson:"tx_id" gorm:"<-;type:char(64);index;comment:This is the transaction id of the outpoint"`
+	Vout                       int    `json:"vout" toml:"vout" yaml:"vout" bson:"vout" gorm:"<-;type:int8;index;comment:This is the output index of the outpoint"`
+	EnforceAtHeightStart       int    `json:"enforce_at_height_start" toml:"enforce_at_height_start" yaml:"enforce_at_height_start" bson:"enforce_at_height_start" gorm:"<-;type:int8;comment:This is the height the freeze starts"`
+	EnforceAtHeightEnd         int    `json:"enforce_at_height_end" toml:"enforce_at_height_end" yaml:"enforce_at_height_end" bson:"enforce_at_height_end" gorm:"<-;type:int8;comment:This is the height the freeze ends"`
+	PolicyExpiresWithConsensus bool   `json:"policy_expires_with_consensus" toml:"policy_expires_with_consensus" yaml:"policy_expires_with_consensus" bson:"policy_expires_with_consensus" gorm:"<-;type:boolean;comment:This is if the policy freeze expires with the consensus freeze"`
+	SequenceNumber             uint32 `json:"sequence_number" toml:"sequence_number" yaml:"sequence_number" bson:"sequence_number" gorm:"<-;type:int8;index;comment:This is the sequence number of the alert that froze the outpoint"`
+}
+
+// RejectedFund is a fund the node refused to process
+type RejectedFund struct {
+	TxID   string `json:"tx_id"`
+	Vout   int    `json:"vout"`
+	Reason string `json:"reason"`
+}
+
+// NewFrozenOutpoint creates a new frozen outpoint
+func NewFrozenOutpoint(opts ...model.Options) *FrozenOutpoint {
+	return &FrozenOutpoint{
+		Model: *model.NewBaseModel(model.NameFrozenOutpoint, opts...),
+	}
+}
+
+// Name will get the name of the model
+func (m *FrozenOutpoint) Name() string {
+	return model.NameFrozenOutpoint.String()
+}
+
+// GetTableName will get the database table name of the model
+func (m *FrozenOutpoint) GetTableName() string {
+	return model.TableFrozenOutpoints
+}
+
+// GetID will get the model ID
+func (m *FrozenOutpoint) GetID() uint64 {
+	return m.ID
+}
+
+// Display filter the model for display
+func (m *FrozenOutpoint) Display() interface{} {
+	return m
+}
+
+// Migrate will run model-specific migrations on startup
+func (m *FrozenOutpoint) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(model.TableFrozenOutpoints), model.MetadataField)
+}
+
+// BeginSaveWithTx will start saving the model into the Datastore with the provided transaction
+func (m *FrozenOutpoint) BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction) ([]model.BaseInterface, error) {
+	return model.BeginSaveWithTx(ctx, tx, m)
+}
+
+// Save will save the model into the Datastore
+func (m *FrozenOutpoint) Save(ctx context.Context) error {
+	return model.Save(ctx, m)
+}
+
+// GetFrozenOutpoint will get the frozen outpoint, or nil if it's not frozen
+func GetFrozenOutpoint(ctx context.Context, txID string, vout int, opts ...model.Options) (*FrozenOutpoint, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldTxID: txID,
+		utils.FieldVout: vout,
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		Page:     1,
+		PageSize: 1,
+	}
+
+	// Get the record
+	modelItems := make([]*FrozenOutpoint, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameFrozenOutpoint, &modelItems, nil, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	} else if len(modelItems) == 0 {
+		return nil, nil
+	}
+
+	// The record may be updated by the caller, so it needs the dependencies to save
+	modelItems[0].SetOptions(opts...)
+	return modelItems[0], nil
+}
+
+// GetFrozenOutpoints will get all the frozen outpoints
+func GetFrozenOutpoints(ctx context.Context, metadata *model.Metadata, opts ...model.Options) ([]*FrozenOutpoint, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  utils.FieldID,
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the records
+	modelItems := make([]*FrozenOutpoint, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameFrozenOutpoint, &modelItems, metadata, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
+
+// saveFrozenOutpoints will store (or update) the funds as frozen by the given alert
+// An earlier freeze alert that no longer holds any outpoint is marked superseded by the given alert
+func saveFrozenOutpoints(ctx context.Context, funds []models.Fund, sequenceNumber uint32, opts ...model.Options) error {
+	replaced := make(map[uint32]struct{})
+	for _, fund := range funds {
+		frozen, err := GetFrozenOutpoint(ctx, fund.TxOut.TxId, fund.TxOut.Vout, opts...)
+		if err != nil {
+			return err
+		}
+		if frozen != nil && frozen.SequenceNumber != sequenceNumber {
+			replaced[frozen.SequenceNumber] = struct{}{}
+		}
+		if frozen == nil {
+			frozen = NewFrozenOutpoint(append(opts, model.New())...)
+			frozen.TxID = fund.TxOut.TxId
+			frozen.Vout = fund.TxOut.Vout
+		}
+		if len(fund.EnforceAtHeight) > 0 {
+			frozen.EnforceAtHeightStart = fund.EnforceAtHeight[0].Start
+			frozen.EnforceAtHeightEnd = fund.EnforceAtHeight[0].Stop
+		}
+		frozen.PolicyExpiresWithConsensus = fund.PolicyExpiresWithConsensus
+		frozen.SequenceNumber = sequenceNumber
+		if err = frozen.Save(ctx); err != nil {
+			return err
+		}
+	}
+	return markReplacedFreezes(ctx, replaced, sequenceNumber, opts...)
+}
+
+// removeFrozenOutpoints will remove the funds from the frozen outpoint store, unfrozen by the given alert
+// A freeze alert that no longer holds any outpoint is marked superseded by the given alert
+func removeFrozenOutpoints(ctx context.Context, funds []models.Fund, sequenceNumber uint32, opts ...model.Options) error {
+	replaced := make(map[uint32]struct{})
+	for _, fund := range funds {
+		frozen, err := GetFrozenOutpoint(ctx, fund.TxOut.TxId, fund.TxOut.Vout, opts...)
+		if err != nil {
+			return err
+		} else if frozen == nil {
+			continue
+		}
+		replaced[frozen.SequenceNumber] = struct{}{}
+		frozen.DeletedAt.Valid = true
+		frozen.DeletedAt.Time = time.Now().UTC()
+		if err = frozen.Save(ctx); err != nil {
+			return err
+		}
+	}
+	return markReplacedFreezes(ctx, replaced, sequenceNumber, opts...)
+}
+
+// markReplacedFreezes will mark the freeze alerts whose outpoints were all unfrozen or frozen again by a later alert
+// as superseded by it. A freeze alert still holding an outpoint is still enforced, so it's left alone
+func markReplacedFreezes(ctx context.Context, sequences map[uint32]struct{}, supersededBy uint32, opts ...model.Options) error {
+	for _, sequence := range slices.Sorted(maps.Keys(sequences)) {
+		if sequence == 0 {
+			continue
+		}
+		holding, err := hasFrozenOutpoints(ctx, sequence, opts...)
+		if err != nil {
+			return err
+		} else if holding {
+			continue
+		}
+		if err = markAlertSuperseded(ctx, sequence, supersededBy, opts...); errors.Is(err, ErrAlertNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasFrozenOutpoints will return true if any outpoint is still frozen by the alert with the sequence number
+func hasFrozenOutpoints(ctx context.Context, sequenceNumber uint32, opts ...model.Options) (bool, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldSequenceNumber: sequenceNumber,
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		Page:     1,
+		PageSize: 1,
+	}
+
+	// Get the record
+	modelItems := make([]*FrozenOutpoint, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameFrozenOutpoint, &modelItems, nil, conditions, queryParams, opts...,
+	); err != nil {
+		return false, err
+	}
+	return len(modelItems) > 0, nil
+}
+
+// splitProcessedFunds will split the funds into the ones the node processed and the ones it rejected
+func splitProcessedFunds(funds []models.Fund, res *models.AddToConsensusBlacklistResponse) ([]models.Fund, []RejectedFund) {
+	if res == nil || len(res.NotProcessed) == 0 {
+		return funds, nil
+	}
+
+	// Index the rejected outpoints
+	reasons := make(map[models.TxOut]string, len(res.NotProcessed))
+	for _, np := range res.NotProcessed {
+		reasons[models.TxOut{TxId: np.TxOut.TxId, Vout: np.TxOut.Vout}] = np.Reason
+	}
+
+	processed := make([]models.Fund, 0, len(funds))
+	rejected := make([]RejectedFund, 0, len(res.NotProcessed))
+	for _, fund := range funds {
+		if reason, ok := reasons[fund.TxOut]; ok {
+			rejected = append(rejected, RejectedFund{TxID: fund.TxOut.TxId, Vout: fund.TxOut.Vout, Reason: reason})
+			continue
+		}
+		processed = append(processed, fund)
+	}
+	return processed, rejected
+}
+
+// notFrozenReasons are the node's reasons for rejecting a fund that isn't frozen (matched case-insensitively)
+var notFrozenReasons = []string{"not frozen", "not blacklisted", "not in blacklist", "not on blacklist"}
+
+// splitNotFrozen will split the rejected funds into the ones the node rejected since they aren't frozen,
+// and the genuine rejections
+func splitNotFrozen(rejected []RejectedFund) ([]RejectedFund, []RejectedFund) {
+	var notFrozen, genuine []RejectedFund
+	for _, fund := range rejected {
+		reason := strings.ToLower(fund.Reason)
+		if slices.ContainsFunc(notFrozenReasons, func(r string) bool { return strings.Contains(reason, r) }) {
+			notFrozen = append(notFrozen, fund)
+			continue
+		}
+		genuine = append(genuine, fund)
+	}
+	return notFrozen, genuine
+}
+
+// PartialSuccessError is returned when the node processed some funds and rejected others
+type PartialSuccessError struct {
+	Cause     error          // The alert specific RPC error
+	Processed int            // Number of funds the node processed
+	Rejected  []RejectedFund // Funds the node rejected
+}
+
+// Error returns the error message listing the rejected outpoints
+func (e *PartialSuccessError) Error() string {
+	outpoints := make([]string, 0, len(e.Rejected))
+	for _, r := range e.Rejected {
+		outpoints = append(outpoints, fmt.Sprintf("%s:%d (%s)", r.TxID, r.Vout, r.Reason))
+	}
+	return fmt.Sprintf("%s: %s; processed %d, rejected %d: %s",
+		e.Cause, ErrPartialSuccess, e.Processed, len(e.Rejected), strings.Join(outpoints, ", "))
+}
+
+// Unwrap allows errors.Is to match both the alert specific RPC error and ErrPartialSuccess
+func (e *PartialSuccessError) Unwrap() []error {
+	return []error{e.Cause, ErrPartialSuccess}
+}
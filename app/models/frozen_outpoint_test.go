@@ -0,0 +1,288 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// testFunds returns funds with distinct outpoints for the frozen outpoint tests
+func testFunds(count int) []models.Fund {
+	funds := make([]models.Fund, 0, count)
+	for i := 0; i < count; i++ {
+		funds = append(funds, models.Fund{
+			TxOut:           models.TxOut{TxId: strings.Repeat(string(rune('a'+i)), 64), Vout: i},
+			EnforceAtHeight: []models.Enforce{{Start: 100, Stop: 200}},
+		})
+	}
+	return funds
+}
+
+// rejectingNode returns a mock node that rejects the given funds
+func rejectingNode(rejected ...models.Fund) *mocks.Node {
+	return rejectingNodeWithReason("already frozen", rejected...)
+}
+
+// rejectingNodeWithReason returns a mock node that rejects the given funds with the reason
+func rejectingNodeWithReason(reason string, rejected ...models.Fund) *mocks.Node {
+	return &mocks.Node{
+		AddToConsensusBlacklistFunc: func(_ context.Context, _ []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
+			notProcessed := make(models.AddToConsensusBlacklistNotProcessed, len(rejected))
+			for i, fund := range rejected {
+				notProcessed[i].TxOut.TxId = fund.TxOut.TxId
+				notProcessed[i].TxOut.Vout = fund.TxOut.Vout
+				notProcessed[i].Reason = reason
+			}
+			return &models.AddToConsensusBlacklistResponse{NotProcessed: notProcessed}, nil
+		},
+	}
+}
+
+// frozenOutpoints returns the outpoints in the frozen outpoint store
+func (ts *TestSuite) frozenOutpoints() []models.TxOut {
+	frozen, err := GetFrozenOutpoints(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	outpoints := make([]models.TxOut, 0, len(frozen))
+	for _, f := range frozen {
+		outpoints = append(outpoints, models.TxOut{TxId: f.TxID, Vout: f.Vout})
+	}
+	return outpoints
+}
+
+// TestAlertMessageFreezeUtxo_Do will test the method Do()
+func (ts *TestSuite) TestAlertMessageFreezeUtxo_Do() {
+	funds := testFunds(3)
+
+	ts.Run("all funds processed", func() {
+		ts.Dependencies.Services.Node = rejectingNode()
+		a := &AlertMessageFreezeUtxo{
+			AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()),
+			Funds:        funds,
+		}
+		a.SequenceNumber = 5
+		ts.Require().NoError(a.Do(context.Background()))
+		ts.Equal([]models.TxOut{funds[0].TxOut, funds[1].TxOut, funds[2].TxOut}, ts.frozenOutpoints())
+
+		frozen, err := GetFrozenOutpoint(context.Background(), funds[0].TxOut.TxId, funds[0].TxOut.Vout, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.Require().NotNil(frozen)
+		ts.Equal(uint32(5), frozen.SequenceNumber)
+		ts.Equal(100, frozen.EnforceAtHeightStart)
+		ts.Equal(200, frozen.EnforceAtHeightEnd)
+	})
+}
+
+// TestAlertMessageFreezeUtxo_DoPartialSuccess will test the method Do() when the node rejects some funds
+func (ts *TestSuite) TestAlertMessageFreezeUtxo_DoPartialSuccess() {
+	funds := testFunds(3)
+	ts.Dependencies.Services.Node = rejectingNode(funds[1])
+	a := &AlertMessageFreezeUtxo{
+		AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()),
+		Funds:        funds,
+	}
+
+	err := a.Do(context.Background())
+	ts.Require().Error(err)
+	ts.Require().ErrorIs(err, ErrPartialSuccess)
+	ts.Require().ErrorIs(err, ErrFreezeAlertRPCError)
+
+	var partial *PartialSuccessError
+	ts.Require().True(errors.As(err, &partial))
+	ts.Equal(2, partial.Processed)
+	ts.Equal([]RejectedFund{{TxID: funds[1].TxOut.TxId, Vout: 1, Reason: "already frozen"}}, partial.Rejected)
+	ts.Contains(err.Error(), funds[1].TxOut.TxId+":1")
+
+	// Only the successful freezes are stored
+	ts.Equal([]models.TxOut{funds[0].TxOut, funds[2].TxOut}, ts.frozenOutpoints())
+}
+
+// TestAlertMessageFreezeUtxo_DoMaxFunds will test the method Do() against the maximum funds per alert
+func (ts *TestSuite) TestAlertMessageFreezeUtxo_DoMaxFunds() {
+	// freeze will run a freeze alert with the given number of funds, returning how many the node was sent
+	freeze := func(count int) (int, error) {
+		sent := 0
+		ts.Dependencies.MaxFreezeFunds = 3
+		ts.Dependencies.Services.Node = &mocks.Node{
+			AddToConsensusBlacklistFunc: func(_ context.Context, funds []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
+				sent += len(funds)
+				return &models.AddToConsensusBlacklistResponse{}, nil
+			},
+		}
+		a := &AlertMessageFreezeUtxo{
+			AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()),
+			Funds:        testFunds(count),
+		}
+		return sent, a.Do(context.Background())
+	}
+
+	ts.Run("at the cap", func() {
+		sent, err := freeze(3)
+		ts.Require().NoError(err)
+		ts.Equal(3, sent)
+	})
+
+	ts.Run("above the cap", func() {
+		sent, err := freeze(4)
+		ts.Require().ErrorIs(err, ErrTooManyFunds)
+		ts.Equal(0, sent)
+	})
+}
+
+// TestAlertMessageUnfreezeUtxo_DoPartialSuccess will test the method Do() when the node rejects some funds
+func (ts *TestSuite) TestAlertMessageUnfreezeUtxo_DoPartialSuccess() {
+	funds := testFunds(3)
+
+	// Freeze everything first
+	ts.Dependencies.Services.Node = rejectingNode()
+	freeze := &AlertMessageFreezeUtxo{
+		AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()),
+		Funds:        funds,
+	}
+	ts.Require().NoError(freeze.Do(context.Background()))
+
+	// Unfreeze, with the node rejecting the first fund
+	ts.Dependencies.Services.Node = rejectingNode(funds[0])
+	unfreeze := &AlertMessageUnfreezeUtxo{
+		AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()),
+		Funds:        funds,
+	}
+	err := unfreeze.Do(context.Background())
+	ts.Require().ErrorIs(err, ErrPartialSuccess)
+	ts.Require().ErrorIs(err, ErrUnfreezeAlertRPCError)
+
+	// The rejected fund is still frozen
+	ts.Equal([]models.TxOut{funds[0].TxOut}, ts.frozenOutpoints())
+}
+
+// TestAlertMessageUnfreezeUtxo_DoNotFrozen will test the method Do() when the node rejects funds that aren't frozen
+func (ts *TestSuite) TestAlertMessageUnfreezeUtxo_DoNotFrozen() {
+	funds := testFunds(3)
+
+	// unfreeze will run an unfreeze alert for the funds, the node rejecting the given funds with the reason
+	unfreeze := func(reason string, rejected ...models.Fund) error {
+		ts.Dependencies.Services.Node = rejectingNodeWithReason(reason, rejected...)
+		a := &AlertMessageUnfreezeUtxo{
+			AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()),
+			Funds:        funds,
+		}
+		return a.Do(context.Background())
+	}
+
+	ts.Run("funds that aren't frozen are already unfrozen", func() {
+		ts.Dependencies.Services.Node = rejectingNode()
+		ts.Require().NoError(saveFrozenOutpoints(context.Background(), funds, 1, model.WithAllDependencies(ts.Dependencies)))
+
+		ts.Require().NoError(unfreeze("TxOut is Not Frozen", funds[0], funds[2]))
+		ts.Empty(ts.frozenOutpoints())
+	})
+
+	ts.Run("genuine rejections are still errors", func() {
+		ts.Require().NoError(saveFrozenOutpoints(context.Background(), funds, 2, model.WithAllDependencies(ts.Dependencies)))
+
+		err := unfreeze("invalid enforce at height", funds[1])
+		ts.Require().ErrorIs(err, ErrUnfreezeAlertRPCError)
+		ts.Require().ErrorIs(err, ErrPartialSuccess)
+		ts.Equal([]models.TxOut{funds[1].TxOut}, ts.frozenOutpoints())
+	})
+}
+
+// TestSplitNotFrozen will test the method splitNotFrozen()
+func TestSplitNotFrozen(t *testing.T) {
+	t.Parallel()
+
+	notFrozen, genuine := splitNotFrozen([]RejectedFund{
+		{TxID: "a", Reason: "TxOut not frozen"},
+		{TxID: "b", Reason: "invalid TxOut"},
+		{TxID: "c", Reason: "TXO NOT BLACKLISTED"},
+	})
+	assert.Equal(t, []RejectedFund{{TxID: "a", Reason: "TxOut not frozen"}, {TxID: "c", Reason: "TXO NOT BLACKLISTED"}}, notFrozen)
+	assert.Equal(t, []RejectedFund{{TxID: "b", Reason: "invalid TxOut"}}, genuine)
+}
+
+// TestAlertMessageUnfreezeUtxo_DoSupersedes will test marking the freeze alerts replaced by later alerts as superseded
+func (ts *TestSuite) TestAlertMessageUnfreezeUtxo_DoSupersedes() {
+	funds := testFunds(3)
+	ts.Dependencies.Services.Node = rejectingNode()
+
+	// store will store an alert with the sequence number, so it can be marked
+	store := func(sequence uint32, alertType AlertType) {
+		a := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		a.SequenceNumber = sequence
+		a.SetAlertType(alertType)
+		ts.Require().NoError(a.Save(context.Background()))
+	}
+
+	// do will store the alert, then run its action
+	do := func(sequence uint32, alertType AlertType, funds ...models.Fund) {
+		store(sequence, alertType)
+		var body AlertMessageInterface
+		if alertType == AlertTypeFreezeUtxo {
+			freeze := &AlertMessageFreezeUtxo{AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()), Funds: funds}
+			freeze.SequenceNumber = sequence
+			body = freeze
+		} else {
+			unfreeze := &AlertMessageUnfreezeUtxo{AlertMessage: *NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New()), Funds: funds}
+			unfreeze.SequenceNumber = sequence
+			body = unfreeze
+		}
+		ts.Require().NoError(body.Do(context.Background()))
+	}
+
+	// active will return the sequence numbers of the active alerts
+	active := func() []uint32 {
+		alerts, err := GetActiveAlerts(context.Background(), nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		sequences := make([]uint32, 0, len(alerts))
+		for _, alert := range alerts {
+			sequences = append(sequences, alert.SequenceNumber)
+		}
+		return sequences
+	}
+
+	// supersededBy will return whether the alert is superseded, and by which alert
+	supersededBy := func(sequence uint32) (bool, uint32) {
+		alert, err := GetAlertMessageBySequenceNumber(context.Background(), sequence, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		return alert.Superseded, alert.SupersededBy
+	}
+
+	do(10, AlertTypeFreezeUtxo, funds[0], funds[1])
+	do(11, AlertTypeFreezeUtxo, funds[2])
+	ts.Equal([]uint32{10, 11}, active())
+
+	ts.Run("partly unfrozen freeze is still active", func() {
+		do(12, AlertTypeUnfreezeUtxo, funds[0])
+		superseded, _ := supersededBy(10)
+		ts.False(superseded)
+		ts.Equal([]uint32{10, 11, 12}, active())
+	})
+
+	ts.Run("fully unfrozen freeze is superseded", func() {
+		do(13, AlertTypeUnfreezeUtxo, funds[1])
+		superseded, by := supersededBy(10)
+		ts.True(superseded)
+		ts.Equal(uint32(13), by)
+		ts.Equal([]uint32{11, 12, 13}, active())
+	})
+
+	ts.Run("freeze replaced by a later freeze is superseded", func() {
+		do(14, AlertTypeFreezeUtxo, funds[2])
+		superseded, by := supersededBy(11)
+		ts.True(superseded)
+		ts.Equal(uint32(14), by)
+		ts.Equal([]uint32{12, 13, 14}, active())
+	})
+
+	ts.Run("freeze alert that isn't stored is skipped", func() {
+		ts.Require().NoError(saveFrozenOutpoints(context.Background(), funds[:1], 20, model.WithAllDependencies(ts.Dependencies)))
+		do(21, AlertTypeUnfreezeUtxo, funds[0])
+		ts.Equal([]uint32{12, 13, 14, 21}, active())
+	})
+}
@@ -26,6 +26,7 @@ type Model struct {
 	// Private fields
 	debug        bool                   // Set from the parent config if debugging is turned on/off
 	dependencies *config.Config         // Application dependencies (app config, services, datastore, cachestore, etc)
+	limit        int                    // Most records a query returns (0 is no limit)
 	logger       config.LoggerInterface // Internal logging
 	name         Name                   // Name of a model (table name)
 	newRecord    bool                   // Determine if the record is new (create vs. update)
@@ -10,14 +10,20 @@ const (
 
 // All base models
 const (
-	NameAlertMessage Name = "alert_message" // AlertMessage is the alert message model
-	NameEmpty        Name = "empty"         // Empty model (base model without a name set)
-	NamePublicKey    Name = "public_key"    // PublicKey is the public key model
+	NameAlertMessage   Name = "alert_message"   // AlertMessage is the alert message model
+	NameAuditEntry     Name = "audit_entry"     // AuditEntry is the audit log entry model
+	NameDroppedAlert   Name = "dropped_alert"   // DroppedAlert is the dropped alert marker model
+	NameEmpty          Name = "empty"           // Empty model (base model without a name set)
+	NameFrozenOutpoint Name = "frozen_outpoint" // FrozenOutpoint is the frozen outpoint model
+	NamePublicKey      Name = "public_key"      // PublicKey is the public key model
 )
 
 // All base model table names
 const (
-	TableAlertMessages = "alert_messages" // TableAlertMessages is the alert message table
-	TableEmpty         = "empty"          // TableEmpty is the empty placeholder table
-	TablePublicKeys    = "public_keys"    // TablePublicKeys is the public key table
+	TableAlertMessages   = "alert_messages"   // TableAlertMessages is the alert message table
+	TableAuditEntries    = "audit_entries"    // TableAuditEntries is the audit log table
+	TableDroppedAlerts   = "dropped_alerts"   // TableDroppedAlerts is the dropped alert marker table
+	TableEmpty           = "empty"            // TableEmpty is the empty placeholder table
+	TableFrozenOutpoints = "frozen_outpoints" // TableFrozenOutpoints is the frozen outpoint table
+	TablePublicKeys      = "public_keys"      // TablePublicKeys is the public key table
 )
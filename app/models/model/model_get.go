@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/mrz1836/go-datastore"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
 )
 
 // Get will retrieve a model from the Datastore using the provided conditions
@@ -17,13 +19,23 @@ func Get(
 	forceWriteDB bool,
 ) error {
 	if timeout == 0 {
-		timeout = DefaultDatabaseReadTimeout
+		timeout = QueryTimeout(model.Config())
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	// Attempt to Get the model (by model fields and given conditions)
 	return model.Datastore().GetModel(ctx, model, conditions, timeout, forceWriteDB)
 }
 
+// QueryTimeout will return the configured datastore query timeout, or the default read timeout if there is none
+func QueryTimeout(c *config.Config) time.Duration {
+	if c == nil || c.Datastore.QueryTimeout <= 0 {
+		return DefaultDatabaseReadTimeout
+	}
+	return c.Datastore.QueryTimeout
+}
+
 // GetModels will retrieve model(s) from the Datastore using the provided conditions
 func GetModels(
 	ctx context.Context,
@@ -33,6 +45,9 @@ func GetModels(
 	queryParams *datastore.QueryParams,
 	timeout time.Duration,
 ) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Attempt to Get the model (by model fields and given conditions)
 	return datastore.GetModels(ctx, models, conditions, queryParams, nil, timeout)
 }
@@ -52,7 +67,6 @@ func GetModelsAggregate(
 }
 */
 
-/*
 // GetModelCount will retrieve a count of the model from the Datastore using the provided conditions
 func GetModelCount(
 	ctx context.Context,
@@ -61,10 +75,12 @@ func GetModelCount(
 	conditions map[string]interface{},
 	timeout time.Duration, //nolint:nolintlint,unparam // default timeout is passed most of the time
 ) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Attempt to Get the model (by model fields & given conditions)
 	return datastore.GetModelCount(ctx, model, conditions, timeout)
 }
-*/
 
 // GetModelsByConditions will get models by given conditions
 func GetModelsByConditions(ctx context.Context, modelName Name, modelItems interface{},
@@ -87,9 +103,10 @@ func GetModelsByConditions(ctx context.Context, modelName Name, modelItems inter
 	}
 
 	// Get the records
+	base := NewBaseModel(modelName, opts...)
 	if err := GetModels(
-		ctx, NewBaseModel(modelName, opts...).Datastore(),
-		modelItems, dbConditions, queryParams, DefaultDatabaseReadTimeout,
+		ctx, base.Datastore(),
+		modelItems, dbConditions, queryParams, QueryTimeout(base.Config()),
 	); err != nil {
 		if errors.Is(err, datastore.ErrNoResults) {
 			return nil
@@ -137,11 +154,10 @@ func getModelsAggregateByConditions(ctx context.Context, modelName Name, models
 }
 */
 
-/*
-// getModelCountByConditions will get model counts (sums) from given conditions
-func getModelCountByConditions(ctx context.Context, modelName Name, model interface{},
-	metadata *Metadata, conditions *map[string]interface{}, opts ...Options) (int64, error) {
-
+// GetModelCountByConditions will get the count of models matching the given conditions
+func GetModelCountByConditions(ctx context.Context, modelName Name, model interface{},
+	metadata *Metadata, conditions *map[string]interface{}, opts ...Options,
+) (int64, error) {
 	dbConditions := map[string]interface{}{}
 
 	if metadata != nil {
@@ -157,10 +173,11 @@ func getModelCountByConditions(ctx context.Context, modelName Name, model interf
 		dbConditions["$and"] = and
 	}
 
-	// Get the records
+	// Get the count
+	base := NewBaseModel(modelName, opts...)
 	count, err := GetModelCount(
-		ctx, NewBaseModel(modelName, opts...).Datastore(),
-		model, dbConditions, DefaultDatabaseReadTimeout,
+		ctx, base.Datastore(),
+		model, dbConditions, QueryTimeout(base.Config()),
 	)
 	if err != nil {
 		if errors.Is(err, datastore.ErrNoResults) {
@@ -171,4 +188,3 @@ func getModelCountByConditions(ctx context.Context, modelName Name, model interf
 
 	return count, nil
 }
-*/
@@ -123,6 +123,11 @@ func (m *Model) IsNew() bool {
 	return m.newRecord
 }
 
+// Limit will return the most records a query returns (0 is no limit)
+func (m *Model) Limit() int {
+	return m.limit
+}
+
 // Logger will return the Logger if it exists
 func (m *Model) Logger() config.LoggerInterface {
 	return m.logger
@@ -40,6 +40,13 @@ func WithDebug() Options {
 	}
 }
 
+// WithLimit will cap the number of records a query returns (0 is no limit)
+func WithLimit(limit int) Options {
+	return func(m *Model) {
+		m.limit = limit
+	}
+}
+
 // WithMetadata will add the metadata record to the model
 func WithMetadata(key string, value interface{}) Options {
 	return func(m *Model) {
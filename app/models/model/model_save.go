@@ -16,6 +16,10 @@ func Save(ctx context.Context, model BaseInterface) (err error) {
 		return ErrMissingDatastore
 	}
 
+	// A save that runs past the query timeout is abandoned (checked between the steps, SQL saves don't take a context)
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeout(model.Config()))
+	defer cancel()
+
 	// Create new Datastore transaction
 	// We need this to be in a callback context for Mongo
 	return ds.NewTx(ctx, func(tx *datastore.Transaction) (err error) {
@@ -62,6 +66,10 @@ func Save(ctx context.Context, model BaseInterface) (err error) {
 
 		// Save all models (or fail!)
 		for index := range modelsToSave {
+			if err = ctx.Err(); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
 			// modelsToSave[index].DebugLog(ctx, fmt.Sprintf("starting to save model: %s id: %d", modelsToSave[index].Name(), modelsToSave[index].GetID()))
 			if err = modelsToSave[index].Datastore().SaveModel(
 				ctx, modelsToSave[index], tx, modelsToSave[index].IsNew(), false,
@@ -71,6 +79,10 @@ func Save(ctx context.Context, model BaseInterface) (err error) {
 		}
 
 		// Commit all the model(s) if needed
+		if err = ctx.Err(); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
 		if tx.CanCommit() {
 			// model.DebugLog(ctx, "committing db transaction...")
 			if err = tx.Commit(); err != nil {
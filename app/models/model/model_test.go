@@ -2,8 +2,12 @@ package model
 
 import (
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
 )
 
 // TestName_IsEmpty will test the method IsEmpty()
@@ -25,3 +29,12 @@ func TestName_IsEmpty(t *testing.T) {
 		require.False(t, n.IsEmpty())
 	})
 }
+
+// TestQueryTimeout will test the method QueryTimeout()
+func TestQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultDatabaseReadTimeout, QueryTimeout(nil))
+	assert.Equal(t, DefaultDatabaseReadTimeout, QueryTimeout(&config.Config{}))
+	assert.Equal(t, 3*time.Second, QueryTimeout(&config.Config{Datastore: config.DatastoreConfig{QueryTimeout: 3 * time.Second}}))
+}
@@ -10,6 +10,21 @@ var BaseModels = []interface{}{
 		Model: *model.NewBaseModel(model.NameAlertMessage),
 	},
 
+	// AuditEntry - used for the audit log of applied alert actions
+	&AuditEntry{
+		Model: *model.NewBaseModel(model.NameAuditEntry),
+	},
+
+	// DroppedAlert - used for the alerts dropped on receipt because their type is disabled
+	&DroppedAlert{
+		Model: *model.NewBaseModel(model.NameDroppedAlert),
+	},
+
+	// FrozenOutpoint - used for outpoints the node has frozen
+	&FrozenOutpoint{
+		Model: *model.NewBaseModel(model.NameFrozenOutpoint),
+	},
+
 	// PublicKey - used for public keys
 	&PublicKey{
 		Model: *model.NewBaseModel(model.NamePublicKey),
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
+// defaultNodeUnavailablePolicies are the policies for alert types that don't need the node
+// Any alert type not listed here is left pending while the node is unavailable
+var defaultNodeUnavailablePolicies = map[AlertType]string{
+	AlertTypeInformational: config.NodeUnavailableProcess,
+	AlertTypeSetKeys:       config.NodeUnavailableProcess,
+	AlertTypeSpecial:       config.NodeUnavailableProcess,
+}
+
+// nodeUnavailablePolicy returns what to do with an alert of the given type when the node is unavailable
+// The config overrides are keyed by alert type name or number
+func nodeUnavailablePolicy(c *config.Config, alertType AlertType) string {
+	for name, policy := range c.NodeUnavailablePolicies {
+		if t, err := ParseAlertType(name); err == nil && t == alertType {
+			return policy
+		}
+	}
+	if policy, ok := defaultNodeUnavailablePolicies[alertType]; ok {
+		return policy
+	}
+	return config.NodeUnavailableDefer
+}
+
+// needsNode returns true if the action of the alert type makes node RPC calls
+func needsNode(alertType AlertType) bool {
+	_, ok := defaultNodeUnavailablePolicies[alertType]
+	return !ok
+}
+
+// checkNodeAvailable will return ErrNodeUnavailable if the alert needs the node and the node can't be reached
+func checkNodeAvailable(ctx context.Context, alert *AlertMessage) error {
+	c := alert.Config()
+	if nodeUnavailablePolicy(c, alert.GetAlertType()) == config.NodeUnavailableProcess {
+		return nil
+	}
+
+	// The cached height doubles as a cheap availability check
+	var err error
+	if c.Services.NodeHeight != nil {
+		_, err = c.Services.NodeHeight.Height(ctx)
+	} else {
+		_, err = c.Services.Node.BlockCount(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: leaving alert %d pending: %s", ErrNodeUnavailable, alert.SequenceNumber, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/config/mocks"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// TestNodeUnavailablePolicy will test the method nodeUnavailablePolicy()
+func TestNodeUnavailablePolicy(t *testing.T) {
+	c := &config.Config{}
+	assert.Equal(t, config.NodeUnavailableProcess, nodeUnavailablePolicy(c, AlertTypeInformational))
+	assert.Equal(t, config.NodeUnavailableProcess, nodeUnavailablePolicy(c, AlertTypeSetKeys))
+	assert.Equal(t, config.NodeUnavailableDefer, nodeUnavailablePolicy(c, AlertTypeFreezeUtxo))
+	assert.Equal(t, config.NodeUnavailableDefer, nodeUnavailablePolicy(c, AlertTypeConfiscateUtxo))
+	assert.Equal(t, config.NodeUnavailableDefer, nodeUnavailablePolicy(c, AlertType(250)))
+
+	// Overrides from the config
+	c.NodeUnavailablePolicies = map[string]string{
+		"1": config.NodeUnavailableDefer,
+		"5": config.NodeUnavailableProcess,
+	}
+	assert.Equal(t, config.NodeUnavailableDefer, nodeUnavailablePolicy(c, AlertTypeInformational))
+	assert.Equal(t, config.NodeUnavailableProcess, nodeUnavailablePolicy(c, AlertTypeBanPeer))
+
+	// Overrides by type name
+	c.NodeUnavailablePolicies = map[string]string{
+		"freeze_utxo": config.NodeUnavailableProcess,
+		"special":     config.NodeUnavailableDefer,
+	}
+	assert.Equal(t, config.NodeUnavailableProcess, nodeUnavailablePolicy(c, AlertTypeFreezeUtxo))
+	assert.Equal(t, config.NodeUnavailableDefer, nodeUnavailablePolicy(c, AlertTypeSpecial))
+	assert.Equal(t, config.NodeUnavailableDefer, nodeUnavailablePolicy(c, AlertTypeConfiscateUtxo))
+}
+
+// TestExecuteAlertAction_NodeUnavailable will test the method ExecuteAlertAction() with the node down
+func (ts *TestSuite) TestExecuteAlertAction_NodeUnavailable() {
+	blacklisted := false
+	ts.Dependencies.Services.Node = &mocks.Node{
+		BlockCountFunc: func(_ context.Context) (uint32, error) {
+			return 0, errors.New("connection refused")
+		},
+		AddToConsensusBlacklistFunc: func(_ context.Context, _ []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
+			blacklisted = true
+			return &models.AddToConsensusBlacklistResponse{}, nil
+		},
+	}
+	ts.Dependencies.Services.NodeHeight = config.NewNodeHeightCache(ts.Dependencies.Services.Node, config.DefaultNodeHeightCacheTTL)
+
+	ts.Run("informational alerts complete", func() {
+		info, action := ts.newTestAuditAlert(1, AlertTypeInformational, []byte{0x02, 'h', 'i'})
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), info, action, AuditSourceRetry))
+	})
+
+	ts.Run("freeze alerts stay pending", func() {
+		freeze, action := ts.newTestAuditAlert(2, AlertTypeFreezeUtxo, make([]byte, 57))
+		ts.Require().ErrorIs(ExecuteAlertAction(context.Background(), freeze, action, AuditSourceRetry), ErrNodeUnavailable)
+		ts.False(blacklisted)
+
+		// Nothing was executed, so nothing is audited
+		entries, err := GetAuditEntries(context.Background(), &AuditFilter{}, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		for _, entry := range entries {
+			ts.NotEqual(uint32(AlertTypeFreezeUtxo), entry.AlertType)
+		}
+	})
+
+	ts.Run("freeze alerts complete once the node is back", func() {
+		ts.Dependencies.Services.Node.(*mocks.Node).BlockCountFunc = nil
+		freeze, action := ts.newTestAuditAlert(2, AlertTypeFreezeUtxo, make([]byte, 57))
+		ts.Require().NoError(ExecuteAlertAction(context.Background(), freeze, action, AuditSourceRetry))
+		ts.True(blacklisted)
+	})
+}
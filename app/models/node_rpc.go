@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
+// callNode will make a node RPC call of the alert action, retrying the failures that may be transient
+//
+// Each attempt gets its own node RPC timeout. A call that failed to reach the node (connection error or timeout)
+// is retried up to the configured max retries, waiting the base backoff (doubled for each retry) in between.
+// An error returned by the node itself is a rejection and is never retried, and neither are funds or
+// transactions the node reports as not processed, since those come back in a successful response.
+//
+// Only idempotent calls may go through here: a call that timed out may still have been processed by the node,
+// so the retry sends it again. Adding the same funds to the consensus blacklist, or the same transaction to the
+// confiscation whitelist, with the same enforce heights leaves the node in the same state.
+func (m *AlertMessage) callNode(ctx context.Context, call func(ctx context.Context) error) error {
+	maxRetries, backoff := config.DefaultNodeRPCMaxRetries, config.DefaultNodeRPCBaseBackoff
+	if c := m.Config(); c != nil {
+		if c.NodeRPCMaxRetries > 0 {
+			maxRetries = c.NodeRPCMaxRetries
+		}
+		if c.NodeRPCBaseBackoff > 0 {
+			backoff = c.NodeRPCBaseBackoff
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		rpcCtx, cancel := m.nodeContext(ctx)
+		err := call(rpcCtx)
+		cancel()
+		if err == nil {
+			return nil
+		} else if ctx.Err() != nil || !isTransientNodeError(err) {
+			return m.wrapTimeout(err)
+		} else if attempt > maxRetries {
+			return fmt.Errorf("%w after %d attempts: %w", ErrNodeRPCRetriesExhausted, attempt, m.wrapTimeout(err))
+		}
+
+		if c := m.Config(); c != nil && c.Services.Log != nil {
+			c.Services.Log.Warnf("node RPC for %s alert %d failed (attempt %d), retrying: %s", m.GetAlertType(), m.SequenceNumber, attempt, err.Error())
+		}
+
+		// Wait before the next attempt, unless the context is done first
+		timer := time.NewTimer(backoff << (attempt - 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return m.wrapTimeout(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// isTransientNodeError returns true if the node RPC call failed to reach the node or timed out
+// Errors returned by the node (a JSON-RPC error response) are rejections and aren't transient
+func isTransientNodeError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr)
+}
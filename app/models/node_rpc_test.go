@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// newNodeRPCTestAlert will return an alert configured for fast node RPC retries
+func newNodeRPCTestAlert() *AlertMessage {
+	return NewAlertMessage(model.WithAllDependencies(&config.Config{
+		NodeRPCBaseBackoff: time.Millisecond,
+		NodeRPCMaxRetries:  2,
+		NodeRPCTimeout:     time.Second,
+	}))
+}
+
+// TestAlertMessage_CallNode will test the method callNode()
+func TestAlertMessage_CallNode(t *testing.T) {
+	t.Parallel()
+
+	refused := &url.Error{Op: "Post", URL: "http://localhost:8332", Err: syscall.ECONNREFUSED}
+
+	t.Run("transient failures are retried", func(t *testing.T) {
+		calls := 0
+		err := newNodeRPCTestAlert().callNode(context.Background(), func(_ context.Context) error {
+			if calls++; calls < 3 {
+				return refused
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("retries are exhausted", func(t *testing.T) {
+		calls := 0
+		err := newNodeRPCTestAlert().callNode(context.Background(), func(_ context.Context) error {
+			calls++
+			return refused
+		})
+		require.ErrorIs(t, err, ErrNodeRPCRetriesExhausted)
+		require.ErrorIs(t, err, syscall.ECONNREFUSED)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("node rejections are not retried", func(t *testing.T) {
+		calls := 0
+		rejection := &models.Error{Code: -8, Message: "invalid parameter"}
+		err := newNodeRPCTestAlert().callNode(context.Background(), func(_ context.Context) error {
+			calls++
+			return rejection
+		})
+		require.ErrorIs(t, err, rejection)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("canceled context stops the retries", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := newNodeRPCTestAlert().callNode(ctx, func(_ context.Context) error {
+			calls++
+			cancel()
+			return refused
+		})
+		require.ErrorIs(t, err, syscall.ECONNREFUSED)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// TestIsTransientNodeError will test the method isTransientNodeError()
+func TestIsTransientNodeError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{name: "connection refused", err: &url.Error{Op: "Post", Err: syscall.ECONNREFUSED}, transient: true},
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), transient: true},
+		{name: "timeout", err: context.DeadlineExceeded, transient: true},
+		{name: "node rejection", err: &models.Error{Code: -8, Message: "invalid parameter"}},
+		{name: "other error", err: errors.New("unexpected")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.transient, isTransientNodeError(tt.err))
+		})
+	}
+}
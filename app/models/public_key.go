@@ -2,8 +2,11 @@ package models
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/mrz1836/go-datastore"
+	customTypes "github.com/mrz1836/go-datastore/custom_types"
 
 	"github.com/bsv-blockchain/go-alert-system/app/config"
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
@@ -20,6 +23,10 @@ type PublicKey struct {
 	Key            string `json:"key" toml:"key" yaml:"key" bson:"key" gorm:"<-;type:char(66);index;comment:This is the key"`
 	LastUpdateHash string `json:"last_update_hash" toml:"last_update_hash" yaml:"last_update_hash" bson:"last_update_hash" gorm:"<-;type:char(64);index;comment:This is the last update hash"`
 	Active         bool   `json:"active" toml:"active" yaml:"active" bson:"active" gorm:"<-;type:boolean;index;comment:This is the active flag"`
+
+	// RevokedAt is when a set keys alert replaced the key, it's kept to verify historical alerts (null if active,
+	// or if it was revoked before the time was recorded)
+	RevokedAt customTypes.NullTime `json:"revoked_at" toml:"revoked_at" yaml:"revoked_at" bson:"revoked_at,omitempty" gorm:"<-;index;comment:This is when the key was revoked"`
 }
 
 // NewPublicKey creates a new public key
@@ -64,6 +71,18 @@ func (m *PublicKey) Save(ctx context.Context) error {
 	return model.Save(ctx, m)
 }
 
+// AfterCreated will fire after the model is created in the Datastore, the cached active keys are out of date
+func (m *PublicKey) AfterCreated(ctx context.Context) error {
+	activePublicKeys.invalidate()
+	return m.Model.AfterCreated(ctx)
+}
+
+// AfterUpdated will fire after a successful update into the Datastore, the cached active keys are out of date
+func (m *PublicKey) AfterUpdated(ctx context.Context) error {
+	activePublicKeys.invalidate()
+	return m.Model.AfterUpdated(ctx)
+}
+
 // GetActivePublicKey will get the active public key
 func GetActivePublicKey(ctx context.Context, metadata *model.Metadata, opts ...model.Options) ([]*PublicKey, error) {
 	// Set the conditions
@@ -93,17 +112,56 @@ func GetActivePublicKey(ctx context.Context, metadata *model.Metadata, opts ...m
 	return modelItems, nil
 }
 
-// ClearActivePublicKeys will clear the active public keys
+// GetPublicKeyHistory will get every public key, active and revoked, in the order they were added
+func GetPublicKeyHistory(ctx context.Context, metadata *model.Metadata, opts ...model.Options) ([]*PublicKey, error) {
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  utils.FieldID,
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the records
+	modelItems := make([]*PublicKey, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NamePublicKey, &modelItems, metadata, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
+
+// revoke will mark the key as no longer active, keeping it for the history
+func (m *PublicKey) revoke(at time.Time) {
+	m.Active = false
+	m.RevokedAt = customTypes.NullTime{NullTime: sql.NullTime{Time: at, Valid: true}}
+}
+
+// activate will mark the key as active, clearing any earlier revocation
+func (m *PublicKey) activate() {
+	m.Active = true
+	m.RevokedAt = customTypes.NullTime{}
+}
+
+// ClearActivePublicKeys will revoke the active public keys
 // todo this needs to be refactored to use model update/save
 func ClearActivePublicKeys(_ context.Context, ds datastore.ClientInterface) error {
 	// Execute the query
 	tx := ds.Execute("").Exec(
-		"UPDATE "+config.DatabasePrefix+"_"+model.TablePublicKeys+" SET "+utils.FieldActive+" = ?",
-		false,
+		"UPDATE "+config.DatabasePrefix+"_"+model.TablePublicKeys+" SET "+utils.FieldActive+" = ?, "+utils.FieldRevokedAt+" = ? WHERE "+utils.FieldActive+" = ?",
+		false, time.Now().UTC(), true,
 	).Begin()
 
 	// Commit the transaction
 	tx.Commit()
+	activePublicKeys.invalidate()
 
 	// Return the error
 	return tx.Error
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mrz1836/go-datastore"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// activePublicKeys caches the active public keys, so verifying an alert doesn't query the datastore each time
+var activePublicKeys = &publicKeyCache{}
+
+// publicKeyCache is the active public key set, loaded from one datastore
+type publicKeyCache struct {
+	sync.RWMutex
+	changes    atomic.Uint64             // Bumped whenever a public key is saved or cleared
+	datastore  datastore.ClientInterface // The datastore the keys were loaded from
+	generation uint64                    // The changes count when the keys were loaded
+	keys       []*PublicKey
+	loaded     bool
+}
+
+// GetActivePublicKeys will get the active public keys, from the cache unless they changed since they were loaded
+func GetActivePublicKeys(ctx context.Context, opts ...model.Options) ([]*PublicKey, error) {
+	cfg := NewPublicKey(opts...).Config()
+	if cfg == nil || cfg.Services.Datastore == nil {
+		return GetActivePublicKey(ctx, nil, opts...)
+	}
+	ds := cfg.Services.Datastore
+
+	activePublicKeys.RLock()
+	keys, ok := activePublicKeys.cached(ds)
+	activePublicKeys.RUnlock()
+	if ok {
+		return keys, nil
+	}
+
+	// Only one caller loads the keys, the others wait and use them
+	activePublicKeys.Lock()
+	defer activePublicKeys.Unlock()
+	if keys, ok = activePublicKeys.cached(ds); ok {
+		return keys, nil
+	}
+	generation := activePublicKeys.changes.Load()
+	keys, err := GetActivePublicKey(ctx, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	activePublicKeys.datastore = ds
+	activePublicKeys.generation = generation
+	activePublicKeys.keys = keys
+	activePublicKeys.loaded = true
+	return slices.Clone(keys), nil
+}
+
+// cached returns a copy of the cached keys, if they were loaded from the datastore and haven't changed since
+func (c *publicKeyCache) cached(ds datastore.ClientInterface) ([]*PublicKey, bool) {
+	if !c.loaded || c.datastore != ds || c.generation != c.changes.Load() {
+		return nil, false
+	}
+	return slices.Clone(c.keys), true
+}
+
+// invalidate will make the next GetActivePublicKeys load the keys again
+func (c *publicKeyCache) invalidate() {
+	c.changes.Add(1)
+}
+
+// swap will run the change of the key set while no keys are read from the cache or loaded,
+// so a verification never sees part of the old set and part of the new one
+func (c *publicKeyCache) swap(change func() error) error {
+	c.Lock()
+	defer c.Unlock()
+	defer c.invalidate()
+	return change()
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// TestPublicKey_GetActivePublicKeys will test the cached active public keys
+func (ts *TestSuite) TestPublicKey_GetActivePublicKeys() {
+	ctx := context.Background()
+	opts := model.WithAllDependencies(ts.Dependencies)
+
+	key := NewPublicKey(opts, model.New())
+	key.Key = testPublicKey
+	key.Active = true
+	ts.Require().NoError(key.Save(ctx))
+
+	ts.Run("loaded and then cached", func() {
+		keys, err := GetActivePublicKeys(ctx, opts)
+		ts.Require().NoError(err)
+		ts.Require().Len(keys, 1)
+		ts.Equal(testPublicKey, keys[0].Key)
+
+		// A change the cache doesn't know about isn't seen
+		tx := ts.Dependencies.Services.Datastore.Execute("").Exec(
+			"UPDATE "+config.DatabasePrefix+"_"+model.TablePublicKeys+" SET "+utils.FieldActive+" = ?", false,
+		).Begin()
+		tx.Commit()
+		ts.Require().NoError(tx.Error)
+
+		keys, err = GetActivePublicKeys(ctx, opts)
+		ts.Require().NoError(err)
+		ts.Len(keys, 1)
+	})
+
+	ts.Run("saving a key loads them again", func() {
+		other := NewPublicKey(opts, model.New())
+		other.Key = testPublicKey + "2"
+		other.Active = true
+		ts.Require().NoError(other.Save(ctx))
+
+		keys, err := GetActivePublicKeys(ctx, opts)
+		ts.Require().NoError(err)
+		ts.Require().Len(keys, 1)
+		ts.Equal(testPublicKey+"2", keys[0].Key)
+	})
+
+	ts.Run("a set keys alert swaps the set", func() {
+		alert := &AlertMessageSetKeys{
+			AlertMessage: *NewAlertMessage(opts),
+			Keys:         vectorPublicKeys(utils.MainKey1, utils.MainKey2, utils.MainKey3, utils.MainKey4, utils.MainKey5),
+		}
+		ts.Require().NoError(alert.Do(ctx))
+
+		keys, err := GetActivePublicKeys(ctx, opts)
+		ts.Require().NoError(err)
+		ts.Require().Len(keys, SetKeysCount)
+		for i, k := range alert.Keys {
+			ts.Equal(hex.EncodeToString(k[:]), keys[i].Key)
+		}
+	})
+
+	ts.Run("the cached keys can't be changed by the caller", func() {
+		keys, err := GetActivePublicKeys(ctx, opts)
+		ts.Require().NoError(err)
+		keys[0] = nil
+
+		keys, err = GetActivePublicKeys(ctx, opts)
+		ts.Require().NoError(err)
+		ts.NotNil(keys[0])
+	})
+}
@@ -93,3 +93,37 @@ func (ts *TestSuite) TestPublicKey_GetActiveKeys_None() {
 	ts.Require().NotNil(keys)
 	ts.Require().Empty(keys)
 }
+
+// TestPublicKey_GetPublicKeyHistory will test that revoked keys are kept with the time they were revoked
+func (ts *TestSuite) TestPublicKey_GetPublicKeyHistory() {
+	ctx := context.Background()
+	opts := model.WithAllDependencies(ts.Dependencies)
+
+	// Save an active key, then revoke it
+	key := NewPublicKey(opts, model.New())
+	key.Key = testPublicKey
+	key.Active = true
+	ts.Require().NoError(key.Save(ctx))
+	ts.Require().NoError(ClearActivePublicKeys(ctx, ts.Dependencies.Services.Datastore))
+
+	// And a new active key
+	other := NewPublicKey(opts, model.New())
+	other.Key = testPublicKey + "2"
+	other.Active = true
+	ts.Require().NoError(other.Save(ctx))
+
+	active, err := GetActivePublicKeys(ctx, opts)
+	ts.Require().NoError(err)
+	ts.Require().Len(active, 1)
+	ts.Equal(testPublicKey+"2", active[0].Key)
+
+	history, err := GetPublicKeyHistory(ctx, nil, opts)
+	ts.Require().NoError(err)
+	ts.Require().Len(history, 2)
+	ts.Equal(testPublicKey, history[0].Key)
+	ts.False(history[0].Active)
+	ts.True(history[0].RevokedAt.Valid)
+	ts.Equal(testPublicKey+"2", history[1].Key)
+	ts.True(history[1].Active)
+	ts.False(history[1].RevokedAt.Valid)
+}
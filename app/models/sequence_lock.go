@@ -0,0 +1,43 @@
+package models
+
+import "sync"
+
+// sequenceLocks serializes the processing of each alert sequence across the gossip, sync and retry paths
+var sequenceLocks = &sequenceLockSet{locks: map[uint32]*sequenceLock{}}
+
+// sequenceLockSet is the set of locks of the sequences being processed
+type sequenceLockSet struct {
+	sync.Mutex
+	locks map[uint32]*sequenceLock
+}
+
+// sequenceLock is the lock of a sequence, removed from the set once nobody holds or waits for it
+type sequenceLock struct {
+	sync.Mutex
+	refs int
+}
+
+// LockSequence will lock the alert sequence until the returned unlock is called
+//
+// An alert delivered by several peers at once (gossip and sync) is checked, executed and saved while
+// its sequence is locked, so only the first delivery runs the action and the others see it processed
+func LockSequence(sequence uint32) (unlock func()) {
+	sequenceLocks.Lock()
+	l, ok := sequenceLocks.locks[sequence]
+	if !ok {
+		l = &sequenceLock{}
+		sequenceLocks.locks[sequence] = l
+	}
+	l.refs++
+	sequenceLocks.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		sequenceLocks.Lock()
+		defer sequenceLocks.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(sequenceLocks.locks, sequence)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLockSequence will test the method LockSequence()
+func TestLockSequence(t *testing.T) {
+	t.Run("the same sequence is serialized", func(t *testing.T) {
+		unlock := LockSequence(1000)
+		locked := make(chan struct{})
+		go func() {
+			defer LockSequence(1000)()
+			close(locked)
+		}()
+
+		select {
+		case <-locked:
+			require.Fail(t, "second lock of the sequence was not blocked")
+		case <-time.After(20 * time.Millisecond):
+		}
+		unlock()
+		<-locked
+	})
+
+	t.Run("other sequences are not blocked", func(t *testing.T) {
+		defer LockSequence(1001)()
+		LockSequence(1002)()
+	})
+
+	t.Run("released locks are removed", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				LockSequence(1003)()
+			}()
+		}
+		wg.Wait()
+
+		sequenceLocks.Lock()
+		defer sequenceLocks.Unlock()
+		assert.NotContains(t, sequenceLocks.locks, uint32(1003))
+	})
+}
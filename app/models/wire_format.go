@@ -0,0 +1,145 @@
+package models
+
+// Alert wire format
+//
+// An alert is a fixed header, the type specific message, then the signature block:
+//
+//	version(4) | sequence(4) | timestamp(8) | type(4) | message(n) | signatures(195)
+//
+// The header and message are the signed data, and the alert hash is the double hash of that data
+//
+// From version 2 the header is followed by the sequence of an earlier alert this one supersedes (0 for none):
+//
+//	version(4) | sequence(4) | timestamp(8) | type(4) | supersedes(4) | message(n) | signatures(195)
+//
+// From version 3 the signature block moves to straight after the fixed header and is prefixed with a varint count,
+// so an alert can carry as many signatures as the M-of-N threshold needs. The signed data is the same as version 2:
+//
+//	version(4) | sequence(4) | timestamp(8) | type(4) | count(varint) | signatures(count*65) | supersedes(4) | message(n)
+//
+// The special alert (type 99) has a shorter legacy signature block, a single signature followed by padding that is
+// written as zeros and ignored when read. It's accepted with one valid signature from an active key. From version 3 it uses the counted block like any other type:
+//
+//	version(4) | sequence(4) | timestamp(8) | type(4) | message(n) | signature(65) | padding(63)
+//
+// Version 0 is malformed. A version newer than this node knows keeps the fixed header, but everything between the
+// header and the signatures is treated as an opaque message: the alert is stored and relayed, but never executed.
+// The signatures of a future version are found with the version 3 layout
+
+// Alert header layout
+const (
+	AlertVersionSize   = 4                                                                         // Alert version (uint32)
+	AlertSequenceSize  = 4                                                                         // Sequence number (uint32)
+	AlertTimestampSize = 8                                                                         // Unix timestamp (uint64)
+	AlertTypeSize      = 4                                                                         // Alert type (uint32)
+	AlertHeaderSize    = AlertVersionSize + AlertSequenceSize + AlertTimestampSize + AlertTypeSize // 20 bytes
+
+	AlertSupersedesSize        = 4 // Superseded sequence number (uint32), version 2 and later
+	AlertVersionSupersedes     = 2 // First alert version carrying the superseded sequence number
+	AlertVersionSignatureCount = 3 // First alert version with the counted signature block after the header
+	AlertVersionCurrent        = 3 // Newest alert version this node understands
+
+	alertSequenceOffset  = AlertVersionSize
+	alertTimestampOffset = alertSequenceOffset + AlertSequenceSize
+	alertTypeOffset      = alertTimestampOffset + AlertTimestampSize
+)
+
+// Signature block layout
+const (
+	SignatureSize             = 65                             // Compact recoverable signature
+	SignatureCount            = 3                              // Signatures on a standard alert
+	SignatureBlockSize        = SignatureCount * SignatureSize // 195 bytes
+	SignatureQuorum           = SignatureCount                 // Valid signatures from distinct active keys needed when no threshold is configured
+	MaxSignatureCount         = 16                             // Most signatures a counted signature block may carry
+	AlertType99SignatureBlock = 128                            // Alert type 99 has a shorter signature block (one full signature)
+	SpecialSignatureCount     = 1                              // Signatures on a special alert (type 99)
+	MinAlertMessageSize       = 2                              // Smallest message the parser accepts
+)
+
+// Alert message layouts
+const (
+	TxIDSize            = 32 // Transaction id
+	BlockHashSize       = 32 // Block hash (invalidate block)
+	PublicKeySize       = 33 // Compressed public key (set keys)
+	SetKeysCount        = 5  // Number of keys in a set keys alert
+	EnforceAtHeightSize = 8  // Block height (uint64)
+	VoutSize            = 8  // Output index (uint64)
+	PolicyFlagSize      = 1  // Policy expires with consensus flag
+	BanDurationSize     = 8  // Ban duration in seconds (uint64), optional after the ban peer reason
+
+	SetKeysMessageSize  = SetKeysCount * PublicKeySize                                 // 165 bytes
+	FundSize            = TxIDSize + VoutSize + 2*EnforceAtHeightSize + PolicyFlagSize // 57 bytes
+	MinConfiscationSize = EnforceAtHeightSize + 1                                      // Enforce height and at least a varint
+
+	fundVoutOffset   = TxIDSize
+	fundStartOffset  = fundVoutOffset + VoutSize
+	fundEndOffset    = fundStartOffset + EnforceAtHeightSize
+	fundPolicyOffset = fundEndOffset + EnforceAtHeightSize
+)
+
+// alertParsers maps each known alert type to its message, unknown types have no entry
+var alertParsers = map[AlertType]func(m *AlertMessage) AlertMessageInterface{
+	AlertTypeInformational: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageInformational{AlertMessage: *m}
+	},
+	AlertTypeFreezeUtxo: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageFreezeUtxo{AlertMessage: *m}
+	},
+	AlertTypeUnfreezeUtxo: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageUnfreezeUtxo{AlertMessage: *m}
+	},
+	AlertTypeConfiscateUtxo: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageConfiscateTransaction{AlertMessage: *m}
+	},
+	AlertTypeBanPeer: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageBanPeer{AlertMessage: *m}
+	},
+	AlertTypeUnbanPeer: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageUnbanPeer{AlertMessage: *m}
+	},
+	AlertTypeInvalidateBlock: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageInvalidateBlock{AlertMessage: *m}
+	},
+	AlertTypeSetKeys: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageSetKeys{AlertMessage: *m, Hash: m.Hash}
+	},
+	AlertTypeSpecial: func(m *AlertMessage) AlertMessageInterface {
+		return &AlertMessageSpecial{AlertMessage: *m}
+	},
+}
+
+// headerSize returns the size of the alert header for the alert version
+func headerSize(version uint32) int {
+	if hasSupersedes(version) {
+		return AlertHeaderSize + AlertSupersedesSize
+	}
+	return AlertHeaderSize
+}
+
+// hasSupersedes returns true if the alert version carries the superseded sequence number
+// A future version's header is unknown past the fixed fields, so it's left in the opaque message
+func hasSupersedes(version uint32) bool {
+	return version >= AlertVersionSupersedes && version <= AlertVersionCurrent
+}
+
+// hasSignatureCount returns true if the alert version has the counted signature block after the header
+// Future versions keep this layout, so their signatures can still be found
+func hasSignatureCount(version uint32) bool {
+	return version >= AlertVersionSignatureCount
+}
+
+// signatureBlockSize returns the size of the legacy signature block for the alert type
+func signatureBlockSize(alertType AlertType) int {
+	if alertType == AlertTypeSpecial {
+		return AlertType99SignatureBlock
+	}
+	return SignatureBlockSize
+}
+
+// signaturePadding returns the number of zero bytes after the signatures in the legacy signature block of the alert type
+func signaturePadding(alertType AlertType, signatures int) int {
+	if alertType != AlertTypeSpecial {
+		return 0
+	}
+	return max(AlertType99SignatureBlock-signatures*SignatureSize, 0)
+}
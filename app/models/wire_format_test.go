@@ -0,0 +1,291 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bitcoinsv/bsvd/bsvec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// TestWireFormat_Constants will test the documented wire-format sizes
+func TestWireFormat_Constants(t *testing.T) {
+	assert.Equal(t, 20, AlertHeaderSize)
+	assert.Equal(t, 195, SignatureBlockSize)
+	assert.Equal(t, 165, SetKeysMessageSize)
+	assert.Equal(t, 57, FundSize)
+	assert.Equal(t, FundSize, fundPolicyOffset+PolicyFlagSize)
+	assert.Equal(t, AlertType99SignatureBlock, signatureBlockSize(AlertTypeSpecial))
+	assert.Equal(t, SignatureBlockSize, signatureBlockSize(AlertTypeFreezeUtxo))
+}
+
+// TestWireFormat_AlertRoundTrip will test that a signed alert parses back using the header and signature sizes
+func TestWireFormat_AlertRoundTrip(t *testing.T) {
+	a := NewAlertMessage()
+	a.SetVersion(1)
+	a.SetTimestamp(1700000000)
+	a.SetAlertType(AlertTypeInformational)
+	a.SetRawMessage([]byte("wire format"))
+	a.SequenceNumber = 7
+	a.SerializeData()
+	require.Len(t, a.GetRawData(), AlertHeaderSize+len("wire format"))
+
+	sigs, err := utils.SignWithGenesis(a.GetRawData())
+	require.NoError(t, err)
+	require.Len(t, sigs, SignatureCount)
+	for _, sig := range sigs {
+		require.Len(t, sig, SignatureSize)
+	}
+	a.SetSignatures(sigs)
+	raw := a.Serialize()
+	require.Len(t, raw, AlertHeaderSize+len("wire format")+SignatureBlockSize)
+
+	parsed, err := NewAlertFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), parsed.Version())
+	assert.Equal(t, uint32(7), parsed.SequenceNumber)
+	assert.Equal(t, uint64(1700000000), parsed.Timestamp())
+	assert.Equal(t, AlertTypeInformational, parsed.GetAlertType())
+	assert.Equal(t, []byte("wire format"), parsed.GetRawMessage())
+	assert.Equal(t, a.signatures, parsed.signatures)
+	assert.Equal(t, a.Hash, parsed.Hash)
+
+	// A message one byte short of the minimum is rejected
+	_, err = NewAlertFromBytes(raw[:AlertHeaderSize+MinAlertMessageSize+SignatureBlockSize-1])
+	require.ErrorIs(t, err, ErrAlertMessageInvalidLength)
+}
+
+// TestWireFormat_CountedSignatures will test the counted signature block of version 3 alerts
+func TestWireFormat_CountedSignatures(t *testing.T) {
+	a := NewAlertMessage()
+	a.SetVersion(AlertVersionSignatureCount)
+	a.SetTimestamp(1700000000)
+	a.SetAlertType(AlertTypeInformational)
+	a.SetRawMessage([]byte("wire format"))
+	a.SetSupersedes(2)
+	a.SequenceNumber = 7
+	a.SerializeData()
+	require.Len(t, a.GetRawData(), AlertHeaderSize+AlertSupersedesSize+len("wire format"))
+
+	sigs, err := utils.SignWithKeys(a.GetRawData(), []string{utils.Key1, utils.Key2, utils.Key3, utils.Key4})
+	require.NoError(t, err)
+	a.SetSignatures(sigs)
+	raw := a.Serialize()
+	require.Len(t, raw, AlertHeaderSize+1+4*SignatureSize+AlertSupersedesSize+len("wire format"))
+	assert.Equal(t, byte(4), raw[AlertHeaderSize])
+
+	parsed, err := NewAlertFromBytes(raw)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(AlertVersionSignatureCount), parsed.Version())
+	assert.Equal(t, uint32(2), parsed.Supersedes)
+	assert.Equal(t, []byte("wire format"), parsed.GetRawMessage())
+	assert.Equal(t, a.GetRawData(), parsed.GetRawData())
+	assert.Equal(t, a.signatures, parsed.signatures)
+	assert.Equal(t, a.Hash, parsed.Hash)
+	assert.Equal(t, raw, parsed.Serialize())
+
+	t.Run("no signatures", func(t *testing.T) {
+		bad := slices.Concat(raw[:AlertHeaderSize], []byte{0x00}, raw[AlertHeaderSize+1+4*SignatureSize:])
+		_, err = NewAlertFromBytes(bad)
+		require.ErrorIs(t, err, ErrInvalidSignatureCount)
+	})
+
+	t.Run("too many signatures", func(t *testing.T) {
+		bad := slices.Clone(raw)
+		bad[AlertHeaderSize] = MaxSignatureCount + 1
+		_, err = NewAlertFromBytes(bad)
+		require.ErrorIs(t, err, ErrInvalidSignatureCount)
+	})
+
+	t.Run("fewer signatures than counted", func(t *testing.T) {
+		_, err = NewAlertFromBytes(raw[:AlertHeaderSize+1+3*SignatureSize])
+		require.ErrorIs(t, err, ErrAlertTooShort)
+	})
+}
+
+// TestWireFormat_FundRoundTrip will test that a serialized fund is FundSize bytes and reads back
+func TestWireFormat_FundRoundTrip(t *testing.T) {
+	txID, err := hex.DecodeString(strings.Repeat("ab", TxIDSize))
+	require.NoError(t, err)
+	fund := Fund{
+		TransactionOutID:           [32]byte(txID),
+		Vout:                       3,
+		EnforceAtHeightStart:       100,
+		EnforceAtHeightEnd:         200,
+		PolicyExpiresWithConsensus: true,
+	}
+	raw := fund.Serialize()
+	require.Len(t, raw, FundSize)
+
+	// Two funds back to back
+	a := &AlertMessageFreezeUtxo{}
+	require.NoError(t, a.Read(append(bytes.Clone(raw), raw...)))
+	require.Len(t, a.Funds, 2)
+	for _, f := range a.Funds {
+		assert.Equal(t, hex.EncodeToString(txID), f.TxOut.TxId)
+		assert.Equal(t, 3, f.TxOut.Vout)
+		assert.Equal(t, 100, f.EnforceAtHeight[0].Start)
+		assert.Equal(t, 200, f.EnforceAtHeight[0].Stop)
+		assert.True(t, f.PolicyExpiresWithConsensus)
+	}
+
+	// A trailing partial fund is rejected
+	require.ErrorIs(t, a.Read(append(bytes.Clone(raw), raw[:FundSize-1]...)), ErrFreezeAlertInvalidLength)
+}
+
+// TestWireFormat_SetKeysRoundTrip will test that a set keys message is SetKeysCount keys of PublicKeySize
+func TestWireFormat_SetKeysRoundTrip(t *testing.T) {
+	raw := make([]byte, 0, SetKeysMessageSize)
+	for i := 0; i < SetKeysCount; i++ {
+		raw = append(raw, bytes.Repeat([]byte{byte(i + 1)}, PublicKeySize)...)
+	}
+
+	a := &AlertMessageSetKeys{}
+	require.NoError(t, a.Read(raw))
+	require.Len(t, a.Keys, SetKeysCount)
+	for i, key := range a.Keys {
+		assert.Equal(t, bytes.Repeat([]byte{byte(i + 1)}, PublicKeySize), key[:])
+	}
+
+	require.ErrorIs(t, (&AlertMessageSetKeys{}).Read(raw[:SetKeysMessageSize-1]), ErrSetKeysAlertInvalidLength)
+}
+
+// TestWireFormat_ConfiscationHeight will test that the enforce height is read from the first EnforceAtHeightSize bytes
+func TestWireFormat_ConfiscationHeight(t *testing.T) {
+	raw := binary.LittleEndian.AppendUint64(nil, 12345)
+	require.Len(t, raw, EnforceAtHeightSize)
+	raw = append(raw, 0x00)
+
+	a := &AlertMessageConfiscateTransaction{}
+	require.NoError(t, a.Read(raw))
+	require.Len(t, a.Transactions, 1)
+	assert.Equal(t, int64(12345), a.Transactions[0].ConfiscationTransaction.EnforceAtHeight)
+}
+
+// TestAlertParsers will test that every known alert type has a parser
+func TestAlertParsers(t *testing.T) {
+	for alertType := AlertTypeInformational; alertType <= AlertTypeSetKeys; alertType++ {
+		a := NewAlertMessage()
+		a.SetAlertType(alertType)
+		assert.True(t, a.KnownType(), alertType.String())
+	}
+	assert.Contains(t, alertParsers, AlertTypeSpecial)
+	assert.Len(t, alertParsers, int(AlertTypeSetKeys)+1)
+
+	a := NewAlertMessage()
+	a.SetAlertType(AlertType(250))
+	assert.False(t, a.KnownType())
+	body, err := a.ProcessAlertMessage()
+	require.NoError(t, err)
+	assert.Nil(t, body)
+
+	// The body is read from the raw message, and set keys carries the alert hash
+	a.SetAlertType(AlertTypeSetKeys)
+	a.SetRawMessage(make([]byte, SetKeysMessageSize))
+	a.Hash = "hash"
+	body, err = a.ProcessAlertMessage()
+	require.NoError(t, err)
+	keys, ok := body.(*AlertMessageSetKeys)
+	require.True(t, ok)
+	assert.Equal(t, "hash", keys.Hash)
+	assert.Len(t, keys.Keys, SetKeysCount)
+
+	// A body that doesn't parse is an error
+	a.SetRawMessage(make([]byte, SetKeysMessageSize-1))
+	_, err = a.ProcessAlertMessage()
+	require.ErrorIs(t, err, ErrSetKeysAlertInvalidLength)
+}
+
+// TestAlertBody_Serialize will test the Serialize() methods of the alert bodies
+func TestAlertBody_Serialize(t *testing.T) {
+	t.Run("fund is always FundSize bytes", func(t *testing.T) {
+		a := &AlertMessageFreezeUtxo{Funds: testFunds(3)}
+		raw, err := a.Serialize()
+		require.NoError(t, err)
+		require.Len(t, raw, 3*FundSize)
+
+		u := &AlertMessageUnfreezeUtxo{Funds: testFunds(1)}
+		raw, err = u.Serialize()
+		require.NoError(t, err)
+		require.Len(t, raw, FundSize)
+	})
+
+	t.Run("fund that can't be written", func(t *testing.T) {
+		funds := testFunds(1)
+		funds[0].TxOut.TxId = "abcd"
+		_, err := (&AlertMessageFreezeUtxo{Funds: funds}).Serialize()
+		require.ErrorIs(t, err, ErrInvalidFundTxID)
+
+		funds = testFunds(1)
+		funds[0].TxOut.Vout = -1
+		_, err = (&AlertMessageFreezeUtxo{Funds: funds}).Serialize()
+		require.ErrorIs(t, err, ErrNegativeFundValue)
+
+		funds = testFunds(1)
+		funds[0].EnforceAtHeight = nil
+		_, err = (&AlertMessageUnfreezeUtxo{Funds: funds}).Serialize()
+		require.ErrorIs(t, err, ErrInvalidFundEnforceAtHeight)
+	})
+
+	t.Run("set keys is always SetKeysMessageSize bytes", func(t *testing.T) {
+		a := &AlertMessageSetKeys{Keys: make([][33]byte, SetKeysCount)}
+		raw, err := a.Serialize()
+		require.NoError(t, err)
+		require.Len(t, raw, SetKeysMessageSize)
+
+		a.Keys = a.Keys[1:]
+		_, err = a.Serialize()
+		require.ErrorIs(t, err, ErrSetKeysAlertInvalidLength)
+	})
+
+	t.Run("confiscation carries one transaction", func(t *testing.T) {
+		_, err := (&AlertMessageConfiscateTransaction{}).Serialize()
+		require.ErrorIs(t, err, ErrConfiscationTxCount)
+	})
+
+	t.Run("invalidate block needs a block hash", func(t *testing.T) {
+		_, err := (&AlertMessageInvalidateBlock{Reason: []byte("test")}).Serialize()
+		require.ErrorIs(t, err, ErrNoBlockHash)
+	})
+
+	t.Run("body built from fields reads back", func(t *testing.T) {
+		ban := &AlertMessageBanPeer{Peer: []byte("192.168.1.1:8333"), Reason: []byte("spam")}
+		raw, err := ban.Serialize()
+		require.NoError(t, err)
+
+		parsed := &AlertMessageBanPeer{}
+		require.NoError(t, parsed.Read(raw))
+		assert.Equal(t, ban.Peer, parsed.Peer)
+		assert.Equal(t, ban.Reason, parsed.Reason)
+		assert.Equal(t, uint64(len(ban.Peer)), parsed.PeerLength)
+	})
+}
+
+// TestAlertMessageSetKeys_Validate will test the method Validate()
+func TestAlertMessageSetKeys_Validate(t *testing.T) {
+	keys := make([][33]byte, 0, SetKeysCount)
+	for i := 0; i < SetKeysCount; i++ {
+		pk, err := bsvec.NewPrivateKey(bsvec.S256())
+		require.NoError(t, err)
+		keys = append(keys, [33]byte(pk.PubKey().SerializeCompressed()))
+	}
+	require.NoError(t, (&AlertMessageSetKeys{Keys: keys}).Validate(context.Background()))
+
+	// A key that isn't a point on the curve
+	invalid := append([][33]byte{}, keys...)
+	invalid[2] = [33]byte{0x02}
+	require.ErrorIs(t, (&AlertMessageSetKeys{Keys: invalid}).Validate(context.Background()), ErrInvalidPubKeyFormat)
+
+	// The same key twice
+	duplicate := append([][33]byte{}, keys...)
+	duplicate[4] = duplicate[0]
+	require.ErrorIs(t, (&AlertMessageSetKeys{Keys: duplicate}).Validate(context.Background()), ErrDuplicatePubKey)
+}
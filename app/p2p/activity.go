@@ -0,0 +1,38 @@
+package p2p
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// peerActivity tracks when we last received a message (sync or gossip) from each peer
+type peerActivity struct {
+	sync.Mutex
+	lastSeen map[peer.ID]time.Time
+	now      func() time.Time
+}
+
+// newPeerActivity will create a new peer activity tracker
+func newPeerActivity() *peerActivity {
+	return &peerActivity{
+		lastSeen: make(map[peer.ID]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Seen records that a message was just received from the peer
+func (a *peerActivity) Seen(peerID peer.ID) {
+	a.Lock()
+	defer a.Unlock()
+	a.lastSeen[peerID] = a.now()
+}
+
+// LastSeen returns when we last received a message from the peer, false if we never did
+func (a *peerActivity) LastSeen(peerID peer.ID) (time.Time, bool) {
+	a.Lock()
+	defer a.Unlock()
+	at, ok := a.lastSeen[peerID]
+	return at, ok
+}
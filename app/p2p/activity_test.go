@@ -0,0 +1,33 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeerActivity will test the methods Seen() and LastSeen()
+func TestPeerActivity(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := newPeerActivity()
+	a.now = func() time.Time { return now }
+
+	_, ok := a.LastSeen(peer.ID("peer-a"))
+	assert.False(t, ok)
+
+	a.Seen(peer.ID("peer-a"))
+	now = now.Add(time.Minute)
+	a.Seen(peer.ID("peer-a"))
+
+	at, ok := a.LastSeen(peer.ID("peer-a"))
+	require.True(t, ok)
+	assert.Equal(t, now, at)
+
+	_, ok = a.LastSeen(peer.ID("peer-b"))
+	assert.False(t, ok)
+}
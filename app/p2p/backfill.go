@@ -0,0 +1,99 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// syncBackfill requests the alerts missing below our latest sequence from the connected peers
+//
+// A sequence already in flight (requested by a sync, a retry or an earlier run) isn't requested again,
+// and at most maxPerRun requests are sent each run, spread over the peers, so a node that is far behind
+// doesn't flood them. Sequences dropped for a disabled alert type are gaps on purpose and are skipped
+type syncBackfill struct {
+	sync.Mutex
+	dropped     *droppedAlerts
+	find        func(ctx context.Context, from, to uint32) ([]uint32, error)
+	latest      func(ctx context.Context) (uint32, error)
+	log         config.LoggerInterface
+	maxPerRun   int
+	outstanding map[uint32]struct{} // Sequences requested by the backfill that weren't answered yet
+	peers       func() []peer.ID
+	requests    *requestTracker
+	send        func(ctx context.Context, peerID peer.ID, msg *SyncMessage) error
+}
+
+// Run will request the missing sequences that aren't in flight, returning the number of sequences still missing
+func (b *syncBackfill) Run(ctx context.Context) (int, error) {
+	latest, err := b.latest(ctx)
+	if err != nil || latest == 0 {
+		return 0, err
+	}
+
+	// Sequence 0 is the genesis alert, which is created locally, and only the most recent range is checked
+	from := uint32(1)
+	if latest >= models.MaxMissingSequenceRange {
+		from = latest - models.MaxMissingSequenceRange + 1
+	}
+	found, err := b.find(ctx, from, latest)
+	if err != nil {
+		return 0, err
+	}
+	missing := make([]uint32, 0, len(found))
+	for _, sequence := range found {
+		if b.dropped == nil || !b.dropped.Dropped(sequence) {
+			missing = append(missing, sequence)
+		}
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	b.prune()
+	peers := b.peers()
+	if len(missing) == 0 || len(peers) == 0 {
+		return len(missing), nil
+	}
+
+	sent := 0
+	for _, sequence := range missing {
+		if sent >= b.maxPerRun {
+			break
+		} else if b.requests.Requested(sequence) {
+			continue
+		}
+		target := peers[sent%len(peers)]
+		if err = b.send(ctx, target, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: sequence}); err != nil {
+			b.log.Debugf("failed to request missing sequence %d from peer %s: %s", sequence, target.String(), err.Error())
+			continue
+		}
+		b.outstanding[sequence] = struct{}{}
+		sent++
+	}
+	b.log.Infof("%d sequences missing, requested %d from peers", len(missing), sent)
+	return len(missing), nil
+}
+
+// Outstanding returns the number of backfill requests waiting on a response from a peer
+func (b *syncBackfill) Outstanding() int {
+	b.Lock()
+	defer b.Unlock()
+	b.prune()
+	return len(b.outstanding)
+}
+
+// prune will forget the backfill requests that were answered or abandoned (caller must hold the lock)
+func (b *syncBackfill) prune() {
+	if b.outstanding == nil {
+		b.outstanding = make(map[uint32]struct{})
+	}
+	for sequence := range b.outstanding {
+		if !b.requests.Requested(sequence) {
+			delete(b.outstanding, sequence)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package p2p
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// newTestBackfill will create a backfill over the stored sequences, where every peer ignores our requests
+func newTestBackfill(tracker *requestTracker, stored map[uint32]bool, latest uint32, peers []peer.ID) (*syncBackfill, *[]sentRequest) {
+	sent := make([]sentRequest, 0)
+	return &syncBackfill{
+		dropped: newDroppedAlerts(nil),
+		find: func(_ context.Context, from, to uint32) ([]uint32, error) {
+			missing := make([]uint32, 0)
+			for sequence := from; sequence <= to; sequence++ {
+				if !stored[sequence] {
+					missing = append(missing, sequence)
+				}
+			}
+			return missing, nil
+		},
+		latest: func(context.Context) (uint32, error) {
+			return latest, nil
+		},
+		log:       &config.ExtendedLogger{Logger: log.New(io.Discard, "", 0)},
+		maxPerRun: 2,
+		peers: func() []peer.ID {
+			return peers
+		},
+		requests: tracker,
+		send: func(_ context.Context, peerID peer.ID, msg *SyncMessage) error {
+			sent = append(sent, sentRequest{peer: peerID, msg: *msg})
+			return tracker.Track(peerID, msg)
+		},
+	}, &sent
+}
+
+// TestSyncBackfill_Run will test the method Run()
+func TestSyncBackfill_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requests the gaps a few at a time", func(t *testing.T) {
+		peerA, peerB := peer.ID("peer-a"), peer.ID("peer-b")
+		tracker := newRequestTracker(5, time.Minute)
+		stored := map[uint32]bool{1: true, 3: true, 6: true}
+		backfill, sent := newTestBackfill(tracker, stored, 6, []peer.ID{peerA, peerB})
+
+		// Sequence 4 was dropped for its disabled type, so it's not a gap
+		dropped := models.NewAlertMessage()
+		dropped.SequenceNumber = 4
+		backfill.dropped.Drop(context.Background(), dropped)
+
+		missing, err := backfill.Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, missing)
+		assert.Equal(t, []sentRequest{
+			{peer: peerA, msg: SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 2}},
+			{peer: peerB, msg: SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 5}},
+		}, *sent)
+		assert.Equal(t, 2, backfill.Outstanding())
+
+		// Requests in flight aren't sent again
+		_, err = backfill.Run(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, *sent, 2)
+
+		// An answered request is no longer outstanding
+		tracker.Complete(peerA, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 2})
+		stored[2] = true
+		assert.Equal(t, 1, backfill.Outstanding())
+
+		tracker.Complete(peerB, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 5})
+		stored[5] = true
+		missing, err = backfill.Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, missing)
+		assert.Equal(t, 0, backfill.Outstanding())
+		assert.Len(t, *sent, 2)
+	})
+
+	t.Run("sequences requested by a sync aren't requested again", func(t *testing.T) {
+		peerA := peer.ID("peer-a")
+		tracker := newRequestTracker(5, time.Minute)
+		require.NoError(t, tracker.Track(peerA, &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 1, EndSequence: 2}))
+		backfill, sent := newTestBackfill(tracker, map[uint32]bool{}, 3, []peer.ID{peerA})
+
+		missing, err := backfill.Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 3, missing)
+		assert.Equal(t, []sentRequest{{peer: peerA, msg: SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 3}}}, *sent)
+	})
+
+	t.Run("nothing stored or no peers", func(t *testing.T) {
+		backfill, sent := newTestBackfill(newRequestTracker(5, time.Minute), map[uint32]bool{}, 0, []peer.ID{"peer-a"})
+		missing, err := backfill.Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, missing)
+		assert.Empty(t, *sent)
+
+		backfill, sent = newTestBackfill(newRequestTracker(5, time.Minute), map[uint32]bool{}, 2, nil)
+		missing, err = backfill.Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, missing)
+		assert.Empty(t, *sent)
+	})
+}
@@ -0,0 +1,112 @@
+package p2p
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// dialBackoffJitter is the fraction of the backoff interval that is randomized, so peers that failed together
+// don't all get redialed at the same moment
+const dialBackoffJitter = 0.2
+
+// dialAttempts is the backoff state of a single peer address
+type dialAttempts struct {
+	failures    int
+	interval    time.Duration
+	nextAttempt time.Time
+}
+
+// DialBackoff is the backoff state of a peer address we failed to dial
+type DialBackoff struct {
+	Address         string    `json:"address"`
+	Failures        int       `json:"failures"`
+	IntervalSeconds float64   `json:"interval_seconds"`
+	NextAttempt     time.Time `json:"next_attempt"`
+}
+
+// dialBackoff tracks failed outbound dials per peer address
+// The wait before the next dial doubles with each failure (with jitter) up to the max, and is reset on success
+type dialBackoff struct {
+	sync.Mutex
+	initial time.Duration
+	jitter  func() float64
+	max     time.Duration
+	now     func() time.Time
+	peers   map[string]*dialAttempts
+}
+
+// newDialBackoff will create a new dial backoff tracker
+func newDialBackoff(initial, maxInterval time.Duration) *dialBackoff {
+	return &dialBackoff{
+		initial: initial,
+		jitter:  rand.Float64, //nolint:gosec // jitter doesn't need a secure source
+		max:     maxInterval,
+		now:     time.Now,
+		peers:   make(map[string]*dialAttempts),
+	}
+}
+
+// Ready returns true if the address can be dialed now
+func (b *dialBackoff) Ready(address string) bool {
+	b.Lock()
+	defer b.Unlock()
+	attempts, ok := b.peers[address]
+	return !ok || !b.now().Before(attempts.nextAttempt)
+}
+
+// Failure records a failed dial to the address and returns how long to wait before dialing it again
+func (b *dialBackoff) Failure(address string) time.Duration {
+	b.Lock()
+	defer b.Unlock()
+
+	attempts, ok := b.peers[address]
+	if !ok {
+		attempts = &dialAttempts{}
+		b.peers[address] = attempts
+	}
+	attempts.failures++
+
+	// Double the interval for each failure after the first, stopping at the max
+	interval := b.initial
+	for i := 1; i < attempts.failures && interval < b.max; i++ {
+		interval *= 2
+	}
+
+	// Spread the interval by +/- the jitter fraction, never going past the max
+	interval += time.Duration(float64(interval) * dialBackoffJitter * (2*b.jitter() - 1))
+	if interval > b.max {
+		interval = b.max
+	}
+
+	attempts.interval = interval
+	attempts.nextAttempt = b.now().Add(interval)
+	return interval
+}
+
+// Success resets the backoff of the address after a successful dial
+func (b *dialBackoff) Success(address string) {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.peers, address)
+}
+
+// State returns the backoff state of every address that is backing off, sorted by address
+func (b *dialBackoff) State() []DialBackoff {
+	b.Lock()
+	defer b.Unlock()
+	state := make([]DialBackoff, 0, len(b.peers))
+	for address, attempts := range b.peers {
+		state = append(state, DialBackoff{
+			Address:         address,
+			Failures:        attempts.failures,
+			IntervalSeconds: attempts.interval.Seconds(),
+			NextAttempt:     attempts.nextAttempt,
+		})
+	}
+	sort.Slice(state, func(i, j int) bool {
+		return state[i].Address < state[j].Address
+	})
+	return state
+}
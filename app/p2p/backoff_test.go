@@ -0,0 +1,98 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDialBackoff will create a dial backoff with a fake clock and the given jitter
+func newTestDialBackoff(initial, maxInterval time.Duration, jitter float64) (*dialBackoff, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newDialBackoff(initial, maxInterval)
+	b.now = func() time.Time {
+		return now
+	}
+	b.jitter = func() float64 {
+		return jitter
+	}
+	return b, &now
+}
+
+// TestDialBackoff_Failure will test the method Failure()
+func TestDialBackoff_Failure(t *testing.T) {
+	t.Parallel()
+
+	t.Run("doubles up to the max", func(t *testing.T) {
+		b, _ := newTestDialBackoff(time.Second, 10*time.Second, 0.5) // 0.5 is no jitter
+
+		var waits []time.Duration
+		for i := 0; i < 6; i++ {
+			waits = append(waits, b.Failure("peer-a"))
+		}
+		assert.Equal(t, []time.Duration{
+			time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second,
+		}, waits)
+	})
+
+	t.Run("jitter spreads the interval", func(t *testing.T) {
+		low, _ := newTestDialBackoff(10*time.Second, time.Minute, 0)
+		assert.Equal(t, 8*time.Second, low.Failure("peer-a"))
+
+		high, _ := newTestDialBackoff(10*time.Second, time.Minute, 1)
+		assert.Equal(t, 12*time.Second, high.Failure("peer-a"))
+	})
+
+	t.Run("jitter never goes past the max", func(t *testing.T) {
+		b, _ := newTestDialBackoff(10*time.Second, 10*time.Second, 1)
+		assert.Equal(t, 10*time.Second, b.Failure("peer-a"))
+	})
+}
+
+// TestDialBackoff_Ready will test the method Ready()
+func TestDialBackoff_Ready(t *testing.T) {
+	t.Parallel()
+
+	t.Run("waits out the interval and resets on success", func(t *testing.T) {
+		b, now := newTestDialBackoff(time.Second, time.Minute, 0.5)
+		assert.True(t, b.Ready("peer-a"))
+
+		b.Failure("peer-a")
+		b.Failure("peer-a")
+		assert.False(t, b.Ready("peer-a"))
+		assert.True(t, b.Ready("peer-b"))
+
+		*now = now.Add(time.Second)
+		assert.False(t, b.Ready("peer-a"))
+
+		*now = now.Add(time.Second)
+		assert.True(t, b.Ready("peer-a"))
+
+		// A successful dial starts over from the initial interval
+		b.Success("peer-a")
+		assert.True(t, b.Ready("peer-a"))
+		assert.Equal(t, time.Second, b.Failure("peer-a"))
+	})
+}
+
+// TestDialBackoff_State will test the method State()
+func TestDialBackoff_State(t *testing.T) {
+	t.Parallel()
+
+	b, now := newTestDialBackoff(time.Second, time.Minute, 0.5)
+	assert.Empty(t, b.State())
+
+	b.Failure("peer-b")
+	b.Failure("peer-a")
+	b.Failure("peer-a")
+
+	state := b.State()
+	require.Len(t, state, 2)
+	assert.Equal(t, DialBackoff{Address: "peer-a", Failures: 2, IntervalSeconds: 2, NextAttempt: now.Add(2 * time.Second)}, state[0])
+	assert.Equal(t, DialBackoff{Address: "peer-b", Failures: 1, IntervalSeconds: 1, NextAttempt: now.Add(time.Second)}, state[1])
+
+	b.Success("peer-a")
+	assert.Len(t, b.State(), 1)
+}
@@ -0,0 +1,45 @@
+package p2p
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// peerClocks tracks how far each peer's clock is from ours, as measured when a stream was opened
+type peerClocks struct {
+	sync.Mutex
+	skews map[peer.ID]time.Duration
+}
+
+// newPeerClocks will create a new peer clock tracker
+func newPeerClocks() *peerClocks {
+	return &peerClocks{
+		skews: make(map[peer.ID]time.Duration),
+	}
+}
+
+// Record records the skew of the peer's clock (positive when the peer is ahead of us)
+func (c *peerClocks) Record(peerID peer.ID, skew time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.skews[peerID] = skew
+}
+
+// Skew returns the last recorded skew of the peer's clock, false if it was never measured
+func (c *peerClocks) Skew(peerID peer.ID) (time.Duration, bool) {
+	c.Lock()
+	defer c.Unlock()
+	skew, ok := c.skews[peerID]
+	return skew, ok
+}
+
+// clockSkew returns the skew of the peer's clock against ours and whether it's within the max skew
+func clockSkew(peerTime, now time.Time, maxSkew time.Duration) (time.Duration, bool) {
+	skew := peerTime.Sub(now.Truncate(time.Second))
+	if skew < 0 {
+		return skew, -skew <= maxSkew
+	}
+	return skew, skew <= maxSkew
+}
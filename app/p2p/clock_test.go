@@ -0,0 +1,90 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStreamThread_ProcessGotTime will test the method ProcessGotTime()
+func TestStreamThread_ProcessGotTime(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	// newClockThread will create a stream thread with a fake clock
+	newClockThread := func(t *testing.T, disconnect bool) (*StreamThread, *fakeStream) {
+		deps := loadTestDependencies(t)
+		deps.P2P.MaxClockSkew = time.Minute
+		deps.P2P.DisconnectOnClockSkew = disconnect
+
+		stream := &fakeStream{}
+		return &StreamThread{
+			clocks: newPeerClocks(),
+			config: deps,
+			ctx:    context.Background(),
+			now:    func() time.Time { return now },
+			peer:   peer.ID("peer-a"),
+			stream: stream,
+		}, stream
+	}
+
+	t.Run("within skew is accepted and answered", func(t *testing.T) {
+		s, stream := newClockThread(t, true)
+
+		require.NoError(t, s.ProcessGotTime(newTimeMessage(now.Add(-30*time.Second))))
+		skew, ok := s.clocks.Skew(s.peer)
+		require.True(t, ok)
+		assert.Equal(t, -30*time.Second, skew)
+
+		// Our clock is sent back once
+		require.Len(t, stream.written, 1)
+		require.NoError(t, s.ProcessGotTime(newTimeMessage(now)))
+		assert.Len(t, stream.written, 1)
+	})
+
+	t.Run("beyond skew is warned about", func(t *testing.T) {
+		s, stream := newClockThread(t, false)
+
+		require.NoError(t, s.ProcessGotTime(newTimeMessage(now.Add(2*time.Hour))))
+		skew, ok := s.clocks.Skew(s.peer)
+		require.True(t, ok)
+		assert.Equal(t, 2*time.Hour, skew)
+		assert.Len(t, stream.written, 1)
+	})
+
+	t.Run("beyond skew is disconnected", func(t *testing.T) {
+		s, stream := newClockThread(t, true)
+
+		require.ErrorIs(t, s.ProcessGotTime(newTimeMessage(now.Add(-2*time.Hour))), ErrPeerClockSkew)
+		skew, ok := s.clocks.Skew(s.peer)
+		require.True(t, ok)
+		assert.Equal(t, -2*time.Hour, skew)
+		assert.Empty(t, stream.written)
+	})
+
+	t.Run("short message", func(t *testing.T) {
+		s, _ := newClockThread(t, true)
+
+		require.ErrorIs(t, s.ProcessGotTime(&SyncMessage{Type: IGotTime, Data: []byte{1, 2}}), ErrSyncTimeEightBytes)
+		_, ok := s.clocks.Skew(s.peer)
+		assert.False(t, ok)
+	})
+}
+
+// TestSyncMessage_Time will test the method Time()
+func TestSyncMessage_Time(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	msg, err := NewSyncMessageFromBytes(newTimeMessage(now).Serialize())
+	require.NoError(t, err)
+	assert.Equal(t, byte(IGotTime), msg.Type)
+
+	var got time.Time
+	got, err = msg.Time()
+	require.NoError(t, err)
+	assert.True(t, now.Equal(got))
+}
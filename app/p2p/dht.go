@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -48,6 +49,19 @@ func (s *Server) initDHT(ctx context.Context) (*dht.IpfsDHT, error) {
 		peers = append(peers, pubPeer)
 	}
 
+	// The static peers are dialed on startup like the bootstrap nodes (once each), then kept connected by RunStaticPeers
+	for _, info := range s.static.peers {
+		staticAddrs, addrErr := peer.AddrInfoToP2pAddrs(&info)
+		if addrErr != nil {
+			return nil, addrErr
+		}
+		for _, staticAddr := range staticAddrs {
+			if !slices.ContainsFunc(peers, staticAddr.Equal) {
+				peers = append(peers, staticAddr)
+			}
+		}
+	}
+
 	// Connect to the chosen ipfs nodes
 	connected := uint32(0)
 	for atomic.LoadUint32(&connected) == 0 {
@@ -61,6 +75,12 @@ func (s *Server) initDHT(ctx context.Context) (*dht.IpfsDHT, error) {
 				if peerInfo, err = peer.AddrInfoFromP2pAddr(peerAddr); err != nil {
 					return nil, err
 				}
+
+				// Don't redial a bootstrap peer that is still backing off
+				address := peerAddr.String()
+				if !s.backoff.Ready(address) {
+					continue
+				}
 				wg.Add(1)
 
 				// Create a local copy of peerInfo for the goroutine
@@ -68,9 +88,11 @@ func (s *Server) initDHT(ctx context.Context) (*dht.IpfsDHT, error) {
 				go func(logger config.LoggerInterface, peerInfo peer.AddrInfo) {
 					defer wg.Done()
 					if localErr := s.host.Connect(ctx, peerInfo); localErr != nil {
-						logger.Errorf("bootstrap warning: %s", localErr.Error())
+						wait := s.backoff.Failure(address)
+						logger.Errorf("bootstrap warning (retrying in %s): %s", wait.String(), localErr.Error())
 						return
 					}
+					s.backoff.Success(address)
 					logger.Infof("connected to peer %v", peerInfo.ID)
 					atomic.StoreUint32(&connected, 1)
 				}(logger, pi)
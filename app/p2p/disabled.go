@@ -0,0 +1,92 @@
+package p2p
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// droppedAlerts tracks the alerts dropped on receipt because their type is disabled
+// The sequence numbers are kept so the alert that follows one isn't rejected for a missing prior alert,
+// and stored (see models.DroppedAlert) so they still aren't gaps after a restart
+type droppedAlerts struct {
+	sync.Mutex
+	config    *config.Config // Without a config the dropped alerts are only kept in memory
+	counts    map[models.AlertType]uint64
+	sequences map[uint32]struct{}
+}
+
+// newDroppedAlerts will create a new dropped alert tracker
+func newDroppedAlerts(conf *config.Config) *droppedAlerts {
+	return &droppedAlerts{
+		config:    conf,
+		counts:    make(map[models.AlertType]uint64),
+		sequences: make(map[uint32]struct{}),
+	}
+}
+
+// Load reads the alerts dropped before a restart from the datastore
+// An alert of a type that is no longer disabled is left out, so its sequence is a gap again and is synced
+func (d *droppedAlerts) Load(ctx context.Context) error {
+	if d.config == nil {
+		return nil
+	}
+	dropped, err := models.GetDroppedAlerts(ctx, model.WithAllDependencies(d.config))
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	for _, a := range dropped {
+		if _, ok := d.sequences[a.SequenceNumber]; ok || !slices.Contains(d.config.DisabledAlertTypes, a.AlertType) {
+			continue
+		}
+		d.sequences[a.SequenceNumber] = struct{}{}
+		d.counts[models.AlertType(a.AlertType)]++
+	}
+	return nil
+}
+
+// Drop records an alert that was dropped
+func (d *droppedAlerts) Drop(ctx context.Context, a *models.AlertMessage) {
+	d.Lock()
+	if _, ok := d.sequences[a.SequenceNumber]; ok {
+		d.Unlock()
+		return // The same alert synced or gossiped again
+	}
+	d.sequences[a.SequenceNumber] = struct{}{}
+	d.counts[a.GetAlertType()]++
+	d.Unlock()
+
+	// A marker that fails to store only costs a request for the alert after a restart
+	if d.config == nil {
+		return
+	}
+	if err := models.RecordDroppedAlert(ctx, a, model.WithAllDependencies(d.config)); err != nil {
+		d.config.Services.Log.Errorf("failed to store dropped alert %d: %s", a.SequenceNumber, err.Error())
+	}
+}
+
+// Dropped returns true if the alert with the sequence number was dropped
+func (d *droppedAlerts) Dropped(sequenceNumber uint32) bool {
+	d.Lock()
+	defer d.Unlock()
+	_, ok := d.sequences[sequenceNumber]
+	return ok
+}
+
+// Counts returns the number of dropped alerts per alert type
+func (d *droppedAlerts) Counts() map[models.AlertType]uint64 {
+	d.Lock()
+	defer d.Unlock()
+	counts := make(map[models.AlertType]uint64, len(d.counts))
+	for alertType, count := range d.counts {
+		counts[alertType] = count
+	}
+	return counts
+}
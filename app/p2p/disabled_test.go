@@ -0,0 +1,187 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// TestStreamThread_ProcessGotSequenceNumber_DisabledType will test the method ProcessGotSequenceNumber()
+func TestStreamThread_ProcessGotSequenceNumber_DisabledType(t *testing.T) {
+	body := []byte{0x05, 'h', 'e', 'l', 'l', 'o'}
+
+	// syncAlert will sync a single informational alert, with more alerts left to sync after it
+	syncAlert := func(t *testing.T, deps *config.Config, dropped *droppedAlerts) *fakeStream {
+		stream := &fakeStream{}
+		s := &StreamThread{
+			config:         deps,
+			ctx:            context.Background(),
+			dropped:        dropped,
+			latestSequence: 2,
+			peer:           peer.ID("peer-a"),
+			stream:         stream,
+		}
+		require.NoError(t, s.ProcessGotSequenceNumber(&SyncMessage{
+			Type:           IGotSequenceNumber,
+			SequenceNumber: 1,
+			Data:           newSignedAlert(t, deps, 1, models.AlertTypeInformational, body),
+		}))
+		assert.Equal(t, uint32(1), s.myLatestSequence)
+		return stream
+	}
+
+	t.Run("disabled type is dropped entirely", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeInformational)}
+		dropped := newDroppedAlerts(deps)
+
+		// The sync moves on to the next sequence
+		stream := syncAlert(t, deps, dropped)
+		assert.Len(t, stream.written, 1)
+		assert.False(t, stream.closed)
+
+		// Never stored or executed, but counted
+		_, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+		require.ErrorIs(t, err, models.ErrAlertNotFound)
+		assert.Equal(t, 0, auditEntries(t, deps))
+		assert.True(t, dropped.Dropped(1))
+		assert.Equal(t, map[models.AlertType]uint64{models.AlertTypeInformational: 1}, dropped.Counts())
+	})
+
+	t.Run("enabled type flows normally", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeConfiscateUtxo)}
+		dropped := newDroppedAlerts(deps)
+
+		stream := syncAlert(t, deps, dropped)
+		assert.Len(t, stream.written, 1)
+
+		a, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+		require.NoError(t, err)
+		assert.True(t, a.Processed)
+		assert.Equal(t, 1, auditEntries(t, deps))
+		assert.False(t, dropped.Dropped(1))
+		assert.Empty(t, dropped.Counts())
+	})
+}
+
+// TestDroppedAlerts_Load will test the method Load()
+func TestDroppedAlerts_Load(t *testing.T) {
+	// drop will drop the informational alert at sequence 1
+	drop := func(t *testing.T, deps *config.Config) {
+		a, err := models.NewAlertFromBytes(
+			newSignedAlert(t, deps, 1, models.AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'}),
+			model.WithAllDependencies(deps),
+		)
+		require.NoError(t, err)
+		a.SerializeData()
+		newDroppedAlerts(deps).Drop(context.Background(), a)
+	}
+
+	// restart will return a new tracker with the dropped alerts loaded, as after a restart
+	restart := func(t *testing.T, deps *config.Config) *droppedAlerts {
+		d := newDroppedAlerts(deps)
+		require.NoError(t, d.Load(context.Background()))
+		return d
+	}
+
+	t.Run("dropped alerts survive a restart", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeInformational)}
+		drop(t, deps)
+
+		restarted := restart(t, deps)
+		assert.True(t, restarted.Dropped(1))
+		assert.Equal(t, map[models.AlertType]uint64{models.AlertTypeInformational: 1}, restarted.Counts())
+
+		// Backfill skips the sequence
+		backfill, sent := newTestBackfill(newRequestTracker(5, time.Minute), map[uint32]bool{0: true, 2: true}, 2, []peer.ID{"peer-a"})
+		backfill.dropped = restarted
+		missing, err := backfill.Run(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, missing)
+		assert.Empty(t, *sent)
+	})
+
+	t.Run("type enabled again is synced", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeInformational)}
+		drop(t, deps)
+
+		deps.DisabledAlertTypes = nil
+		restarted := restart(t, deps)
+		assert.False(t, restarted.Dropped(1))
+		assert.Empty(t, restarted.Counts())
+	})
+}
+
+// TestServer_ValidateAlertType will test the method validateAlertType()
+func TestServer_ValidateAlertType(t *testing.T) {
+	body := []byte{0x05, 'h', 'e', 'l', 'l', 'o'}
+
+	// gossip will wrap the alert in a pubsub message
+	gossip := func(data []byte) *pubsub.Message {
+		return &pubsub.Message{Message: &pb.Message{Data: data}, ReceivedFrom: peer.ID("peer-a")}
+	}
+
+	t.Run("disabled type is neither delivered nor relayed", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeInformational)}
+		s := &Server{config: deps, dropped: newDroppedAlerts(deps)}
+
+		assert.False(t, s.validateAlertType(context.Background(), peer.ID("peer-a"), gossip(newSignedAlert(t, deps, 1, models.AlertTypeInformational, body))))
+		assert.True(t, s.dropped.Dropped(1))
+	})
+
+	t.Run("disabled type is relayed when configured", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeInformational)}
+		deps.P2P.RelayDisabledAlerts = true
+		s := &Server{config: deps, dropped: newDroppedAlerts(deps)}
+
+		assert.True(t, s.validateAlertType(context.Background(), peer.ID("peer-a"), gossip(newSignedAlert(t, deps, 1, models.AlertTypeInformational, body))))
+		assert.True(t, s.dropped.Dropped(1), "relayed but never executed here")
+	})
+
+	t.Run("forged alert of a disabled type is never relayed", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeInformational)}
+		deps.P2P.RelayDisabledAlerts = true
+		s := &Server{config: deps, dropped: newDroppedAlerts(deps)}
+
+		data := newSignedAlert(t, deps, 1, models.AlertTypeInformational, body)
+		data[models.AlertHeaderSize+1] = 'j'
+		assert.False(t, s.validateAlertType(context.Background(), peer.ID("peer-a"), gossip(data)))
+		assert.False(t, s.dropped.Dropped(1))
+	})
+
+	t.Run("forged alert of a disabled type is not counted", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeInformational)}
+		s := &Server{config: deps, dropped: newDroppedAlerts(deps)}
+
+		data := newSignedAlert(t, deps, 1, models.AlertTypeInformational, body)
+		data[models.AlertHeaderSize+1] = 'j'
+		assert.False(t, s.validateAlertType(context.Background(), peer.ID("peer-a"), gossip(data)))
+		assert.False(t, s.dropped.Dropped(1))
+	})
+
+	t.Run("enabled type is accepted", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.DisabledAlertTypes = []uint32{uint32(models.AlertTypeConfiscateUtxo)}
+		s := &Server{config: deps, dropped: newDroppedAlerts(deps)}
+
+		assert.True(t, s.validateAlertType(context.Background(), peer.ID("peer-a"), gossip(newSignedAlert(t, deps, 1, models.AlertTypeInformational, body))))
+		assert.Empty(t, s.dropped.Counts())
+	})
+}
@@ -6,8 +6,26 @@ import "errors"
 var (
 	ErrAlertNotFoundBySequence = errors.New("failed to find alert by sequence in datastore")
 	ErrAlertNotLatest          = errors.New("failed to find latest alert datastore")
+	ErrInvalidAddress          = errors.New("p2p address is not an IPv4 or IPv6 address")
 	ErrInvalidAlerts           = errors.New("peer is sending invalid alerts")
+	ErrNoAlertTopics           = errors.New("not joined to any alert topic yet")
+	ErrPeerBanned              = errors.New("peer was banned for misbehaving")
+	ErrPeerClockSkew           = errors.New("peer clock differs from ours by more than the max clock skew")
+	ErrSyncBatchMalformed      = errors.New("sync batch message is malformed")
+	ErrSyncBatchTooLarge       = errors.New("sync batch message is too large")
 	ErrSyncFiveBytes           = errors.New("sync message is less than 5 bytes, not valid")
+	ErrSyncFrameTooLarge       = errors.New("sync frame is larger than the maximum size")
+	ErrSyncFrameTruncated      = errors.New("sync frame ended before its length")
+	ErrSyncHelloFourBytes      = errors.New("sync hello message is less than 4 bytes, not valid")
+	ErrSyncMessageNotSupported = errors.New("sync message type is not supported by the peer")
 	ErrSyncMessageByte         = errors.New("sync message needs at least a byte")
+	ErrSyncRangeInvalid        = errors.New("sync range start is after its end")
+	ErrSyncRangeNineBytes      = errors.New("sync range message is less than 9 bytes, not valid")
+	ErrSyncRangeTooLarge       = errors.New("sync range is too large")
+	ErrSyncSequenceMismatch    = errors.New("synced alert isn't the sequence the peer sent it as")
+	ErrSyncTimeEightBytes      = errors.New("sync time message is less than 8 bytes, not valid")
+	ErrSyncUnknownType         = errors.New("sync message type is unknown")
 	ErrSyncTimeout             = errors.New("sync from peer process timed out after 1 minute")
+	ErrTooManyInFlightRequests = errors.New("too many in-flight sync requests for peer")
+	ErrUnknownSyncMessageType  = errors.New("sync message carries an unknown alert type")
 )
@@ -0,0 +1,70 @@
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bsv-blockchain/go-sdk/util"
+)
+
+// Sync messages are framed with their length (varint), so they can be read from a transport that doesn't keep
+// message boundaries, like the libp2p sync streams or a raw TCP stream:
+//
+//	length(varint) | sync message(length)
+//
+// An empty frame (length 0) ends the stream
+//
+// The length is a varint rather than a fixed 4-byte prefix: the sync streams of earlier releases already write
+// each message with a varint length (the WriteIntBytes framing of go-sdk), so these frames stay readable by those peers
+
+// MaxSyncFrameSize is the largest sync message read from a frame, so a peer can't make us allocate
+// an arbitrary buffer by announcing a huge frame length
+const MaxSyncFrameSize = maxSyncMessageSize
+
+// FrameSyncMessage will serialize the sync message prefixed with its length
+func FrameSyncMessage(msg *SyncMessage) []byte {
+	data := msg.Serialize()
+	return append(util.VarInt(len(data)).Bytes(), data...)
+}
+
+// WriteFramedSyncMessage will write the sync message to the writer as one frame
+func WriteFramedSyncMessage(w io.Writer, msg *SyncMessage) error {
+	_, err := w.Write(FrameSyncMessage(msg))
+	return err
+}
+
+// ReadFramedSyncMessage will read exactly one framed sync message from the reader (see ReadSyncFrame)
+func ReadFramedSyncMessage(r io.Reader) (*SyncMessage, error) {
+	data, err := ReadSyncFrame(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewSyncMessageFromBytes(data)
+}
+
+// ReadSyncFrame will read exactly one frame from the reader, returning the sync message it carries unparsed
+//
+// A reader that ends between frames returns io.EOF, one that ends part way through a frame returns
+// ErrSyncFrameTruncated. A frame longer than MaxSyncFrameSize returns ErrSyncFrameTooLarge without reading it
+func ReadSyncFrame(r io.Reader) ([]byte, error) {
+	var size util.VarInt
+	if n, err := size.ReadFrom(r); n == 0 && errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	} else if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("%w: the stream ended in the length", ErrSyncFrameTruncated)
+	} else if err != nil {
+		return nil, err
+	}
+	if size > MaxSyncFrameSize {
+		return nil, fmt.Errorf("%w: %d bytes, the limit is %d", ErrSyncFrameTooLarge, size, MaxSyncFrameSize)
+	}
+
+	data := make([]byte, size)
+	if read, err := io.ReadFull(r, data); errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("%w: the stream ended after %d of %d bytes", ErrSyncFrameTruncated, read, size)
+	} else if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,86 @@
+package p2p
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bsv-blockchain/go-sdk/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadFramedSyncMessage will test the method ReadFramedSyncMessage()
+func TestReadFramedSyncMessage(t *testing.T) {
+	t.Parallel()
+
+	messages := []*SyncMessage{
+		{Type: IWantLatest},
+		{Type: IWantSequenceNumber, SequenceNumber: 7},
+		{Type: IWantSequenceRange, SequenceNumber: 3, EndSequence: 9},
+		{Type: IGotSequenceNumber, SequenceNumber: 8, Data: []byte("alert")},
+		{Type: IGotBatch, SequenceNumber: 4, Batch: [][]byte{[]byte("one"), bytes.Repeat([]byte{0x01}, 300)}},
+	}
+
+	t.Run("concatenated frames", func(t *testing.T) {
+		var buf bytes.Buffer
+		for _, msg := range messages {
+			require.NoError(t, WriteFramedSyncMessage(&buf, msg))
+		}
+
+		// The reader returns one byte at a time, so every frame is read in pieces
+		r := iotest.OneByteReader(&buf)
+		for _, expected := range messages {
+			msg, err := ReadFramedSyncMessage(r)
+			require.NoError(t, err)
+			assert.Equal(t, expected.Type, msg.Type)
+			assert.Equal(t, expected.SequenceNumber, msg.SequenceNumber)
+			assert.Equal(t, expected.EndSequence, msg.EndSequence)
+			assert.Equal(t, expected.Serialize(), msg.Serialize())
+		}
+
+		_, err := ReadFramedSyncMessage(r)
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("the varint wire format", func(t *testing.T) {
+		// The frames are what the sync streams always carried, a util.Writer varint prefixed message
+		for _, msg := range messages {
+			writer := util.NewWriter()
+			writer.WriteIntBytes(msg.Serialize())
+			assert.Equal(t, writer.Buf, FrameSyncMessage(msg))
+		}
+	})
+
+	t.Run("empty frame", func(t *testing.T) {
+		data, err := ReadSyncFrame(bytes.NewReader([]byte{0x00}))
+		require.NoError(t, err)
+		assert.Empty(t, data)
+	})
+
+	t.Run("truncated frame", func(t *testing.T) {
+		for _, msg := range messages[3:] {
+			frame := FrameSyncMessage(msg)
+			for i := 1; i < len(frame); i++ {
+				_, err := ReadFramedSyncMessage(bytes.NewReader(frame[:i]))
+				require.ErrorIs(t, err, ErrSyncFrameTruncated, "%d bytes", i)
+			}
+		}
+	})
+
+	t.Run("frame too large", func(t *testing.T) {
+		_, err := ReadFramedSyncMessage(bytes.NewReader(util.VarInt(MaxSyncFrameSize + 1).Bytes()))
+		require.ErrorIs(t, err, ErrSyncFrameTooLarge)
+
+		// A huge declared length is rejected before anything is allocated for it
+		_, err = ReadFramedSyncMessage(bytes.NewReader(util.VarInt(1 << 62).Bytes()))
+		require.ErrorIs(t, err, ErrSyncFrameTooLarge)
+	})
+
+	t.Run("malformed message", func(t *testing.T) {
+		_, err := ReadFramedSyncMessage(bytes.NewReader([]byte{0x02, IGotSequenceNumber, 0x01}))
+		var parseErr *SyncParseError
+		require.ErrorAs(t, err, &parseErr)
+	})
+}
@@ -0,0 +1,20 @@
+package p2p
+
+import (
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// gossipParams returns the gossipsub parameters pushing each new alert to fanout peers
+//
+// The fanout is the mesh size (D), the bounds around it are widened or narrowed so gossipsub accepts it.
+// Gossipsub relays the original message bytes (so the signatures stay valid), and never back to the
+// peer it was received from or the peer that published it
+func gossipParams(fanout int) pubsub.GossipSubParams {
+	params := pubsub.DefaultGossipSubParams()
+	params.D = fanout
+	params.Dlo = min(params.Dlo, fanout)
+	params.Dhi = max(params.Dhi, fanout)
+	params.Dscore = min(params.Dscore, fanout)
+	params.Dout = max(0, min(params.Dout, params.Dlo-1, fanout/2-1))
+	return params
+}
@@ -0,0 +1,37 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
+// TestGossipParams will test the method gossipParams()
+func TestGossipParams(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, pubsub.DefaultGossipSubParams(), gossipParams(config.DefaultGossipFanout))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	net := mocknet.New()
+	t.Cleanup(func() {
+		_ = net.Close()
+	})
+	h, err := net.GenPeer()
+	require.NoError(t, err)
+
+	// Gossipsub refuses parameters it can't build a mesh with
+	for _, fanout := range []int{config.MinGossipFanout, 3, 4, 5, 8, 12, 20} {
+		params := gossipParams(fanout)
+		assert.Equal(t, fanout, params.D)
+		_, err = pubsub.NewGossipSub(ctx, h, pubsub.WithGossipSubParams(params))
+		require.NoError(t, err, "fanout %d", fanout)
+	}
+}
@@ -0,0 +1,151 @@
+package p2p
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
+// PeerCounts are the peers we are connected to per direction, and the most allowed
+type PeerCounts struct {
+	Inbound     int `json:"inbound"`
+	MaxInbound  int `json:"max_inbound"`
+	MaxOutbound int `json:"max_outbound"`
+	Outbound    int `json:"outbound"`
+}
+
+// peerLimits is the connection gater that caps the inbound and outbound peers, after the IP filter it wraps
+//
+// Once the inbound peers are at the max a new inbound peer is refused, or with evict the worst-behaved
+// inbound peer (highest misbehavior score) is disconnected to make room. Once the outbound peers are at the max
+// no new peer is dialed. Static peers are never refused, counted against a full limit or evicted
+type peerLimits struct {
+	connmgr.ConnectionGater
+	sync.Mutex
+	conns       func() []network.Conn // The open connections, nil until attached to the host
+	closePeer   func(peerID peer.ID) error
+	evict       bool
+	isStatic    func(peerID peer.ID) bool
+	log         config.LoggerInterface
+	maxInbound  int
+	maxOutbound int
+	score       func(peerID peer.ID) float64
+}
+
+// attach will start limiting the peers of the host, the gater lets every peer through until then
+func (l *peerLimits) attach(conns func() []network.Conn, closePeer func(peerID peer.ID) error, score func(peerID peer.ID) float64) {
+	l.Lock()
+	defer l.Unlock()
+	l.closePeer = closePeer
+	l.score = score
+	l.conns = conns
+}
+
+// directions returns the direction of each connected peer (of its first connection), the lock must be held
+func (l *peerLimits) directions() map[peer.ID]network.Direction {
+	peers := make(map[peer.ID]network.Direction)
+	if l.conns == nil {
+		return peers
+	}
+	for _, conn := range l.conns() {
+		if _, ok := peers[conn.RemotePeer()]; !ok {
+			peers[conn.RemotePeer()] = conn.Stat().Direction
+		}
+	}
+	return peers
+}
+
+// Counts returns the connected peers per direction that count against the limits (static peers don't), and the limits
+func (l *peerLimits) Counts() PeerCounts {
+	l.Lock()
+	defer l.Unlock()
+	peers := l.directions()
+	return PeerCounts{
+		Inbound:     l.count(peers, network.DirInbound),
+		MaxInbound:  l.maxInbound,
+		MaxOutbound: l.maxOutbound,
+		Outbound:    l.count(peers, network.DirOutbound),
+	}
+}
+
+// InterceptPeerDial refuses to dial a new peer once the outbound peers are at the max
+func (l *peerLimits) InterceptPeerDial(peerID peer.ID) bool {
+	if !l.ConnectionGater.InterceptPeerDial(peerID) {
+		return false
+	}
+	l.Lock()
+	defer l.Unlock()
+	peers := l.directions()
+	if _, connected := peers[peerID]; connected || l.isStatic(peerID) {
+		return true
+	}
+	if l.count(peers, network.DirOutbound) >= l.maxOutbound {
+		l.log.Debugf("not dialing %s, already at the max of %d outbound peers", peerID.String(), l.maxOutbound)
+		return false
+	}
+	return true
+}
+
+// InterceptSecured refuses a new inbound peer once the inbound peers are at the max, or evicts one for it
+func (l *peerLimits) InterceptSecured(direction network.Direction, peerID peer.ID, addrs network.ConnMultiaddrs) bool {
+	if !l.ConnectionGater.InterceptSecured(direction, peerID, addrs) {
+		return false
+	} else if direction != network.DirInbound {
+		return true
+	}
+	l.Lock()
+	defer l.Unlock()
+	peers := l.directions()
+	if _, connected := peers[peerID]; connected || l.isStatic(peerID) {
+		return true
+	}
+	if l.count(peers, network.DirInbound) < l.maxInbound {
+		return true
+	}
+
+	// Make room by disconnecting the worst-behaved inbound peer, if configured
+	if l.evict {
+		if victim, ok := l.evictable(peers); ok {
+			l.log.Infof("evicting inbound peer %s to make room for %s, at the max of %d inbound peers", victim.String(), peerID.String(), l.maxInbound)
+			if err := l.closePeer(victim); err != nil {
+				l.log.Errorf("failed to evict peer %s: %s", victim.String(), err.Error())
+				return false
+			}
+			return true
+		}
+	}
+	l.log.Debugf("refusing inbound peer %s, already at the max of %d inbound peers", peerID.String(), l.maxInbound)
+	return false
+}
+
+// count returns the peers in the direction that count against its limit (static peers don't)
+func (l *peerLimits) count(peers map[peer.ID]network.Direction, direction network.Direction) int {
+	count := 0
+	for peerID, d := range peers {
+		if d == direction && !l.isStatic(peerID) {
+			count++
+		}
+	}
+	return count
+}
+
+// evictable returns the inbound peer to evict, the one with the highest misbehavior score (static peers are exempt)
+// Peers with the same score are compared by ID, so the choice doesn't depend on the map order
+func (l *peerLimits) evictable(peers map[peer.ID]network.Direction) (peer.ID, bool) {
+	var victim peer.ID
+	worst, found := 0.0, false
+	for peerID, direction := range peers {
+		if direction != network.DirInbound || l.isStatic(peerID) {
+			continue
+		}
+		score := l.score(peerID)
+		if !found || score > worst || (score == worst && peerID < victim) {
+			victim, worst, found = peerID, score, true
+		}
+	}
+	return victim, found
+}
@@ -0,0 +1,137 @@
+package p2p
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/conngater"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
+// testConn is an open connection to a peer, only its peer and direction are known
+type testConn struct {
+	network.Conn
+	direction network.Direction
+	peer      peer.ID
+}
+
+// RemotePeer returns the peer of the connection
+func (c *testConn) RemotePeer() peer.ID {
+	return c.peer
+}
+
+// Stat returns the direction of the connection
+func (c *testConn) Stat() network.ConnStats {
+	return network.ConnStats{Stats: network.Stats{Direction: c.direction}}
+}
+
+// testPeerLimits is a peer limits gater over fake connections
+type testPeerLimits struct {
+	*peerLimits
+	conns  map[peer.ID]network.Direction // The connected peers
+	closed []peer.ID                     // The peers evicted, in order
+	scores map[peer.ID]float64           // The misbehavior scores
+}
+
+// newTestPeerLimits will create a peer limits gater with the limits, where the static peer is static
+func newTestPeerLimits(t *testing.T, maxInbound, maxOutbound int, evict bool, static peer.ID) *testPeerLimits {
+	base, err := conngater.NewBasicConnectionGater(nil)
+	require.NoError(t, err)
+	tl := &testPeerLimits{conns: make(map[peer.ID]network.Direction), scores: make(map[peer.ID]float64)}
+	tl.peerLimits = &peerLimits{
+		ConnectionGater: base,
+		evict:           evict,
+		isStatic: func(peerID peer.ID) bool {
+			return peerID == static
+		},
+		log:         &config.ExtendedLogger{Logger: log.New(io.Discard, "", 0)},
+		maxInbound:  maxInbound,
+		maxOutbound: maxOutbound,
+	}
+	tl.attach(func() []network.Conn {
+		conns := make([]network.Conn, 0, len(tl.conns))
+		for peerID, direction := range tl.conns {
+			conns = append(conns, &testConn{direction: direction, peer: peerID})
+		}
+		return conns
+	}, func(peerID peer.ID) error {
+		tl.closed = append(tl.closed, peerID)
+		delete(tl.conns, peerID)
+		return nil
+	}, func(peerID peer.ID) float64 {
+		return tl.scores[peerID]
+	})
+	return tl
+}
+
+// TestPeerLimits_InterceptSecured will test the method InterceptSecured()
+func TestPeerLimits_InterceptSecured(t *testing.T) {
+	t.Parallel()
+
+	t.Run("refuses inbound peers at the max", func(t *testing.T) {
+		limits := newTestPeerLimits(t, 2, 1, false, "static")
+		limits.conns["a"] = network.DirInbound
+		limits.conns["b"] = network.DirOutbound
+		assert.True(t, limits.InterceptSecured(network.DirInbound, "c", nil))
+
+		limits.conns["c"] = network.DirInbound
+		assert.False(t, limits.InterceptSecured(network.DirInbound, "d", nil))
+		assert.True(t, limits.InterceptSecured(network.DirInbound, "a", nil), "a second connection of a connected peer")
+		assert.True(t, limits.InterceptSecured(network.DirInbound, "static", nil))
+		assert.True(t, limits.InterceptSecured(network.DirOutbound, "d", nil), "outbound is limited when dialing")
+		assert.Empty(t, limits.closed)
+	})
+
+	t.Run("evicts the worst inbound peer", func(t *testing.T) {
+		limits := newTestPeerLimits(t, 2, 1, true, "static")
+		limits.conns["a"] = network.DirInbound
+		limits.conns["b"] = network.DirInbound
+		limits.conns["static"] = network.DirInbound
+		limits.scores["b"] = 10
+		limits.scores["static"] = 50
+
+		assert.True(t, limits.InterceptSecured(network.DirInbound, "c", nil))
+		assert.Equal(t, []peer.ID{"b"}, limits.closed)
+	})
+
+	t.Run("static peers are never evicted", func(t *testing.T) {
+		limits := newTestPeerLimits(t, 0, 1, true, "static")
+		limits.conns["static"] = network.DirInbound
+
+		assert.False(t, limits.InterceptSecured(network.DirInbound, "c", nil))
+		assert.Empty(t, limits.closed)
+	})
+}
+
+// TestPeerLimits_InterceptPeerDial will test the method InterceptPeerDial()
+func TestPeerLimits_InterceptPeerDial(t *testing.T) {
+	t.Parallel()
+
+	limits := newTestPeerLimits(t, 1, 1, false, "static")
+	assert.True(t, limits.InterceptPeerDial("a"))
+
+	limits.conns["a"] = network.DirOutbound
+	limits.conns["b"] = network.DirInbound
+	assert.False(t, limits.InterceptPeerDial("c"))
+	assert.True(t, limits.InterceptPeerDial("a"))
+	assert.True(t, limits.InterceptPeerDial("b"))
+	assert.True(t, limits.InterceptPeerDial("static"))
+}
+
+// TestPeerLimits_Counts will test the method Counts()
+func TestPeerLimits_Counts(t *testing.T) {
+	t.Parallel()
+
+	limits := newTestPeerLimits(t, 8, 4, false, "static")
+	limits.conns["a"] = network.DirInbound
+	limits.conns["b"] = network.DirOutbound
+	limits.conns["c"] = network.DirOutbound
+	limits.conns["static"] = network.DirInbound
+	assert.Equal(t, PeerCounts{Inbound: 1, MaxInbound: 8, MaxOutbound: 4, Outbound: 2}, limits.Counts())
+}
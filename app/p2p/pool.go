@@ -0,0 +1,145 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/bsv-blockchain/go-alert-system/app/metrics"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// alertJob is an alert received on a topic, waiting in the pool for a worker
+type alertJob struct {
+	alert *models.AlertMessage
+	after []chan struct{} // Closed by the jobs that have to finish before this one starts
+	done  chan struct{}   // Closed once the job is processed
+	from  peer.ID
+	topic string
+}
+
+// alertPool processes the alerts received on the topics with a bounded number of workers
+//
+// Alerts are taken from a buffered queue in the order they arrived. An alert waits for the alert with the
+// sequence before it when that one is still in the pool, since it can't pass the prior sequence check
+// until it's saved. A set keys alert waits for every alert before it, and every alert after it waits
+// for it, so the signatures of the alerts it authorizes are checked against the new keys
+type alertPool struct {
+	sync.Mutex
+	closed      bool
+	inFlight    utils.InFlight
+	jobs        chan *alertJob
+	lastSetKeys chan struct{}            // Done channel of the latest set keys alert in the pool
+	pending     map[uint32]chan struct{} // Done channels of the alerts in the pool, by sequence
+	process     func(ctx context.Context, job *alertJob)
+	workers     int
+}
+
+// newAlertPool will create a pool of workers processing up to queueSize queued alerts
+func newAlertPool(workers, queueSize int, process func(ctx context.Context, job *alertJob)) *alertPool {
+	return &alertPool{
+		jobs:    make(chan *alertJob, queueSize),
+		pending: make(map[uint32]chan struct{}),
+		process: process,
+		workers: workers,
+	}
+}
+
+// Start will start the workers, they run until the pool is closed and the queue is empty
+func (p *alertPool) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		go func() {
+			for job := range p.jobs {
+				metrics.AlertQueueDepth.Set(float64(len(p.jobs)))
+				for _, after := range job.after {
+					<-after
+				}
+				p.process(ctx, job)
+				close(job.done)
+				p.inFlight.End()
+			}
+		}()
+	}
+}
+
+// Submit will queue an alert, waiting for room in the queue. It returns false if the pool is closed
+// or the context is done before the alert is queued
+func (p *alertPool) Submit(ctx context.Context, alert *models.AlertMessage, from peer.ID, topic string) bool {
+	p.Lock()
+	defer p.Unlock()
+	if p.closed {
+		return false
+	}
+	p.prune()
+
+	job := &alertJob{alert: alert, done: make(chan struct{}), from: from, topic: topic}
+	if alert.GetAlertType() == models.AlertTypeSetKeys {
+		for _, done := range p.pending {
+			job.after = append(job.after, done)
+		}
+	} else {
+		if prior, ok := p.pending[alert.SequenceNumber-1]; ok {
+			job.after = append(job.after, prior)
+		}
+		if p.lastSetKeys != nil {
+			job.after = append(job.after, p.lastSetKeys)
+		}
+	}
+
+	// The jobs are queued under the lock, so a job only ever waits for jobs queued before it
+	p.inFlight.Begin()
+	select {
+	case p.jobs <- job:
+	case <-ctx.Done():
+		p.inFlight.End()
+		return false
+	}
+	p.pending[alert.SequenceNumber] = job.done
+	if alert.GetAlertType() == models.AlertTypeSetKeys {
+		p.lastSetKeys = job.done
+	}
+	metrics.AlertQueueDepth.Set(float64(len(p.jobs)))
+	return true
+}
+
+// Depth returns the number of alerts waiting in the queue
+func (p *alertPool) Depth() int {
+	return len(p.jobs)
+}
+
+// Close will stop the pool taking new alerts, the workers finish the alerts already queued
+func (p *alertPool) Close() {
+	p.Lock()
+	defer p.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+}
+
+// Drain will close the pool and wait for the queued and running alerts to finish, or for the context to be done
+// It returns how many alerts finished, and how many were still queued or running
+func (p *alertPool) Drain(ctx context.Context) (drained, abandoned int) {
+	p.Close()
+	return p.inFlight.Drain(ctx)
+}
+
+// prune will forget the alerts that are done (the lock must be held)
+func (p *alertPool) prune() {
+	for sequence, done := range p.pending {
+		select {
+		case <-done:
+			delete(p.pending, sequence)
+		default:
+		}
+	}
+	if p.lastSetKeys != nil {
+		select {
+		case <-p.lastSetKeys:
+			p.lastSetKeys = nil
+		default:
+		}
+	}
+}
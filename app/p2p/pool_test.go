@@ -0,0 +1,135 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// newPoolAlert will create an alert of the type with the sequence number
+func newPoolAlert(alertType models.AlertType, sequence uint32) *models.AlertMessage {
+	a := models.NewAlertMessage()
+	a.SetAlertType(alertType)
+	a.SequenceNumber = sequence
+	return a
+}
+
+// TestAlertPool will test processing alerts with the pool
+func TestAlertPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no more alerts than workers are processed at once", func(t *testing.T) {
+		var running, most atomic.Int32
+		pool := newAlertPool(2, 10, func(_ context.Context, _ *alertJob) {
+			n := running.Add(1)
+			for {
+				m := most.Load()
+				if n <= m || most.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+		})
+		pool.Start(context.Background())
+
+		// Unrelated sequences don't wait for each other
+		for i := uint32(0); i < 6; i++ {
+			require.True(t, pool.Submit(context.Background(), newPoolAlert(models.AlertTypeInformational, 10*i+10), peer.ID("peer"), "topic"))
+		}
+		drained, abandoned := pool.Drain(context.Background())
+		assert.Equal(t, 6, drained)
+		assert.Zero(t, abandoned)
+		assert.LessOrEqual(t, most.Load(), int32(2))
+	})
+
+	t.Run("an alert waits for the sequence before it", func(t *testing.T) {
+		var mu sync.Mutex
+		order := make([]uint32, 0, 3)
+		pool := newAlertPool(3, 10, func(_ context.Context, job *alertJob) {
+			// The earlier sequences take longer, they still finish first
+			time.Sleep(time.Duration(5-job.alert.SequenceNumber) * 5 * time.Millisecond)
+			mu.Lock()
+			order = append(order, job.alert.SequenceNumber)
+			mu.Unlock()
+		})
+		pool.Start(context.Background())
+
+		for _, sequence := range []uint32{1, 2, 3} {
+			require.True(t, pool.Submit(context.Background(), newPoolAlert(models.AlertTypeInformational, sequence), peer.ID("peer"), "topic"))
+		}
+		pool.Drain(context.Background())
+		assert.Equal(t, []uint32{1, 2, 3}, order)
+	})
+
+	t.Run("a set keys alert applies before the alerts after it", func(t *testing.T) {
+		var mu sync.Mutex
+		order := make([]uint32, 0, 3)
+		pool := newAlertPool(3, 10, func(_ context.Context, job *alertJob) {
+			if job.alert.GetAlertType() == models.AlertTypeSetKeys {
+				time.Sleep(20 * time.Millisecond)
+			}
+			mu.Lock()
+			order = append(order, job.alert.SequenceNumber)
+			mu.Unlock()
+		})
+		pool.Start(context.Background())
+
+		require.True(t, pool.Submit(context.Background(), newPoolAlert(models.AlertTypeSetKeys, 5), peer.ID("peer"), "topic"))
+		require.True(t, pool.Submit(context.Background(), newPoolAlert(models.AlertTypeInformational, 9), peer.ID("peer"), "topic"))
+		pool.Drain(context.Background())
+		assert.Equal(t, []uint32{5, 9}, order)
+	})
+
+	t.Run("a closed pool takes no alerts", func(t *testing.T) {
+		pool := newAlertPool(1, 1, func(_ context.Context, _ *alertJob) {})
+		pool.Start(context.Background())
+		pool.Close()
+		assert.False(t, pool.Submit(context.Background(), newPoolAlert(models.AlertTypeInformational, 1), peer.ID("peer"), "topic"))
+	})
+
+	t.Run("alerts still queued at the deadline are abandoned", func(t *testing.T) {
+		release := make(chan struct{})
+		pool := newAlertPool(1, 5, func(_ context.Context, _ *alertJob) {
+			<-release
+		})
+		pool.Start(context.Background())
+		for i := uint32(1); i <= 3; i++ {
+			require.True(t, pool.Submit(context.Background(), newPoolAlert(models.AlertTypeInformational, 10*i), peer.ID("peer"), "topic"))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		drained, abandoned := pool.Drain(ctx)
+		assert.Zero(t, drained)
+		assert.Equal(t, 3, abandoned)
+		close(release)
+	})
+
+	t.Run("a full queue waits until the context is done", func(t *testing.T) {
+		release := make(chan struct{})
+		pool := newAlertPool(1, 1, func(_ context.Context, _ *alertJob) {
+			<-release
+		})
+		pool.Start(context.Background())
+		defer close(release)
+
+		// One alert is taken by the worker, one waits in the queue
+		require.True(t, pool.Submit(context.Background(), newPoolAlert(models.AlertTypeInformational, 10), peer.ID("peer"), "topic"))
+		require.Eventually(t, func() bool { return pool.Depth() == 0 }, time.Second, time.Millisecond)
+		require.True(t, pool.Submit(context.Background(), newPoolAlert(models.AlertTypeInformational, 20), peer.ID("peer"), "topic"))
+		assert.Equal(t, 1, pool.Depth())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.False(t, pool.Submit(ctx, newPoolAlert(models.AlertTypeInformational, 30), peer.ID("peer"), "topic"))
+	})
+}
@@ -0,0 +1,102 @@
+package p2p
+
+import (
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// advertisement is the latest sequence a peer says it has, and when it said so
+type advertisement struct {
+	at       time.Time
+	sequence uint32
+}
+
+// syncProgress tracks our latest sequence against the latest sequences advertised by peers
+// Advertisements older than the staleness window are ignored, the peer may have moved on or gone away
+type syncProgress struct {
+	sync.Mutex
+	advertised map[peer.ID]advertisement
+	local      uint32
+	now        func() time.Time
+	staleness  time.Duration
+}
+
+// newSyncProgress will create a new sync progress tracker
+func newSyncProgress(staleness time.Duration) *syncProgress {
+	return &syncProgress{
+		advertised: make(map[peer.ID]advertisement),
+		now:        time.Now,
+		staleness:  staleness,
+	}
+}
+
+// Advertise records the latest sequence a peer says it has
+func (p *syncProgress) Advertise(peerID peer.ID, sequence uint32) {
+	p.Lock()
+	defer p.Unlock()
+	p.advertised[peerID] = advertisement{at: p.now(), sequence: sequence}
+}
+
+// Advertised returns the latest sequence the peer said it has, false if it never said
+func (p *syncProgress) Advertised(peerID peer.ID) (uint32, bool) {
+	p.Lock()
+	defer p.Unlock()
+	ad, ok := p.advertised[peerID]
+	return ad.sequence, ok
+}
+
+// SetLocal records our latest sequence (it only moves forward)
+func (p *syncProgress) SetLocal(sequence uint32) {
+	p.Lock()
+	defer p.Unlock()
+	if sequence > p.local {
+		p.local = sequence
+	}
+}
+
+// highest returns the highest sequence advertised within the staleness window by the peers passing the filter
+// A nil filter counts every peer, the lock must be held
+func (p *syncProgress) highest(include func(peerID peer.ID) bool) uint32 {
+	var highest uint32
+	now := p.now()
+	for peerID, ad := range p.advertised {
+		if now.Sub(ad.at) > p.staleness || (include != nil && !include(peerID)) {
+			continue
+		}
+		if ad.sequence > highest {
+			highest = ad.sequence
+		}
+	}
+	return highest
+}
+
+// Progress returns our latest sequence, the highest sequence advertised by any peer and the percent synced
+// With no peers (or no alerts) there is nothing known to catch up on, so the target is our own sequence
+func (p *syncProgress) Progress() (current, target uint32, pct float64) {
+	p.Lock()
+	defer p.Unlock()
+
+	current, target = p.local, max(p.local, p.highest(nil))
+	if target == 0 || current >= target {
+		return current, target, 100
+	}
+	return current, target, float64(current) / float64(target) * 100
+}
+
+// Synced returns true if we have every sequence advertised by the connected peers, with the highest of those and our own
+func (p *syncProgress) Synced(connected []peer.ID) (synced bool, highestKnown, local uint32) {
+	peers := make(map[peer.ID]struct{}, len(connected))
+	for _, peerID := range connected {
+		peers[peerID] = struct{}{}
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	highestKnown = p.highest(func(peerID peer.ID) bool {
+		_, ok := peers[peerID]
+		return ok
+	})
+	return p.local >= highestKnown, highestKnown, p.local
+}
@@ -0,0 +1,196 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSyncProgress will create a sync progress tracker with an hour staleness window and a fake clock
+func newTestSyncProgress() (*syncProgress, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := newSyncProgress(time.Hour)
+	p.now = func() time.Time {
+		return now
+	}
+	return p, &now
+}
+
+// TestSyncProgress_Progress will test the method Progress()
+func TestSyncProgress_Progress(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mid sync", func(t *testing.T) {
+		p := newSyncProgress(time.Hour)
+		p.SetLocal(25)
+		p.Advertise(peer.ID("peer-a"), 80)
+		p.Advertise(peer.ID("peer-b"), 100)
+
+		current, target, pct := p.Progress()
+		assert.Equal(t, uint32(25), current)
+		assert.Equal(t, uint32(100), target)
+		assert.InDelta(t, 25.0, pct, 0.001)
+	})
+
+	t.Run("fully synced", func(t *testing.T) {
+		p := newSyncProgress(time.Hour)
+		p.Advertise(peer.ID("peer-a"), 100)
+		p.SetLocal(100)
+
+		current, target, pct := p.Progress()
+		assert.Equal(t, uint32(100), current)
+		assert.Equal(t, uint32(100), target)
+		assert.InDelta(t, 100.0, pct, 0.001)
+	})
+
+	t.Run("ahead of peers", func(t *testing.T) {
+		p := newSyncProgress(time.Hour)
+		p.Advertise(peer.ID("peer-a"), 90)
+		p.SetLocal(100)
+
+		current, target, pct := p.Progress()
+		assert.Equal(t, uint32(100), current)
+		assert.Equal(t, uint32(100), target)
+		assert.InDelta(t, 100.0, pct, 0.001)
+	})
+
+	t.Run("no peers", func(t *testing.T) {
+		p := newSyncProgress(time.Hour)
+		p.SetLocal(42)
+
+		current, target, pct := p.Progress()
+		assert.Equal(t, uint32(42), current)
+		assert.Equal(t, uint32(42), target)
+		assert.InDelta(t, 100.0, pct, 0.001)
+	})
+
+	t.Run("no alerts", func(t *testing.T) {
+		p := newSyncProgress(time.Hour)
+		p.Advertise(peer.ID("peer-a"), 0)
+
+		current, target, pct := p.Progress()
+		assert.Equal(t, uint32(0), current)
+		assert.Equal(t, uint32(0), target)
+		assert.InDelta(t, 100.0, pct, 0.001)
+	})
+
+	t.Run("stale advertisements are ignored", func(t *testing.T) {
+		p, now := newTestSyncProgress()
+		p.SetLocal(25)
+		p.Advertise(peer.ID("peer-a"), 100)
+
+		*now = now.Add(time.Hour + time.Second)
+		current, target, pct := p.Progress()
+		assert.Equal(t, uint32(25), current)
+		assert.Equal(t, uint32(25), target)
+		assert.InDelta(t, 100.0, pct, 0.001)
+	})
+
+	t.Run("local sequence never goes backwards", func(t *testing.T) {
+		p := newSyncProgress(time.Hour)
+		p.SetLocal(10)
+		p.SetLocal(5)
+
+		current, _, _ := p.Progress()
+		assert.Equal(t, uint32(10), current)
+	})
+}
+
+// TestSyncProgress_Advertised will test the method Advertised()
+func TestSyncProgress_Advertised(t *testing.T) {
+	t.Parallel()
+
+	p, _ := newTestSyncProgress()
+	_, ok := p.Advertised(peer.ID("peer-a"))
+	assert.False(t, ok)
+
+	p.Advertise(peer.ID("peer-a"), 80)
+	p.Advertise(peer.ID("peer-a"), 75)
+	sequence, ok := p.Advertised(peer.ID("peer-a"))
+	assert.True(t, ok)
+	assert.Equal(t, uint32(75), sequence)
+}
+
+// TestSyncProgress_Synced will test the method Synced()
+func TestSyncProgress_Synced(t *testing.T) {
+	t.Parallel()
+
+	connected := []peer.ID{"peer-a", "peer-b"}
+
+	t.Run("catching up", func(t *testing.T) {
+		p, _ := newTestSyncProgress()
+		p.SetLocal(25)
+		p.Advertise(peer.ID("peer-a"), 80)
+		p.Advertise(peer.ID("peer-b"), 100)
+
+		synced, highestKnown, local := p.Synced(connected)
+		assert.False(t, synced)
+		assert.Equal(t, uint32(100), highestKnown)
+		assert.Equal(t, uint32(25), local)
+	})
+
+	t.Run("caught up", func(t *testing.T) {
+		p, _ := newTestSyncProgress()
+		p.Advertise(peer.ID("peer-a"), 100)
+		p.SetLocal(100)
+
+		synced, highestKnown, local := p.Synced(connected)
+		assert.True(t, synced)
+		assert.Equal(t, uint32(100), highestKnown)
+		assert.Equal(t, uint32(100), local)
+	})
+
+	t.Run("disconnected peers don't count", func(t *testing.T) {
+		p, _ := newTestSyncProgress()
+		p.SetLocal(25)
+		p.Advertise(peer.ID("peer-c"), 100)
+
+		synced, highestKnown, _ := p.Synced(connected)
+		assert.True(t, synced)
+		assert.Equal(t, uint32(0), highestKnown)
+	})
+
+	t.Run("stale advertisements don't count", func(t *testing.T) {
+		p, now := newTestSyncProgress()
+		p.SetLocal(25)
+		p.Advertise(peer.ID("peer-a"), 100)
+		*now = now.Add(30 * time.Minute)
+		p.Advertise(peer.ID("peer-b"), 50)
+
+		synced, highestKnown, _ := p.Synced(connected)
+		assert.False(t, synced)
+		assert.Equal(t, uint32(100), highestKnown)
+
+		*now = now.Add(31 * time.Minute)
+		synced, highestKnown, _ = p.Synced(connected)
+		assert.False(t, synced)
+		assert.Equal(t, uint32(50), highestKnown)
+
+		p.SetLocal(50)
+		synced, _, _ = p.Synced(connected)
+		assert.True(t, synced)
+	})
+}
+
+// TestServer_SyncProgress will test the method SyncProgress()
+func TestServer_SyncProgress(t *testing.T) {
+	t.Run("no tracker", func(t *testing.T) {
+		current, target, pct := (&Server{}).SyncProgress()
+		assert.Equal(t, uint32(0), current)
+		assert.Equal(t, uint32(0), target)
+		assert.InDelta(t, 100.0, pct, 0.001)
+	})
+
+	t.Run("mid sync", func(t *testing.T) {
+		s := &Server{progress: newSyncProgress(time.Hour)}
+		s.progress.SetLocal(1)
+		s.progress.Advertise(peer.ID("peer-a"), 4)
+
+		current, target, pct := s.SyncProgress()
+		assert.Equal(t, uint32(1), current)
+		assert.Equal(t, uint32(4), target)
+		assert.InDelta(t, 25.0, pct, 0.001)
+	})
+}
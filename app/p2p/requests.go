@@ -0,0 +1,230 @@
+package p2p
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// syncRequest is an outstanding IWant* request that was sent to a peer
+type syncRequest struct {
+	Attempts       int
+	EndSequence    uint32
+	MessageType    byte
+	Peer           peer.ID
+	SentAt         time.Time
+	SequenceNumber uint32
+}
+
+// requestKey identifies a sync request for a single peer
+type requestKey struct {
+	messageType    byte
+	sequenceNumber uint32
+}
+
+// requestTracker keeps track of the in-flight sync requests for each peer
+// This bounds how many outstanding requests a single peer can make us hold
+type requestTracker struct {
+	sync.Mutex
+	maxPerPeer int
+	requests   map[peer.ID]map[requestKey]*syncRequest
+	timeout    time.Duration
+}
+
+// newRequestTracker will create a new request tracker
+func newRequestTracker(maxPerPeer int, timeout time.Duration) *requestTracker {
+	return &requestTracker{
+		maxPerPeer: maxPerPeer,
+		requests:   make(map[peer.ID]map[requestKey]*syncRequest),
+		timeout:    timeout,
+	}
+}
+
+// Track will record a request that is about to be sent to the peer
+// If the peer already has the maximum number of requests in flight, the request is rejected
+func (r *requestTracker) Track(peerID peer.ID, msg *SyncMessage) error {
+	r.Lock()
+	defer r.Unlock()
+
+	// Timed out requests don't count against the cap, but are left for Expire to retry
+	now := time.Now()
+	key := requestKey{messageType: msg.Type, sequenceNumber: msg.SequenceNumber}
+	outstanding := r.requests[peerID]
+	if _, ok := outstanding[key]; !ok && r.maxPerPeer > 0 {
+		if active := r.activeCount(outstanding, now); active >= r.maxPerPeer {
+			return fmt.Errorf("%w: peer %s has %d outstanding requests", ErrTooManyInFlightRequests, peerID.String(), active)
+		}
+	}
+
+	if outstanding == nil {
+		outstanding = make(map[requestKey]*syncRequest)
+		r.requests[peerID] = outstanding
+	}
+
+	// Re-sending the same request keeps the retry count
+	if existing, ok := outstanding[key]; ok {
+		existing.SentAt = now
+		return nil
+	}
+	outstanding[key] = &syncRequest{
+		EndSequence:    msg.EndSequence,
+		MessageType:    msg.Type,
+		Peer:           peerID,
+		SentAt:         now,
+		SequenceNumber: msg.SequenceNumber,
+	}
+	return nil
+}
+
+// Retry will track a timed out request again against the given peer, incrementing the attempts
+func (r *requestTracker) Retry(peerID peer.ID, req *syncRequest) error {
+	msg := &SyncMessage{Type: req.MessageType, SequenceNumber: req.SequenceNumber, EndSequence: req.EndSequence}
+	if err := r.Track(peerID, msg); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if tracked, ok := r.requests[peerID][requestKey{messageType: req.MessageType, sequenceNumber: req.SequenceNumber}]; ok {
+		tracked.Attempts = req.Attempts + 1
+	}
+	return nil
+}
+
+// Complete will correlate a response from the peer with its outstanding request
+// Returns false if there was no matching request in flight
+//
+// A sequence number answers either the request for that sequence, or a range request covering it.
+// A batch answers the range request covering its first sequence.
+// The range request is only completed by the last sequence of the range.
+func (r *requestTracker) Complete(peerID peer.ID, msg *SyncMessage) bool {
+	var key requestKey
+	switch msg.Type {
+	case IGotLatest:
+		key = requestKey{messageType: IWantLatest}
+	case IGotSequenceNumber:
+		key = requestKey{messageType: IWantSequenceNumber, sequenceNumber: msg.SequenceNumber}
+	case IGotBatch:
+		// Only answers a range request
+	default:
+		return false
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	outstanding := r.requests[peerID]
+	if _, ok := outstanding[key]; !ok || msg.Type == IGotBatch {
+		var rangeReq *syncRequest
+		if key, rangeReq, ok = findRange(outstanding, msg); !ok {
+			return false
+		} else if lastSequence(msg) < rangeReq.EndSequence {
+			rangeReq.SentAt = time.Now()
+			return true
+		}
+	}
+	delete(outstanding, key)
+	if len(outstanding) == 0 {
+		delete(r.requests, peerID)
+	}
+	return true
+}
+
+// lastSequence returns the last sequence carried by a response, the sequence after the first for each alert of a batch
+func lastSequence(msg *SyncMessage) uint32 {
+	if msg.Type == IGotBatch && len(msg.Batch) > 0 {
+		return msg.SequenceNumber + uint32(len(msg.Batch)) - 1 //nolint:gosec // a batch has at most MaxSyncBatchCount alerts
+	}
+	return msg.SequenceNumber
+}
+
+// findRange will find the outstanding range request covering the sequence number of the response
+func findRange(outstanding map[requestKey]*syncRequest, msg *SyncMessage) (requestKey, *syncRequest, bool) {
+	if msg.Type != IGotSequenceNumber && msg.Type != IGotBatch {
+		return requestKey{}, nil, false
+	}
+	for key, req := range outstanding {
+		if req.MessageType == IWantSequenceRange && req.SequenceNumber <= msg.SequenceNumber && msg.SequenceNumber <= req.EndSequence {
+			return key, req, true
+		}
+	}
+	return requestKey{}, nil, false
+}
+
+// Requested returns true if the sequence number was requested from any peer, on its own or in a range, and not answered yet
+func (r *requestTracker) Requested(sequenceNumber uint32) bool {
+	r.Lock()
+	defer r.Unlock()
+	msg := &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: sequenceNumber}
+	for _, outstanding := range r.requests {
+		if _, ok := outstanding[requestKey{messageType: IWantSequenceNumber, sequenceNumber: sequenceNumber}]; ok {
+			return true
+		} else if _, _, ok = findRange(outstanding, msg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// InFlight returns the number of outstanding requests for the peer
+func (r *requestTracker) InFlight(peerID peer.ID) int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.requests[peerID])
+}
+
+// Total returns the number of outstanding requests across all peers
+func (r *requestTracker) Total() int {
+	r.Lock()
+	defer r.Unlock()
+	total := 0
+	for _, outstanding := range r.requests {
+		total += len(outstanding)
+	}
+	return total
+}
+
+// Expire will remove and return all requests that have been outstanding longer than the timeout
+func (r *requestTracker) Expire(now time.Time) []*syncRequest {
+	r.Lock()
+	defer r.Unlock()
+	var expired []*syncRequest
+	for peerID := range r.requests {
+		expired = append(expired, r.pruneExpired(peerID, now)...)
+	}
+	return expired
+}
+
+// activeCount returns the number of requests that have not timed out (caller must hold the lock)
+func (r *requestTracker) activeCount(outstanding map[requestKey]*syncRequest, now time.Time) int {
+	if r.timeout <= 0 {
+		return len(outstanding)
+	}
+	active := 0
+	for _, req := range outstanding {
+		if now.Sub(req.SentAt) < r.timeout {
+			active++
+		}
+	}
+	return active
+}
+
+// pruneExpired removes the timed out requests for a peer (caller must hold the lock)
+func (r *requestTracker) pruneExpired(peerID peer.ID, now time.Time) []*syncRequest {
+	if r.timeout <= 0 {
+		return nil
+	}
+	var expired []*syncRequest
+	outstanding := r.requests[peerID]
+	for key, req := range outstanding {
+		if now.Sub(req.SentAt) >= r.timeout {
+			expired = append(expired, req)
+			delete(outstanding, key)
+		}
+	}
+	if outstanding != nil && len(outstanding) == 0 {
+		delete(r.requests, peerID)
+	}
+	return expired
+}
@@ -0,0 +1,166 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequestTracker_Track will test the method Track()
+func TestRequestTracker_Track(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requests beyond the cap are rejected", func(t *testing.T) {
+		tracker := newRequestTracker(2, time.Minute)
+		peerID := peer.ID("peer-a")
+
+		require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantLatest}))
+		require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 1}))
+
+		err := tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 2})
+		require.ErrorIs(t, err, ErrTooManyInFlightRequests)
+		assert.Equal(t, 2, tracker.InFlight(peerID))
+	})
+
+	t.Run("cap is per peer", func(t *testing.T) {
+		tracker := newRequestTracker(1, time.Minute)
+
+		require.NoError(t, tracker.Track(peer.ID("peer-a"), &SyncMessage{Type: IWantLatest}))
+		require.NoError(t, tracker.Track(peer.ID("peer-b"), &SyncMessage{Type: IWantLatest}))
+	})
+
+	t.Run("re-sending the same request does not count twice", func(t *testing.T) {
+		tracker := newRequestTracker(1, time.Minute)
+		peerID := peer.ID("peer-a")
+
+		require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 5}))
+		require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 5}))
+		assert.Equal(t, 1, tracker.InFlight(peerID))
+	})
+
+	t.Run("timed out requests free up the cap", func(t *testing.T) {
+		tracker := newRequestTracker(1, time.Millisecond)
+		peerID := peer.ID("peer-a")
+
+		require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 1}))
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 2}))
+
+		// The timed out request is kept until it's expired
+		assert.Equal(t, 2, tracker.InFlight(peerID))
+	})
+
+	t.Run("tracking does not drop timed out requests", func(t *testing.T) {
+		tracker := newRequestTracker(5, time.Second)
+		peerID := peer.ID("peer-a")
+
+		require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 1}))
+		tracker.requests[peerID][requestKey{messageType: IWantSequenceNumber, sequenceNumber: 1}].SentAt = time.Now().Add(-2 * time.Second)
+
+		// Another request to the same peer before the retry tick
+		require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 2}))
+
+		// The timed out request is still handed to the retrier
+		expired := tracker.Expire(time.Now())
+		require.Len(t, expired, 1)
+		assert.Equal(t, uint32(1), expired[0].SequenceNumber)
+		assert.Equal(t, 1, tracker.InFlight(peerID))
+	})
+}
+
+// TestRequestTracker_Complete will test the method Complete()
+func TestRequestTracker_Complete(t *testing.T) {
+	t.Parallel()
+
+	tracker := newRequestTracker(5, time.Minute)
+	peerID := peer.ID("peer-a")
+
+	require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantLatest}))
+	require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 3}))
+
+	assert.True(t, tracker.Complete(peerID, &SyncMessage{Type: IGotLatest, SequenceNumber: 10}))
+	assert.False(t, tracker.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 4}))
+	assert.False(t, tracker.Complete(peer.ID("peer-b"), &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 3}))
+	assert.True(t, tracker.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 3}))
+	assert.Equal(t, 0, tracker.InFlight(peerID))
+}
+
+// TestRequestTracker_Complete_Range will test the method Complete() for a range request
+func TestRequestTracker_Complete_Range(t *testing.T) {
+	t.Parallel()
+
+	tracker := newRequestTracker(5, time.Minute)
+	peerID := peer.ID("peer-a")
+	require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 3, EndSequence: 5}))
+
+	// Each sequence in the range is solicited, the range stays in flight until its end
+	assert.False(t, tracker.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 2}))
+	assert.True(t, tracker.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 3}))
+	assert.True(t, tracker.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 4}))
+	assert.Equal(t, 1, tracker.InFlight(peerID))
+	assert.True(t, tracker.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 5}))
+	assert.Equal(t, 0, tracker.InFlight(peerID))
+	assert.False(t, tracker.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 5}))
+}
+
+// TestRequestTracker_Complete_Batch will test the method Complete() for the batches answering a range request
+func TestRequestTracker_Complete_Batch(t *testing.T) {
+	t.Parallel()
+
+	tracker := newRequestTracker(5, time.Minute)
+	peerID := peer.ID("peer-a")
+	require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 3, EndSequence: 7}))
+
+	// A batch is only solicited by a range covering its first sequence, the range stays in flight until a batch reaches its end
+	assert.False(t, tracker.Complete(peerID, &SyncMessage{Type: IGotBatch, SequenceNumber: 2, Batch: [][]byte{{1}, {2}}}))
+	assert.False(t, tracker.Complete(peer.ID("peer-b"), &SyncMessage{Type: IGotBatch, SequenceNumber: 3, Batch: [][]byte{{1}}}))
+	assert.True(t, tracker.Complete(peerID, &SyncMessage{Type: IGotBatch, SequenceNumber: 3, Batch: [][]byte{{1}, {2}, {3}}}))
+	assert.Equal(t, 1, tracker.InFlight(peerID))
+	assert.True(t, tracker.Complete(peerID, &SyncMessage{Type: IGotBatch, SequenceNumber: 6, Batch: [][]byte{{1}, {2}}}))
+	assert.Equal(t, 0, tracker.InFlight(peerID))
+	assert.False(t, tracker.Complete(peerID, &SyncMessage{Type: IGotBatch, SequenceNumber: 6, Batch: [][]byte{{1}, {2}}}))
+}
+
+// TestRequestTracker_Requested will test the method Requested()
+func TestRequestTracker_Requested(t *testing.T) {
+	t.Parallel()
+
+	tracker := newRequestTracker(5, time.Minute)
+	require.NoError(t, tracker.Track(peer.ID("peer-a"), &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 2}))
+	require.NoError(t, tracker.Track(peer.ID("peer-b"), &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 5, EndSequence: 7}))
+
+	assert.True(t, tracker.Requested(2))
+	assert.True(t, tracker.Requested(6))
+	assert.False(t, tracker.Requested(3))
+	assert.False(t, tracker.Requested(8))
+
+	tracker.Complete(peer.ID("peer-a"), &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 2})
+	assert.False(t, tracker.Requested(2))
+}
+
+// TestRequestTracker_Expire will test the method Expire()
+func TestRequestTracker_Expire(t *testing.T) {
+	t.Parallel()
+
+	tracker := newRequestTracker(5, time.Second)
+	peerID := peer.ID("peer-a")
+
+	require.NoError(t, tracker.Track(peerID, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 7}))
+
+	// Nothing has timed out yet
+	assert.Empty(t, tracker.Expire(time.Now()))
+	assert.Equal(t, 1, tracker.InFlight(peerID))
+
+	// The request is removed once it times out
+	expired := tracker.Expire(time.Now().Add(2 * time.Second))
+	require.Len(t, expired, 1)
+	assert.Equal(t, peerID, expired[0].Peer)
+	assert.Equal(t, uint32(7), expired[0].SequenceNumber)
+	assert.Equal(t, 0, tracker.InFlight(peerID))
+
+	// A late response no longer matches anything
+	assert.False(t, tracker.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 7}))
+}
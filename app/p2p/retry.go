@@ -0,0 +1,71 @@
+package p2p
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/metrics"
+)
+
+// syncRetrier retries sync requests that a peer never answered
+// The timed out request is re-sent to an alternate peer (or the same peer if it's the only one)
+// until the maximum number of retries is reached, after which it is abandoned
+// A request that can't be tracked against the target (the peer is at its in-flight cap) is also abandoned
+type syncRetrier struct {
+	abandoned  atomic.Uint64
+	log        config.LoggerInterface
+	maxRetries int
+	peers      func() []peer.ID
+	requests   *requestTracker
+	send       func(ctx context.Context, peerID peer.ID, msg *SyncMessage) error
+}
+
+// Retry will retry or abandon all requests that have timed out
+func (r *syncRetrier) Retry(ctx context.Context, now time.Time) {
+	for _, req := range r.requests.Expire(now) {
+		if req.Attempts >= r.maxRetries {
+			r.abandon()
+			r.log.Errorf("abandoning sync request type %d for sequence %d after %d retries", req.MessageType, req.SequenceNumber, req.Attempts)
+			continue
+		}
+
+		// Pick the peer to retry against
+		target := r.alternatePeer(req.Peer)
+		if err := r.requests.Retry(target, req); err != nil {
+			r.abandon()
+			r.log.Errorf("abandoning sync request type %d for sequence %d: %s", req.MessageType, req.SequenceNumber, err.Error())
+			continue
+		}
+
+		// Re-send the original request, the response is handled by the stream that sends it
+		r.log.Infof("retrying sync request type %d for sequence %d against peer %s (attempt %d)", req.MessageType, req.SequenceNumber, target.String(), req.Attempts+1)
+		if err := r.send(ctx, target, &SyncMessage{Type: req.MessageType, SequenceNumber: req.SequenceNumber, EndSequence: req.EndSequence}); err != nil {
+			r.log.Debugf("failed to send sync request to peer %s: %s", target.String(), err.Error())
+		}
+	}
+}
+
+// abandon will count a sync request that was given up on
+func (r *syncRetrier) abandon() {
+	r.abandoned.Add(1)
+	metrics.SyncRequestsAbandoned.Inc()
+}
+
+// Abandoned returns the number of sync requests that were given up on
+func (r *syncRetrier) Abandoned() uint64 {
+	return r.abandoned.Load()
+}
+
+// alternatePeer will return a connected peer other than the one given, or the same peer if no others exist
+func (r *syncRetrier) alternatePeer(current peer.ID) peer.ID {
+	for _, p := range r.peers() {
+		if p != current {
+			return p
+		}
+	}
+	return current
+}
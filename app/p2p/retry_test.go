@@ -0,0 +1,135 @@
+package p2p
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
+// sentRequest is a sync request the fake peer received
+type sentRequest struct {
+	peer peer.ID
+	msg  SyncMessage
+}
+
+// newTestRetrier will create a retrier where every peer ignores our requests
+func newTestRetrier(tracker *requestTracker, maxRetries int, peers []peer.ID) (*syncRetrier, *[]sentRequest) {
+	sent := make([]sentRequest, 0)
+	return &syncRetrier{
+		log:        &config.ExtendedLogger{Logger: log.New(io.Discard, "", 0)},
+		maxRetries: maxRetries,
+		peers: func() []peer.ID {
+			return peers
+		},
+		requests: tracker,
+		send: func(_ context.Context, peerID peer.ID, msg *SyncMessage) error {
+			sent = append(sent, sentRequest{peer: peerID, msg: *msg}) // the fake peer never responds
+			return nil
+		},
+	}, &sent
+}
+
+// TestSyncRetrier_Retry will test the method Retry()
+func TestSyncRetrier_Retry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries against an alternate peer and then gives up", func(t *testing.T) {
+		peerA, peerB := peer.ID("peer-a"), peer.ID("peer-b")
+		tracker := newRequestTracker(5, time.Second)
+		retrier, sent := newTestRetrier(tracker, 2, []peer.ID{peerA, peerB})
+
+		require.NoError(t, tracker.Track(peerA, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 4}))
+
+		// Not timed out yet, nothing happens
+		now := time.Now()
+		retrier.Retry(context.Background(), now)
+		assert.Empty(t, *sent)
+
+		// First timeout: retried against peer b
+		now = now.Add(2 * time.Second)
+		retrier.Retry(context.Background(), now)
+		assert.Equal(t, []sentRequest{{peer: peerB, msg: SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 4}}}, *sent)
+		assert.Equal(t, 0, tracker.InFlight(peerA))
+		assert.Equal(t, 1, tracker.InFlight(peerB))
+
+		// Second timeout: peer b ignored it as well, back to peer a
+		now = now.Add(2 * time.Second)
+		retrier.Retry(context.Background(), now)
+		assert.Equal(t, []sentRequest{{peer: peerB, msg: SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 4}}, {peer: peerA, msg: SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 4}}}, *sent)
+		assert.Equal(t, uint64(0), retrier.Abandoned())
+
+		// Third timeout: out of retries, abandoned
+		now = now.Add(2 * time.Second)
+		retrier.Retry(context.Background(), now)
+		assert.Len(t, *sent, 2)
+		assert.Equal(t, uint64(1), retrier.Abandoned())
+		assert.Equal(t, 0, tracker.Total())
+	})
+
+	t.Run("retries the same peer when it's the only one", func(t *testing.T) {
+		peerA := peer.ID("peer-a")
+		tracker := newRequestTracker(5, time.Second)
+		retrier, sent := newTestRetrier(tracker, 1, []peer.ID{peerA})
+
+		require.NoError(t, tracker.Track(peerA, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 4}))
+		retrier.Retry(context.Background(), time.Now().Add(2*time.Second))
+		assert.Equal(t, []sentRequest{{peer: peerA, msg: SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 4}}}, *sent)
+	})
+
+	t.Run("answered retry is not abandoned", func(t *testing.T) {
+		peerA, peerB := peer.ID("peer-a"), peer.ID("peer-b")
+		tracker := newRequestTracker(5, time.Second)
+		retrier, _ := newTestRetrier(tracker, 1, []peer.ID{peerA, peerB})
+
+		require.NoError(t, tracker.Track(peerA, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 4}))
+		retrier.Retry(context.Background(), time.Now().Add(2*time.Second))
+
+		// Peer b answers the retried request
+		assert.True(t, tracker.Complete(peerB, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 4}))
+		retrier.Retry(context.Background(), time.Now().Add(4*time.Second))
+		assert.Equal(t, uint64(0), retrier.Abandoned())
+	})
+	t.Run("retry is abandoned when the target is at its cap", func(t *testing.T) {
+		peerA, peerB := peer.ID("peer-a"), peer.ID("peer-b")
+		tracker := newRequestTracker(1, time.Second)
+		retrier, sent := newTestRetrier(tracker, 3, []peer.ID{peerA, peerB})
+
+		require.NoError(t, tracker.Track(peerA, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 4}))
+		now := time.Now().Add(2 * time.Second)
+		require.NoError(t, tracker.Track(peerB, &SyncMessage{Type: IWantLatest}))
+		tracker.requests[peerB][requestKey{messageType: IWantLatest}].SentAt = now
+
+		retrier.Retry(context.Background(), now)
+		assert.Empty(t, *sent)
+		assert.Equal(t, uint64(1), retrier.Abandoned())
+	})
+
+	t.Run("re-sends the whole range request", func(t *testing.T) {
+		peerA, peerB := peer.ID("peer-a"), peer.ID("peer-b")
+		tracker := newRequestTracker(5, time.Second)
+		retrier, sent := newTestRetrier(tracker, 1, []peer.ID{peerA, peerB})
+
+		request := SyncMessage{Type: IWantSequenceRange, SequenceNumber: 4, EndSequence: 9}
+		require.NoError(t, tracker.Track(peerA, &request))
+		retrier.Retry(context.Background(), time.Now().Add(2*time.Second))
+		assert.Equal(t, []sentRequest{{peer: peerB, msg: request}}, *sent)
+	})
+
+	t.Run("re-sends the latest request", func(t *testing.T) {
+		peerA, peerB := peer.ID("peer-a"), peer.ID("peer-b")
+		tracker := newRequestTracker(5, time.Second)
+		retrier, sent := newTestRetrier(tracker, 1, []peer.ID{peerA, peerB})
+
+		require.NoError(t, tracker.Track(peerA, &SyncMessage{Type: IWantLatest}))
+		retrier.Retry(context.Background(), time.Now().Add(2*time.Second))
+		assert.Equal(t, []sentRequest{{peer: peerB, msg: SyncMessage{Type: IWantLatest}}}, *sent)
+	})
+}
@@ -0,0 +1,165 @@
+package p2p
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Points added to a peer's misbehavior score
+const (
+	// parseErrorPenalty is added for a sync message that breaks the protocol
+	parseErrorPenalty = 25.0
+
+	// requestFloodPenalty is added for each sync request past the per-minute limit
+	requestFloodPenalty = 5.0
+)
+
+// misbehavior is the score and request rate of a single peer
+type misbehavior struct {
+	requests    int
+	score       float64
+	updated     time.Time
+	windowStart time.Time
+}
+
+// PeerScore is the misbehavior score of a sync peer
+type PeerScore struct {
+	BannedUntil *time.Time `json:"banned_until"` // Set while the peer is banned
+	ID          string     `json:"id"`
+	Score       float64    `json:"score"`
+}
+
+// peerScores tracks the misbehavior of sync peers
+// Parse errors and request floods add to a peer's score, which decays by the minute
+// A peer reaching the threshold is banned for a while and handed to onBan to be disconnected
+type peerScores struct {
+	sync.Mutex
+	banDuration time.Duration
+	bans        map[peer.ID]time.Time
+	decay       float64 // Points shed per minute
+	maxRequests int     // Sync requests allowed per minute
+	now         func() time.Time
+	onBan       func(peerID peer.ID)
+	peers       map[peer.ID]*misbehavior
+	threshold   float64
+}
+
+// newPeerScores will create a new peer score tracker
+func newPeerScores(threshold, decay float64, banDuration time.Duration, maxRequests int) *peerScores {
+	return &peerScores{
+		banDuration: banDuration,
+		bans:        make(map[peer.ID]time.Time),
+		decay:       decay,
+		maxRequests: maxRequests,
+		now:         time.Now,
+		peers:       make(map[peer.ID]*misbehavior),
+		threshold:   threshold,
+	}
+}
+
+// misbehavior returns the peer's record with its score decayed to now, the lock must be held
+func (p *peerScores) misbehavior(peerID peer.ID, now time.Time) *misbehavior {
+	m, ok := p.peers[peerID]
+	if !ok {
+		m = &misbehavior{updated: now, windowStart: now}
+		p.peers[peerID] = m
+	}
+	m.score = max(m.score-p.decay*now.Sub(m.updated).Minutes(), 0)
+	m.updated = now
+	return m
+}
+
+// Penalize adds the points to the peer's score and returns true if that got the peer banned
+func (p *peerScores) Penalize(peerID peer.ID, points float64) bool {
+	p.Lock()
+	now := p.now()
+	m := p.misbehavior(peerID, now)
+	m.score += points
+	banned := m.score >= p.threshold
+	if banned {
+		// The peer starts over with a clean score once the ban is served
+		p.bans[peerID] = now.Add(p.banDuration)
+		delete(p.peers, peerID)
+	}
+	onBan := p.onBan
+	p.Unlock()
+
+	if banned && onBan != nil {
+		onBan(peerID)
+	}
+	return banned
+}
+
+// Request counts a sync request from the peer, penalizing it when it's over the per-minute limit
+// Returns true if that got the peer banned
+func (p *peerScores) Request(peerID peer.ID) bool {
+	p.Lock()
+	now := p.now()
+	m := p.misbehavior(peerID, now)
+	if now.Sub(m.windowStart) >= time.Minute {
+		m.requests = 0
+		m.windowStart = now
+	}
+	m.requests++
+	flooding := m.requests > p.maxRequests
+	p.Unlock()
+
+	if !flooding {
+		return false
+	}
+	return p.Penalize(peerID, requestFloodPenalty)
+}
+
+// Score returns the peer's misbehavior score, decayed to now (0 for a peer that never misbehaved)
+func (p *peerScores) Score(peerID peer.ID) float64 {
+	p.Lock()
+	defer p.Unlock()
+	if _, ok := p.peers[peerID]; !ok {
+		return 0
+	}
+	return p.misbehavior(peerID, p.now()).score
+}
+
+// Banned returns true if the peer is still serving a ban
+func (p *peerScores) Banned(peerID peer.ID) bool {
+	p.Lock()
+	defer p.Unlock()
+	until, ok := p.bans[peerID]
+	if !ok {
+		return false
+	}
+	if !p.now().Before(until) {
+		delete(p.bans, peerID)
+		return false
+	}
+	return true
+}
+
+// Scores returns every peer with a misbehavior score or a ban, sorted by peer
+func (p *peerScores) Scores() []PeerScore {
+	p.Lock()
+	defer p.Unlock()
+	now := p.now()
+
+	scores := make([]PeerScore, 0, len(p.peers)+len(p.bans))
+	for peerID := range p.peers {
+		if m := p.misbehavior(peerID, now); m.score > 0 {
+			scores = append(scores, PeerScore{ID: peerID.String(), Score: m.score})
+		}
+	}
+	for peerID, until := range p.bans {
+		if !now.Before(until) {
+			delete(p.bans, peerID)
+			continue
+		}
+		bannedUntil := until
+		scores = append(scores, PeerScore{BannedUntil: &bannedUntil, ID: peerID.String(), Score: p.threshold})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].ID < scores[j].ID
+	})
+	return scores
+}
@@ -0,0 +1,139 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPeerScores will create a peer score tracker with a fake clock that records the banned peers
+func newTestPeerScores(maxRequests int) (*peerScores, *time.Time, *[]peer.ID) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	banned := make([]peer.ID, 0)
+	p := newPeerScores(100, 10, 30*time.Minute, maxRequests)
+	p.now = func() time.Time {
+		return now
+	}
+	p.onBan = func(peerID peer.ID) {
+		banned = append(banned, peerID)
+	}
+	return p, &now, &banned
+}
+
+// TestPeerScores_Penalize will test the method Penalize()
+func TestPeerScores_Penalize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bans at the threshold", func(t *testing.T) {
+		p, now, banned := newTestPeerScores(10)
+
+		for i := 0; i < 3; i++ {
+			assert.False(t, p.Penalize("peer-a", parseErrorPenalty))
+		}
+		assert.False(t, p.Banned("peer-a"))
+		assert.Empty(t, *banned)
+
+		assert.True(t, p.Penalize("peer-a", parseErrorPenalty))
+		assert.True(t, p.Banned("peer-a"))
+		assert.False(t, p.Banned("peer-b"))
+		assert.Equal(t, []peer.ID{"peer-a"}, *banned)
+
+		// The ban is served and the peer starts over with a clean score
+		*now = now.Add(30 * time.Minute)
+		assert.False(t, p.Banned("peer-a"))
+		assert.Empty(t, p.Scores())
+	})
+
+	t.Run("score decays by the minute", func(t *testing.T) {
+		p, now, banned := newTestPeerScores(10)
+
+		assert.False(t, p.Penalize("peer-a", 90))
+		*now = now.Add(2 * time.Minute)
+		assert.False(t, p.Penalize("peer-a", 25)) // 90 - 20 + 25
+		assert.Empty(t, *banned)
+
+		scores := p.Scores()
+		require.Len(t, scores, 1)
+		assert.InDelta(t, 95.0, scores[0].Score, 0.001)
+
+		// Never below zero
+		*now = now.Add(time.Hour)
+		assert.Empty(t, p.Scores())
+	})
+}
+
+// TestPeerScores_Request will test the method Request()
+func TestPeerScores_Request(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requests within the limit are free", func(t *testing.T) {
+		p, now, _ := newTestPeerScores(5)
+		for i := 0; i < 5; i++ {
+			assert.False(t, p.Request("peer-a"))
+		}
+		assert.Empty(t, p.Scores())
+
+		// A new minute starts a new window
+		*now = now.Add(time.Minute)
+		for i := 0; i < 5; i++ {
+			assert.False(t, p.Request("peer-a"))
+		}
+		assert.Empty(t, p.Scores())
+	})
+
+	t.Run("a flood of requests gets the peer banned", func(t *testing.T) {
+		p, _, banned := newTestPeerScores(5)
+
+		var bannedAt int
+		for i := 1; i <= 100 && bannedAt == 0; i++ {
+			if p.Request("peer-a") {
+				bannedAt = i
+			}
+		}
+		assert.Equal(t, 5+int(100/requestFloodPenalty), bannedAt)
+		assert.Equal(t, []peer.ID{"peer-a"}, *banned)
+	})
+}
+
+// TestPeerScores_Scores will test the method Scores()
+func TestPeerScores_Scores(t *testing.T) {
+	t.Parallel()
+
+	p, now, _ := newTestPeerScores(10)
+	assert.Empty(t, p.Scores())
+
+	p.Penalize("peer-c", 100)
+	p.Penalize("peer-b", 10)
+	p.Penalize("peer-a", 25)
+
+	scores := p.Scores()
+	require.Len(t, scores, 3)
+	assert.IsIncreasing(t, []string{scores[0].ID, scores[1].ID, scores[2].ID})
+
+	byID := make(map[string]PeerScore)
+	for _, score := range scores {
+		byID[score.ID] = score
+	}
+	bannedUntil := now.Add(30 * time.Minute)
+	assert.Equal(t, PeerScore{ID: peer.ID("peer-a").String(), Score: 25}, byID[peer.ID("peer-a").String()])
+	assert.Equal(t, PeerScore{ID: peer.ID("peer-b").String(), Score: 10}, byID[peer.ID("peer-b").String()])
+	assert.Equal(t, PeerScore{BannedUntil: &bannedUntil, ID: peer.ID("peer-c").String(), Score: 100}, byID[peer.ID("peer-c").String()])
+}
+
+// TestPeerScores_Score will test the method Score()
+func TestPeerScores_Score(t *testing.T) {
+	t.Parallel()
+
+	p, now, _ := newTestPeerScores(10)
+	assert.Zero(t, p.Score("peer-a"))
+
+	p.Penalize("peer-a", 25)
+	assert.InDelta(t, 25.0, p.Score("peer-a"), 0.001)
+
+	// The score decays by the minute
+	*now = now.Add(time.Minute)
+	assert.InDelta(t, 15.0, p.Score("peer-a"), 0.001)
+}
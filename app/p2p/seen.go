@@ -0,0 +1,59 @@
+package p2p
+
+import (
+	"container/list"
+	"sync"
+)
+
+// seenAlerts remembers the hashes of the most recent alerts received on the topics (least recently seen are evicted)
+// Well connected nodes get the same alert from several peers, only the first copy is processed
+type seenAlerts struct {
+	sync.Mutex
+	hashes map[string]*list.Element
+	order  *list.List // Most recently seen at the front
+	size   int
+}
+
+// newSeenAlerts will create a new seen alert cache holding up to size hashes
+func newSeenAlerts(size int) *seenAlerts {
+	return &seenAlerts{
+		hashes: make(map[string]*list.Element, size),
+		order:  list.New(),
+		size:   size,
+	}
+}
+
+// Seen returns true if the alert hash was already seen, otherwise it's recorded as seen
+func (s *seenAlerts) Seen(hash string) bool {
+	s.Lock()
+	defer s.Unlock()
+	if element, ok := s.hashes[hash]; ok {
+		s.order.MoveToFront(element)
+		return true
+	}
+	s.hashes[hash] = s.order.PushFront(hash)
+	if s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.hashes, oldest.Value.(string))
+	}
+	return false
+}
+
+// Forget removes an alert hash, so another copy of the alert is processed
+// The hash doesn't cover the signatures, so a copy that failed its checks mustn't block a valid one
+func (s *seenAlerts) Forget(hash string) {
+	s.Lock()
+	defer s.Unlock()
+	if element, ok := s.hashes[hash]; ok {
+		s.order.Remove(element)
+		delete(s.hashes, hash)
+	}
+}
+
+// Len returns the number of hashes remembered
+func (s *seenAlerts) Len() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.order.Len()
+}
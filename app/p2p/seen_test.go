@@ -0,0 +1,63 @@
+package p2p
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSeenAlerts_Seen will test the method Seen()
+func TestSeenAlerts_Seen(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a copy of an alert is seen", func(t *testing.T) {
+		s := newSeenAlerts(10)
+		assert.False(t, s.Seen("hash-1"))
+		assert.True(t, s.Seen("hash-1"))
+		assert.False(t, s.Seen("hash-2"))
+		assert.Equal(t, 2, s.Len())
+	})
+
+	t.Run("the least recently seen hash is evicted", func(t *testing.T) {
+		s := newSeenAlerts(2)
+		assert.False(t, s.Seen("hash-1"))
+		assert.False(t, s.Seen("hash-2"))
+		assert.True(t, s.Seen("hash-1")) // hash-2 is now the oldest
+		assert.False(t, s.Seen("hash-3"))
+
+		assert.Equal(t, 2, s.Len())
+		assert.True(t, s.Seen("hash-1"))
+		assert.False(t, s.Seen("hash-2"))
+	})
+
+	t.Run("a forgotten alert is processed again", func(t *testing.T) {
+		s := newSeenAlerts(10)
+		assert.False(t, s.Seen("hash-1"))
+		s.Forget("hash-1")
+		s.Forget("unknown")
+		assert.Zero(t, s.Len())
+		assert.False(t, s.Seen("hash-1"))
+	})
+
+	t.Run("only one of the concurrent copies is new", func(t *testing.T) {
+		s := newSeenAlerts(100)
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		fresh := 0
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if !s.Seen("hash-" + strconv.Itoa(i%5)) {
+					mu.Lock()
+					fresh++
+					mu.Unlock()
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.Equal(t, 5, fresh)
+	})
+}
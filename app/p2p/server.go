@@ -10,6 +10,10 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/libp2p/go-libp2p"
@@ -24,10 +28,10 @@ import (
 	drouting "github.com/libp2p/go-libp2p/p2p/discovery/routing"
 	dutil "github.com/libp2p/go-libp2p/p2p/discovery/util"
 	"github.com/libp2p/go-libp2p/p2p/net/conngater"
-	"github.com/mrz1836/go-datastore"
 	maddr "github.com/multiformats/go-multiaddr"
 
 	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/metrics"
 	"github.com/bsv-blockchain/go-alert-system/app/models"
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
 	"github.com/bsv-blockchain/go-alert-system/app/webhook"
@@ -56,9 +60,29 @@ type Server struct {
 	topics                        map[string]*pubsub.Topic
 	dht                           *dht.IpfsDHT
 	quitAlertProcessingChannel    chan bool
+	quitBackfillChannel           chan bool
 	quitPeerDiscoveryChannel      chan bool
 	quitPeerInitializationChannel chan bool
+	quitSyncRetryChannel          chan bool
+	quitRetryThreadsChannel       chan bool
+	quitStaticPeersChannel        chan bool
 	activePeers                   int
+	activity                      *peerActivity
+	backfill                      *syncBackfill
+	backoff                       *dialBackoff
+	clocks                        *peerClocks
+	dropped                       *droppedAlerts
+	pool                          *alertPool
+	progress                      *syncProgress
+	requests                      *requestTracker
+	retrier                       *syncRetrier
+	scores                        *peerScores
+	limits                        *peerLimits
+	seen                          *seenAlerts
+	static                        *staticPeers
+	stopping                      atomic.Bool // set once Stop is called, no new alerts are taken in
+	versions                      *peerVersions
+	webhooks                      *webhook.Dedup
 	// peers         []peer.AddrInfo
 }
 
@@ -87,10 +111,14 @@ func NewServer(o ServerOptions) (*Server, error) {
 		}
 	}
 
+	// The address to listen on, and the external address to advertise (behind NAT or in a container they differ)
+	listenAddr, err := tcpMultiaddr(o.Config.P2P.IP, o.Config.P2P.Port)
+	if err != nil {
+		return nil, err
+	}
 	var extMultiAddr maddr.Multiaddr
 	if o.Config.P2P.BroadcastIP != "" {
-		extMultiAddr, err = maddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%s", o.Config.P2P.BroadcastIP, o.Config.P2P.Port))
-		if err != nil {
+		if extMultiAddr, err = tcpMultiaddr(o.Config.P2P.BroadcastIP, advertisedPort(o.Config)); err != nil {
 			return nil, err
 		}
 	}
@@ -116,7 +144,7 @@ func NewServer(o ServerOptions) (*Server, error) {
 			// Ignore errors because we don't care if we can't find it
 			ifconfig, _ := GetPublicIP(context.Background())
 			if len(ifconfig) > 0 {
-				addr, _ := maddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%s", ifconfig, o.Config.P2P.Port))
+				addr, _ := tcpMultiaddr(strings.TrimSpace(ifconfig), advertisedPort(o.Config))
 				if addr != nil {
 					publicAddrs = append(publicAddrs, addr)
 				}
@@ -125,12 +153,32 @@ func NewServer(o ServerOptions) (*Server, error) {
 		return publicAddrs
 	}
 
+	// The static peers are kept connected, and exempt from the peer limits
+	staticAddrs, err := parseStaticPeers(o.Config.P2P.StaticPeers)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create an IP filter to block private network ranges from being dialed
 	ipFilter, err := conngater.NewBasicConnectionGater(nil)
 	if err != nil {
 		return nil, err
 	}
 
+	// Cap the inbound and outbound peers, after the IP filter
+	limits := &peerLimits{
+		ConnectionGater: ipFilter,
+		evict:           o.Config.P2P.EvictInboundPeers,
+		isStatic: func(peerID peer.ID) bool {
+			return slices.ContainsFunc(staticAddrs, func(info peer.AddrInfo) bool {
+				return info.ID == peerID
+			})
+		},
+		log:         o.Config.Services.Log,
+		maxInbound:  o.Config.P2P.MaxInboundPeers,
+		maxOutbound: o.Config.P2P.MaxOutboundPeers,
+	}
+
 	// By default, filter private IPs
 	if !o.Config.P2P.AllowPrivateIPs {
 		// Add private IP blocks to be filtered out
@@ -157,11 +205,11 @@ func NewServer(o ServerOptions) (*Server, error) {
 	// Create a new host
 	var h host.Host
 	if h, err = libp2p.New(
-		libp2p.ListenAddrStrings(fmt.Sprintf("/ip4/%s/tcp/%s", o.Config.P2P.IP, o.Config.P2P.Port)),
+		libp2p.ListenAddrs(listenAddr),
 		libp2p.Identity(*pk),
 		libp2p.EnableHolePunching(),
 		libp2p.AddrsFactory(addressFactory),
-		libp2p.ConnectionGater(ipFilter),
+		libp2p.ConnectionGater(limits),
 	); err != nil {
 		return nil, err
 	}
@@ -173,14 +221,91 @@ func NewServer(o ServerOptions) (*Server, error) {
 		o.Config.Services.Log.Infof(" %s/p2p/%s", addr, h.ID().String())
 	}
 
-	// Return the server
-	return &Server{
+	// Create the server
+	s := &Server{
 		host:                          h,
 		topicNames:                    o.TopicNames,
 		privateKey:                    pk,
 		config:                        o.Config,
 		quitPeerInitializationChannel: make(chan bool, 1),
-	}, nil
+		quitRetryThreadsChannel:       make(chan bool),
+		activity:                      newPeerActivity(),
+		backoff:                       newDialBackoff(o.Config.P2P.DialBackoffInitial, o.Config.P2P.DialBackoffMax),
+		clocks:                        newPeerClocks(),
+		dropped:                       newDroppedAlerts(o.Config),
+		limits:                        limits,
+		progress:                      newSyncProgress(o.Config.P2P.SyncStalenessWindow),
+		requests:                      newRequestTracker(o.Config.P2P.MaxInFlightSyncRequests, o.Config.P2P.SyncRequestTimeout),
+		seen:                          newSeenAlerts(o.Config.P2P.SeenAlertCacheSize),
+		scores:                        newPeerScores(o.Config.P2P.PeerScoreThreshold, o.Config.P2P.PeerScoreDecay, o.Config.P2P.PeerBanDuration, o.Config.P2P.MaxRequestsPerMinute),
+		versions:                      newPeerVersions(),
+		webhooks:                      webhook.NewDedup(o.Config.WebhookDedupWindow),
+	}
+
+	// Retry unanswered sync requests against the other connected peers
+	s.retrier = &syncRetrier{
+		log:        o.Config.Services.Log,
+		maxRetries: o.Config.P2P.MaxSyncRequestRetries,
+		peers:      h.Network().Peers,
+		requests:   s.requests,
+		send:       s.resendRequest,
+	}
+
+	// Process the alerts received on the topics with a bounded number of workers
+	s.pool = newAlertPool(o.Config.P2P.AlertWorkers, o.Config.P2P.AlertQueueSize, s.handleAlert)
+
+	// Request the sequences missing below our latest alert from the connected peers
+	s.backfill = &syncBackfill{
+		dropped: s.dropped,
+		find: func(ctx context.Context, from, to uint32) ([]uint32, error) {
+			return models.FindMissingSequences(ctx, from, to, model.WithAllDependencies(o.Config))
+		},
+		latest:    s.latestSequence,
+		log:       o.Config.Services.Log,
+		maxPerRun: o.Config.P2P.MaxBackfillRequests,
+		peers:     h.Network().Peers,
+		requests:  s.requests,
+		send:      s.resendRequest,
+	}
+
+	// Keep the connections to the static peers open, syncing from each one that is (re)connected
+	s.static = &staticPeers{
+		backoff: s.backoff,
+		connect: h.Connect,
+		connected: func(peerID peer.ID) bool {
+			return h.Network().Connectedness(peerID) == network.Connected
+		},
+		log: o.Config.Services.Log,
+		onConnect: func(ctx context.Context, peerID peer.ID) {
+			if t, syncErr := s.syncWithPeer(ctx, peerID); syncErr != nil {
+				o.Config.Services.Log.Debugf("failed to sync with static peer %s error: %s", peerID.String(), syncErr.Error())
+			} else {
+				o.Config.Services.Log.Infof("successfully synced up to %d from static peer %s", t.LatestSequence(), peerID.String())
+			}
+		},
+		peers: staticAddrs,
+	}
+	for _, info := range staticAddrs {
+		h.ConnManager().Protect(info.ID, staticPeerTag)
+	}
+
+	// Start limiting the peers, evicting by misbehavior score
+	limits.attach(h.Network().Conns, h.Network().ClosePeer, s.scores.Score)
+
+	// Disconnect misbehaving peers and refuse their connections until the ban is served
+	s.scores.onBan = func(peerID peer.ID) {
+		o.Config.Services.Log.Warnf("banning peer %s for %s after misbehaving during sync", peerID.String(), o.Config.P2P.PeerBanDuration.String())
+		if blockErr := ipFilter.BlockPeer(peerID); blockErr != nil {
+			o.Config.Services.Log.Errorf("failed to block peer %s: %s", peerID.String(), blockErr.Error())
+		}
+		_ = h.Network().ClosePeer(peerID)
+		time.AfterFunc(o.Config.P2P.PeerBanDuration, func() {
+			_ = ipFilter.UnblockPeer(peerID)
+		})
+	}
+
+	// Return the server
+	return s, nil
 }
 
 // GetPublicIP fetches the public IP address from ifconfig.me
@@ -246,6 +371,25 @@ func extractIPFromMultiaddr(addr maddr.Multiaddr) (string, error) {
 	return addr.ValueForProtocol(maddr.P_IP4)
 }
 
+// tcpMultiaddr will return the TCP multiaddr of the IPv4 or IPv6 address and port
+func tcpMultiaddr(ip, port string) (maddr.Multiaddr, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, ip)
+	} else if parsed.To4() != nil {
+		return maddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%s", parsed.String(), port))
+	}
+	return maddr.NewMultiaddr(fmt.Sprintf("/ip6/%s/tcp/%s", parsed.String(), port))
+}
+
+// advertisedPort will return the port to advertise to peers, the broadcast port or else the port the server binds to
+func advertisedPort(c *config.Config) string {
+	if c.P2P.BroadcastPort != "" {
+		return c.P2P.BroadcastPort
+	}
+	return c.P2P.Port
+}
+
 // Start the server and subscribe to all topics
 func (s *Server) Start(ctx context.Context) error {
 	s.config.Services.Log.Infof("p2p service initializing & starting")
@@ -256,6 +400,16 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 	s.dht = kademliaDHT
 
+	// Seed the sync progress with our latest sequence
+	if latest, latestErr := models.GetLatestAlert(ctx, nil, model.WithAllDependencies(s.config)); latestErr == nil && latest != nil {
+		s.progress.SetLocal(latest.SequenceNumber)
+	}
+
+	// Restore the alerts dropped before the restart, so the alerts after them aren't held back as gaps
+	if err = s.dropped.Load(ctx); err != nil {
+		s.config.Services.Log.Errorf("failed to load the dropped alerts: %s", err.Error())
+	}
+
 	// Advertise our existence so that other peers can find us
 	routingDiscovery := drouting.NewRoutingDiscovery(kademliaDHT)
 	for _, topicName := range s.topicNames {
@@ -265,8 +419,15 @@ func (s *Server) Start(ctx context.Context) error {
 	// initialize the channel before use in discoverPeers is called
 	s.RunPeerDiscovery(ctx, routingDiscovery)
 	s.quitAlertProcessingChannel = s.RunAlertProcessingCron(ctx)
-
-	ps, err := pubsub.NewGossipSub(ctx, s.host, pubsub.WithDiscovery(routingDiscovery))
+	s.quitSyncRetryChannel = s.RunSyncRequestRetry(ctx)
+	s.quitBackfillChannel = s.RunBackfill(ctx)
+	s.quitStaticPeersChannel = s.RunStaticPeers(ctx)
+	s.pool.Start(ctx)
+
+	ps, err := pubsub.NewGossipSub(ctx, s.host,
+		pubsub.WithDiscovery(routingDiscovery),
+		pubsub.WithGossipSubParams(gossipParams(s.config.P2P.GossipFanout)),
+	)
 	if err != nil {
 		return err
 	}
@@ -275,11 +436,28 @@ func (s *Server) Start(ctx context.Context) error {
 
 	s.host.SetStreamHandler(protocol.ID(s.config.P2P.AlertSystemProtocolID), func(stream network.Stream) {
 		s.config.Services.Log.Infof("received stream %v", stream.ID())
+		if s.stopping.Load() {
+			_ = stream.Reset()
+			return
+		}
+		if s.scores.Banned(stream.Conn().RemotePeer()) {
+			s.config.Services.Log.Debugf("refusing stream %v from banned peer %s", stream.ID(), stream.Conn().RemotePeer().String())
+			_ = stream.Reset()
+			return
+		}
 		t := StreamThread{
-			stream: stream,
-			config: s.config,
-			ctx:    ctx,
-			peer:   stream.Conn().RemotePeer(),
+			activity:      s.activity,
+			clocks:        s.clocks,
+			dropped:       s.dropped,
+			hasAlertPeers: s.HasAlertPeers,
+			stream:        stream,
+			config:        s.config,
+			ctx:           ctx,
+			peer:          stream.Conn().RemotePeer(),
+			progress:      s.progress,
+			requests:      s.requests,
+			scores:        s.scores,
+			versions:      s.versions,
 		}
 
 		if err = t.ProcessSyncMessage(ctx); err != nil {
@@ -311,6 +489,11 @@ OUTER:
 	}
 
 	for _, topicName := range s.topicNames {
+		// Don't deliver alerts of a disabled type, or relay them unless configured to
+		if err = ps.RegisterTopicValidator(topicName, s.validateAlertType); err != nil {
+			return err
+		}
+
 		var topic *pubsub.Topic
 		if topic, err = ps.Join(topicName); err != nil {
 			return err
@@ -349,18 +532,45 @@ func (s *Server) Connected() bool {
 }
 
 // Stop the server
-func (s *Server) Stop(_ context.Context) error {
-	// todo there needs to be a way to stop the server
+func (s *Server) Stop(ctx context.Context) error {
 	s.config.Services.Log.Infof("stopping the p2p server")
+
+	// Stop taking in new alerts, from streams and from the topics
+	s.stopping.Store(true)
+	s.config.Services.Log.Debugf("removing stream handler to stop allowing connections")
+	s.host.RemoveStreamHandler(protocol.ID(s.config.P2P.AlertSystemProtocolID))
+	for _, sub := range s.subscriptions {
+		sub.Cancel()
+	}
+
 	s.config.Services.Log.Debugf("sending signals to persistent processes...")
 	s.quitPeerDiscoveryChannel <- true
 	s.quitAlertProcessingChannel <- true
+	s.quitSyncRetryChannel <- true
+	s.quitBackfillChannel <- true
+	s.quitStaticPeersChannel <- true
+	close(s.quitRetryThreadsChannel)
 	s.quitPeerInitializationChannel <- true
 
-	s.config.Services.Log.Debugf("removing stream handler to stop allowing connections")
-	s.host.RemoveStreamHandler(protocol.ID(s.config.P2P.AlertSystemProtocolID))
+	// Let the workers finish the alerts already received, within the shutdown grace period
+	drained, abandoned := s.pool.Drain(ctx)
+	s.config.Services.Log.Infof("drained %d queued alerts, abandoned %d", drained, abandoned)
+
+	// Wait for the alert actions and webhook deliveries that are still running, within the shutdown grace period
+	s.config.Services.Log.Debugf("draining in-flight alert actions and webhook deliveries")
+	drained, abandoned = models.DrainAlertActions(ctx)
+	s.config.Services.Log.Infof("drained %d in-flight alert actions, abandoned %d", drained, abandoned)
+	drained, abandoned = webhook.DrainDeliveries(ctx)
+	s.config.Services.Log.Infof("drained %d in-flight webhook deliveries, abandoned %d", drained, abandoned)
+
 	s.config.Services.Log.Debugf("shutting down libp2p host")
 	err := s.host.Close()
+
+	// Wait for the audit entries of alert actions that were still running, within the shutdown grace period
+	s.config.Services.Log.Debugf("flushing the audit log")
+	if flushErr := models.FlushAuditLog(ctx); flushErr != nil {
+		s.config.Services.Log.Errorf("audit log flush incomplete: %s", flushErr.Error())
+	}
 	if err != nil {
 		return err
 	}
@@ -476,6 +686,20 @@ func (s *Server) Topics() map[string]*pubsub.Topic {
 	return s.topics
 }
 
+// PublishAlert will publish a serialized alert on the alert topics, the peers process and relay it like any alert they
+// receive. Our own subscription skips it, so the alert must already be stored
+func (s *Server) PublishAlert(ctx context.Context, raw []byte) error {
+	if len(s.topics) == 0 {
+		return ErrNoAlertTopics
+	}
+	for name, topic := range s.topics {
+		if err := topic.Publish(ctx, raw); err != nil {
+			return fmt.Errorf("failed to publish alert on %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
 // Subscribe will subscribe to the alert system
 func (s *Server) Subscribe(ctx context.Context, subscriber *pubsub.Subscription, hostID peer.ID) {
 	s.config.Services.Log.Infof("subscribed to %s topic", subscriber.Topic())
@@ -483,7 +707,7 @@ func (s *Server) Subscribe(ctx context.Context, subscriber *pubsub.Subscription,
 
 		msg, err := subscriber.Next(ctx)
 		if err != nil {
-			if ctx.Err() != nil {
+			if ctx.Err() != nil || s.stopping.Load() {
 				return
 			}
 			s.config.Services.Log.Infof("error subscribing via next: %s", err.Error())
@@ -494,6 +718,9 @@ func (s *Server) Subscribe(ctx context.Context, subscriber *pubsub.Subscription,
 		if msg.ReceivedFrom == hostID {
 			continue
 		}
+		if s.activity != nil {
+			s.activity.Seen(msg.ReceivedFrom)
+		}
 
 		// Read the alert key header
 		var ak *models.AlertMessage
@@ -502,87 +729,218 @@ func (s *Server) Subscribe(ctx context.Context, subscriber *pubsub.Subscription,
 			continue
 		}
 
-		// Set the hash
-		ak.SerializeData()
-
-		// Ensure signatures are valid
-		var valid bool
-		if valid, err = ak.AreSignaturesValid(ctx); err != nil {
-			s.config.Services.Log.Infof("error verifying signatures: %s", err.Error())
+		// An alert of a disabled type only got here to be relayed, the validator already counted it as dropped
+		if ak.CheckTypeEnabled() != nil {
 			continue
 		}
+		metrics.AlertsReceived.WithLabelValues(ak.GetAlertType().String()).Inc()
 
-		// Ensure the signature is valid
-		if !valid {
-			// TODO save these messages still and ban the peer?
-			s.config.Services.Log.Info("signature block is invalid")
+		// Set the hash, and drop the copies of an alert gossiped by several peers
+		ak.SerializeData()
+		if s.seen.Seen(ak.Hash) {
+			s.config.Services.Log.Debugf("dropping alert %d already received (%s)", ak.SequenceNumber, ak.Hash)
 			continue
 		}
 
-		// Ensure the sequence number is correct
-		if _, err = models.GetAlertMessageBySequenceNumber(
-			ctx, ak.SequenceNumber-1, model.WithAllDependencies(s.config),
-		); err != nil {
-			// TODO save these messages still and ban the peer? and possibly resync
-			s.config.Services.Log.Errorf("failed to find prior sequenced alert (num %d): %s", ak.SequenceNumber-1, err.Error())
-			continue
+		// Hand the alert to the worker pool, this waits while the queue is full
+		if !s.pool.Submit(ctx, ak, msg.ReceivedFrom, subscriber.Topic()) {
+			return
 		}
+	}
+}
 
-		// Check if the alert already exists
-		var dup *models.AlertMessage
-		if dup, err = models.GetAlertMessageBySequenceNumber(
-			ctx, ak.SequenceNumber, model.WithAllDependencies(s.config),
-		); err == nil && dup != nil && len(dup.Hash) > 0 {
-			// TODO save these messages still?
-			s.config.Services.Log.Errorf("alert %s already has sequence number %d", dup.Hash, ak.SequenceNumber)
-			continue
-		}
+// handleAlert will check and process an alert received on a topic, it's run by the worker pool
+func (s *Server) handleAlert(ctx context.Context, job *alertJob) {
+	ak := job.alert
 
-		// Did we get a real error?
-		if err != nil && !errors.Is(err, datastore.ErrNoResults) {
-			s.config.Services.Log.Errorf("error looking for duplicate alert: %s", err.Error())
-			continue
-		}
+	// Ensure signatures are valid, a copy with valid signatures can still be processed
+	valid, err := ak.AreSignaturesValid(ctx)
+	if err != nil {
+		s.config.Services.Log.Infof("error verifying signatures: %s", err.Error())
+		s.seen.Forget(ak.Hash)
+		return
+	}
 
-		// Process the alert message into the correct interface
-		am := ak.ProcessAlertMessage()
-		if err = am.Read(ak.GetRawMessage()); err != nil {
-			s.config.Services.Log.Errorf("failed to read message: %s", err.Error())
-			continue
-		}
-		ak.Processed = true
+	// Ensure the signature is valid
+	if !valid {
+		// TODO save these messages still and ban the peer?
+		s.config.Services.Log.Info("signature block is invalid")
+		s.seen.Forget(ak.Hash)
+		return
+	}
 
-		// Perform alert action
-		if err = am.Do(ctx); err != nil {
-			s.config.Services.Log.Errorf("failed to do alert action: %s", err.Error())
-			ak.Processed = false
+	// Ensure the sequence number is correct (the prior alert may have been dropped for its disabled type)
+	if _, err = models.GetAlertMessageBySequenceNumber(
+		ctx, ak.SequenceNumber-1, model.WithAllDependencies(s.config),
+	); err != nil && !s.dropped.Dropped(ak.SequenceNumber-1) {
+		// TODO save these messages still and ban the peer? and possibly resync
+		s.config.Services.Log.Errorf("failed to find prior sequenced alert (num %d): %s", ak.SequenceNumber-1, err.Error())
+		s.seen.Forget(ak.Hash)
+		return
+	}
+
+	// Execute and store the alert, unless it's a duplicate or couldn't be read
+	if !s.processReceivedAlert(ctx, ak, job.from) {
+		return
+	}
+
+	s.config.Services.Log.Infof("[%s] got alert type: %s, from: %s", job.topic, ak.GetAlertType(), job.from.String())
+
+	// Send the webhook
+	s.sendWebhook(ctx, ak)
+}
+
+// processReceivedAlert will execute and save an alert received on the topic, returning false if it was skipped
+// The sequence is locked until the alert is saved, so the same alert arriving by a sync at the same time runs once
+func (s *Server) processReceivedAlert(ctx context.Context, ak *models.AlertMessage, from peer.ID) bool {
+	unlock := models.LockSequence(ak.SequenceNumber)
+	defer unlock()
+
+	// Check if the alert already exists
+	dup, err := models.GetAlertMessageBySequenceNumber(
+		ctx, ak.SequenceNumber, model.WithAllDependencies(s.config),
+	)
+	if err == nil && dup != nil && len(dup.Hash) > 0 {
+		// TODO save these messages still?
+		s.config.Services.Log.Errorf("alert %s already has sequence number %d", dup.Hash, ak.SequenceNumber)
+		return false
+	}
+
+	// Did we get a real error?
+	if err != nil && !errors.Is(err, models.ErrAlertNotFound) {
+		s.config.Services.Log.Errorf("error looking for duplicate alert: %s", err.Error())
+		return false
+	}
+
+	// Process the alert message into the correct interface
+	var am models.AlertBody
+	if am, err = ak.ProcessAlertMessage(); err != nil {
+		s.config.Services.Log.Errorf("failed to read message: %s", err.Error())
+		return false
+	} else if am == nil {
+		s.saveUnknownAlertType(ctx, ak)
+		return false
+	}
+	ak.Processed = true
+	source := models.AuditSourceGossip + ":" + from.String()
+
+	// Sanity check the new alert, a rejected alert is stored (so it's not synced and run later) but never executed
+	// With too few peers to trust it yet, it's stored for the alert processing to execute once enough are connected
+	if !s.HasAlertPeers() {
+		s.config.Services.Log.Infof("storing alert %d unprocessed until %d peers are connected", ak.SequenceNumber, s.config.P2P.MinAlertPeers)
+		ak.Processed = false
+	} else if err = models.CheckReceivedAlert(ctx, am); err != nil {
+		s.config.Services.Log.Errorf("rejected alert %d: %s", ak.SequenceNumber, err.Error())
+		if auditErr := models.RecordAuditEntry(ctx, ak, am, source, err); auditErr != nil {
+			s.config.Services.Log.Errorf("failed to record audit entry for alert %d: %s", ak.SequenceNumber, auditErr.Error())
 		}
+	} else if err = models.ExecuteAlertAction(ctx, ak, am, source); errors.Is(err, models.ErrPartialSuccess) {
+		// The node applied part of the alert, the rejected outpoints won't succeed on a retry
+		s.config.Services.Log.Warnf("alert %d partially applied: %s", ak.SequenceNumber, err.Error())
+	} else if errors.Is(err, models.ErrAlertInvalid) {
+		// The alert breaks the rules of its type, it would fail the same way on a retry
+		s.config.Services.Log.Errorf("rejected alert %d: %s", ak.SequenceNumber, err.Error())
+	} else if errors.Is(err, models.ErrSequenceProcessed) {
+		// Already executed by a sync of the same alert (which stored it)
+		s.config.Services.Log.Debugf("alert %d was already processed by another delivery", ak.SequenceNumber)
+		return false
+	} else if err != nil {
+		// Perform alert action
+		s.config.Services.Log.Errorf("failed to do alert action: %s", err.Error())
+		ak.Processed = false
+	}
 
-		// Save the alert message
-		if err = ak.Save(ctx); err != nil {
-			s.config.Services.Log.Errorf("failed to save alert message: %s", err.Error())
+	// Save the alert message
+	if err = ak.Save(ctx); err != nil {
+		s.config.Services.Log.Errorf("failed to save alert message: %s", err.Error())
+	} else {
+		s.progress.SetLocal(ak.SequenceNumber)
+		if err = models.MarkSupersededAlert(ctx, ak); err != nil {
+			s.config.Services.Log.Errorf("failed to mark alert %d as superseded by %d: %s", ak.Supersedes, ak.SequenceNumber, err.Error())
 		}
+	}
 
-		s.config.Services.Log.Infof("[%s] got alert type: %d, from: %s", subscriber.Topic(), ak.GetAlertType(), msg.ReceivedFrom.String())
+	return true
+}
 
-		// Send the webhook
-		if len(s.config.AlertWebhookURL) > 0 {
-			if err = webhook.PostAlert(ctx, s.config.Services.HTTPClient, s.config.AlertWebhookURL, ak); err != nil {
-				s.config.Services.Log.Errorf("error processing webhook request: %s", err.Error())
-			}
+// sendWebhook will send the webhook for the alert, unless it was already sent within the de-duplication window
+// A delivery that fails isn't counted as sent, so the alert's webhook is sent again the next time it's processed
+func (s *Server) sendWebhook(ctx context.Context, ak *models.AlertMessage) {
+	if len(s.config.AlertWebhookURL) == 0 {
+		return
+	}
+	if s.webhooks != nil && !s.webhooks.Allow(ak.Hash) {
+		s.config.Services.Log.Debugf("webhook already sent for alert %d, skipping", ak.SequenceNumber)
+		return
+	}
+	if err := webhook.PostAlert(ctx, s.config.Services.HTTPClient, s.config.Webhook, s.config.AlertWebhookURL, ak); err != nil {
+		s.config.Services.Log.Errorf("error processing webhook request: %s", err.Error())
+		if s.webhooks != nil {
+			s.webhooks.Forget(ak.Hash)
 		}
 	}
 }
 
+// validateAlertType is the topic validator that drops alerts of a disabled type, so they are neither processed nor relayed
+// With RelayDisabledAlerts a genuine one is accepted so it's relayed, the subscriber skips it. Anything else is
+// accepted here and checked by the subscriber
+func (s *Server) validateAlertType(ctx context.Context, _ peer.ID, msg *pubsub.Message) bool {
+	ak, err := models.NewAlertFromBytes(msg.Data, model.WithAllDependencies(s.config))
+	if err != nil {
+		return true
+	}
+	var reason error
+	if reason = ak.CheckTypeEnabled(); reason == nil {
+		return true
+	}
+
+	// Only a genuine alert is counted, so a forged one can't fill the gap in the sequence
+	ak.SerializeData()
+	if valid, _ := ak.AreSignaturesValid(ctx); !valid {
+		return false
+	}
+	s.dropped.Drop(ctx, ak)
+	if s.config.P2P.RelayDisabledAlerts {
+		s.config.Services.Log.Infof("relaying alert from %s without executing it: %s", msg.ReceivedFrom.String(), reason.Error())
+		return true
+	}
+	s.config.Services.Log.Infof("dropping alert from %s: %s", msg.ReceivedFrom.String(), reason.Error())
+	return false
+}
+
+// saveUnknownAlertType will save an alert of an unknown type (or newer version) unparsed so it can be served to syncing peers
+// In strict mode an unknown type is dropped instead, an alert of a newer version is always saved
+func (s *Server) saveUnknownAlertType(ctx context.Context, ak *models.AlertMessage) {
+	if !ak.SupportedVersion() {
+		s.config.Services.Log.Infof("relaying alert %d with newer version %d unparsed", ak.SequenceNumber, ak.Version())
+	} else if s.config.P2P.StrictSyncMessageTypes {
+		s.config.Services.Log.Errorf("%s: type %d at sequence %d", ErrUnknownSyncMessageType.Error(), ak.GetAlertType(), ak.SequenceNumber)
+		return
+	} else {
+		s.config.Services.Log.Infof("relaying alert %d with unknown type %d unparsed", ak.SequenceNumber, ak.GetAlertType())
+	}
+	ak.Processed = false
+	if err := ak.Save(ctx); err != nil {
+		s.config.Services.Log.Errorf("failed to save alert message: %s", err.Error())
+	}
+}
+
 // processAlerts performs the alert processing
 func (s *Server) processAlerts(ctx context.Context) error {
 	alerts, err := models.GetAllUnprocessedAlerts(ctx, nil, model.WithAllDependencies(s.config))
 	if err != nil {
 		return err
 	}
+	if !s.HasAlertPeers() {
+		s.config.Services.Log.Infof("not processing %d failed alerts until %d peers are connected", len(alerts), s.config.P2P.MinAlertPeers)
+		return nil
+	}
 	s.config.Services.Log.Infof("Attempting to process %d failed alerts", len(alerts))
 	success := 0
 	for _, alert := range alerts {
+		if s.stopping.Load() {
+			break
+		}
 		alert.SetOptions(model.WithAllDependencies(s.config))
 		// Serialize the alert data and hash
 		err := alert.ReadRaw()
@@ -591,32 +949,53 @@ func (s *Server) processAlerts(ctx context.Context) error {
 		}
 		alert.SerializeData()
 		// Process the alert
-		ak := alert.ProcessAlertMessage()
-		if ak == nil {
+		var ak models.AlertBody
+		if ak, err = alert.ProcessAlertMessage(); err != nil {
+			return err
+		} else if ak == nil {
 			continue
 		}
-		if err = ak.Read(alert.GetRawMessage()); err != nil {
+		s.config.Services.Log.Debugf("attempting to process alert %d of type %s", alert.SequenceNumber, alert.GetAlertType())
+		if err = s.retryAlert(ctx, alert, ak); err != nil {
 			return err
-		}
-		s.config.Services.Log.Debugf("attempting to process alert %d of type %d", alert.SequenceNumber, alert.GetAlertType())
-		alert.Processed = true
-		if err = ak.Do(ctx); err != nil {
-			s.config.Services.Log.Errorf("failed to process alert %d; err: %v", alert.SequenceNumber, err.Error())
-			alert.Processed = false
-		}
-
-		if alert.Processed {
+		} else if alert.Processed {
 			success++
-			// Save the alert
-			if err = alert.Save(ctx); err != nil {
-				return err
-			}
 		}
 	}
 	s.config.Services.Log.Infof("Processed %d failed alerts", success)
+	metrics.UnprocessedAlerts.Set(float64(len(alerts) - success))
 	return nil
 }
 
+// retryAlert will perform the action of an unprocessed alert again, saving it if it's now processed
+// The sequence is locked until the alert is saved, so a delivery of the same alert from a peer can't run it as well
+func (s *Server) retryAlert(ctx context.Context, alert *models.AlertMessage, ak models.AlertBody) error {
+	unlock := models.LockSequence(alert.SequenceNumber)
+	defer unlock()
+
+	alert.Processed = true
+	if err := models.ExecuteAlertAction(ctx, alert, ak, models.AuditSourceRetry); errors.Is(err, models.ErrPartialSuccess) {
+		s.config.Services.Log.Warnf("alert %d partially applied: %s", alert.SequenceNumber, err.Error())
+	} else if errors.Is(err, models.ErrAlertInvalid) {
+		s.config.Services.Log.Errorf("rejected alert %d: %s", alert.SequenceNumber, err.Error())
+	} else if errors.Is(err, models.ErrAlertNotVerified) {
+		// Stored by a lazy sync and its signatures don't check out, it's kept flagged and never executed
+		s.config.Services.Log.Errorf("alert %d was stored unverified and has invalid signatures", alert.SequenceNumber)
+		alert.Processed = false
+	} else if errors.Is(err, models.ErrSequenceProcessed) {
+		// Another copy of the alert was already executed, this one is marked processed without running it again
+		s.config.Services.Log.Debugf("alert %d was already processed by another delivery", alert.SequenceNumber)
+	} else if err != nil {
+		s.config.Services.Log.Errorf("failed to process alert %d; err: %v", alert.SequenceNumber, err.Error())
+		alert.Processed = false
+	}
+
+	if !alert.Processed {
+		return nil
+	}
+	return alert.Save(ctx)
+}
+
 // discoverPeers discovers and connects to peers
 func (s *Server) discoverPeers(ctx context.Context, routingDiscovery *drouting.RoutingDiscovery) error {
 	s.config.Services.Log.Infof("Running peer discovery at %s", time.Now().String())
@@ -652,37 +1031,42 @@ OUTER:
 							continue // No self-connection
 						}
 
+						// Leave dialing a static peer to the static peer connections, it's only synced here once connected
+						if s.static.Contains(foundPeer.ID) && !s.static.connected(foundPeer.ID) {
+							s.config.Services.Log.Debugf("skipping %s, static peers are dialed separately", foundPeer.ID.String())
+							continue
+						}
+
+						// Don't redial a peer that is still backing off
+						if !s.backoff.Ready(foundPeer.ID.String()) {
+							s.config.Services.Log.Debugf("skipping %s, backing off after failed connections", foundPeer.ID.String())
+							continue
+						}
+
+						// Don't redial a peer that is banned for misbehaving
+						if s.scores.Banned(foundPeer.ID) {
+							s.config.Services.Log.Debugf("skipping %s, banned for misbehaving", foundPeer.ID.String())
+							continue
+						}
+
 						// Failed to connect to peer
 						s.config.Services.Log.Debugf("attempting connection to %s", foundPeer.ID.String())
 
 						if err = s.host.Connect(ctx, foundPeer); err != nil {
 							// we fail to connect to a lot of peers. Ignore it for now.
-							s.config.Services.Log.Debugf("failed connecting to %s, error: %s", foundPeer.ID.String(), err.Error())
+							wait := s.backoff.Failure(foundPeer.ID.String())
+							s.config.Services.Log.Debugf("failed connecting to %s, retrying in %s, error: %s", foundPeer.ID.String(), wait.String(), err.Error())
 							continue
 						}
+						s.backoff.Success(foundPeer.ID.String())
 
 						// Connected to peer
 						s.config.Services.Log.Infof("connected to: %s", foundPeer.ID.String())
 
-						// Open a stream to the peer
-						var stream network.Stream
-						if stream, err = s.host.NewStream(ctx, foundPeer.ID, protocol.ID(s.config.P2P.AlertSystemProtocolID)); err != nil {
-							s.config.Services.Log.Debugf("failed new stream to %s error: %s", foundPeer.ID.String(), err.Error())
-							continue
-						}
-
-						// Sync the stream thread
-						t := StreamThread{
-							config:      s.config,
-							ctx:         ctx,
-							peer:        foundPeer.ID,
-							stream:      stream,
-							quitChannel: s.quitPeerDiscoveryChannel,
-						}
-
-						// Sync the stream thread
-						if err = t.Sync(ctx); err != nil {
-							s.config.Services.Log.Debugf("failed to start stream thread to %s error: %s", foundPeer.ID.String(), err.Error())
+						// Open a stream to the peer and sync
+						var t *StreamThread
+						if t, err = s.syncWithPeer(ctx, foundPeer.ID); err != nil {
+							s.config.Services.Log.Debugf("failed to sync with %s error: %s", foundPeer.ID.String(), err.Error())
 							continue
 						}
 
@@ -705,6 +1089,324 @@ OUTER:
 	s.config.Services.Log.Debugf("peerstore has %d peers\n", len(s.host.Peerstore().Peers()))
 	s.config.Services.Log.Infof("Successfully discovered %d active peers at %s", connected, time.Now().String())
 	s.activePeers = connected
+	metrics.ActivePeers.Set(float64(connected))
 	s.connected = true
 	return nil
 }
+
+// syncWithPeer opens a new stream to the peer and syncs any missing alerts from it
+func (s *Server) syncWithPeer(ctx context.Context, peerID peer.ID) (*StreamThread, error) {
+	stream, err := s.host.NewStream(ctx, peerID, protocol.ID(s.config.P2P.AlertSystemProtocolID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Sync the stream thread
+	t := &StreamThread{
+		activity:      s.activity,
+		clocks:        s.clocks,
+		dropped:       s.dropped,
+		config:        s.config,
+		ctx:           ctx,
+		hasAlertPeers: s.HasAlertPeers,
+		peer:          peerID,
+		stream:        stream,
+		progress:      s.progress,
+		quitChannel:   s.quitPeerDiscoveryChannel,
+		requests:      s.requests,
+		scores:        s.scores,
+		versions:      s.versions,
+	}
+	if err = t.Sync(ctx); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// resendRequest opens a new stream to the peer and re-sends a sync request that another peer never answered
+// The response is processed in the background, so the retry cron is never blocked on a peer
+func (s *Server) resendRequest(ctx context.Context, peerID peer.ID, msg *SyncMessage) error {
+	// The alert may have arrived some other way (gossip) since the request was sent
+	if msg.Type == IWantSequenceNumber {
+		if a, err := models.GetAlertMessageBySequenceNumber(ctx, msg.SequenceNumber, model.WithAllDependencies(s.config)); err == nil && a != nil {
+			s.requests.Complete(peerID, &SyncMessage{Type: IGotSequenceNumber, SequenceNumber: msg.SequenceNumber})
+			return nil
+		}
+	}
+
+	stream, err := s.host.NewStream(ctx, peerID, protocol.ID(s.config.P2P.AlertSystemProtocolID))
+	if err != nil {
+		return err
+	}
+
+	t := &StreamThread{
+		activity:       s.activity,
+		clocks:         s.clocks,
+		dropped:        s.dropped,
+		config:         s.config,
+		ctx:            ctx,
+		hasAlertPeers:  s.HasAlertPeers,
+		latestSequence: max(msg.SequenceNumber, msg.EndSequence), // stop once the requested sequences are received
+		peer:           peerID,
+		stream:         stream,
+		progress:       s.progress,
+		quitChannel:    s.quitRetryThreadsChannel,
+		requests:       s.requests,
+		scores:         s.scores,
+		versions:       s.versions,
+	}
+	if err = t.writeRequest(msg); err != nil {
+		_ = stream.Close()
+		return err
+	}
+
+	go func() {
+		defer func() {
+			_ = stream.Close()
+		}()
+		if processErr := t.ProcessSyncMessage(ctx); processErr != nil {
+			s.config.Services.Log.Debugf("failed to process retried sync request from %s: %s", peerID.String(), processErr.Error())
+		}
+	}()
+	return nil
+}
+
+// RunSyncRequestRetry starts a cron job to retry sync requests that peers never answered
+func (s *Server) RunSyncRequestRetry(ctx context.Context) chan bool {
+	ticker := time.NewTicker(s.config.P2P.SyncRequestTimeout)
+	quit := make(chan bool, 1)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.retrier.Retry(ctx, time.Now())
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			case <-quit:
+				s.config.Services.Log.Infof("stopping sync request retry process")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return quit
+}
+
+// RunBackfill starts a cron job to request the sequences missing below our latest alert from peers
+func (s *Server) RunBackfill(ctx context.Context) chan bool {
+	ticker := time.NewTicker(s.config.P2P.BackfillInterval)
+	quit := make(chan bool, 1)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.backfill.Run(ctx); err != nil {
+					s.config.Services.Log.Errorf("error backfilling missing sequences: %s", err.Error())
+				}
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			case <-quit:
+				s.config.Services.Log.Infof("stopping backfill process")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return quit
+}
+
+// RunStaticPeers starts a cron job to redial the static peers we aren't connected to
+func (s *Server) RunStaticPeers(ctx context.Context) chan bool {
+	ticker := time.NewTicker(s.config.P2P.StaticPeerInterval)
+	quit := make(chan bool, 1)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.static.Run(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			case <-quit:
+				s.config.Services.Log.Infof("stopping static peer process")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return quit
+}
+
+// latestSequence returns the sequence of our latest stored alert, 0 if there are none
+func (s *Server) latestSequence(ctx context.Context) (uint32, error) {
+	latest, err := models.GetLatestAlert(ctx, nil, model.WithAllDependencies(s.config))
+	if errors.Is(err, models.ErrLatestAlertNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return latest.SequenceNumber, nil
+}
+
+// BackfillRequests returns the number of requests for missing sequences waiting on a response from a peer
+func (s *Server) BackfillRequests() int {
+	if s.backfill == nil {
+		return 0
+	}
+	return s.backfill.Outstanding()
+}
+
+// InFlightSyncRequests returns the number of sync requests waiting on a response from a peer
+func (s *Server) InFlightSyncRequests() int {
+	if s.requests == nil {
+		return 0
+	}
+	return s.requests.Total()
+}
+
+// AbandonedSyncRequests returns the number of sync requests that were given up on after retrying
+func (s *Server) AbandonedSyncRequests() uint64 {
+	if s.retrier == nil {
+		return 0
+	}
+	return s.retrier.Abandoned()
+}
+
+// SyncProgress returns our latest sequence, the highest sequence advertised by peers and the percent synced
+func (s *Server) SyncProgress() (current, target uint32, pct float64) {
+	if s.progress == nil {
+		return 0, 0, 100
+	}
+	return s.progress.Progress()
+}
+
+// HasAlertPeers returns true if we are connected to enough peers to execute an alert received over P2P
+func (s *Server) HasAlertPeers() bool {
+	if s.config.P2P.MinAlertPeers <= 0 {
+		return true
+	}
+	return s.host != nil && len(s.host.Network().Peers()) >= s.config.P2P.MinAlertPeers
+}
+
+// Synced returns true if we have every alert the connected peers advertised within the staleness window
+// It also returns the highest sequence those peers advertised and our own latest sequence
+func (s *Server) Synced() (synced bool, highestKnown, local uint32) {
+	if s.progress == nil {
+		return true, 0, 0
+	}
+	var connected []peer.ID
+	if s.host != nil {
+		connected = s.host.Network().Peers()
+	}
+	return s.progress.Synced(connected)
+}
+
+// PeerInfo is a connected peer and what we know about it
+type PeerInfo struct {
+	Address          string     `json:"address"`            // Remote address of the connection to the peer
+	ClockSkewSeconds *int64     `json:"clock_skew_seconds"` // Peer clock minus ours, nil if it was never measured
+	Direction        string     `json:"direction"`          // Who opened the connection, inbound or outbound
+	HighestSequence  *uint32    `json:"highest_sequence"`   // Latest sequence the peer advertised, nil if it never did
+	ID               string     `json:"id"`
+	LastSeen         *time.Time `json:"last_seen"`        // When we last received a sync or gossip message from the peer
+	ProtocolVersion  *uint32    `json:"protocol_version"` // Sync protocol version negotiated with the peer, nil if it never said hello
+	Score            float64    `json:"score"`            // Current misbehavior score of the peer
+}
+
+// Peers returns the connected peers and what we know about each, sorted by peer ID
+func (s *Server) Peers() []PeerInfo {
+	peers := make([]PeerInfo, 0)
+	if s.host == nil {
+		return peers
+	}
+	for _, peerID := range s.host.Network().Peers() {
+		info := PeerInfo{ID: peerID.String()}
+		if conns := s.host.Network().ConnsToPeer(peerID); len(conns) > 0 {
+			info.Address = conns[0].RemoteMultiaddr().String()
+			info.Direction = peerDirection(conns[0].Stat().Direction)
+		}
+		if s.activity != nil {
+			if at, ok := s.activity.LastSeen(peerID); ok {
+				info.LastSeen = &at
+			}
+		}
+		if s.clocks != nil {
+			if skew, ok := s.clocks.Skew(peerID); ok {
+				seconds := int64(skew / time.Second)
+				info.ClockSkewSeconds = &seconds
+			}
+		}
+		if s.progress != nil {
+			if sequence, ok := s.progress.Advertised(peerID); ok {
+				info.HighestSequence = &sequence
+			}
+		}
+		if s.scores != nil {
+			info.Score = s.scores.Score(peerID)
+		}
+		if s.versions != nil {
+			if version, ok := s.versions.Version(peerID); ok {
+				info.ProtocolVersion = &version
+			}
+		}
+		peers = append(peers, info)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ID < peers[j].ID
+	})
+	return peers
+}
+
+// peerDirection returns the name of the connection direction, as reported on the health endpoint
+func peerDirection(direction network.Direction) string {
+	switch direction {
+	case network.DirInbound:
+		return "inbound"
+	case network.DirOutbound:
+		return "outbound"
+	case network.DirUnknown:
+	}
+	return "unknown"
+}
+
+// DroppedAlerts returns the number of alerts dropped on receipt per disabled alert type
+func (s *Server) DroppedAlerts() map[models.AlertType]uint64 {
+	if s.dropped == nil {
+		return make(map[models.AlertType]uint64)
+	}
+	return s.dropped.Counts()
+}
+
+// DialBackoff returns the peer addresses we are backing off from dialing after failed connections
+func (s *Server) DialBackoff() []DialBackoff {
+	if s.backoff == nil {
+		return make([]DialBackoff, 0)
+	}
+	return s.backoff.State()
+}
+
+// PeerCounts returns the connected peers per direction that count against the peer limits, and the limits
+func (s *Server) PeerCounts() PeerCounts {
+	if s.limits == nil {
+		return PeerCounts{}
+	}
+	return s.limits.Counts()
+}
+
+// StaticPeers returns the configured static peers and whether we are connected to each
+func (s *Server) StaticPeers() []StaticPeer {
+	if s.static == nil {
+		return make([]StaticPeer, 0)
+	}
+	return s.static.Status()
+}
+
+// PeerScores returns the misbehavior score of every sync peer that has one, and the peers that are banned
+func (s *Server) PeerScores() []PeerScore {
+	if s.scores == nil {
+		return make([]PeerScore, 0)
+	}
+	return s.scores.Scores()
+}
@@ -0,0 +1,196 @@
+package p2p
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// TestTCPMultiaddr will test the method tcpMultiaddr()
+func TestTCPMultiaddr(t *testing.T) {
+	t.Parallel()
+
+	addr, err := tcpMultiaddr("0.0.0.0", "9906")
+	require.NoError(t, err)
+	assert.Equal(t, "/ip4/0.0.0.0/tcp/9906", addr.String())
+
+	addr, err = tcpMultiaddr("::", "9906")
+	require.NoError(t, err)
+	assert.Equal(t, "/ip6/::/tcp/9906", addr.String())
+
+	addr, err = tcpMultiaddr("::ffff:203.0.113.7", "19906")
+	require.NoError(t, err)
+	assert.Equal(t, "/ip4/203.0.113.7/tcp/19906", addr.String())
+
+	_, err = tcpMultiaddr("localhost", "9906")
+	require.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+// TestAdvertisedPort will test the method advertisedPort()
+func TestAdvertisedPort(t *testing.T) {
+	t.Parallel()
+
+	c := &config.Config{P2P: config.P2PConfig{Port: "9906"}}
+	assert.Equal(t, "9906", advertisedPort(c))
+
+	c.P2P.BroadcastPort = "19906"
+	assert.Equal(t, "19906", advertisedPort(c))
+}
+
+// TestPeerDirection will test the method peerDirection()
+func TestPeerDirection(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "inbound", peerDirection(network.DirInbound))
+	assert.Equal(t, "outbound", peerDirection(network.DirOutbound))
+	assert.Equal(t, "unknown", peerDirection(network.DirUnknown))
+}
+
+// TestServer_ProcessReceivedAlert_MinAlertPeers will test the method processReceivedAlert() with a minimum peer count
+func TestServer_ProcessReceivedAlert_MinAlertPeers(t *testing.T) {
+	net, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = net.Close()
+	})
+	lonelyNet := mocknet.New()
+	t.Cleanup(func() {
+		_ = lonelyNet.Close()
+	})
+	lonely, err := lonelyNet.GenPeer()
+	require.NoError(t, err)
+
+	// receive will process an alert received on the topic by a server on the host, returning the stored alert
+	receive := func(t *testing.T, deps *config.Config, s *Server) *models.AlertMessage {
+		ak, err := models.NewAlertFromBytes(
+			newSignedAlert(t, deps, 1, models.AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'}),
+			model.WithAllDependencies(deps), model.New(),
+		)
+		require.NoError(t, err)
+		ak.SerializeData()
+		require.True(t, s.processReceivedAlert(context.Background(), ak, peer.ID("peer-a")))
+
+		stored, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+		require.NoError(t, err)
+		return stored
+	}
+
+	t.Run("stored unprocessed with too few peers", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.P2P.MinAlertPeers = 1
+		s := &Server{config: deps, host: lonely, progress: newSyncProgress(time.Hour)}
+		assert.False(t, s.HasAlertPeers())
+
+		assert.False(t, receive(t, deps, s).Processed)
+		assert.Equal(t, 0, auditEntries(t, deps))
+
+		// Nor executed by the alert processing until enough peers are connected
+		require.NoError(t, s.processAlerts(context.Background()))
+		assert.Equal(t, 0, auditEntries(t, deps))
+	})
+
+	t.Run("executed with enough peers", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.P2P.MinAlertPeers = 1
+		s := &Server{config: deps, host: net.Hosts()[0], progress: newSyncProgress(time.Hour)}
+		assert.True(t, s.HasAlertPeers())
+
+		assert.True(t, receive(t, deps, s).Processed)
+		assert.Equal(t, 1, auditEntries(t, deps))
+	})
+
+	t.Run("executed with no peers when disabled", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		s := &Server{config: deps, host: lonely, progress: newSyncProgress(time.Hour)}
+		assert.True(t, s.HasAlertPeers())
+
+		assert.True(t, receive(t, deps, s).Processed)
+		assert.Equal(t, 1, auditEntries(t, deps))
+	})
+}
+
+// TestServer_ProcessAlerts_UnchangedSetKeys will test the method processAlerts() with a set keys alert that changes nothing
+func TestServer_ProcessAlerts_UnchangedSetKeys(t *testing.T) {
+	deps := loadTestDependencies(t)
+	s := &Server{config: deps, progress: newSyncProgress(time.Hour)}
+
+	// A rotation to the keys that are already active
+	body := make([]byte, 0, models.SetKeysMessageSize)
+	for _, key := range deps.GenesisKeys {
+		raw, err := hex.DecodeString(key)
+		require.NoError(t, err)
+		body = append(body, raw...)
+	}
+	ak, err := models.NewAlertFromBytes(
+		newSignedAlert(t, deps, 1, models.AlertTypeSetKeys, body),
+		model.WithAllDependencies(deps), model.New(),
+	)
+	require.NoError(t, err)
+	ak.SerializeData()
+	require.NoError(t, ak.Save(context.Background()))
+
+	// Rejected once and stored as processed, so it isn't run again
+	require.NoError(t, s.processAlerts(context.Background()))
+	stored, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+	require.NoError(t, err)
+	assert.True(t, stored.Processed)
+	assert.Equal(t, 1, auditEntries(t, deps))
+
+	require.NoError(t, s.processAlerts(context.Background()))
+	assert.Equal(t, 1, auditEntries(t, deps))
+}
+
+// TestServer_PublishAlert will test the method PublishAlert()
+func TestServer_PublishAlert(t *testing.T) {
+	t.Run("not joined to a topic", func(t *testing.T) {
+		s := &Server{config: &config.Config{}}
+		require.ErrorIs(t, s.PublishAlert(context.Background(), []byte{0x01}), ErrNoAlertTopics)
+	})
+
+	t.Run("delivered to a peer on the topic", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		net, err := mocknet.FullMeshConnected(2)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = net.Close()
+		})
+
+		// join will start gossipsub on the host and join the alert topic
+		join := func(i int) *pubsub.Topic {
+			ps, err := pubsub.NewGossipSub(ctx, net.Hosts()[i])
+			require.NoError(t, err)
+			topic, err := ps.Join(config.DefaultTopicName)
+			require.NoError(t, err)
+			return topic
+		}
+		topic := join(0)
+		sub, err := join(1).Subscribe()
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return len(topic.ListPeers()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		s := &Server{config: &config.Config{}, topics: map[string]*pubsub.Topic{config.DefaultTopicName: topic}}
+		require.NoError(t, s.PublishAlert(ctx, []byte("alert")))
+
+		received, cancelNext := context.WithTimeout(ctx, 5*time.Second)
+		defer cancelNext()
+		msg, err := sub.Next(received)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("alert"), msg.Data)
+		assert.Equal(t, net.Hosts()[0].ID(), msg.ReceivedFrom)
+	})
+}
@@ -0,0 +1,109 @@
+package p2p
+
+import (
+	"context"
+	"sort"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
+// staticPeerTag is the connection manager tag protecting the connections to static peers from being pruned
+const staticPeerTag = "alert-system-static-peer"
+
+// StaticPeer is a configured static peer and whether we are connected to it
+type StaticPeer struct {
+	Address   string `json:"address"`
+	Connected bool   `json:"connected"`
+	ID        string `json:"id"`
+}
+
+// staticPeers keeps the connections to the configured static peers (trusted relays) open
+//
+// Each run dials the static peers we aren't connected to, backing off from the ones that failed like any other peer.
+// Peer discovery leaves the static peers to it, so they're never dialed twice
+type staticPeers struct {
+	backoff   *dialBackoff
+	connect   func(ctx context.Context, info peer.AddrInfo) error
+	connected func(peerID peer.ID) bool
+	log       config.LoggerInterface
+	onConnect func(ctx context.Context, peerID peer.ID) // Called after a static peer is (re)connected
+	peers     []peer.AddrInfo
+}
+
+// parseStaticPeers will parse the static peer addresses (multiaddrs ending in /p2p/<peer id>),
+// merging the addresses of the same peer, sorted by peer ID
+func parseStaticPeers(addresses []string) ([]peer.AddrInfo, error) {
+	addrs := make([]multiaddr.Multiaddr, 0, len(addresses))
+	for _, address := range addresses {
+		addr, err := multiaddr.NewMultiaddr(address)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	peers, err := peer.AddrInfosFromP2pAddrs(addrs...)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ID < peers[j].ID
+	})
+	return peers, nil
+}
+
+// Contains returns true if the peer is a static peer
+func (s *staticPeers) Contains(peerID peer.ID) bool {
+	for _, info := range s.peers {
+		if info.ID == peerID {
+			return true
+		}
+	}
+	return false
+}
+
+// Run will dial the static peers we aren't connected to and that aren't backing off, returning how many connected
+func (s *staticPeers) Run(ctx context.Context) int {
+	reconnected := 0
+	for _, info := range s.peers {
+		if ctx.Err() != nil {
+			return reconnected
+		} else if s.connected(info.ID) || !s.backoff.Ready(info.ID.String()) {
+			continue
+		}
+		if err := s.connect(ctx, info); err != nil {
+			wait := s.backoff.Failure(info.ID.String())
+			s.log.Warnf("failed connecting to static peer %s, retrying in %s: %s", info.ID.String(), wait.String(), err.Error())
+			continue
+		}
+		s.backoff.Success(info.ID.String())
+		s.log.Infof("connected to static peer %s", info.ID.String())
+		reconnected++
+		if s.onConnect != nil {
+			s.onConnect(ctx, info.ID)
+		}
+	}
+	return reconnected
+}
+
+// Status returns the static peers and whether we are connected to each, sorted by peer ID
+func (s *staticPeers) Status() []StaticPeer {
+	status := make([]StaticPeer, 0, len(s.peers))
+	for _, info := range s.peers {
+		address := ""
+		if len(info.Addrs) > 0 {
+			address = info.Addrs[0].String()
+		}
+		status = append(status, StaticPeer{
+			Address:   address,
+			Connected: s.connected(info.ID),
+			ID:        info.ID.String(),
+		})
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].ID < status[j].ID
+	})
+	return status
+}
@@ -0,0 +1,127 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+)
+
+// Static peer addresses for the tests
+const (
+	testStaticPeerA = "/ip4/203.0.113.1/tcp/9906/p2p/12D3KooWQYhTNQdmr3ArTeUHRYzFg94BKyTkoWBDWez9kSCVe2Xo"
+	testStaticPeerB = "/ip4/203.0.113.2/tcp/9906/p2p/12D3KooWJWoaqZhDaoEFshF7Rh1bpY9ohihFhzcW6d69Lr2NASuq"
+)
+
+// testStaticPeers is a static peer tracker over fake connections
+type testStaticPeers struct {
+	*staticPeers
+	connected map[peer.ID]bool // The peers we are connected to
+	down      map[peer.ID]bool // The peers that refuse connections
+	synced    []peer.ID        // The peers synced after connecting, in order
+}
+
+// newTestStaticPeers will create static peers over fake connections, where every peer accepts connections
+func newTestStaticPeers(t *testing.T) *testStaticPeers {
+	peers, err := parseStaticPeers([]string{testStaticPeerA, testStaticPeerB})
+	require.NoError(t, err)
+	ts := &testStaticPeers{connected: make(map[peer.ID]bool), down: make(map[peer.ID]bool)}
+	ts.staticPeers = &staticPeers{
+		backoff: newDialBackoff(time.Minute, time.Hour),
+		connect: func(_ context.Context, info peer.AddrInfo) error {
+			if ts.down[info.ID] {
+				return errors.New("connection refused")
+			}
+			ts.connected[info.ID] = true
+			return nil
+		},
+		connected: func(peerID peer.ID) bool {
+			return ts.connected[peerID]
+		},
+		log: &config.ExtendedLogger{Logger: log.New(io.Discard, "", 0)},
+		onConnect: func(_ context.Context, peerID peer.ID) {
+			ts.synced = append(ts.synced, peerID)
+		},
+		peers: peers,
+	}
+	return ts
+}
+
+// TestParseStaticPeers will test the method parseStaticPeers()
+func TestParseStaticPeers(t *testing.T) {
+	t.Parallel()
+
+	peers, err := parseStaticPeers([]string{testStaticPeerA, testStaticPeerB, "/ip4/198.51.100.1/tcp/9906/p2p/12D3KooWQYhTNQdmr3ArTeUHRYzFg94BKyTkoWBDWez9kSCVe2Xo"})
+	require.NoError(t, err)
+	require.Len(t, peers, 2)
+	assert.Less(t, peers[0].ID, peers[1].ID)
+	for _, info := range peers {
+		if info.ID.String() == "12D3KooWQYhTNQdmr3ArTeUHRYzFg94BKyTkoWBDWez9kSCVe2Xo" {
+			assert.Len(t, info.Addrs, 2) // The addresses of the same peer are merged
+		}
+	}
+
+	_, err = parseStaticPeers([]string{"not a multiaddr"})
+	require.Error(t, err)
+}
+
+// TestStaticPeers_Run will test the method Run()
+func TestStaticPeers_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dials the peers that aren't connected", func(t *testing.T) {
+		static := newTestStaticPeers(t)
+		static.down[static.peers[1].ID] = true
+
+		assert.Equal(t, 1, static.Run(context.Background()))
+		assert.Equal(t, []peer.ID{static.peers[0].ID}, static.synced)
+
+		// The peer that failed backs off, the connected one isn't dialed again
+		assert.False(t, static.backoff.Ready(static.peers[1].ID.String()))
+		assert.Zero(t, static.Run(context.Background()))
+		assert.Len(t, static.synced, 1)
+	})
+
+	t.Run("redials a peer that dropped", func(t *testing.T) {
+		static := newTestStaticPeers(t)
+		assert.Equal(t, 2, static.Run(context.Background()))
+
+		static.connected[static.peers[1].ID] = false
+		assert.Equal(t, 1, static.Run(context.Background()))
+		assert.Len(t, static.synced, 3)
+	})
+
+	t.Run("a cancelled run stops", func(t *testing.T) {
+		static := newTestStaticPeers(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Zero(t, static.Run(ctx))
+	})
+}
+
+// TestStaticPeers_Status will test the methods Status() and Contains()
+func TestStaticPeers_Status(t *testing.T) {
+	t.Parallel()
+
+	static := newTestStaticPeers(t)
+	static.connected[static.peers[0].ID] = true
+
+	status := static.Status()
+	require.Len(t, status, 2)
+	for _, s := range status {
+		assert.Equal(t, s.ID == static.peers[0].ID.String(), s.Connected)
+		assert.NotEmpty(t, s.Address)
+	}
+	assert.Less(t, status[0].ID, status[1].ID)
+
+	assert.True(t, static.Contains(static.peers[1].ID))
+	assert.False(t, static.Contains(peer.ID("someone-else")))
+}
@@ -2,6 +2,12 @@ package p2p
 
 import (
 	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/util"
+
+	"github.com/bsv-blockchain/go-alert-system/utils"
 )
 
 // IWantLatest is the byte for "I want the latest"
@@ -16,36 +22,185 @@ const IGotSequenceNumber = 0x03
 // IGotLatest is the byte for "I got latest"
 const IGotLatest = 0x04
 
+// IGotTime is the byte for "I got time", sent when a stream is opened so peers can compare clocks
+// Older peers ignore it, so it doesn't need to be answered
+const IGotTime = 0x05
+
+// IWantSequenceRange is the byte for "I want sequence range", answered with the alerts from the start to the end
+// sequence (inclusive) in IGotBatch messages, or an IGotSequenceNumber for each alert to a peer that never said hello
+const IWantSequenceRange = 0x06
+
+// MaxSyncRangeSize is the most sequences a peer can ask for in a single IWantSequenceRange
+const MaxSyncRangeSize = 500
+
+// IGotBatch is the byte for "I got batch", carrying several alerts in one message
+// The sequence number is the sequence of the first alert, and the data is each alert prefixed with its length (varint)
+const IGotBatch = 0x07
+
+// IHello is the byte for "hello", sent when a stream is opened to advertise our sync protocol version
+// Older peers ignore it, and are treated as speaking the legacy protocol
+const IHello = 0x08
+
+// MaxSyncBatchCount is the most alerts a single IGotBatch may carry
+const MaxSyncBatchCount = MaxSyncRangeSize
+
+// MaxSyncBatchSize is the most bytes of alerts (with their length prefixes) a single IGotBatch may carry
+const MaxSyncBatchSize = 1 << 20
+
+// maxSyncMessageSize is the largest sync message read from a stream, so a peer can't make us allocate
+// an arbitrary buffer by announcing a huge message length
+const maxSyncMessageSize = syncHeaderSize + MaxSyncBatchSize
+
+// syncHelloSize is the size of the hello message data: protocol version(4)
+const syncHelloSize = 4
+
+// syncTimeSize is the size of the sync time message data: unix timestamp(8)
+const syncTimeSize = 8
+
+// syncHeaderSize is the size of the sync message header: type(1) + sequence number(4)
+const syncHeaderSize = 5
+
+// syncRangeSize is the size of the sync range message header: type(1) + start sequence(4) + end sequence(4)
+const syncRangeSize = 9
+
 // SyncMessage is the message for syncing
+// For an IWantSequenceRange, the sequence number is the start of the range
+// For an IGotBatch, the batch is the alerts carried in the data
 type SyncMessage struct {
-	Data           []byte `json:"data"`
-	SequenceNumber uint32 `json:"sequence_number"`
-	Type           byte   `json:"type"`
+	Batch          [][]byte `json:"batch,omitempty"`
+	Data           []byte   `json:"data"`
+	EndSequence    uint32   `json:"end_sequence,omitempty"`
+	SequenceNumber uint32   `json:"sequence_number"`
+	Type           byte     `json:"type"`
 }
 
 // NewSyncMessageFromBytes will create a new sync message from bytes
+// Any failure is a *SyncParseError, so the caller can tell a broken peer from one that is a version ahead
 func NewSyncMessageFromBytes(in []byte) (*SyncMessage, error) {
 	if len(in) < 1 {
-		return nil, ErrSyncMessageByte
+		return nil, newSyncParseError(SyncParseTooShort, 0, ErrSyncMessageByte)
 	}
 	s := SyncMessage{}
 	s.Type = in[0]
 	if s.Type == IWantLatest {
 		return &s, nil
 	}
-	if len(in) < 5 {
-		return nil, ErrSyncFiveBytes
+	if !isKnownSyncMessageType(s.Type) {
+		return nil, newSyncParseError(SyncParseUnknownType, s.Type, fmt.Errorf("%w: %d", ErrSyncUnknownType, s.Type))
+	}
+	if len(in) < syncHeaderSize {
+		return nil, newSyncParseError(SyncParseMissingSequence, s.Type, ErrSyncFiveBytes)
+	}
+	s.SequenceNumber = binary.LittleEndian.Uint32(in[1:syncHeaderSize])
+	if s.Type == IWantSequenceRange {
+		if len(in) < syncRangeSize {
+			return nil, newSyncParseError(SyncParseTooShort, s.Type, ErrSyncRangeNineBytes)
+		}
+		s.EndSequence = binary.LittleEndian.Uint32(in[syncHeaderSize:syncRangeSize])
+		s.Data = in[syncRangeSize:]
+		return &s, nil
+	}
+	s.Data = in[syncHeaderSize:]
+	if s.Type == IGotBatch {
+		var err error
+		if s.Batch, err = readBatch(s.Data); err != nil {
+			return nil, newSyncParseError(SyncParseMalformed, s.Type, err)
+		}
 	}
-	s.SequenceNumber = binary.LittleEndian.Uint32(in[1:5])
-	s.Data = in[5:]
 	return &s, nil
 }
 
+// readBatch will split the data of an IGotBatch into its alerts, enforcing the batch limits
+// The alerts are framed as models.AlertMessage.WriteTo streams them, each is a slice of the data so nothing is allocated
+func readBatch(data []byte) ([][]byte, error) {
+	if len(data) > MaxSyncBatchSize {
+		return nil, fmt.Errorf("%w: %d bytes is more than %d", ErrSyncBatchTooLarge, len(data), MaxSyncBatchSize)
+	}
+	var batch [][]byte
+	reader := util.NewReader(data)
+	for !reader.IsComplete() {
+		if len(batch) == MaxSyncBatchCount {
+			return nil, fmt.Errorf("%w: more than %d alerts", ErrSyncBatchTooLarge, MaxSyncBatchCount)
+		}
+		length, err := reader.ReadVarInt()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSyncBatchMalformed, err)
+		}
+		if length > uint64(len(reader.Data)-reader.Pos) {
+			return nil, fmt.Errorf("%w: alert %d is %d bytes, only %d left", ErrSyncBatchMalformed, len(batch), length, len(reader.Data)-reader.Pos)
+		}
+		var alert []byte
+		if alert, err = reader.ReadBytes(int(length)); err != nil { //nolint:gosec // checked against the data left
+			return nil, fmt.Errorf("%w: %w", ErrSyncBatchMalformed, err)
+		}
+		batch = append(batch, alert)
+	}
+	return batch, nil
+}
+
 // Serialize will serialize the sync message
 func (s *SyncMessage) Serialize() []byte {
-	var ret []byte
-	ret = append(ret, s.Type)
-	ret = binary.LittleEndian.AppendUint32(ret, s.SequenceNumber)
-	ret = append(ret, s.Data...)
-	return ret
+	return utils.PooledSerialize(func(ret []byte) []byte {
+		ret = append(ret, s.Type)
+		ret = binary.LittleEndian.AppendUint32(ret, s.SequenceNumber)
+		if s.Type == IWantSequenceRange {
+			ret = binary.LittleEndian.AppendUint32(ret, s.EndSequence)
+		}
+		if s.Type == IGotBatch {
+			for _, alert := range s.Batch {
+				ret = append(ret, util.VarInt(len(alert)).Bytes()...)
+				ret = append(ret, alert...)
+			}
+			return ret
+		}
+		return append(ret, s.Data...)
+	})
+}
+
+// newTimeMessage will create a new sync message carrying our clock
+func newTimeMessage(now time.Time) *SyncMessage {
+	return &SyncMessage{
+		Type: IGotTime,
+		Data: binary.LittleEndian.AppendUint64(nil, uint64(now.Unix())), //nolint:gosec // unix time is positive
+	}
+}
+
+// Time will read the peer's clock from a sync time message
+func (s *SyncMessage) Time() (time.Time, error) {
+	if len(s.Data) < syncTimeSize {
+		return time.Time{}, ErrSyncTimeEightBytes
+	}
+	return time.Unix(int64(binary.LittleEndian.Uint64(s.Data[:syncTimeSize])), 0), nil //nolint:gosec // checked against our clock
+}
+
+// validateRange will check the range of an IWantSequenceRange is in order and no bigger than the max range size
+func (s *SyncMessage) validateRange() error {
+	if s.SequenceNumber > s.EndSequence {
+		return fmt.Errorf("%w: %d to %d", ErrSyncRangeInvalid, s.SequenceNumber, s.EndSequence)
+	}
+	if s.EndSequence-s.SequenceNumber >= MaxSyncRangeSize {
+		return fmt.Errorf("%w: %d to %d is more than %d sequences", ErrSyncRangeTooLarge, s.SequenceNumber, s.EndSequence, MaxSyncRangeSize)
+	}
+	return nil
+}
+
+// newHelloMessage will create a new sync message advertising our protocol version
+func newHelloMessage(version uint32) *SyncMessage {
+	return &SyncMessage{
+		Type: IHello,
+		Data: binary.LittleEndian.AppendUint32(nil, version),
+	}
+}
+
+// ProtocolVersion will read the peer's protocol version from a hello message
+func (s *SyncMessage) ProtocolVersion() (uint32, error) {
+	if len(s.Data) < syncHelloSize {
+		return 0, ErrSyncHelloFourBytes
+	}
+	return binary.LittleEndian.Uint32(s.Data[:syncHelloSize]), nil
+}
+
+// isSyncRequest returns true if the sync message type asks the receiver for alerts
+func isSyncRequest(msgType byte) bool {
+	return msgType == IWantLatest || msgType == IWantSequenceNumber || msgType == IWantSequenceRange
 }
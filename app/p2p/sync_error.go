@@ -0,0 +1,59 @@
+package p2p
+
+import "fmt"
+
+// SyncParseReason is why a sync message couldn't be parsed
+type SyncParseReason uint8
+
+// Reasons a sync message couldn't be parsed
+const (
+	SyncParseTooShort        SyncParseReason = iota + 1 // Shorter than its type needs (no type byte, or a range without its end)
+	SyncParseMissingSequence                            // No sequence number after the type
+	SyncParseUnknownType                                // A type we don't know, most likely from a newer peer
+	SyncParseMalformed                                  // The data doesn't match the structure of its type (a batch that doesn't split)
+)
+
+// String returns the name of the reason
+func (r SyncParseReason) String() string {
+	switch r {
+	case SyncParseTooShort:
+		return "too short"
+	case SyncParseMissingSequence:
+		return "missing sequence"
+	case SyncParseUnknownType:
+		return "unknown type"
+	case SyncParseMalformed:
+		return "malformed"
+	default:
+		return fmt.Sprintf("reason %d", r)
+	}
+}
+
+// SyncParseError is the error returned by NewSyncMessageFromBytes
+// It wraps the static error of the failure, so errors.Is still matches those
+type SyncParseError struct {
+	Err    error
+	Reason SyncParseReason
+	Type   byte
+}
+
+// newSyncParseError will create a new sync parse error for the message type
+func newSyncParseError(reason SyncParseReason, msgType byte, err error) *SyncParseError {
+	return &SyncParseError{Err: err, Reason: reason, Type: msgType}
+}
+
+// Error returns the error message
+func (e *SyncParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped static error
+func (e *SyncParseError) Unwrap() error {
+	return e.Err
+}
+
+// IsProtocolViolation returns true if the message could never be valid, rather than being from a newer peer
+// A peer that sends these is broken or malicious, a peer that sends unknown types is merely a version ahead
+func (e *SyncParseError) IsProtocolViolation() bool {
+	return e.Reason != SyncParseUnknownType
+}
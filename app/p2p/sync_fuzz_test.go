@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"testing"
 
+	"github.com/bsv-blockchain/go-sdk/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,6 +30,22 @@ func FuzzNewSyncMessageFromBytes(f *testing.F) {
 	gotLatestMsg = append(gotLatestMsg, []byte("latest alert data")...)
 	f.Add(gotLatestMsg)
 
+	// Seed with valid IWantSequenceRange message (type + start + end)
+	wantRangeMsg := []byte{IWantSequenceRange}
+	wantRangeMsg = binary.LittleEndian.AppendUint32(wantRangeMsg, 10)
+	wantRangeMsg = binary.LittleEndian.AppendUint32(wantRangeMsg, 20)
+	f.Add(wantRangeMsg)
+	f.Add(wantRangeMsg[:5]) // range without its end
+
+	// Seed with IGotBatch messages: empty, single and multi-entry batches
+	batchMsg := []byte{IGotBatch}
+	batchMsg = binary.LittleEndian.AppendUint32(batchMsg, 5)
+	f.Add(batchMsg)
+	f.Add(append(append([]byte{}, batchMsg...), 0x03, 'o', 'n', 'e'))
+	f.Add(append(append([]byte{}, batchMsg...), 0x03, 'o', 'n', 'e', 0x00, 0x05, 't', 'h', 'r', 'e', 'e'))
+	f.Add(append(append([]byte{}, batchMsg...), 0x05, 's', 'h', 'o'))          // entry longer than the data
+	f.Add(append(append([]byte{}, batchMsg...), 0xFF, 0xFF, 0xFF, 0xFF, 0xFF)) // truncated huge length
+
 	// Seed with edge cases
 	f.Add([]byte{})                                      // empty
 	f.Add([]byte{0x00})                                  // unknown type, no sequence
@@ -59,8 +76,13 @@ func FuzzNewSyncMessageFromBytes(f *testing.F) {
 		// Should never panic
 		msg, err := NewSyncMessageFromBytes(data)
 		if err != nil {
-			// Error is acceptable for invalid input
+			// Error is acceptable for invalid input, and always says why
 			require.Nil(t, msg, "message should be nil when error is returned")
+			var parseErr *SyncParseError
+			require.ErrorAs(t, err, &parseErr, "error should be a parse error")
+			if len(data) > 0 {
+				require.Equal(t, !isKnownSyncMessageType(data[0]), parseErr.Reason == SyncParseUnknownType, "only unknown types should have the unknown type reason")
+			}
 			return
 		}
 
@@ -78,6 +100,9 @@ func FuzzNewSyncMessageFromBytes(f *testing.F) {
 			return
 		}
 
+		// Unknown types (from a newer peer) are never parsed
+		require.True(t, isKnownSyncMessageType(msg.Type), "unknown types should return an error")
+
 		// For other types, sequence number should be present
 		require.GreaterOrEqual(t, len(data), 5, "data should have at least 5 bytes for non-IWantLatest types")
 
@@ -85,6 +110,24 @@ func FuzzNewSyncMessageFromBytes(f *testing.F) {
 		expectedSeq := binary.LittleEndian.Uint32(data[1:5])
 		require.Equal(t, expectedSeq, msg.SequenceNumber, "sequence number should match bytes 1-4")
 
+		// A range carries its end sequence before the data
+		if msg.Type == IWantSequenceRange {
+			require.GreaterOrEqual(t, len(data), 9, "data should have at least 9 bytes for IWantSequenceRange")
+			require.Equal(t, binary.LittleEndian.Uint32(data[5:9]), msg.EndSequence, "end sequence should match bytes 5-8")
+			require.Equal(t, data[9:], msg.Data, "data should match remaining bytes")
+			return
+		}
+
+		// A batch is the length prefixed alerts in the data
+		if msg.Type == IGotBatch {
+			require.LessOrEqual(t, len(msg.Batch), MaxSyncBatchCount, "batch should be within the max count")
+			total := 0
+			for _, alert := range msg.Batch {
+				total += len(alert)
+			}
+			require.LessOrEqual(t, total, len(data)-5, "batch alerts should fit in the data")
+		}
+
 		// Validate data field
 		if len(data) > 5 {
 			require.Equal(t, data[5:], msg.Data, "data should match remaining bytes")
@@ -101,6 +144,8 @@ func FuzzSyncMessageSerialize(f *testing.F) {
 	f.Add(byte(IWantSequenceNumber), uint32(12345), []byte{})
 	f.Add(byte(IGotSequenceNumber), uint32(67890), []byte("alert data"))
 	f.Add(byte(IGotLatest), uint32(99999), []byte("latest alert"))
+	f.Add(byte(IWantSequenceRange), uint32(10), []byte{})
+	f.Add(byte(IGotBatch), uint32(5), []byte("batched alert"))
 	f.Add(byte(0x00), uint32(0), []byte{})
 	f.Add(byte(0xFF), ^uint32(0), make([]byte, 100))
 
@@ -109,9 +154,15 @@ func FuzzSyncMessageSerialize(f *testing.F) {
 		msg := &SyncMessage{
 			Type:           msgType,
 			SequenceNumber: seqNum,
+			EndSequence:    seqNum + 1,
 			Data:           data,
 		}
 
+		// A batch carries its alerts instead of the data
+		if msgType == IGotBatch {
+			msg.Batch = [][]byte{data}
+		}
+
 		// Serialize should never panic
 		serialized := msg.Serialize()
 
@@ -124,8 +175,16 @@ func FuzzSyncMessageSerialize(f *testing.F) {
 		parsedSeq := binary.LittleEndian.Uint32(serialized[1:5])
 		require.Equal(t, seqNum, parsedSeq, "bytes 1-4 should be sequence number")
 
-		if len(data) > 0 {
-			require.Equal(t, data, serialized[5:], "remaining bytes should be data")
+		// A range carries its end sequence before the data
+		dataStart := 5
+		if msgType == IWantSequenceRange {
+			dataStart = 9
+			require.Equal(t, seqNum+1, binary.LittleEndian.Uint32(serialized[5:9]), "bytes 5-8 should be the end sequence")
+		}
+		if msgType == IGotBatch {
+			require.Equal(t, append(util.VarInt(len(data)).Bytes(), data...), serialized[dataStart:], "remaining bytes should be the length prefixed alert")
+		} else if len(data) > 0 {
+			require.Equal(t, data, serialized[dataStart:], "remaining bytes should be data")
 		}
 
 		// Test round-trip consistency (serialize → deserialize)
@@ -142,7 +201,14 @@ func FuzzSyncMessageSerialize(f *testing.F) {
 			if err == nil {
 				require.Equal(t, msgType, deserialized.Type, "deserialized type should match")
 				require.Equal(t, seqNum, deserialized.SequenceNumber, "deserialized sequence should match")
-				require.Equal(t, data, deserialized.Data, "deserialized data should match")
+				if msgType == IGotBatch {
+					require.Equal(t, [][]byte{data}, deserialized.Batch, "deserialized batch should match")
+				} else {
+					require.Equal(t, data, deserialized.Data, "deserialized data should match")
+				}
+				if msgType == IWantSequenceRange {
+					require.Equal(t, seqNum+1, deserialized.EndSequence, "deserialized end sequence should match")
+				}
 			}
 		}
 	})
@@ -155,6 +221,9 @@ func FuzzSyncMessageTypes(f *testing.F) {
 	f.Add(byte(IWantSequenceNumber))
 	f.Add(byte(IGotSequenceNumber))
 	f.Add(byte(IGotLatest))
+	f.Add(byte(IWantSequenceRange))
+	f.Add(byte(IGotBatch))
+	f.Add(byte(IHello))
 
 	// Seed with boundary values
 	f.Add(byte(0x00))
@@ -174,6 +243,17 @@ func FuzzSyncMessageTypes(f *testing.F) {
 			return
 		}
 
+		// Unknown types (from a newer peer) should fail whatever their length, so they can be ignored
+		if !isKnownSyncMessageType(msgType) {
+			var parseErr *SyncParseError
+			require.ErrorAs(t, err1, &parseErr, "unknown types should return a parse error")
+			require.Equal(t, SyncParseUnknownType, parseErr.Reason, "reason should be the unknown type")
+			require.False(t, parseErr.IsProtocolViolation(), "an unknown type isn't a protocol violation")
+			_, err1 = NewSyncMessageFromBytes([]byte{msgType, 0x01, 0x02, 0x03, 0x04})
+			require.ErrorIs(t, err1, ErrSyncUnknownType, "unknown types should fail with 5 bytes")
+			return
+		}
+
 		// All other types should fail with just 1 byte (need 5 bytes minimum)
 		require.Error(t, err1, "non-IWantLatest types should fail with just 1 byte")
 		require.Nil(t, msg1, "message should be nil on error")
@@ -182,6 +262,12 @@ func FuzzSyncMessageTypes(f *testing.F) {
 		fullMsg := []byte{msgType, 0x01, 0x02, 0x03, 0x04}
 		msg2, err2 := NewSyncMessageFromBytes(fullMsg)
 
+		// IWantSequenceRange needs the end sequence as well (9 bytes minimum)
+		if msgType == IWantSequenceRange {
+			require.ErrorIs(t, err2, ErrSyncRangeNineBytes, "IWantSequenceRange should fail without an end sequence")
+			msg2, err2 = NewSyncMessageFromBytes(append(fullMsg, 0x05, 0x06, 0x07, 0x08))
+		}
+
 		// Should succeed with 5 bytes regardless of type
 		require.NoError(t, err2, "should parse with 5 bytes")
 		require.NotNil(t, msg2, "message should not be nil with 5 bytes")
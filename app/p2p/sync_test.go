@@ -0,0 +1,103 @@
+package p2p
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// TestSyncMessage_Batch will test serializing and parsing an IGotBatch
+func TestSyncMessage_Batch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		batch := [][]byte{[]byte("one"), {}, bytes.Repeat([]byte{0x01}, 300)}
+		msg, err := NewSyncMessageFromBytes((&SyncMessage{Type: IGotBatch, SequenceNumber: 4, Batch: batch}).Serialize())
+		require.NoError(t, err)
+		assert.Equal(t, uint32(4), msg.SequenceNumber)
+		assert.Equal(t, batch, msg.Batch)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		msg, err := NewSyncMessageFromBytes((&SyncMessage{Type: IGotBatch, SequenceNumber: 4}).Serialize())
+		require.NoError(t, err)
+		assert.Empty(t, msg.Batch)
+	})
+
+	t.Run("too many alerts", func(t *testing.T) {
+		batch := make([][]byte, MaxSyncBatchCount+1)
+		_, err := NewSyncMessageFromBytes((&SyncMessage{Type: IGotBatch, Batch: batch}).Serialize())
+		require.ErrorIs(t, err, ErrSyncBatchTooLarge)
+
+		msg, err := NewSyncMessageFromBytes((&SyncMessage{Type: IGotBatch, Batch: batch[1:]}).Serialize())
+		require.NoError(t, err)
+		assert.Len(t, msg.Batch, MaxSyncBatchCount)
+	})
+
+	t.Run("too many bytes", func(t *testing.T) {
+		batch := [][]byte{make([]byte, MaxSyncBatchSize)}
+		_, err := NewSyncMessageFromBytes((&SyncMessage{Type: IGotBatch, Batch: batch}).Serialize())
+		require.ErrorIs(t, err, ErrSyncBatchTooLarge)
+	})
+
+	t.Run("alert longer than the data", func(t *testing.T) {
+		data := (&SyncMessage{Type: IGotBatch, Batch: [][]byte{[]byte("alert")}}).Serialize()
+		_, err := NewSyncMessageFromBytes(data[:len(data)-1])
+		require.ErrorIs(t, err, ErrSyncBatchMalformed)
+	})
+}
+
+// TestSyncMessage_BatchAlertStream will test that the alerts of an IGotBatch are framed the same as the alert stream
+func TestSyncMessage_BatchAlertStream(t *testing.T) {
+	deps := loadTestDependencies(t)
+	var stream bytes.Buffer
+	var batch [][]byte
+	for sequence := uint32(1); sequence <= 2; sequence++ {
+		raw := newSignedAlert(t, deps, sequence, models.AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+		alert, err := models.NewAlertFromBytes(raw)
+		require.NoError(t, err)
+		_, err = alert.WriteTo(&stream)
+		require.NoError(t, err)
+		batch = append(batch, raw)
+	}
+
+	msg, err := NewSyncMessageFromBytes(append([]byte{IGotBatch, 0x01, 0x00, 0x00, 0x00}, stream.Bytes()...))
+	require.NoError(t, err)
+	assert.Equal(t, batch, msg.Batch)
+	assert.Equal(t, stream.Bytes(), msg.Data)
+}
+
+// TestNewSyncMessageFromBytes_ParseError will test the reason of each parse failure
+func TestNewSyncMessageFromBytes_ParseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		in        []byte
+		reason    SyncParseReason
+		sentinel  error
+		violation bool
+	}{
+		{"empty", []byte{}, SyncParseTooShort, ErrSyncMessageByte, true},
+		{"unknown type", []byte{0x42, 0x01}, SyncParseUnknownType, ErrSyncUnknownType, false},
+		{"missing sequence", []byte{IGotSequenceNumber, 0x01}, SyncParseMissingSequence, ErrSyncFiveBytes, true},
+		{"range without its end", []byte{IWantSequenceRange, 0x01, 0x00, 0x00, 0x00}, SyncParseTooShort, ErrSyncRangeNineBytes, true},
+		{"batch that doesn't split", []byte{IGotBatch, 0x01, 0x00, 0x00, 0x00, 0x05, 'a'}, SyncParseMalformed, ErrSyncBatchMalformed, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg, err := NewSyncMessageFromBytes(test.in)
+			assert.Nil(t, msg)
+			require.ErrorIs(t, err, test.sentinel)
+
+			var parseErr *SyncParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, test.reason, parseErr.Reason)
+			assert.Equal(t, test.violation, parseErr.IsProtocolViolation())
+		})
+	}
+}
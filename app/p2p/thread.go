@@ -3,8 +3,8 @@ package p2p
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"io"
 	"time"
 
 	"github.com/bsv-blockchain/go-sdk/util"
@@ -12,6 +12,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/metrics"
 	"github.com/bsv-blockchain/go-alert-system/app/models"
 	"github.com/bsv-blockchain/go-alert-system/app/models/model"
 )
@@ -24,13 +25,25 @@ type Thread interface {
 
 // StreamThread is a thread for a stream
 type StreamThread struct {
+	activity         *peerActivity
+	clocks           *peerClocks
 	config           *config.Config
 	ctx              context.Context //nolint:containedctx // TODO should remove this, should be passed in via methods only
+	dropped          *droppedAlerts
+	hasAlertPeers    func() bool // Whether enough peers are connected to execute an alert (always if nil)
 	latestSequence   uint32
 	myLatestSequence uint32
+	now              func() time.Time // Clock used for the handshake (time.Now if nil)
 	peer             peer.ID
-	stream           network.Stream
+	progress         *syncProgress
 	quitChannel      chan bool
+	rangeEnd         uint32 // End of the last range requested from the peer
+	requests         *requestTracker
+	scores           *peerScores
+	sentHello        bool
+	sentTime         bool
+	stream           network.Stream
+	versions         *peerVersions
 }
 
 // LatestSequence will return the threads latest sequence
@@ -50,20 +63,26 @@ func (s *StreamThread) Sync(ctx context.Context) error {
 		return ErrAlertNotLatest
 	}
 
-	s.myLatestSequence = a.SequenceNumber
-	// construct get the latest message
-	msg := SyncMessage{
-		Type: IWantLatest,
-	}
-	data := msg.Serialize()
+	s.setMyLatestSequence(a.SequenceNumber)
 
 	defer func() {
 		_ = s.stream.Close()
 	}()
 
-	writer := util.NewWriter()
-	writer.WriteIntBytes(data)
-	if _, err = s.stream.Write(writer.Buf); err != nil {
+	// Send our clock so the peer can check the skew (it answers with its own)
+	if err = s.writeTime(); err != nil {
+		return err
+	}
+
+	// Send our protocol version so the peer knows which messages we understand (it answers with its own)
+	if err = s.writeHello(); err != nil {
+		return err
+	}
+
+	// construct get the latest message
+	if err = s.writeRequest(&SyncMessage{
+		Type: IWantLatest,
+	}); err != nil {
 		return err
 	}
 
@@ -77,20 +96,12 @@ func (s *StreamThread) ProcessSyncMessage(ctx context.Context) error {
 	done := make(chan error)
 	go func() {
 		for {
-			var vi util.VarInt
-			_, err := vi.ReadFrom(s.stream)
-			if err != nil {
-				if s.stream.Conn().IsClosed() {
-					done <- nil
-					return
-				}
-				s.config.Services.Log.Debugf("failed to read sync message: %s; closing stream", err.Error())
+			b, err := ReadSyncFrame(s.stream)
+			if errors.Is(err, ErrSyncFrameTooLarge) {
+				s.config.Services.Log.Debugf("sync message from peer %s is too large: %s; closing stream", s.peer.String(), err.Error())
 				done <- s.stream.Close()
 				return
-			}
-			b := make([]byte, vi)
-			_, err = io.ReadFull(s.stream, b)
-			if err != nil {
+			} else if err != nil {
 				if s.stream.Conn().IsClosed() {
 					done <- nil
 					return
@@ -105,12 +116,34 @@ func (s *StreamThread) ProcessSyncMessage(ctx context.Context) error {
 				done <- nil
 				return
 			}
+			if s.activity != nil {
+				s.activity.Seen(s.peer)
+			}
 			var msg *SyncMessage
 			if msg, err = NewSyncMessageFromBytes(b); err != nil {
+				// A newer peer may send types we don't know, they are ignored rather than failing the stream
+				var parseErr *SyncParseError
+				if errors.As(err, &parseErr) && !parseErr.IsProtocolViolation() {
+					s.config.Services.Log.Debugf("ignoring sync message from peer %s: %s", s.peer.String(), err.Error())
+					continue
+				}
 				s.config.Services.Log.Errorf("failed to convert to sync message: %s", err.Error())
+				s.penalize(parseErrorPenalty)
 				done <- err
 				return
 			}
+
+			// Correlate any response with the request we sent, and drop responses we never asked for
+			if !s.isSolicited(msg) {
+				s.config.Services.Log.Warnf("dropping unsolicited sync message type %d sequence %d from peer %s", msg.Type, msg.SequenceNumber, s.peer.String())
+				continue
+			}
+
+			// Count the requests the peer makes of us, flooding us with them gets the peer banned
+			if isSyncRequest(msg.Type) && s.countRequest() {
+				done <- fmt.Errorf("%w: peer %s", ErrPeerBanned, s.peer.String())
+				return
+			}
 			switch msg.Type {
 			case IGotLatest:
 				s.config.Services.Log.Debugf("received latest sequence %d from peer %s", msg.SequenceNumber, s.peer.String())
@@ -136,6 +169,17 @@ func (s *StreamThread) ProcessSyncMessage(ctx context.Context) error {
 					return
 				}
 				s.config.Services.Log.Debugf("wrote msg requesting next sequence %d from peer %s", msg.SequenceNumber+1, s.peer.String())
+			case IGotBatch:
+				s.config.Services.Log.Debugf("received IGotBatch of %d alerts from sequence %d from peer %s", len(msg.Batch), msg.SequenceNumber, s.peer.String())
+				if err = s.ProcessGotBatch(msg); err != nil {
+					done <- err
+					return
+				}
+				if s.myLatestSequence == s.latestSequence {
+					_ = s.stream.Close()
+					done <- nil
+					return
+				}
 			case IWantSequenceNumber:
 				s.config.Services.Log.Debugf("received IWantSequenceNumber %d from peer %s", msg.SequenceNumber, s.peer.String())
 				if err = s.ProcessWantSequenceNumber(ctx, msg); err != nil {
@@ -148,6 +192,31 @@ func (s *StreamThread) ProcessSyncMessage(ctx context.Context) error {
 					done <- err
 					return
 				}
+			case IWantSequenceRange:
+				s.config.Services.Log.Debugf("received IWantSequenceRange %d to %d from peer %s", msg.SequenceNumber, msg.EndSequence, s.peer.String())
+				if err = s.ProcessWantSequenceRange(ctx, msg); err != nil {
+					done <- err
+					return
+				}
+				s.config.Services.Log.Debugf("wrote sequences %d to %d to peer %s", msg.SequenceNumber, msg.EndSequence, s.peer.String())
+				if msg.SequenceNumber <= s.myLatestSequence && s.myLatestSequence <= msg.EndSequence {
+					err = s.stream.Close()
+					done <- err
+					return
+				}
+			case IGotTime:
+				if err = s.ProcessGotTime(msg); err != nil {
+					if errors.Is(err, ErrPeerClockSkew) {
+						_ = s.stream.Conn().Close()
+					}
+					done <- err
+					return
+				}
+			case IHello:
+				if err = s.ProcessHello(msg); err != nil {
+					done <- err
+					return
+				}
 			case IWantLatest:
 				s.config.Services.Log.Debugf("received IWantLatest from peer %s", s.peer.String())
 				if err = s.ProcessWantLatest(ctx); err != nil {
@@ -155,6 +224,8 @@ func (s *StreamThread) ProcessSyncMessage(ctx context.Context) error {
 					return
 				}
 				s.config.Services.Log.Debugf("wrote latest sequence %d to peer %s", s.myLatestSequence, s.peer.String())
+			default:
+				s.config.Services.Log.Debugf("ignoring unhandled sync message type %d from peer %s", msg.Type, s.peer.String())
 			}
 		}
 	}()
@@ -180,7 +251,8 @@ func (s *StreamThread) ProcessGotLatest(ctx context.Context, msg *SyncMessage) e
 		return ErrAlertNotLatest
 	}
 
-	s.myLatestSequence = a.SequenceNumber // this is redundant, but doesn't hurt
+	s.setMyLatestSequence(a.SequenceNumber) // this is redundant, but doesn't hurt
+	s.reportAdvertised(msg.SequenceNumber)
 	if msg.SequenceNumber < a.SequenceNumber {
 		s.config.Services.Log.Debugf("peer %s is not synced yet, ignoring...", s.peer.String())
 		return nil
@@ -195,23 +267,70 @@ func (s *StreamThread) ProcessGotLatest(ctx context.Context, msg *SyncMessage) e
 	s.config.Services.Log.Infof("peer %s has sequence %d and we have %d", s.peer.String(), msg.SequenceNumber, a.SequenceNumber)
 
 	// need to get the next sequence
-	res := SyncMessage{
-		Type:           IWantSequenceNumber,
-		SequenceNumber: a.SequenceNumber + 1,
-	}
-	writer := util.NewWriter()
-	writer.WriteIntBytes(res.Serialize())
-	_, err = s.stream.Write(writer.Buf)
-	return err
+	return s.requestFrom(a.SequenceNumber + 1)
 }
 
 // ProcessGotSequenceNumber will process the got sequence number message
 func (s *StreamThread) ProcessGotSequenceNumber(msg *SyncMessage) error {
+	if err := s.syncAlert(msg.SequenceNumber, msg.Data); err != nil {
+		return err
+	}
+	return s.requestNextSequence(msg.SequenceNumber)
+}
+
+// ProcessGotBatch will process the got batch message, syncing each alert in order
+// The next sequences are only requested once the batch completes the range we asked for
+// (a range too large for one batch is answered with several)
+func (s *StreamThread) ProcessGotBatch(msg *SyncMessage) error {
+	if len(msg.Batch) == 0 {
+		s.penalize(parseErrorPenalty)
+		return fmt.Errorf("%w: empty batch from peer %s", ErrSyncBatchMalformed, s.peer.String())
+	}
+	sequence := msg.SequenceNumber
+	for i, data := range msg.Batch {
+		sequence = msg.SequenceNumber + uint32(i) //nolint:gosec // a batch has at most MaxSyncBatchCount alerts
+		if err := s.syncAlert(sequence, data); err != nil {
+			return err
+		}
+	}
+	if sequence < s.rangeEnd && sequence < s.latestSequence {
+		s.setMyLatestSequence(sequence)
+		return nil
+	}
+	return s.requestNextSequence(sequence)
+}
+
+// syncAlert will check, execute and save an alert synced from the peer
+// The alert must be the sequence the peer said it is, anything else breaks the protocol
+func (s *StreamThread) syncAlert(sequence uint32, data []byte) error {
 	// Sync with a new alert
-	a, err := models.NewAlertFromBytes(msg.Data, model.WithAllDependencies(s.config), model.New())
+	a, err := models.NewAlertFromBytes(data, model.WithAllDependencies(s.config), model.New())
 	if err != nil {
-		// todo probably want to ban this peer?
+		s.penalize(parseErrorPenalty)
 		return err
+	} else if a.SequenceNumber != sequence {
+		s.penalize(parseErrorPenalty)
+		return fmt.Errorf("%w: got alert %d for sequence %d from peer %s", ErrSyncSequenceMismatch, a.SequenceNumber, sequence, s.peer.String())
+	}
+
+	// Serialize the alert data and hash
+	a.SerializeData()
+	metrics.AlertsReceived.WithLabelValues(a.GetAlertType().String()).Inc()
+
+	// Drop alerts of a disabled type
+	if err = a.CheckTypeEnabled(); err != nil {
+		return s.dropDisabledAlert(a, err)
+	}
+
+	// In lazy mode the alert is stored unverified, its signatures are checked before it's executed by the alert processing
+	if s.config.P2P.LazySyncVerification {
+		if !a.KnownType() {
+			if err = s.checkUnknownAlertType(a); err != nil {
+				return err
+			}
+		}
+		a.Unverified = true
+		return s.saveSyncedAlert(a)
 	}
 
 	// Verify signatures
@@ -223,29 +342,80 @@ func (s *StreamThread) ProcessGotSequenceNumber(msg *SyncMessage) error {
 		return ErrInvalidAlerts
 	}
 
-	// Serialize the alert data and hash
-	a.SerializeData()
-
 	// Process the alert (if it's a set keys alert)
 	// TODO: For now lets just process all alerts... why not?
 	// if a.GetAlertType() == models.AlertTypeSetKeys || a.GetAlertType() == models.AlertTypeInvalidateBlock {
-	ak := a.ProcessAlertMessage()
-	if err = ak.Read(a.GetRawMessage()); err != nil {
+	// The sequence is locked until the alert is saved, so the same alert arriving on the topic at the same time runs once
+	unlock := models.LockSequence(a.SequenceNumber)
+	defer unlock()
+
+	var ak models.AlertBody
+	if ak, err = a.ProcessAlertMessage(); err != nil {
 		return err
-	}
-	a.Processed = true
-	if err = ak.Do(s.ctx); err != nil {
-		s.config.Services.Log.Errorf("failed to process alert %d; err: %v", a.SequenceNumber, err.Error())
+	} else if ak == nil {
+		// Unknown alert type, either reject it or keep it unparsed so it's relayed to other peers
+		if err = s.checkUnknownAlertType(a); err != nil {
+			return err
+		}
+	} else if s.hasAlertPeers != nil && !s.hasAlertPeers() {
+		// Too few peers to trust the alert yet, the alert processing executes it once enough are connected
+		s.config.Services.Log.Infof("storing alert %d unprocessed until %d peers are connected", a.SequenceNumber, s.config.P2P.MinAlertPeers)
 		a.Processed = false
+	} else {
+		a.Processed = true
+		if err = models.ExecuteAlertAction(s.ctx, a, ak, models.AuditSourceSync+":"+s.peer.String()); errors.Is(err, models.ErrPartialSuccess) {
+			// The node applied part of the alert, the rejected outpoints won't succeed on a retry
+			s.config.Services.Log.Warnf("alert %d partially applied: %s", a.SequenceNumber, err.Error())
+		} else if errors.Is(err, models.ErrAlertInvalid) {
+			// The alert breaks the rules of its type, it would fail the same way on a retry
+			s.config.Services.Log.Errorf("rejected alert %d: %s", a.SequenceNumber, err.Error())
+		} else if errors.Is(err, models.ErrSequenceProcessed) {
+			// Already executed and stored by another delivery of the same alert
+			s.config.Services.Log.Debugf("alert %d was already processed by another delivery", a.SequenceNumber)
+			return nil
+		} else if err != nil {
+			s.config.Services.Log.Errorf("failed to process alert %d; err: %v", a.SequenceNumber, err.Error())
+			a.Processed = false
+		}
 	}
 
+	return s.saveSyncedAlert(a)
+}
+
+// saveSyncedAlert will save the synced alert
+func (s *StreamThread) saveSyncedAlert(a *models.AlertMessage) error {
 	// Save the alert
-	if err = a.Save(s.ctx); err != nil {
+	if err := a.Save(s.ctx); err != nil {
+		return err
+	}
+	if err := models.MarkSupersededAlert(s.ctx, a); err != nil {
+		s.config.Services.Log.Errorf("failed to mark alert %d as superseded by %d: %s", a.Supersedes, a.SequenceNumber, err.Error())
+	}
+	return nil
+}
+
+// dropDisabledAlert will drop a synced alert of a disabled type
+// The signatures are still checked, so only a genuine alert can leave a gap in the stored sequence
+func (s *StreamThread) dropDisabledAlert(a *models.AlertMessage, reason error) error {
+	valid, err := a.AreSignaturesValid(s.ctx)
+	if err != nil {
 		return err
+	} else if !valid {
+		s.config.Services.Log.Error(ErrInvalidAlerts.Error())
+		return ErrInvalidAlerts
 	}
 
+	s.config.Services.Log.Infof("dropping alert from peer %s: %s", s.peer.String(), reason.Error())
+	if s.dropped != nil {
+		s.dropped.Drop(s.ctx, a)
+	}
+	return nil
+}
+
+// requestNextSequence will update our latest sequence and request the one after it, unless we are synced
+func (s *StreamThread) requestNextSequence(sequenceNumber uint32) error {
 	// Update the latest sequence
-	s.myLatestSequence = a.SequenceNumber
+	s.setMyLatestSequence(sequenceNumber)
 	if s.myLatestSequence == s.latestSequence {
 		s.config.Services.Log.Infof("successfully synced up to sequence %d", s.latestSequence)
 		_ = s.stream.Close()
@@ -253,14 +423,82 @@ func (s *StreamThread) ProcessGotSequenceNumber(msg *SyncMessage) error {
 	}
 
 	// need to get the next sequence
-	res := SyncMessage{
-		Type:           IWantSequenceNumber,
-		SequenceNumber: a.SequenceNumber + 1,
+	return s.requestFrom(sequenceNumber + 1)
+}
+
+// requestFrom will request the alerts from the sequence up to the peer's latest
+// A peer that speaks ranges is asked for as many as a range holds, any other peer for one alert at a time
+func (s *StreamThread) requestFrom(sequenceNumber uint32) error {
+	if s.peerVersion() < ProtocolVersionRanges || sequenceNumber >= s.latestSequence {
+		return s.writeRequest(&SyncMessage{
+			Type:           IWantSequenceNumber,
+			SequenceNumber: sequenceNumber,
+		})
+	}
+	return s.writeRequest(&SyncMessage{
+		Type:           IWantSequenceRange,
+		SequenceNumber: sequenceNumber,
+		EndSequence:    min(s.latestSequence, sequenceNumber+MaxSyncRangeSize-1),
+	})
+}
+
+// checkUnknownAlertType will decide what to do with an alert type (or version) this node doesn't understand
+// In strict mode an unknown type is rejected, otherwise it's saved unprocessed so it can be served to other peers
+// An alert of a newer version is always saved unprocessed, for forward compatibility
+func (s *StreamThread) checkUnknownAlertType(a *models.AlertMessage) error {
+	if !a.SupportedVersion() {
+		s.config.Services.Log.Infof("relaying alert %d with newer version %d unparsed", a.SequenceNumber, a.Version())
+	} else if s.config.P2P.StrictSyncMessageTypes {
+		return fmt.Errorf("%w: type %d at sequence %d from peer %s", ErrUnknownSyncMessageType, a.GetAlertType(), a.SequenceNumber, s.peer.String())
+	} else {
+		s.config.Services.Log.Infof("relaying alert %d with unknown type %d unparsed", a.SequenceNumber, a.GetAlertType())
+	}
+	a.Processed = false
+	return nil
+}
+
+// ProcessGotTime will process the got time message, checking the peer's clock against ours
+// A peer beyond the max clock skew is only warned about, unless disconnecting is enabled
+func (s *StreamThread) ProcessGotTime(msg *SyncMessage) error {
+	peerTime, err := msg.Time()
+	if err != nil {
+		return err
+	}
+
+	skew, ok := clockSkew(peerTime, s.clock(), s.config.P2P.MaxClockSkew)
+	if s.clocks != nil {
+		s.clocks.Record(s.peer, skew)
+	}
+	if !ok {
+		if s.config.P2P.DisconnectOnClockSkew {
+			return fmt.Errorf("%w: peer %s is off by %s", ErrPeerClockSkew, s.peer.String(), skew.String())
+		}
+		s.config.Services.Log.Warnf("clock of peer %s is off by %s, expiry and freshness checks may disagree", s.peer.String(), skew.String())
 	}
-	writer := util.NewWriter()
-	writer.WriteIntBytes(res.Serialize())
-	_, err = s.stream.Write(writer.Buf)
-	return err
+
+	// Answer with our own clock if the peer opened the stream
+	if !s.sentTime {
+		return s.writeTime()
+	}
+	return nil
+}
+
+// ProcessHello will process the hello message, recording the protocol version negotiated with the peer
+func (s *StreamThread) ProcessHello(msg *SyncMessage) error {
+	advertised, err := msg.ProtocolVersion()
+	if err != nil {
+		return err
+	}
+	if s.versions != nil {
+		negotiated := s.versions.Record(s.peer, advertised)
+		s.config.Services.Log.Debugf("peer %s speaks sync protocol version %d, using version %d", s.peer.String(), advertised, negotiated)
+	}
+
+	// Answer with our own version if the peer opened the stream
+	if !s.sentHello {
+		return s.writeHello()
+	}
+	return nil
 }
 
 // ProcessWantSequenceNumber will process the want sequence number message
@@ -273,20 +511,56 @@ func (s *StreamThread) ProcessWantSequenceNumber(ctx context.Context, msg *SyncM
 		s.config.Services.Log.Error(ErrAlertNotFoundBySequence.Error())
 		return ErrAlertNotFoundBySequence
 	}
-	var data []byte
-	if data, err = hex.DecodeString(a.Raw); err != nil {
-		s.config.Services.Log.Errorf("failed to decode raw alert data: %s", err.Error())
+	return s.writeAlert(IGotSequenceNumber, a)
+}
+
+// ProcessWantSequenceRange will process the want sequence range message, sending the alerts in the range
+// in IGotBatch messages, or each in an IGotSequenceNumber to a peer that never negotiated ranges
+// The range stops early at the first sequence we don't have, which is past our latest alert
+func (s *StreamThread) ProcessWantSequenceRange(ctx context.Context, msg *SyncMessage) error {
+	if err := msg.validateRange(); err != nil {
 		return err
 	}
-	res := SyncMessage{
-		Type:           IGotSequenceNumber,
-		SequenceNumber: a.SequenceNumber,
-		Data:           data,
+	batched := s.peerVersion() >= ProtocolVersionRanges
+	batch := &SyncMessage{Type: IGotBatch, SequenceNumber: msg.SequenceNumber}
+	batchSize := 0
+	for sequence := msg.SequenceNumber; ; sequence++ {
+		a, err := models.GetAlertMessageBySequenceNumber(ctx, sequence, model.WithAllDependencies(s.config))
+		if errors.Is(err, models.ErrAlertNotFound) {
+			break
+		} else if err != nil {
+			s.config.Services.Log.Errorf("failed to get alert %d to send to peer: %s", sequence, err.Error())
+			return err
+		}
+		if !batched {
+			if err = s.writeAlert(IGotSequenceNumber, a); err != nil {
+				return err
+			}
+		} else {
+			var data []byte
+			if data, err = s.storedAlertData(a); err != nil {
+				return err
+			}
+
+			// Send the batch before it grows past the batch size, the next one starts at this alert
+			size := len(util.VarInt(len(data)).Bytes()) + len(data)
+			if len(batch.Batch) > 0 && batchSize+size > MaxSyncBatchSize {
+				if err = s.writeMessage(batch); err != nil {
+					return err
+				}
+				batch, batchSize = &SyncMessage{Type: IGotBatch, SequenceNumber: sequence}, 0
+			}
+			batch.Batch = append(batch.Batch, data)
+			batchSize += size
+		}
+		if sequence == msg.EndSequence {
+			break
+		}
 	}
-	writer := util.NewWriter()
-	writer.WriteIntBytes(res.Serialize())
-	_, err = s.stream.Write(writer.Buf)
-	return err
+	if len(batch.Batch) == 0 {
+		return nil
+	}
+	return s.writeMessage(batch)
 }
 
 // ProcessWantLatest will process the want latest message
@@ -299,20 +573,129 @@ func (s *StreamThread) ProcessWantLatest(ctx context.Context) error {
 		s.config.Services.Log.Error(ErrAlertNotLatest.Error())
 		return ErrAlertNotLatest
 	}
-	s.myLatestSequence = a.SequenceNumber
+	s.setMyLatestSequence(a.SequenceNumber)
+	return s.writeAlert(IGotLatest, a)
+}
 
-	var data []byte
-	if data, err = hex.DecodeString(a.Raw); err != nil {
-		s.config.Services.Log.Errorf("failed to decode raw alert data: %s", err.Error())
+// writeAlert will write the stored alert to the stream as a sync message of the given type
+func (s *StreamThread) writeAlert(msgType byte, a *models.AlertMessage) error {
+	data, err := s.storedAlertData(a)
+	if err != nil {
 		return err
 	}
-	res := SyncMessage{
-		Type:           IGotLatest,
+	return s.writeMessage(&SyncMessage{
+		Type:           msgType,
 		SequenceNumber: a.SequenceNumber,
 		Data:           data,
+	})
+}
+
+// storedAlertData will return the raw bytes of a stored alert to send to the peer
+// A stored alert that doesn't match its hash isn't served, peers would store it under the wrong hash
+// (the genesis alert is created by each node from its config, it isn't read back from its raw bytes)
+func (s *StreamThread) storedAlertData(a *models.AlertMessage) ([]byte, error) {
+	if a.SequenceNumber > 0 {
+		if err := a.VerifyHash(); err != nil {
+			s.config.Services.Log.Errorf("not sending stored alert %d: %s", a.SequenceNumber, err.Error())
+			return nil, err
+		}
+	}
+	data, err := hex.DecodeString(a.Raw)
+	if err != nil {
+		s.config.Services.Log.Errorf("failed to decode raw alert data: %s", err.Error())
+		return nil, err
+	}
+	return data, nil
+}
+
+// writeMessage will write the sync message to the stream, prefixed with its length
+func (s *StreamThread) writeMessage(msg *SyncMessage) error {
+	return WriteFramedSyncMessage(s.stream, msg)
+}
+
+// setMyLatestSequence will set our latest sequence and report it to the sync progress
+func (s *StreamThread) setMyLatestSequence(sequence uint32) {
+	s.myLatestSequence = sequence
+	if s.progress != nil {
+		s.progress.SetLocal(sequence)
+	}
+}
+
+// reportAdvertised will report the latest sequence the peer advertised to the sync progress
+func (s *StreamThread) reportAdvertised(sequence uint32) {
+	if s.progress != nil {
+		s.progress.Advertise(s.peer, sequence)
+	}
+}
+
+// isSolicited will check that a response from the peer matches a request we sent (and complete it)
+// Requests from the peer are always accepted
+func (s *StreamThread) isSolicited(msg *SyncMessage) bool {
+	if s.requests == nil || (msg.Type != IGotLatest && msg.Type != IGotSequenceNumber && msg.Type != IGotBatch) {
+		return true
+	}
+	return s.requests.Complete(s.peer, msg)
+}
+
+// penalize will add the points to the peer's misbehavior score, returning true if that got the peer banned
+func (s *StreamThread) penalize(points float64) bool {
+	if s.scores == nil {
+		return false
+	}
+	return s.scores.Penalize(s.peer, points)
+}
+
+// countRequest will count a sync request from the peer, returning true if that got the peer banned
+func (s *StreamThread) countRequest() bool {
+	if s.scores == nil {
+		return false
+	}
+	return s.scores.Request(s.peer)
+}
+
+// clock will return the current time for the handshake
+func (s *StreamThread) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// writeTime will write our clock to the stream
+func (s *StreamThread) writeTime() error {
+	s.sentTime = true
+	return s.writeMessage(newTimeMessage(s.clock()))
+}
+
+// writeHello will write our protocol version to the stream
+func (s *StreamThread) writeHello() error {
+	s.sentHello = true
+	return s.writeMessage(newHelloMessage(ProtocolVersionCurrent))
+}
+
+// peerVersion returns the protocol version negotiated with the peer, the legacy version if it never said hello
+func (s *StreamThread) peerVersion() uint32 {
+	if s.versions != nil {
+		if version, ok := s.versions.Version(s.peer); ok {
+			return version
+		}
+	}
+	return ProtocolVersionLegacy
+}
+
+// writeRequest will track the sync request for the peer and write it to the stream
+// A request the peer doesn't understand is never sent
+func (s *StreamThread) writeRequest(msg *SyncMessage) error {
+	if needed := messageVersion(msg.Type); needed > s.peerVersion() {
+		return fmt.Errorf("%w: type %d needs version %d, peer %s speaks version %d", ErrSyncMessageNotSupported, msg.Type, needed, s.peer.String(), s.peerVersion())
+	}
+	if s.requests != nil {
+		if err := s.requests.Track(s.peer, msg); err != nil {
+			return err
+		}
+	}
+	if msg.Type == IWantSequenceRange {
+		s.rangeEnd = msg.EndSequence
 	}
-	writer := util.NewWriter()
-	writer.WriteIntBytes(res.Serialize())
-	_, err = s.stream.Write(writer.Buf)
-	return err
+	return WriteFramedSyncMessage(s.stream, msg)
 }
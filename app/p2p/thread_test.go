@@ -0,0 +1,607 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/util"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// fakeStream is a stream that records writes and closes
+type fakeStream struct {
+	network.Stream
+
+	closed  bool
+	written [][]byte
+}
+
+// Close marks the stream as closed
+func (f *fakeStream) Close() error {
+	f.closed = true
+	return nil
+}
+
+// Write records the written bytes
+func (f *fakeStream) Write(b []byte) (int, error) {
+	f.written = append(f.written, b)
+	return len(b), nil
+}
+
+// loadTestDependencies will load the test config with a genesis alert in the datastore
+func loadTestDependencies(t *testing.T) *config.Config {
+	require.NoError(t, os.Setenv(config.EnvironmentKey, config.EnvironmentTest))
+	deps, err := config.LoadDependencies(context.Background(), models.BaseModels, true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		deps.CloseAll(context.Background())
+	})
+	require.NoError(t, models.CreateGenesisAlert(context.Background(), model.WithAllDependencies(deps)))
+	return deps
+}
+
+// auditEntries will count the executed alert actions
+func auditEntries(t *testing.T, deps *config.Config) int {
+	entries, err := models.GetAuditEntries(context.Background(), nil, nil, model.WithAllDependencies(deps))
+	require.NoError(t, err)
+	return len(entries)
+}
+
+// newSignedAlert will create a genesis signed alert of the given type
+func newSignedAlert(t *testing.T, deps *config.Config, sequence uint32, alertType models.AlertType, body []byte) []byte {
+	return newSignedAlertVersion(t, deps, 1, sequence, alertType, body)
+}
+
+// newSignedAlertVersion will create a genesis signed alert of the given version and type
+func newSignedAlertVersion(t *testing.T, deps *config.Config, version, sequence uint32, alertType models.AlertType, body []byte) []byte {
+	a := models.NewAlertMessage(model.WithAllDependencies(deps), model.New())
+	a.SetVersion(version)
+	a.SetTimestamp(1700000000)
+	a.SetAlertType(alertType)
+	a.SetRawMessage(body)
+	a.SequenceNumber = sequence
+	a.SerializeData()
+
+	sigs, err := utils.SignWithGenesis(a.GetRawData())
+	require.NoError(t, err)
+	a.SetSignatures(sigs)
+	return a.Serialize()
+}
+
+// TestStreamThread_ProcessGotSequenceNumber_UnknownType will test the method ProcessGotSequenceNumber()
+func TestStreamThread_ProcessGotSequenceNumber_UnknownType(t *testing.T) {
+	unknownType := models.AlertType(250)
+
+	t.Run("relay mode stores the alert unparsed", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.P2P.StrictSyncMessageTypes = false
+
+		stream := &fakeStream{}
+		s := &StreamThread{
+			config:         deps,
+			ctx:            context.Background(),
+			latestSequence: 1,
+			peer:           peer.ID("peer-a"),
+			stream:         stream,
+		}
+
+		err := s.ProcessGotSequenceNumber(&SyncMessage{
+			Type:           IGotSequenceNumber,
+			SequenceNumber: 1,
+			Data:           newSignedAlert(t, deps, 1, unknownType, []byte("from the future")),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), s.myLatestSequence)
+		assert.True(t, stream.closed)
+
+		// The alert can now be served to other peers
+		a, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+		require.NoError(t, err)
+		require.NotNil(t, a)
+		assert.False(t, a.Processed)
+		require.NoError(t, a.ReadRaw())
+		assert.Equal(t, unknownType, a.GetAlertType())
+	})
+
+	t.Run("strict mode rejects the alert", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+		deps.P2P.StrictSyncMessageTypes = true
+
+		stream := &fakeStream{}
+		s := &StreamThread{
+			config:         deps,
+			ctx:            context.Background(),
+			latestSequence: 1,
+			peer:           peer.ID("peer-a"),
+			stream:         stream,
+		}
+
+		err := s.ProcessGotSequenceNumber(&SyncMessage{
+			Type:           IGotSequenceNumber,
+			SequenceNumber: 1,
+			Data:           newSignedAlert(t, deps, 1, unknownType, []byte("from the future")),
+		})
+		require.ErrorIs(t, err, ErrUnknownSyncMessageType)
+		assert.Empty(t, stream.written)
+
+		// Nothing was stored
+		a, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+		require.ErrorIs(t, err, models.ErrAlertNotFound)
+		assert.Nil(t, a)
+	})
+}
+
+// TestStreamThread_ProcessGotSequenceNumber_FutureVersion will test the method ProcessGotSequenceNumber()
+func TestStreamThread_ProcessGotSequenceNumber_FutureVersion(t *testing.T) {
+	deps := loadTestDependencies(t)
+	deps.P2P.StrictSyncMessageTypes = true // Strict mode only rejects unknown types, not newer versions
+
+	stream := &fakeStream{}
+	s := &StreamThread{
+		config:         deps,
+		ctx:            context.Background(),
+		latestSequence: 1,
+		peer:           peer.ID("peer-a"),
+		stream:         stream,
+	}
+	data := newSignedAlertVersion(t, deps, models.AlertVersionCurrent+1, 1, models.AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+	require.NoError(t, s.ProcessGotSequenceNumber(&SyncMessage{
+		Type:           IGotSequenceNumber,
+		SequenceNumber: 1,
+		Data:           data,
+	}))
+	assert.True(t, stream.closed)
+
+	// Stored as received so it's served to other peers, but never executed
+	a, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+	require.NoError(t, err)
+	assert.False(t, a.Processed)
+	require.NoError(t, a.ReadRaw())
+	assert.Equal(t, data, a.Serialize())
+
+	entries, err := models.GetAuditEntries(context.Background(), nil, nil, model.WithAllDependencies(deps))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// TestStreamThread_ProcessGotSequenceNumber_LazyVerification will test the method ProcessGotSequenceNumber()
+func TestStreamThread_ProcessGotSequenceNumber_LazyVerification(t *testing.T) {
+	body := []byte{0x05, 'h', 'e', 'l', 'l', 'o'}
+
+	// syncLazily will sync a single alert with lazy verification and load it back from the datastore
+	syncLazily := func(t *testing.T, deps *config.Config, data []byte) (*models.AlertMessage, models.AlertMessageInterface) {
+		deps.P2P.LazySyncVerification = true
+		s := &StreamThread{
+			config:         deps,
+			ctx:            context.Background(),
+			latestSequence: 1,
+			peer:           peer.ID("peer-a"),
+			stream:         &fakeStream{},
+		}
+		require.NoError(t, s.ProcessGotSequenceNumber(&SyncMessage{
+			Type:           IGotSequenceNumber,
+			SequenceNumber: 1,
+			Data:           data,
+		}))
+
+		a, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+		require.NoError(t, err)
+		a.SetOptions(model.WithAllDependencies(deps))
+		require.NoError(t, a.ReadRaw())
+		action, err := a.ProcessAlertMessage()
+		require.NoError(t, err)
+		return a, action
+	}
+
+	t.Run("stores the alert unverified without executing it", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+
+		a, action := syncLazily(t, deps, newSignedAlert(t, deps, 1, models.AlertTypeInformational, body))
+		assert.True(t, a.Unverified)
+		assert.False(t, a.Processed)
+		assert.Equal(t, 0, auditEntries(t, deps))
+
+		// Verified before it's executed
+		require.NoError(t, models.ExecuteAlertAction(context.Background(), a, action, models.AuditSourceRetry))
+		assert.False(t, a.Unverified)
+		assert.Equal(t, 1, auditEntries(t, deps))
+	})
+
+	t.Run("never executes an alert with invalid signatures", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+
+		// Tamper with the message after it was signed
+		data := newSignedAlert(t, deps, 1, models.AlertTypeInformational, body)
+		data[models.AlertHeaderSize+1] = 'j'
+
+		a, action := syncLazily(t, deps, data)
+		assert.True(t, a.Unverified)
+
+		require.ErrorIs(t, models.ExecuteAlertAction(context.Background(), a, action, models.AuditSourceRetry), models.ErrAlertNotVerified)
+		assert.True(t, a.Unverified)
+		assert.Equal(t, 0, auditEntries(t, deps))
+	})
+
+	t.Run("eager mode rejects an alert with invalid signatures", func(t *testing.T) {
+		deps := loadTestDependencies(t)
+
+		data := newSignedAlert(t, deps, 1, models.AlertTypeInformational, body)
+		data[models.AlertHeaderSize+1] = 'j'
+
+		s := &StreamThread{
+			config:         deps,
+			ctx:            context.Background(),
+			latestSequence: 1,
+			peer:           peer.ID("peer-a"),
+			stream:         &fakeStream{},
+		}
+		require.ErrorIs(t, s.ProcessGotSequenceNumber(&SyncMessage{
+			Type:           IGotSequenceNumber,
+			SequenceNumber: 1,
+			Data:           data,
+		}), ErrInvalidAlerts)
+
+		_, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+		require.ErrorIs(t, err, models.ErrAlertNotFound)
+	})
+}
+
+// TestStreamThread_ProcessGotSequenceNumber_MinAlertPeers will test the method ProcessGotSequenceNumber() with too few peers
+func TestStreamThread_ProcessGotSequenceNumber_MinAlertPeers(t *testing.T) {
+	deps := loadTestDependencies(t)
+	s := &StreamThread{
+		config:         deps,
+		ctx:            context.Background(),
+		hasAlertPeers:  func() bool { return false },
+		latestSequence: 1,
+		peer:           peer.ID("peer-a"),
+		stream:         &fakeStream{},
+	}
+	require.NoError(t, s.ProcessGotSequenceNumber(&SyncMessage{
+		Type:           IGotSequenceNumber,
+		SequenceNumber: 1,
+		Data:           newSignedAlert(t, deps, 1, models.AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'}),
+	}))
+
+	// Stored for the alert processing, without executing it
+	a, err := models.GetAlertMessageBySequenceNumber(context.Background(), 1, model.WithAllDependencies(deps))
+	require.NoError(t, err)
+	assert.False(t, a.Processed)
+	entries, err := models.GetAuditEntries(context.Background(), nil, nil, model.WithAllDependencies(deps))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// TestStreamThread_IsSolicited will test the method isSolicited()
+func TestStreamThread_IsSolicited(t *testing.T) {
+	t.Parallel()
+
+	s := &StreamThread{
+		peer:     peer.ID("peer-a"),
+		requests: newRequestTracker(5, time.Minute),
+	}
+	require.NoError(t, s.requests.Track(s.peer, &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 3}))
+
+	// Requests from the peer are always handled
+	assert.True(t, s.isSolicited(&SyncMessage{Type: IWantLatest}))
+	assert.True(t, s.isSolicited(&SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 9}))
+
+	// Responses must match a request
+	assert.False(t, s.isSolicited(&SyncMessage{Type: IGotLatest, SequenceNumber: 10}))
+	assert.False(t, s.isSolicited(&SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 4}))
+	assert.True(t, s.isSolicited(&SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 3}))
+
+	// Only once
+	assert.False(t, s.isSolicited(&SyncMessage{Type: IGotSequenceNumber, SequenceNumber: 3}))
+}
+
+// TestStreamThread_ProcessWantSequenceRange will test the method ProcessWantSequenceRange()
+func TestStreamThread_ProcessWantSequenceRange(t *testing.T) {
+	deps := loadTestDependencies(t)
+	for sequence := uint32(1); sequence <= 3; sequence++ {
+		a, err := models.NewAlertFromBytes(
+			newSignedAlert(t, deps, sequence, models.AlertTypeInformational, []byte{0x02, 'h', 'i'}),
+			model.WithAllDependencies(deps), model.New(),
+		)
+		require.NoError(t, err)
+		require.NoError(t, a.Save(context.Background()))
+	}
+
+	// sequences will read the sequence of each alert written to the stream
+	sequences := func(t *testing.T, stream *fakeStream) []uint32 {
+		var got []uint32
+		for _, written := range stream.written {
+			msg, err := NewSyncMessageFromBytes(written[1:]) // skip the varint length
+			require.NoError(t, err)
+			require.Equal(t, byte(IGotSequenceNumber), msg.Type)
+			got = append(got, msg.SequenceNumber)
+		}
+		return got
+	}
+
+	t.Run("sends each alert in the range", func(t *testing.T) {
+		stream := &fakeStream{}
+		s := &StreamThread{config: deps, peer: peer.ID("peer-a"), stream: stream}
+		require.NoError(t, s.ProcessWantSequenceRange(context.Background(), &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 0, EndSequence: 2}))
+		assert.Equal(t, []uint32{0, 1, 2}, sequences(t, stream))
+	})
+
+	t.Run("stops after the latest alert", func(t *testing.T) {
+		stream := &fakeStream{}
+		s := &StreamThread{config: deps, peer: peer.ID("peer-a"), stream: stream}
+		require.NoError(t, s.ProcessWantSequenceRange(context.Background(), &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 2, EndSequence: 10}))
+		assert.Equal(t, []uint32{2, 3}, sequences(t, stream))
+	})
+
+	t.Run("start after the end", func(t *testing.T) {
+		stream := &fakeStream{}
+		s := &StreamThread{config: deps, peer: peer.ID("peer-a"), stream: stream}
+		require.ErrorIs(t, s.ProcessWantSequenceRange(context.Background(), &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 3, EndSequence: 1}), ErrSyncRangeInvalid)
+		assert.Empty(t, stream.written)
+	})
+
+	t.Run("sends a batch to a peer that speaks ranges", func(t *testing.T) {
+		stream := &fakeStream{}
+		s := &StreamThread{config: deps, peer: peer.ID("peer-a"), stream: stream, versions: newPeerVersions()}
+		s.versions.Record(s.peer, ProtocolVersionRanges)
+		require.NoError(t, s.ProcessWantSequenceRange(context.Background(), &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 1, EndSequence: 10}))
+		require.Len(t, stream.written, 1)
+
+		reader := util.NewReader(stream.written[0])
+		_, err := reader.ReadVarInt() // skip the length
+		require.NoError(t, err)
+		msg, err := NewSyncMessageFromBytes(reader.Data[reader.Pos:])
+		require.NoError(t, err)
+		assert.Equal(t, byte(IGotBatch), msg.Type)
+		assert.Equal(t, uint32(1), msg.SequenceNumber)
+		require.Len(t, msg.Batch, 3)
+		for i, data := range msg.Batch {
+			a, readErr := models.NewAlertFromBytes(data, model.WithAllDependencies(deps))
+			require.NoError(t, readErr)
+			assert.Equal(t, i+1, int(a.SequenceNumber))
+		}
+	})
+
+	t.Run("range too large", func(t *testing.T) {
+		stream := &fakeStream{}
+		s := &StreamThread{config: deps, peer: peer.ID("peer-a"), stream: stream}
+		require.NoError(t, s.ProcessWantSequenceRange(context.Background(), &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 1, EndSequence: MaxSyncRangeSize}))
+		require.ErrorIs(t, s.ProcessWantSequenceRange(context.Background(), &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 1, EndSequence: MaxSyncRangeSize + 1}), ErrSyncRangeTooLarge)
+	})
+}
+
+// TestStreamThread_ProcessWantSequenceNumber_HashMismatch will test a stored alert that doesn't match its hash isn't served
+func TestStreamThread_ProcessWantSequenceNumber_HashMismatch(t *testing.T) {
+	deps := loadTestDependencies(t)
+	a, err := models.NewAlertFromBytes(
+		newSignedAlert(t, deps, 1, models.AlertTypeInformational, []byte{0x02, 'h', 'i'}),
+		model.WithAllDependencies(deps), model.New(),
+	)
+	require.NoError(t, err)
+	a.SerializeData()
+	a.Hash = strings.Repeat("ab", 32)
+	require.NoError(t, a.Save(context.Background()))
+
+	stream := &fakeStream{}
+	s := &StreamThread{config: deps, peer: peer.ID("peer-a"), stream: stream}
+	require.ErrorIs(t, s.ProcessWantSequenceNumber(context.Background(), &SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 1}), models.ErrAlertHashMismatch)
+	assert.Empty(t, stream.written)
+
+	// The alerts before it in a range are still served
+	require.ErrorIs(t, s.ProcessWantSequenceRange(context.Background(), &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 0, EndSequence: 1}), models.ErrAlertHashMismatch)
+	assert.Len(t, stream.written, 1)
+}
+
+// TestStreamThread_ProcessGotSequenceNumber_Duplicate will test that an alert synced from two peers runs once
+func TestStreamThread_ProcessGotSequenceNumber_Duplicate(t *testing.T) {
+	deps := loadTestDependencies(t)
+	data := newSignedAlert(t, deps, 1, models.AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'})
+
+	for _, p := range []peer.ID{"peer-a", "peer-b"} {
+		s := &StreamThread{
+			config:         deps,
+			ctx:            context.Background(),
+			latestSequence: 1,
+			peer:           p,
+			stream:         &fakeStream{},
+		}
+		require.NoError(t, s.ProcessGotSequenceNumber(&SyncMessage{
+			Type:           IGotSequenceNumber,
+			SequenceNumber: 1,
+			Data:           data,
+		}))
+		assert.Equal(t, uint32(1), s.myLatestSequence)
+	}
+
+	// Only the first delivery was executed
+	entries, err := models.GetAuditEntries(context.Background(), nil, nil, model.WithAllDependencies(deps))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditSourceSync+":"+peer.ID("peer-a").String(), entries[0].Source)
+}
+
+// TestStreamThread_ProcessGotBatch will test the method ProcessGotBatch()
+func TestStreamThread_ProcessGotBatch(t *testing.T) {
+	deps := loadTestDependencies(t)
+	alerts := [][]byte{
+		newSignedAlert(t, deps, 1, models.AlertTypeInformational, []byte{0x02, 'h', 'i'}),
+		newSignedAlert(t, deps, 2, models.AlertTypeInformational, []byte{0x02, 'h', 'i'}),
+		newSignedAlert(t, deps, 3, models.AlertTypeInformational, []byte{0x02, 'h', 'i'}),
+	}
+
+	// newBatchThread will create a stream thread waiting on a range of the peer's alerts up to sequence 3
+	newBatchThread := func(stream *fakeStream) *StreamThread {
+		return &StreamThread{
+			config:         deps,
+			ctx:            context.Background(),
+			latestSequence: 3,
+			peer:           peer.ID("peer-a"),
+			rangeEnd:       3,
+			scores:         newPeerScores(100, 0, time.Minute, 0),
+			stream:         stream,
+		}
+	}
+
+	t.Run("alerts that aren't the sequences of the batch", func(t *testing.T) {
+		s := newBatchThread(&fakeStream{})
+		err := s.ProcessGotBatch(&SyncMessage{Type: IGotBatch, SequenceNumber: 1, Batch: [][]byte{alerts[1]}})
+		require.ErrorIs(t, err, ErrSyncSequenceMismatch)
+		assert.Positive(t, s.scores.Score(s.peer))
+	})
+
+	t.Run("an alert that can't be parsed", func(t *testing.T) {
+		s := newBatchThread(&fakeStream{})
+		err := s.ProcessGotBatch(&SyncMessage{Type: IGotBatch, SequenceNumber: 1, Batch: [][]byte{alerts[0][:10]}})
+		require.Error(t, err)
+		assert.Positive(t, s.scores.Score(s.peer))
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		s := newBatchThread(&fakeStream{})
+		require.ErrorIs(t, s.ProcessGotBatch(&SyncMessage{Type: IGotBatch, SequenceNumber: 1}), ErrSyncBatchMalformed)
+	})
+
+	t.Run("waits for the rest of the range", func(t *testing.T) {
+		stream := &fakeStream{}
+		s := newBatchThread(stream)
+		require.NoError(t, s.ProcessGotBatch(&SyncMessage{Type: IGotBatch, SequenceNumber: 1, Batch: alerts[:2]}))
+		assert.Equal(t, uint32(2), s.myLatestSequence)
+		assert.Empty(t, stream.written)
+		assert.False(t, stream.closed)
+
+		require.NoError(t, s.ProcessGotBatch(&SyncMessage{Type: IGotBatch, SequenceNumber: 3, Batch: alerts[2:]}))
+		assert.Equal(t, uint32(3), s.myLatestSequence)
+		assert.True(t, stream.closed)
+	})
+}
+
+// recordingStream is a stream that records the type of each sync message written to it
+type recordingStream struct {
+	network.Stream
+
+	sync.Mutex
+	types []byte
+}
+
+// Write records the type of the sync message after its length
+func (r *recordingStream) Write(b []byte) (int, error) {
+	var length util.VarInt
+	if n, err := length.ReadFrom(bytes.NewReader(b)); err == nil && int(n) < len(b) {
+		r.Lock()
+		r.types = append(r.types, b[n])
+		r.Unlock()
+	}
+	return r.Stream.Write(b)
+}
+
+// written returns the types of the sync messages written so far
+func (r *recordingStream) written() []byte {
+	r.Lock()
+	defer r.Unlock()
+	return slices.Clone(r.types)
+}
+
+// syncWithTestPeer will sync a node from a peer with the given number of alerts over a mock network, like the server does
+// It returns our config, our stream thread and the sync message types the peer wrote
+func syncWithTestPeer(t *testing.T, alerts uint32) (*config.Config, *StreamThread, []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	// The peer has alerts we don't have
+	behind, ahead := loadTestDependencies(t), loadTestDependencies(t)
+	for sequence := uint32(1); sequence <= alerts; sequence++ {
+		a, err := models.NewAlertFromBytes(
+			newSignedAlert(t, ahead, sequence, models.AlertTypeInformational, []byte{0x02, 'h', 'i'}),
+			model.WithAllDependencies(ahead), model.New(),
+		)
+		require.NoError(t, err)
+		a.Processed = true
+		require.NoError(t, a.Save(ctx))
+	}
+
+	net, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = net.Close()
+	})
+	hosts := net.Hosts()
+	const syncProtocol = protocol.ID("/test/sync")
+
+	// The peer answers our stream like the server's stream handler
+	served := make(chan *recordingStream, 1)
+	hosts[1].SetStreamHandler(syncProtocol, func(stream network.Stream) {
+		recorder := &recordingStream{Stream: stream}
+		responder := &StreamThread{
+			config:   ahead,
+			ctx:      ctx,
+			peer:     stream.Conn().RemotePeer(),
+			stream:   recorder,
+			versions: newPeerVersions(),
+		}
+		_ = responder.ProcessSyncMessage(ctx)
+		_ = stream.Close()
+		served <- recorder
+	})
+
+	stream, err := hosts[0].NewStream(ctx, hosts[1].ID(), syncProtocol)
+	require.NoError(t, err)
+	requester := &StreamThread{
+		config:   behind,
+		ctx:      ctx,
+		peer:     hosts[1].ID(),
+		requests: newRequestTracker(5, time.Minute),
+		stream:   stream,
+		versions: newPeerVersions(),
+	}
+	require.NoError(t, requester.Sync(ctx))
+
+	// We have the alerts
+	for sequence := uint32(1); sequence <= alerts; sequence++ {
+		a, getErr := models.GetAlertMessageBySequenceNumber(ctx, sequence, model.WithAllDependencies(behind))
+		require.NoError(t, getErr)
+		assert.True(t, a.Processed)
+	}
+	assert.Equal(t, alerts, requester.myLatestSequence)
+	assert.Equal(t, 0, requester.requests.Total())
+
+	select {
+	case recorder := <-served:
+		return behind, requester, recorder.written()
+	case <-ctx.Done():
+		t.Fatal("the peer never finished serving the stream")
+	}
+	return nil, nil, nil
+}
+
+// TestStreamThread_SyncRange will test a node catching up on a peer that speaks ranges gets the alerts in a batch
+func TestStreamThread_SyncRange(t *testing.T) {
+	_, requester, written := syncWithTestPeer(t, 3)
+
+	// We asked for the alerts in one range answered by one batch
+	assert.Equal(t, uint32(3), requester.rangeEnd)
+	assert.Equal(t, []byte{IGotTime, IHello, IGotLatest, IGotBatch}, written)
+}
+
+// TestStreamThread_SyncSequenceNumber will test a node one alert behind a peer asks for it alone and gets it
+func TestStreamThread_SyncSequenceNumber(t *testing.T) {
+	_, requester, written := syncWithTestPeer(t, 1)
+
+	assert.Zero(t, requester.rangeEnd)
+	assert.Equal(t, []byte{IGotTime, IHello, IGotLatest, IGotSequenceNumber}, written)
+}
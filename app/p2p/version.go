@@ -0,0 +1,66 @@
+package p2p
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Sync protocol versions, advertised in an IHello when a stream is opened
+const (
+	// ProtocolVersionLegacy is the protocol of a peer that never says hello:
+	// IWantLatest, IWantSequenceNumber, IGotSequenceNumber, IGotLatest and IGotTime
+	ProtocolVersionLegacy uint32 = 1
+
+	// ProtocolVersionRanges adds IWantSequenceRange and IGotBatch
+	ProtocolVersionRanges uint32 = 2
+
+	// ProtocolVersionCurrent is the protocol version spoken by this node
+	ProtocolVersionCurrent = ProtocolVersionRanges
+)
+
+// messageVersion returns the protocol version a peer needs to understand the sync message type
+// IHello is safe to send to any peer, older peers ignore it
+func messageVersion(msgType byte) uint32 {
+	switch msgType {
+	case IWantSequenceRange, IGotBatch:
+		return ProtocolVersionRanges
+	default:
+		return ProtocolVersionLegacy
+	}
+}
+
+// isKnownSyncMessageType returns true if the sync message type is part of the protocol spoken by this node
+func isKnownSyncMessageType(msgType byte) bool {
+	return msgType >= IWantLatest && msgType <= IHello
+}
+
+// peerVersions tracks the protocol version negotiated with each peer
+type peerVersions struct {
+	sync.Mutex
+	versions map[peer.ID]uint32
+}
+
+// newPeerVersions will create a new peer version tracker
+func newPeerVersions() *peerVersions {
+	return &peerVersions{
+		versions: make(map[peer.ID]uint32),
+	}
+}
+
+// Record records the version the peer advertised, negotiated down to the highest version we both speak
+func (v *peerVersions) Record(peerID peer.ID, advertised uint32) uint32 {
+	negotiated := min(max(advertised, ProtocolVersionLegacy), ProtocolVersionCurrent)
+	v.Lock()
+	defer v.Unlock()
+	v.versions[peerID] = negotiated
+	return negotiated
+}
+
+// Version returns the protocol version negotiated with the peer, false if the peer never said hello
+func (v *peerVersions) Version(peerID peer.ID) (uint32, bool) {
+	v.Lock()
+	defer v.Unlock()
+	version, ok := v.versions[peerID]
+	return version, ok
+}
@@ -0,0 +1,84 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeerVersions_Record will test the method Record()
+func TestPeerVersions_Record(t *testing.T) {
+	t.Parallel()
+
+	v := newPeerVersions()
+	_, ok := v.Version("peer-a")
+	assert.False(t, ok)
+
+	// A newer peer is talked to in our version, an older one in its own
+	assert.Equal(t, ProtocolVersionCurrent, v.Record("peer-a", ProtocolVersionCurrent+5))
+	assert.Equal(t, ProtocolVersionLegacy, v.Record("peer-b", ProtocolVersionLegacy))
+	assert.Equal(t, ProtocolVersionLegacy, v.Record("peer-c", 0))
+
+	version, ok := v.Version("peer-a")
+	require.True(t, ok)
+	assert.Equal(t, ProtocolVersionCurrent, version)
+}
+
+// TestStreamThread_ProcessHello will test the method ProcessHello()
+func TestStreamThread_ProcessHello(t *testing.T) {
+	// newHelloThread will create a stream thread that tracks peer versions
+	newHelloThread := func(t *testing.T) (*StreamThread, *fakeStream) {
+		stream := &fakeStream{}
+		return &StreamThread{
+			config:   loadTestDependencies(t),
+			ctx:      context.Background(),
+			peer:     peer.ID("peer-a"),
+			stream:   stream,
+			versions: newPeerVersions(),
+		}, stream
+	}
+
+	t.Run("version is recorded and answered once", func(t *testing.T) {
+		s, stream := newHelloThread(t)
+		require.NoError(t, s.ProcessHello(newHelloMessage(ProtocolVersionRanges)))
+		assert.Equal(t, ProtocolVersionRanges, s.peerVersion())
+
+		require.Len(t, stream.written, 1)
+		msg, err := NewSyncMessageFromBytes(stream.written[0][1:]) // skip the varint length
+		require.NoError(t, err)
+		version, err := msg.ProtocolVersion()
+		require.NoError(t, err)
+		assert.Equal(t, ProtocolVersionCurrent, version)
+
+		require.NoError(t, s.ProcessHello(newHelloMessage(ProtocolVersionRanges)))
+		assert.Len(t, stream.written, 1)
+	})
+
+	t.Run("short message", func(t *testing.T) {
+		s, stream := newHelloThread(t)
+		require.ErrorIs(t, s.ProcessHello(&SyncMessage{Type: IHello, Data: []byte{1}}), ErrSyncHelloFourBytes)
+		assert.Equal(t, ProtocolVersionLegacy, s.peerVersion())
+		assert.Empty(t, stream.written)
+	})
+}
+
+// TestStreamThread_WriteRequest_Version will test the version check of the method writeRequest()
+func TestStreamThread_WriteRequest_Version(t *testing.T) {
+	t.Parallel()
+
+	stream := &fakeStream{}
+	s := &StreamThread{peer: peer.ID("peer-a"), stream: stream, versions: newPeerVersions()}
+	rangeRequest := &SyncMessage{Type: IWantSequenceRange, SequenceNumber: 1, EndSequence: 5}
+
+	// A peer that never said hello only gets the legacy messages
+	require.ErrorIs(t, s.writeRequest(rangeRequest), ErrSyncMessageNotSupported)
+	require.NoError(t, s.writeRequest(&SyncMessage{Type: IWantSequenceNumber, SequenceNumber: 1}))
+	assert.Len(t, stream.written, 1)
+
+	s.versions.Record(s.peer, ProtocolVersionRanges)
+	require.NoError(t, s.writeRequest(rangeRequest))
+	assert.Len(t, stream.written, 2)
+}
@@ -0,0 +1,72 @@
+package p2p
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+	"github.com/bsv-blockchain/go-alert-system/app/webhook"
+)
+
+// countingHTTPClient counts the webhook requests it's sent
+type countingHTTPClient struct {
+	fail     atomic.Bool
+	requests atomic.Int32
+}
+
+// Do will count the request and answer with a 200, or a 400 while failing
+func (c *countingHTTPClient) Do(_ *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	status := http.StatusOK
+	if c.fail.Load() {
+		status = http.StatusBadRequest
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+// TestServer_SendWebhook will test the method sendWebhook()
+func TestServer_SendWebhook(t *testing.T) {
+	deps := loadTestDependencies(t)
+	client := &countingHTTPClient{}
+	deps.Services.HTTPClient = client
+	deps.AlertWebhookURL = "https://webhook.url"
+
+	ak, err := models.NewAlertFromBytes(
+		newSignedAlert(t, deps, 1, models.AlertTypeInformational, []byte{0x05, 'h', 'e', 'l', 'l', 'o'}),
+		model.WithAllDependencies(deps),
+	)
+	require.NoError(t, err)
+
+	window := 50 * time.Millisecond
+	s := &Server{config: deps, webhooks: webhook.NewDedup(window)}
+
+	// Processing the same alert twice within the window sends one webhook
+	s.sendWebhook(context.Background(), ak)
+	s.sendWebhook(context.Background(), ak)
+	assert.Equal(t, int32(1), client.requests.Load())
+
+	// Once the window has passed, a reprocess sends it again
+	time.Sleep(window)
+	s.sendWebhook(context.Background(), ak)
+	assert.Equal(t, int32(2), client.requests.Load())
+
+	// A failed delivery doesn't count as sent, the next reprocess within the window sends it again
+	time.Sleep(window)
+	client.fail.Store(true)
+	s.sendWebhook(context.Background(), ak)
+	assert.Equal(t, int32(3), client.requests.Load())
+	client.fail.Store(false)
+	s.sendWebhook(context.Background(), ak)
+	assert.Equal(t, int32(4), client.requests.Load())
+	s.sendWebhook(context.Background(), ak)
+	assert.Equal(t, int32(4), client.requests.Load())
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+// Dedup remembers the alerts the webhook was sent for, keyed by alert hash
+// An alert can be processed more than once (gossiped by several peers, re-broadcast), but the webhook
+// for it is only sent once within the window
+type Dedup struct {
+	sync.Mutex
+	now    func() time.Time
+	sent   map[string]time.Time
+	window time.Duration
+}
+
+// NewDedup will create a new webhook de-duplicator with the given window
+func NewDedup(window time.Duration) *Dedup {
+	return &Dedup{
+		now:    time.Now,
+		sent:   make(map[string]time.Time),
+		window: window,
+	}
+}
+
+// Allow returns true if the webhook for the alert should be sent, and records it as sent
+// A delivery that then fails is forgotten (see Forget), so the alert isn't suppressed for the window
+func (d *Dedup) Allow(hash string) bool {
+	d.Lock()
+	defer d.Unlock()
+
+	// Forget the alerts whose window has passed, so the map doesn't grow forever
+	now := d.now()
+	for h, sentAt := range d.sent {
+		if now.Sub(sentAt) >= d.window {
+			delete(d.sent, h)
+		}
+	}
+
+	if _, ok := d.sent[hash]; ok {
+		return false
+	}
+	d.sent[hash] = now
+	return true
+}
+
+// Forget will drop the record of the alert's webhook, after its delivery failed, so it can be sent again
+func (d *Dedup) Forget(hash string) {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.sent, hash)
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDedup_Allow will test the method Allow()
+func TestDedup_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewDedup(time.Minute)
+	d.now = func() time.Time {
+		return now
+	}
+
+	// The same alert within the window is only sent once
+	assert.True(t, d.Allow("hash-a"))
+	assert.False(t, d.Allow("hash-a"))
+	assert.True(t, d.Allow("hash-b"))
+
+	now = now.Add(59 * time.Second)
+	assert.False(t, d.Allow("hash-a"))
+
+	// After the window it can be sent again
+	now = now.Add(time.Second)
+	assert.True(t, d.Allow("hash-a"))
+	assert.False(t, d.Allow("hash-a"))
+	assert.Len(t, d.sent, 1)
+}
+
+// TestDedup_Forget will test the method Forget()
+func TestDedup_Forget(t *testing.T) {
+	d := NewDedup(time.Minute)
+	assert.True(t, d.Allow("hash-a"))
+
+	// A failed delivery can be sent again within the window
+	d.Forget("hash-a")
+	assert.True(t, d.Allow("hash-a"))
+	assert.False(t, d.Allow("hash-a"))
+}
@@ -8,4 +8,6 @@ var (
 	ErrWebhookURLInvalidPrefix  = errors.New("webhook URL does not have a valid prefix")
 	ErrWebhookUnexpectedStatus  = errors.New("unexpected status code sending payload to webhook")
 	ErrWebhookMockUnimplemented = errors.New("unimplemented")
+	ErrWebhookUnknownAlertType  = errors.New("alert type is not known to this node")
+	ErrWebhookRetriesExhausted  = errors.New("webhook delivery failed")
 )
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// slackMaxTextLength is the most text Slack accepts in a section block
+const slackMaxTextLength = 3000
+
+// slackTruncated marks a message that was cut short to fit in a section block
+const slackTruncated = "…"
+
+// slackEscaper escapes the characters Slack treats as control characters in message text
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Formatter turns an alert (and its parsed body) into the body of the webhook request
+type Formatter interface {
+	Format(alert *models.AlertMessage, body models.AlertBody) ([]byte, error)
+}
+
+// NewFormatter will return the formatter for the configured webhook format, raw if none is configured
+func NewFormatter(format string) Formatter {
+	if format == config.WebhookFormatSlack {
+		return &SlackFormatter{}
+	}
+	return &RawFormatter{}
+}
+
+// RawFormatter sends the alert as a Payload
+type RawFormatter struct{}
+
+// Format will marshal the alert as a Payload
+func (f *RawFormatter) Format(alert *models.AlertMessage, body models.AlertBody) ([]byte, error) {
+	return json.Marshal(Payload{
+		AlertType:  alert.GetAlertType().String(),
+		Sequence:   alert.SequenceNumber,
+		Supersedes: alert.Supersedes,
+		Raw:        hex.EncodeToString(alert.GetRawMessage()),
+		Text:       fmt.Sprintf("Sequence [`%d`], alert type [`%s`], message: [`%s`], processed: [`%v`]", alert.SequenceNumber, alert.GetAlertType(), body.MessageString(), alert.Processed),
+	})
+}
+
+// SlackFormatter sends the alert as a Slack message, with the alert type as the header and the message as the body
+type SlackFormatter struct{}
+
+// slackText is a text object of a Slack block
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackBlock is a block of a Slack message
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackMessage is a Slack message, the text is the fallback shown in notifications
+type slackMessage struct {
+	Blocks []slackBlock `json:"blocks"`
+	Text   string       `json:"text"`
+}
+
+// Format will marshal the alert as a Slack message
+func (f *SlackFormatter) Format(alert *models.AlertMessage, body models.AlertBody) ([]byte, error) {
+	header := alert.GetAlertType().String()
+	return json.Marshal(slackMessage{
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: header}},
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: escapeSlackText(body.MessageString())}},
+			{Type: "context", Elements: []slackText{{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("Sequence %d, processed: %v", alert.SequenceNumber, alert.Processed),
+			}}},
+		},
+		Text: escapeSlackText(header),
+	})
+}
+
+// escapeSlackText will escape the text for a Slack text object, truncating it if it's too long for a section block
+// The text is cut on a character boundary before it's escaped, so an escape sequence is never split
+func escapeSlackText(text string) string {
+	escaped := slackEscaper.Replace(text)
+	if len(escaped) <= slackMaxTextLength {
+		return escaped
+	}
+
+	var b strings.Builder
+	limit := slackMaxTextLength - len(slackTruncated)
+	for _, r := range text {
+		next := slackEscaper.Replace(string(r))
+		if b.Len()+len(next) > limit {
+			break
+		}
+		b.WriteString(next)
+	}
+	b.WriteString(slackTruncated)
+	return b.String()
+}
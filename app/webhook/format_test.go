@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+)
+
+// TestNewFormatter will test the method NewFormatter()
+func TestNewFormatter(t *testing.T) {
+	assert.IsType(t, &RawFormatter{}, NewFormatter(""))
+	assert.IsType(t, &RawFormatter{}, NewFormatter(config.WebhookFormatRaw))
+	assert.IsType(t, &SlackFormatter{}, NewFormatter(config.WebhookFormatSlack))
+}
+
+// TestSlackFormatter_Format will test the method Format()
+func TestSlackFormatter_Format(t *testing.T) {
+	a := models.NewAlertMessage()
+	a.SetAlertType(models.AlertTypeInformational)
+	a.SetRawMessage(append([]byte{0x11}, "<!channel> a & b>"...))
+	a.SequenceNumber = 7
+	body, err := a.ProcessAlertMessage()
+	require.NoError(t, err)
+
+	data, err := (&SlackFormatter{}).Format(a, body)
+	require.NoError(t, err)
+
+	message := &slackMessage{}
+	require.NoError(t, json.Unmarshal(data, message))
+	require.Len(t, message.Blocks, 3)
+	assert.Equal(t, "header", message.Blocks[0].Type)
+	assert.Equal(t, models.AlertTypeInformational.String(), message.Blocks[0].Text.Text)
+	assert.Equal(t, "section", message.Blocks[1].Type)
+	assert.Equal(t, "mrkdwn", message.Blocks[1].Text.Type)
+	assert.Equal(t, slackEscaper.Replace(body.MessageString()), message.Blocks[1].Text.Text)
+	assert.Contains(t, message.Blocks[1].Text.Text, "&lt;!channel&gt; a &amp; b&gt;")
+	assert.NotContains(t, message.Blocks[1].Text.Text, "<!channel>")
+	assert.Contains(t, message.Blocks[2].Elements[0].Text, "Sequence 7")
+}
+
+// TestEscapeSlackText will test the method escapeSlackText()
+func TestEscapeSlackText(t *testing.T) {
+	assert.Equal(t, "a &amp; b &lt;@U123&gt;", escapeSlackText("a & b <@U123>"))
+
+	t.Run("long text is truncated without splitting an escape", func(t *testing.T) {
+		text := escapeSlackText(strings.Repeat("ab&", 2000))
+		assert.LessOrEqual(t, len(text), slackMaxTextLength)
+		assert.True(t, strings.HasSuffix(text, slackTruncated))
+		trimmed := strings.TrimSuffix(text, slackTruncated)
+		assert.Equal(t, trimmed, slackEscaper.Replace(strings.NewReplacer("&amp;", "&").Replace(trimmed)))
+	})
+
+	t.Run("text at the limit is kept", func(t *testing.T) {
+		text := strings.Repeat("a", slackMaxTextLength)
+		assert.Equal(t, text, escapeSlackText(text))
+	})
+}
@@ -4,26 +4,62 @@ package webhook
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/metrics"
 	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/utils"
 )
 
 // Payload is the payload for the webhook
 type Payload struct {
-	AlertType models.AlertType `json:"alert_type"`
-	Raw       string           `json:"raw"`
-	Sequence  uint32           `json:"sequence"`
-	Text      string           `json:"text"`
+	AlertType    string `json:"alert_type"` // Name of the alert type (freeze_utxo, special, unknown(100)...), see models.AlertType.String()
+	Raw          string `json:"raw"`
+	Sequence     uint32 `json:"sequence"`
+	SupersededBy uint32 `json:"superseded_by,omitempty"`
+	Supersedes   uint32 `json:"supersedes,omitempty"`
+	Text         string `json:"text"`
+}
+
+// SignatureHeader is the header that carries the HMAC-SHA256 of the payload, when a webhook secret is configured
+const SignatureHeader = "X-Alert-Signature"
+
+// signaturePrefix names the algorithm of the signature in the header
+const signaturePrefix = "sha256="
+
+// backoffJitter is the fraction of the retry wait that is randomized, so nodes that failed together
+// don't all retry at the same moment
+const backoffJitter = 0.2
+
+// jitter returns a random number in [0, 1) used to spread the retry wait
+var jitter = rand.Float64 //nolint:gosec // jitter doesn't need a secure source
+
+// deliveries tracks the webhooks still being delivered, so shutdown can wait for them
+var deliveries = &utils.InFlight{}
+
+// DrainDeliveries will wait for the webhooks still being delivered (and retried) to finish, or for the context to be done
+// It returns how many of the running deliveries finished and how many were still running when the context was done
+func DrainDeliveries(ctx context.Context) (drained, abandoned int) {
+	return deliveries.Drain(ctx)
 }
 
 // PostAlert sends an alert to a webhook URL using the provided http client
-func PostAlert(ctx context.Context, httpClient config.HTTPInterface, url string, alert *models.AlertMessage) error {
+//
+// A delivery that fails with a 5xx or a network error is retried up to the configured max retries,
+// waiting the base backoff (doubled for each retry, with jitter) in between. A 4xx is never retried.
+// If a webhook secret is configured, the payload is signed with it and the signature is sent in the SignatureHeader
+func PostAlert(ctx context.Context, httpClient config.HTTPInterface, webhookConfig config.WebhookConfig, url string, alert *models.AlertMessage) error {
+	deliveries.Begin()
+	defer deliveries.End()
+
 	var err error
 	// Validate the URL length
 	if len(url) == 0 {
@@ -35,38 +71,75 @@ func PostAlert(ctx context.Context, httpClient config.HTTPInterface, url string,
 		return fmt.Errorf("%w: %s", ErrWebhookURLInvalidPrefix, url)
 	}
 
-	am := alert.ProcessAlertMessage()
-	err = am.Read(alert.GetRawMessage())
-	if err != nil {
+	var am models.AlertBody
+	if am, err = alert.ProcessAlertMessage(); err != nil {
 		return err
+	} else if am == nil {
+		return fmt.Errorf("%w: %s", ErrWebhookUnknownAlertType, alert.GetAlertType())
 	}
-	// Create the payload
-	p := Payload{
-		AlertType: alert.GetAlertType(),
-		Sequence:  alert.SequenceNumber,
-		Raw:       hex.EncodeToString(alert.GetRawMessage()),
-		Text:      fmt.Sprintf("Sequence [`%d`], alert type [`%s`], message: [`%s`], processed: [`%v`]", alert.SequenceNumber, alert.GetAlertType().Name(), am.MessageString(), alert.Processed),
-	}
-
-	// Marshal the payload
+	// Format the payload
 	var payload []byte
-	if payload, err = json.Marshal(p); err != nil {
+	if payload, err = NewFormatter(webhookConfig.Format).Format(alert, am); err != nil {
 		return err
 	}
 
+	// Sign the payload
+	var signature string
+	if len(webhookConfig.Secret) > 0 {
+		signature = signPayload(payload, webhookConfig.Secret)
+	}
+
+	// Send the payload, counting whether it was delivered
+	err = deliver(ctx, httpClient, webhookConfig, url, payload, signature)
+	metrics.ObserveWebhook(err)
+	return err
+}
+
+// deliver will post the payload to the webhook URL, retrying the failures that may be transient
+func deliver(ctx context.Context, httpClient config.HTTPInterface, webhookConfig config.WebhookConfig, url string, payload []byte, signature string) error {
+	attempts := webhookConfig.MaxRetries + 1
+	for attempt := 1; ; attempt++ {
+		retry, err := postPayload(ctx, httpClient, url, payload, signature)
+		if err == nil {
+			return nil
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		} else if !retry {
+			return err
+		} else if attempt >= attempts {
+			return fmt.Errorf("%w after %d attempts: %w", ErrWebhookRetriesExhausted, attempt, err)
+		}
+
+		// Wait before the next attempt, unless the context is done first
+		timer := time.NewTimer(retryBackoff(webhookConfig.BaseBackoff, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// postPayload will post the payload (and its signature, if it's signed) to the webhook URL once
+// Returns true if the failure may be transient (a 5xx or a network error) and is worth retrying
+func postPayload(ctx context.Context, httpClient config.HTTPInterface, url string, payload []byte, signature string) (bool, error) {
 	// Create the http request
-	var req *http.Request
-	if req, err = http.NewRequestWithContext(
+	req, err := http.NewRequestWithContext(
 		ctx, http.MethodPost, url, bytes.NewReader(payload),
-	); err != nil {
-		return err
+	)
+	if err != nil {
+		return false, err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if len(signature) > 0 {
+		req.Header.Set(SignatureHeader, signature)
+	}
 
 	// Fire the http request
 	var res *http.Response
 	if res, err = httpClient.Do(req); err != nil {
-		return err
+		return true, err
 	}
 	defer func() {
 		if res != nil && res.Body != nil {
@@ -75,8 +148,40 @@ func PostAlert(ctx context.Context, httpClient config.HTTPInterface, url string,
 	}()
 
 	// Validate the response
-	if res != nil && res.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %d", ErrWebhookUnexpectedStatus, res.StatusCode)
+	if res != nil && (res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices) {
+		return res.StatusCode >= http.StatusInternalServerError, fmt.Errorf("%w: %d", ErrWebhookUnexpectedStatus, res.StatusCode)
+	}
+	return false, nil
+}
+
+// retryBackoff will return the wait before the retry after the given attempt
+// The base backoff is doubled for each attempt after the first, then spread by +/- the jitter fraction
+func retryBackoff(base time.Duration, attempt int) time.Duration {
+	wait := base
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+	}
+	return wait + time.Duration(float64(wait)*backoffJitter*(2*jitter()-1))
+}
+
+// signPayload will return the signature header value of the payload: the hex HMAC-SHA256 with the sha256= prefix
+func signPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature returns true if the signature header is the HMAC-SHA256 of the body with the secret
+// This is what a webhook consumer runs against the raw request body and the SignatureHeader
+func VerifyWebhookSignature(body []byte, header, secret string) bool {
+	if len(secret) == 0 || !strings.HasPrefix(header, signaturePrefix) {
+		return false
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, signaturePrefix))
+	if err != nil {
+		return false
 	}
-	return nil
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
 }
@@ -1,9 +1,159 @@
 package webhook
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
 )
 
+// errNetwork is a network failure returned by the mock client
+var errNetwork = errors.New("connection refused")
+
+// testRetryConfig retries quickly, so the tests don't wait on the backoff
+var testRetryConfig = config.WebhookConfig{BaseBackoff: time.Millisecond, MaxRetries: 2}
+
+// newTestAlert will create an informational alert to send
+func newTestAlert() *models.AlertMessage {
+	a := models.NewAlertMessage()
+	a.SetAlertType(models.AlertTypeInformational)
+	a.SetRawMessage([]byte{0x02, 'h', 'i'})
+	return a
+}
+
+// statusClient will answer each request with the next result, repeating the last one
+func statusClient(requests *int, results ...interface{}) *MockHTTPClient {
+	return &MockHTTPClient{DoFunc: func(_ *http.Request) (*http.Response, error) {
+		result := results[min(*requests, len(results)-1)]
+		*requests++
+		if err, ok := result.(error); ok {
+			return nil, err
+		}
+		return &http.Response{StatusCode: result.(int), Body: io.NopCloser(strings.NewReader(""))}, nil //nolint:forcetypeassert // results are a status or an error
+	}}
+}
+
+// TestPostAlert_Retries will test the retries of the method PostAlert()
+func TestPostAlert_Retries(t *testing.T) {
+	t.Run("success on the first attempt", func(t *testing.T) {
+		var requests int
+		err := PostAlert(context.Background(), statusClient(&requests, http.StatusNoContent), testRetryConfig, "https://webhook.url", newTestAlert())
+		require.NoError(t, err)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("5xx and network errors are retried", func(t *testing.T) {
+		var requests int
+		err := PostAlert(context.Background(), statusClient(&requests, http.StatusBadGateway, errNetwork, http.StatusOK), testRetryConfig, "https://webhook.url", newTestAlert())
+		require.NoError(t, err)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("4xx is not retried", func(t *testing.T) {
+		var requests int
+		err := PostAlert(context.Background(), statusClient(&requests, http.StatusBadRequest), testRetryConfig, "https://webhook.url", newTestAlert())
+		require.ErrorIs(t, err, ErrWebhookUnexpectedStatus)
+		require.NotErrorIs(t, err, ErrWebhookRetriesExhausted)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("last error is returned when the retries run out", func(t *testing.T) {
+		var requests int
+		err := PostAlert(context.Background(), statusClient(&requests, http.StatusServiceUnavailable, errNetwork), testRetryConfig, "https://webhook.url", newTestAlert())
+		require.ErrorIs(t, err, ErrWebhookRetriesExhausted)
+		require.ErrorIs(t, err, errNetwork)
+		assert.Contains(t, err.Error(), "3 attempts")
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("cancelled context stops the retries", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var requests int
+		client := statusClient(&requests, http.StatusInternalServerError)
+		do := client.DoFunc
+		client.DoFunc = func(req *http.Request) (*http.Response, error) {
+			cancel()
+			return do(req)
+		}
+
+		err := PostAlert(ctx, client, config.WebhookConfig{BaseBackoff: time.Hour, MaxRetries: 5}, "https://webhook.url", newTestAlert())
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, requests)
+	})
+}
+
+// TestPostAlert_Signature will test the payload signature of the method PostAlert()
+func TestPostAlert_Signature(t *testing.T) {
+	// capture will record the body and the signature header of the request
+	capture := func(body *[]byte, header *string) *MockHTTPClient {
+		return &MockHTTPClient{DoFunc: func(req *http.Request) (*http.Response, error) {
+			var err error
+			*body, err = io.ReadAll(req.Body)
+			*header = req.Header.Get(SignatureHeader)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, err
+		}}
+	}
+
+	t.Run("signed with the secret", func(t *testing.T) {
+		var body []byte
+		var header string
+		webhookConfig := config.WebhookConfig{MaxRetries: 1, Secret: "shared-secret"}
+		require.NoError(t, PostAlert(context.Background(), capture(&body, &header), webhookConfig, "https://webhook.url", newTestAlert()))
+
+		assert.True(t, strings.HasPrefix(header, "sha256="))
+		assert.True(t, VerifyWebhookSignature(body, header, "shared-secret"))
+		assert.False(t, VerifyWebhookSignature(body, header, "other-secret"))
+		assert.False(t, VerifyWebhookSignature(append(body, ' '), header, "shared-secret"))
+	})
+
+	t.Run("unsigned without a secret", func(t *testing.T) {
+		var body []byte
+		var header string
+		require.NoError(t, PostAlert(context.Background(), capture(&body, &header), testRetryConfig, "https://webhook.url", newTestAlert()))
+		assert.NotEmpty(t, body)
+		assert.Empty(t, header)
+	})
+}
+
+// TestVerifyWebhookSignature will test the method VerifyWebhookSignature()
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"sequence":1}`)
+	header := signPayload(body, "shared-secret")
+
+	assert.True(t, VerifyWebhookSignature(body, header, "shared-secret"))
+	assert.False(t, VerifyWebhookSignature(body, strings.TrimPrefix(header, "sha256="), "shared-secret"))
+	assert.False(t, VerifyWebhookSignature(body, "sha256=not-hex", "shared-secret"))
+	assert.False(t, VerifyWebhookSignature(body, header, ""))
+	assert.False(t, VerifyWebhookSignature(body, "", "shared-secret"))
+}
+
+// TestRetryBackoff will test the method retryBackoff()
+func TestRetryBackoff(t *testing.T) {
+	defer func(original func() float64) {
+		jitter = original
+	}(jitter)
+
+	jitter = func() float64 { return 0.5 } // 0.5 is no jitter
+	assert.Equal(t, time.Second, retryBackoff(time.Second, 1))
+	assert.Equal(t, 2*time.Second, retryBackoff(time.Second, 2))
+	assert.Equal(t, 4*time.Second, retryBackoff(time.Second, 3))
+
+	jitter = func() float64 { return 0 }
+	assert.Equal(t, 8*time.Second, retryBackoff(10*time.Second, 1))
+
+	jitter = func() float64 { return 1 }
+	assert.Equal(t, 12*time.Second, retryBackoff(10*time.Second, 1))
+}
+
 // MockHTTPClient is a mock HTTP client for testing purposes
 type MockHTTPClient struct {
 	DoFunc func(req *http.Request) (*http.Response, error)
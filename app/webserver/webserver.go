@@ -6,7 +6,6 @@ import (
 	"crypto/tls"
 	"errors"
 	"net/http"
-	"strings"
 
 	apirouter "github.com/mrz1836/go-api-router"
 	"github.com/newrelic/go-agent/v3/integrations/nrhttprouter"
@@ -16,10 +15,6 @@ import (
 	p2palert "github.com/bsv-blockchain/go-alert-system/app/p2p"
 )
 
-const (
-	wildcard = "*"
-)
-
 // Server is the configuration, services, and actual web server
 type Server struct {
 	Config    *config.Config
@@ -71,10 +66,9 @@ func (s *Server) Serve() {
 }
 
 // Shutdown will stop the web server
+//
+// The datastore is left open, alert actions still running may need it until the p2p server is stopped
 func (s *Server) Shutdown(ctx context.Context) error {
-	if s.Config != nil {
-		s.Config.CloseAll(ctx) // Should have been executed in main.go, but might panic and not run?
-	}
 	if s.WebServer != nil {
 		return s.WebServer.Shutdown(ctx)
 	}
@@ -89,21 +83,7 @@ func (s *Server) Handlers() *nrhttprouter.Router {
 	// Custom logger
 	s.Router.Logger = s.Config.Services.Log
 
-	// Turned on all CORs for now
-	s.Router.CrossOriginEnabled = true
-	s.Router.CrossOriginAllowCredentials = true
-	s.Router.CrossOriginAllowOrigin = wildcard
-	s.Router.CrossOriginAllowHeaders = wildcard
-	s.Router.CrossOriginAllowMethods = strings.Join([]string{
-		http.MethodDelete,
-		http.MethodGet,
-		http.MethodOptions,
-		http.MethodPatch,
-		http.MethodPost,
-		http.MethodPut,
-	}, ",")
-
-	// Register all actions (routes / handlers)
+	// Register all actions (routes / handlers), including the CORS handling
 	base.RegisterRoutes(s.Router, s.Config, s.P2pServer)
 
 	// Return the router
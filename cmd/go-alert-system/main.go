@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/bsv-blockchain/go-alert-system/app/config"
 	"github.com/bsv-blockchain/go-alert-system/app/models"
@@ -16,6 +17,11 @@ import (
 
 // main is the entry point for the alert-system
 func main() {
+	// Offline subcommands run without the config, node or datastore
+	if len(os.Args) > 1 && os.Args[1] == verifyCommandName {
+		os.Exit(runVerify(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Load the configuration and services
 	_appConfig, err := config.LoadDependencies(context.Background(), models.BaseModels, false)
 	if err != nil {
@@ -25,6 +31,16 @@ func main() {
 		_appConfig.CloseAll(context.Background())
 	}()
 
+	// Dry-run mode is easy to forget about, since alerts still look processed
+	if _appConfig.DryRun {
+		_appConfig.Services.Log.Warnf("dry_run is enabled, alerts are validated and stored but their node RPC calls are skipped")
+	}
+
+	// Ensure the executed and suppressed alert types are known alert types
+	if err = models.CheckExecutionTypes(_appConfig); err != nil {
+		_appConfig.Services.Log.Fatalf("error in the executed alert types: %s", err.Error())
+	}
+
 	// Ensure we have the genesis alert in the database
 	if err = models.CreateGenesisAlert(
 		context.Background(), model.WithAllDependencies(_appConfig),
@@ -32,6 +48,13 @@ func main() {
 		_appConfig.Services.Log.Fatalf("error creating genesis alert: %s", err.Error())
 	}
 
+	// Record the type of any alerts stored before the type was saved with them
+	if err = models.BackfillAlertTypes(
+		context.Background(), model.WithAllDependencies(_appConfig),
+	); err != nil {
+		_appConfig.Services.Log.Fatalf("error backfilling alert types: %s", err.Error())
+	}
+
 	// Ensure that RPC connection is valid
 	if !_appConfig.DisableRPCVerification {
 		if _, err = _appConfig.Services.Node.BestBlockHash(context.Background()); err != nil {
@@ -40,6 +63,13 @@ func main() {
 		}
 	}
 
+	// Apply the stored alert history to the node, instead of starting the servers
+	if len(os.Args) > 1 && os.Args[1] == replayCommandName {
+		code := runReplay(context.Background(), _appConfig, os.Args[2:], os.Stdout, os.Stderr)
+		_appConfig.CloseAll(context.Background())
+		os.Exit(code)
+	}
+
 	// Create the p2p server
 	var p2pServer *p2p.Server
 	if p2pServer, err = p2p.NewServer(p2p.ServerOptions{
@@ -62,7 +92,7 @@ func main() {
 	idleConnectionsClosed := make(chan struct{})
 	go func(appConfig *config.Config) {
 		sigint := make(chan os.Signal, 1)
-		signal.Notify(sigint, os.Interrupt)
+		signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
 
 		// Log when a signal is received
 		appConfig.Services.Log.Debugf("waiting for interrupt signal")
@@ -72,7 +102,8 @@ func main() {
 		appConfig.Services.Log.Infof("interrupt signal received, starting shutdown process")
 
 		// We received an interrupt signal, shut down the server
-		ctxTimeout, cancel := context.WithTimeout(context.Background(), config.DefaultServerShutdown)
+		// The in-flight alert actions and webhook deliveries get until the shutdown timeout to finish
+		ctxTimeout, cancel := context.WithTimeout(context.Background(), appConfig.ShutdownTimeout)
 		defer cancel()
 		if err = webServer.Shutdown(ctxTimeout); err != nil {
 			appConfig.Services.Log.Infof("error shutting down webserver: %s", err.Error())
@@ -82,6 +113,9 @@ func main() {
 		if err = p2pServer.Stop(ctxTimeout); err != nil {
 			appConfig.Services.Log.Infof("error shutting down p2p server: %s", err.Error())
 		}
+
+		// Close the datastore once nothing is left that writes to it
+		appConfig.CloseAll(context.Background())
 		cancelFunc()
 		appConfig.Services.Log.Infof("successfully shut down server")
 		close(idleConnectionsClosed)
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// replayCommandName is the name of the subcommand that executes the stored alert history against the node
+const replayCommandName = "replay"
+
+// Exit codes of the replay subcommand
+const (
+	replayExitOK     = 0 // Every alert replayed (or was skipped)
+	replayExitFailed = 1 // An alert failed, or the alerts couldn't be read
+	replayExitUsage  = 2 // The arguments are malformed
+)
+
+// runReplay will execute the actions of the active stored alerts again, to apply an existing alert history to a new node
+//
+// The outcome of each alert is printed by sequence, and the exit code is non-zero if any failed,
+// the replay can be resumed from the failed sequence with -from
+func runReplay(ctx context.Context, conf *config.Config, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(replayCommandName, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	from := flags.Uint("from", 0, "sequence to start replaying from")
+	dryRun := flags.Bool("dry-run", false, "only validate the alerts, without executing them")
+	if err := flags.Parse(args); err != nil {
+		return replayExitUsage
+	} else if uint64(*from) > uint64(^uint32(0)) {
+		_, _ = fmt.Fprintf(stderr, "invalid -from sequence: %d\n", *from)
+		return replayExitUsage
+	}
+
+	results, err := models.ReplayAlerts(ctx, uint32(*from), *dryRun, model.WithAllDependencies(conf))
+	code := replayExitOK
+	for _, result := range results {
+		switch result.Outcome {
+		case models.ReplayFailed:
+			code = replayExitFailed
+			_, _ = fmt.Fprintf(stdout, "%d\t%s\t%s\t%s\n", result.Sequence, result.AlertType, result.Outcome, result.Error)
+		case models.ReplaySkipped:
+			_, _ = fmt.Fprintf(stdout, "%d\t%s\t%s\t%s\n", result.Sequence, result.AlertType, result.Outcome, result.Reason)
+		default:
+			_, _ = fmt.Fprintf(stdout, "%d\t%s\t%s\t%s\n", result.Sequence, result.AlertType, result.Outcome, result.Summary)
+		}
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to replay alerts: %s\n", err.Error())
+		return replayExitFailed
+	}
+	return code
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// TestRunReplay will test the method runReplay()
+func TestRunReplay(t *testing.T) {
+	t.Setenv(config.EnvironmentKey, config.EnvironmentTest)
+	conf, err := config.LoadDependencies(context.Background(), models.BaseModels, true)
+	require.NoError(t, err)
+	defer conf.CloseAll(context.Background())
+	require.NoError(t, models.CreateGenesisAlert(context.Background(), model.WithAllDependencies(conf)))
+
+	t.Run("the genesis alert is skipped", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, replayExitOK, runReplay(context.Background(), conf, []string{"-dry-run"}, &stdout, &stderr))
+		assert.Contains(t, stdout.String(), "0\tset_keys\tskipped\t")
+		assert.Empty(t, stderr.String())
+	})
+
+	t.Run("malformed arguments", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, replayExitUsage, runReplay(context.Background(), conf, []string{"-from", "nope"}, &stdout, &stderr))
+		assert.Equal(t, replayExitUsage, runReplay(context.Background(), conf, []string{"-from", "4294967296"}, &stdout, &stderr))
+		assert.Empty(t, stdout.String())
+	})
+}
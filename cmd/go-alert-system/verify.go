@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bsv-blockchain/go-alert-system/app/config"
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/app/models/model"
+)
+
+// verifyCommandName is the name of the subcommand that checks alert signatures offline
+const verifyCommandName = "verify"
+
+// Exit codes of the verify subcommand
+const (
+	verifyExitOK      = 0 // The signatures meet the threshold
+	verifyExitInvalid = 1 // The alert can't be read or its signatures don't meet the threshold
+	verifyExitUsage   = 2 // The arguments are missing or malformed
+)
+
+// errVerifyUsage is returned for missing or malformed verify arguments
+var errVerifyUsage = errors.New("usage: go-alert-system verify -keys <pubkey,pubkey,...> [-threshold n] [-max-skew duration] <alert hex>")
+
+// runVerify will check the signatures of an alert against the expected public keys, without a node or datastore
+//
+// The keys that signed are printed, and the exit code is non-zero if they don't meet the threshold,
+// so it can check alert test vectors in CI
+func runVerify(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(verifyCommandName, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	alertHex := flags.String("alert", "", "alert to verify (hex), instead of the argument")
+	keyList := flags.String("keys", "", "expected public keys (hex, comma separated)")
+	threshold := flags.Int("threshold", models.SignatureQuorum, "valid signatures from distinct keys needed")
+	maxSkew := flags.Duration("max-skew", 0, "reject an alert timestamp further than this in the future (0 doesn't check)")
+	if err := flags.Parse(args); err != nil {
+		return verifyExitUsage
+	}
+	if *alertHex == "" && flags.NArg() == 1 {
+		*alertHex = flags.Arg(0)
+	}
+
+	keys := make([]*models.PublicKey, 0)
+	for _, key := range strings.Split(*keyList, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, &models.PublicKey{Key: key})
+		}
+	}
+	if *alertHex == "" || len(keys) == 0 || *threshold <= 0 || *threshold > len(keys) {
+		_, _ = fmt.Fprintln(stderr, errVerifyUsage.Error())
+		return verifyExitUsage
+	}
+
+	raw, err := hex.DecodeString(strings.TrimSpace(*alertHex))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "invalid alert hex: %s\n", err.Error())
+		return verifyExitUsage
+	}
+	alert, err := models.NewAlertFromBytes(raw, model.WithAllDependencies(&config.Config{
+		MaxAlertTimestampSkew: *maxSkew,
+		SignatureThreshold:    *threshold,
+	}))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to read alert: %s\n", err.Error())
+		return verifyExitInvalid
+	}
+	alert.SerializeData()
+
+	signers, err := alert.SignedBy(keys)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to check signatures: %s\n", err.Error())
+		return verifyExitUsage
+	}
+	signed := make(map[*models.PublicKey]bool, len(signers))
+	for _, signer := range signers {
+		signed[signer] = true
+	}
+
+	_, _ = fmt.Fprintf(stdout, "alert %d (%s): %d signatures\n", alert.SequenceNumber, alert.GetAlertType(), len(alert.Signatures()))
+	for _, key := range keys {
+		status := "not signed"
+		if signed[key] {
+			status = "signed"
+		}
+		_, _ = fmt.Fprintf(stdout, "  %-10s %s\n", status, key.Key)
+	}
+
+	if err = alert.Verify(context.Background(), keys); err != nil {
+		_, _ = fmt.Fprintf(stdout, "FAIL: %s\n", err.Error())
+		return verifyExitInvalid
+	}
+	_, _ = fmt.Fprintf(stdout, "OK: %d of %d needed\n", len(signers), *threshold)
+	return verifyExitOK
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitcoinschema/go-bitcoin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-alert-system/app/models"
+	"github.com/bsv-blockchain/go-alert-system/utils"
+)
+
+// newTestAlertHex will create an informational alert signed with the private keys
+func newTestAlertHex(t *testing.T, privateKeys ...string) string {
+	return newTestAlertHexAt(t, 0, privateKeys...)
+}
+
+// newTestAlertHexAt will create an informational alert with the timestamp, signed with the private keys
+func newTestAlertHexAt(t *testing.T, timestamp uint64, privateKeys ...string) string {
+	a := models.NewAlertMessage()
+	a.SetTimestamp(timestamp)
+	a.SetVersion(models.AlertVersionSignatureCount)
+	a.SetAlertType(models.AlertTypeInformational)
+	a.SetRawMessage([]byte{0x02, 'h', 'i'})
+	a.SequenceNumber = 5
+	a.SerializeData()
+	sigs, err := utils.SignWithKeys(a.GetRawData(), privateKeys)
+	require.NoError(t, err)
+	a.SetSignatures(sigs)
+	return hex.EncodeToString(a.Serialize())
+}
+
+// testPublicKeys will return the public keys of the private keys, comma separated
+func testPublicKeys(t *testing.T, privateKeys ...string) string {
+	keys := make([]string, 0, len(privateKeys))
+	for _, privateKey := range privateKeys {
+		pub, err := bitcoin.PubKeyFromPrivateKeyString(privateKey, true)
+		require.NoError(t, err)
+		keys = append(keys, pub)
+	}
+	return strings.Join(keys, ",")
+}
+
+// TestRunVerify will test the method runVerify()
+func TestRunVerify(t *testing.T) {
+	keys := testPublicKeys(t, utils.Key1, utils.Key2, utils.Key3, utils.Key4)
+
+	t.Run("quorum", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := runVerify([]string{"-keys", keys, newTestAlertHex(t, utils.Key1, utils.Key2, utils.Key4)}, stdout, stderr)
+		assert.Equal(t, verifyExitOK, code, stderr.String())
+		assert.Equal(t, 3, strings.Count(stdout.String(), "  signed "))
+		assert.Equal(t, 1, strings.Count(stdout.String(), "not signed"))
+		assert.Contains(t, stdout.String(), "OK: 3 of 3 needed")
+	})
+
+	t.Run("not enough signatures", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		code := runVerify([]string{"-keys", keys, "-alert", newTestAlertHex(t, utils.Key1, utils.Key5, utils.Key5)}, stdout, &bytes.Buffer{})
+		assert.Equal(t, verifyExitInvalid, code)
+		assert.Contains(t, stdout.String(), "FAIL")
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		code := runVerify([]string{"-keys", keys, "-threshold", "2", newTestAlertHex(t, utils.Key1, utils.Key2)}, &bytes.Buffer{}, &bytes.Buffer{})
+		assert.Equal(t, verifyExitOK, code)
+	})
+
+	t.Run("timestamp in the future", func(t *testing.T) {
+		future := newTestAlertHexAt(t, uint64(time.Now().Add(24*time.Hour).Unix()), utils.Key1, utils.Key2, utils.Key3)
+		assert.Equal(t, verifyExitOK, runVerify([]string{"-keys", keys, future}, &bytes.Buffer{}, &bytes.Buffer{}))
+
+		stderr := &bytes.Buffer{}
+		code := runVerify([]string{"-keys", keys, "-max-skew", "2h", future}, &bytes.Buffer{}, stderr)
+		assert.Equal(t, verifyExitInvalid, code)
+		assert.Contains(t, stderr.String(), models.ErrAlertTimestampInFuture.Error())
+	})
+
+	t.Run("usage errors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{newTestAlertHex(t, utils.Key1)},
+			{"-keys", keys},
+			{"-keys", keys, "-threshold", "5", "00"},
+			{"-keys", keys, "not-hex"},
+		} {
+			assert.Equal(t, verifyExitUsage, runVerify(args, &bytes.Buffer{}, &bytes.Buffer{}), args)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package utils
+
+import "sync"
+
+const (
+	// defaultBufferSize is the starting capacity of a pooled buffer, enough for most alerts with their signatures
+	defaultBufferSize = 1024
+
+	// maxPooledBufferSize is the largest buffer kept in the pool, so one huge alert doesn't pin the memory
+	maxPooledBufferSize = 64 * 1024
+)
+
+// bufferPool holds the reusable buffers for serializing alerts and sync messages
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, defaultBufferSize)
+		return &buf
+	},
+}
+
+// getBuffer will get an empty buffer from the pool
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte) //nolint:errcheck,forcetypeassert // the pool only holds *[]byte
+}
+
+// putBuffer will wipe the buffer and return it to the pool
+// The contents are zeroed so nothing from one alert can leak into the next serialization
+func putBuffer(buf *[]byte) {
+	if cap(*buf) > maxPooledBufferSize {
+		return
+	}
+	clear((*buf)[:cap(*buf)])
+	*buf = (*buf)[:0]
+	bufferPool.Put(buf)
+}
+
+// PooledSerialize will run the serializer against a pooled buffer and return a copy of what it wrote
+//
+// The serializer appends to the empty buffer it's given and returns the result, like the append functions
+// The copy is the only thing handed back, so callers never hold on to a pooled buffer
+func PooledSerialize(serializer func(buf []byte) []byte) []byte {
+	buf := getBuffer()
+	*buf = serializer(*buf)
+
+	out := make([]byte, len(*buf))
+	copy(out, *buf)
+	putBuffer(buf)
+	return out
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPayload will create a payload that is unique to the worker and iteration
+func testPayload(worker, iteration int) []byte {
+	return bytes.Repeat([]byte{byte(worker), byte(iteration)}, 16+worker)
+}
+
+// serializeTestPayload serializes the payload the same way the alert and sync message serializers do
+func serializeTestPayload(ret []byte, sequence uint32, payload []byte) []byte {
+	ret = binary.LittleEndian.AppendUint32(ret, sequence)
+	return append(ret, payload...)
+}
+
+// TestPooledSerialize tests the function PooledSerialize
+func TestPooledSerialize(t *testing.T) {
+	t.Run("returns what the serializer wrote", func(t *testing.T) {
+		out := PooledSerialize(func(buf []byte) []byte {
+			assert.Empty(t, buf)
+			return append(buf, "alert"...)
+		})
+		assert.Equal(t, []byte("alert"), out)
+		assert.Len(t, out, cap(out))
+	})
+
+	t.Run("result is not shared with the pool", func(t *testing.T) {
+		first := PooledSerialize(func(buf []byte) []byte {
+			return append(buf, "first"...)
+		})
+		second := PooledSerialize(func(buf []byte) []byte {
+			return append(buf, "second"...)
+		})
+		first[0] = 'X'
+		assert.Equal(t, []byte("second"), second)
+		assert.Equal(t, []byte("Xirst"), first)
+	})
+
+	t.Run("returned buffers are wiped", func(t *testing.T) {
+		buf := getBuffer()
+		*buf = append(*buf, "secret"...)
+		putBuffer(buf)
+		assert.Empty(t, *buf)
+		assert.Equal(t, make([]byte, 6), (*buf)[:6])
+	})
+
+	t.Run("oversized buffers are not pooled", func(t *testing.T) {
+		out := PooledSerialize(func(buf []byte) []byte {
+			return append(buf, make([]byte, maxPooledBufferSize+1)...)
+		})
+		assert.Len(t, out, maxPooledBufferSize+1)
+	})
+
+	t.Run("no cross contamination between concurrent serializations", func(t *testing.T) {
+		const workers, iterations = 16, 200
+
+		var wg sync.WaitGroup
+		failures := make(chan string, workers)
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for i := 0; i < iterations; i++ {
+					payload := testPayload(worker, i)
+					out := PooledSerialize(func(buf []byte) []byte {
+						return serializeTestPayload(buf, uint32(worker), payload) //nolint:gosec // test worker index
+					})
+					if !bytes.Equal(out, serializeTestPayload(nil, uint32(worker), payload)) { //nolint:gosec // test worker index
+						failures <- string(out)
+						return
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		close(failures)
+
+		for failure := range failures {
+			require.Failf(t, "serialization was contaminated", "%x", failure)
+		}
+	})
+}
+
+// BenchmarkPooledSerialize benchmarks the function PooledSerialize
+func BenchmarkPooledSerialize(b *testing.B) {
+	payload := testPayload(1, 1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = PooledSerialize(func(buf []byte) []byte {
+			buf = serializeTestPayload(buf, uint32(i), payload) //nolint:gosec // benchmark iteration
+			for j := 0; j < 3; j++ {
+				buf = append(buf, payload...)
+			}
+			return buf
+		})
+	}
+}
+
+// BenchmarkAppendSerialize benchmarks serializing into a fresh buffer, the baseline for BenchmarkPooledSerialize
+func BenchmarkAppendSerialize(b *testing.B) {
+	payload := testPayload(1, 1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var buf []byte
+		buf = serializeTestPayload(buf, uint32(i), payload) //nolint:gosec // benchmark iteration
+		for j := 0; j < 3; j++ {
+			buf = append(buf, payload...)
+		}
+		_ = buf
+	}
+}
@@ -3,7 +3,13 @@ package utils
 // Universal fields for the application
 const (
 	FieldActive         = "active"          // Active is boolean field for active models
+	FieldAlertType      = "alert_type"      // AlertType is the type of alert
 	FieldDeletedAt      = "deleted_at"      // Deleted at timestamp on every model
+	FieldExecutedAt     = "executed_at"     // ExecutedAt is the time an alert action was executed
 	FieldID             = "id"              // ID is a generic id for many models
+	FieldRevokedAt      = "revoked_at"      // RevokedAt is the time a public key was revoked
 	FieldSequenceNumber = "sequence_number" // SequenceNumber is used for the alert message sequencing
+	FieldSuperseded     = "superseded"      // Superseded is the flag of an alert replaced by a later alert
+	FieldTxID           = "tx_id"           // TxID is the transaction id of an outpoint
+	FieldVout           = "vout"            // Vout is the output index of an outpoint
 )
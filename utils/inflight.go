@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// InFlight counts the operations that are still running, so shutdown can wait for them to finish
+// The zero value is ready to use
+type InFlight struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{} // closed once the count drops back to zero
+}
+
+// Begin records an operation that started
+func (f *InFlight) Begin() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.count == 0 {
+		f.idle = make(chan struct{})
+	}
+	f.count++
+}
+
+// End records an operation that finished
+func (f *InFlight) End() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count--
+	if f.count == 0 {
+		close(f.idle)
+	}
+}
+
+// Count returns the number of operations still running
+func (f *InFlight) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// Wait returns a channel that is closed once no operations are running
+func (f *InFlight) Wait() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.count == 0 {
+		idle := make(chan struct{})
+		close(idle)
+		return idle
+	}
+	return f.idle
+}
+
+// Drain will wait for the running operations to finish, or for the context to be done
+// It returns how many of the operations running when it was called finished, and how many were still running
+func (f *InFlight) Drain(ctx context.Context) (drained, abandoned int) {
+	running := f.Count()
+	select {
+	case <-f.Wait():
+		return running, 0
+	case <-ctx.Done():
+		abandoned = min(f.Count(), running)
+		return running - abandoned, abandoned
+	}
+}
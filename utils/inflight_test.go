@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInFlight_Drain tests the method Drain()
+func TestInFlight_Drain(t *testing.T) {
+	t.Run("nothing running", func(t *testing.T) {
+		f := &InFlight{}
+		drained, abandoned := f.Drain(context.Background())
+		assert.Zero(t, drained)
+		assert.Zero(t, abandoned)
+	})
+
+	t.Run("operations that finish are drained", func(t *testing.T) {
+		f := &InFlight{}
+		f.Begin()
+		f.Begin()
+		assert.Equal(t, 2, f.Count())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			f.End()
+			f.End()
+		}()
+
+		drained, abandoned := f.Drain(context.Background())
+		assert.Equal(t, 2, drained)
+		assert.Zero(t, abandoned)
+		assert.Zero(t, f.Count())
+	})
+
+	t.Run("operations still running at the deadline are abandoned", func(t *testing.T) {
+		f := &InFlight{}
+		f.Begin()
+		f.Begin()
+		f.End()
+		f.Begin()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		drained, abandoned := f.Drain(ctx)
+		assert.Zero(t, drained)
+		assert.Equal(t, 2, abandoned)
+	})
+
+	t.Run("can be used again once idle", func(t *testing.T) {
+		f := &InFlight{}
+		f.Begin()
+		f.End()
+		<-f.Wait()
+
+		f.Begin()
+		select {
+		case <-f.Wait():
+			t.Fatal("wait returned while an operation is running")
+		default:
+		}
+		f.End()
+		<-f.Wait()
+	})
+}